@@ -0,0 +1,112 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
+)
+
+const (
+	apiTokenURL           = "/v4/token"
+	apiAccountsURL        = "/v4/accounts"
+	apiAccountURL         = "/v4/accounts/%s"
+	apiAccountPasswordURL = "/v4/accounts/%s/password"
+	apiPermissionCheckURL = "/v4/auth/permissions/check"
+)
+
+// Login exchanges the account name and password for a token,
+// the token will expire after the duration expire, e.g. 30m, 12h
+func (c *Client) Login(ctx context.Context, name, password, expire string) (string, *errsvc.Error) {
+	return c.LoginWithTOTP(ctx, name, password, expire, "")
+}
+
+// LoginWithTOTP is Login with the TOTP code or a recovery code,
+// it is required if the account enables TOTP
+func (c *Client) LoginWithTOTP(ctx context.Context, name, password, expire, code string) (string, *errsvc.Error) {
+	req := struct {
+		*rbac.Account
+		rbacpkg.TOTPLogin
+	}{
+		Account:   &rbac.Account{Name: name, Password: password, TokenExpirationTime: expire},
+		TOTPLogin: rbacpkg.TOTPLogin{Code: code},
+	}
+	token := &rbac.Token{}
+	scErr := c.doJSON(ctx, http.MethodPost, apiTokenURL, c.CommonHeaders(ctx), req, token)
+	if scErr != nil {
+		return "", scErr
+	}
+	return token.TokenStr, nil
+}
+
+// Logout revokes the token of the client
+func (c *Client) Logout(ctx context.Context) *errsvc.Error {
+	return c.doJSON(ctx, http.MethodDelete, apiTokenURL, c.CommonHeaders(ctx), nil, nil)
+}
+
+func (c *Client) ListAccounts(ctx context.Context) ([]*rbac.Account, *errsvc.Error) {
+	accountsResp := &rbac.AccountResponse{}
+	scErr := c.doJSON(ctx, http.MethodGet, apiAccountsURL, c.CommonHeaders(ctx), nil, accountsResp)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return accountsResp.Accounts, nil
+}
+
+func (c *Client) GetAccount(ctx context.Context, name string) (*rbac.Account, *errsvc.Error) {
+	account := &rbac.Account{}
+	scErr := c.doJSON(ctx, http.MethodGet, fmt.Sprintf(apiAccountURL, name), c.CommonHeaders(ctx), nil, account)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return account, nil
+}
+
+func (c *Client) CreateAccount(ctx context.Context, account *rbac.Account) *errsvc.Error {
+	return c.doJSON(ctx, http.MethodPost, apiAccountsURL, c.CommonHeaders(ctx), account, nil)
+}
+
+func (c *Client) UpdateAccount(ctx context.Context, account *rbac.Account) *errsvc.Error {
+	return c.doJSON(ctx, http.MethodPut, fmt.Sprintf(apiAccountURL, account.Name), c.CommonHeaders(ctx), account, nil)
+}
+
+func (c *Client) DeleteAccount(ctx context.Context, name string) *errsvc.Error {
+	return c.doJSON(ctx, http.MethodDelete, fmt.Sprintf(apiAccountURL, name), c.CommonHeaders(ctx), nil, nil)
+}
+
+// ChangePassword changes the password of the account, the current password
+// can be empty when an admin changes the password of the others
+func (c *Client) ChangePassword(ctx context.Context, name, current, password string) *errsvc.Error {
+	account := &rbac.Account{CurrentPassword: current, Password: password}
+	return c.doJSON(ctx, http.MethodPost, fmt.Sprintf(apiAccountPasswordURL, name), c.CommonHeaders(ctx), account, nil)
+}
+
+// CheckPermission asks service center whether the current token
+// has the permission described by the request
+func (c *Client) CheckPermission(ctx context.Context, req *rbacpkg.PermissionCheckRequest) (*rbacpkg.PermissionCheckResponse, *errsvc.Error) {
+	resp := &rbacpkg.PermissionCheckResponse{}
+	scErr := c.doJSON(ctx, http.MethodPost, apiPermissionCheckURL, c.CommonHeaders(ctx), req, resp)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return resp, nil
+}
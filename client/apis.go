@@ -20,6 +20,9 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/pkg/errsvc"
@@ -34,6 +37,7 @@ const (
 	apiVersionURL  = "/version"
 	apiDumpURL     = "/v4/default/admin/dump"
 	apiClustersURL = "/v4/default/admin/clusters"
+	apiAuditURL    = "/v4/default/admin/audit"
 	apiHealthURL   = "/v4/default/registry/health"
 
 	QueryGlobal util.CtxKey = "global"
@@ -48,6 +52,43 @@ func (c *Client) toError(body []byte) *errsvc.Error {
 	return message
 }
 
+// doJSON sends the request with the JSON encoded reqBody,
+// and decodes the response body into result if result is not nil
+func (c *Client) doJSON(ctx context.Context, method, api string, headers http.Header, reqBody, result interface{}) *errsvc.Error {
+	var data []byte
+	if reqBody != nil {
+		var err error
+		data, err = json.Marshal(reqBody)
+		if err != nil {
+			return discovery.NewError(discovery.ErrInternal, err.Error())
+		}
+	}
+
+	resp, err := c.RestDoWithContext(ctx, method, api, headers, data)
+	if err != nil {
+		return discovery.NewError(discovery.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return discovery.NewError(discovery.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.toError(body)
+	}
+
+	if result == nil || len(body) == 0 {
+		return nil
+	}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return discovery.NewError(discovery.ErrInternal, err.Error())
+	}
+	return nil
+}
+
 func (c *Client) parseQuery(ctx context.Context) (q string) {
 	switch {
 	case ctx.Value(QueryGlobal) == "1":
@@ -139,6 +180,32 @@ func (c *Client) GetClusters(ctx context.Context) (cluster.Clusters, *errsvc.Err
 	return clusters.Clusters, nil
 }
 
+// ListAuditLogs returns the audit logs in [start, end] of the actor, the latest first,
+// the zero start, end, empty actor or non-positive limit means unlimited
+func (c *Client) ListAuditLogs(ctx context.Context, start, end time.Time, actor string, limit int) ([]*dump.AuditLog, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	// only default domain has admin permission
+	headers.Set("X-Domain-Name", "default")
+	query := url.Values{}
+	if !start.IsZero() {
+		query.Set("start", start.Format(time.RFC3339))
+	}
+	if !end.IsZero() {
+		query.Set("end", end.Format(time.RFC3339))
+	}
+	if len(actor) > 0 {
+		query.Set("actor", actor)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	resp := &dump.AuditLogResponse{}
+	if scErr := c.doJSON(ctx, http.MethodGet, apiAuditURL+"?"+query.Encode(), headers, nil, resp); scErr != nil {
+		return nil, scErr
+	}
+	return resp.Logs, nil
+}
+
 func (c *Client) HealthCheck(ctx context.Context) *errsvc.Error {
 	headers := c.CommonHeaders(ctx)
 	// only default domain has admin permission
@@ -0,0 +1,100 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+)
+
+const (
+	apiApplyURL = "/v4/%s/registry/apply"
+)
+
+// ServiceDocument is the declarative description of a micro-service
+// and the schemas, tags, rules and instances belong to it
+type ServiceDocument struct {
+	Service   *pb.MicroService             `json:"service"`
+	Schemas   []*pb.Schema                 `json:"schemas,omitempty"`
+	Tags      map[string]string            `json:"tags,omitempty"`
+	Rules     []*pb.AddOrUpdateServiceRule `json:"rules,omitempty"`
+	Instances []*pb.MicroServiceInstance   `json:"instances,omitempty"`
+}
+
+// ApplyItemResult is the action taken on one resource in the document
+type ApplyItemResult struct {
+	Kind    string `json:"kind"`
+	ID      string `json:"id,omitempty"`
+	Action  string `json:"action"`
+	Message string `json:"message,omitempty"`
+}
+
+// ApplyResult is the result of applying a service document
+type ApplyResult struct {
+	ServiceID string             `json:"serviceId,omitempty"`
+	Items     []*ApplyItemResult `json:"items,omitempty"`
+}
+
+func (c *Client) ApplyService(ctx context.Context, domain, project string, doc *ServiceDocument) (*ApplyResult, *errsvc.Error) {
+	return c.applyService(ctx, domain, project, doc, false)
+}
+
+// PlanService returns the changes to apply the document, nothing will be written
+func (c *Client) PlanService(ctx context.Context, domain, project string, doc *ServiceDocument) (*ApplyResult, *errsvc.Error) {
+	return c.applyService(ctx, domain, project, doc, true)
+}
+
+func (c *Client) applyService(ctx context.Context, domain, project string, doc *ServiceDocument, dryRun bool) (*ApplyResult, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	reqBody, err := json.Marshal(doc)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+
+	api := fmt.Sprintf(apiApplyURL, project)
+	if dryRun {
+		api += "?dryRun=1"
+	}
+	resp, err := c.RestDoWithContext(ctx, http.MethodPost, api, headers, reqBody)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	result := &ApplyResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	return result, nil
+}
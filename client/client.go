@@ -38,6 +38,7 @@ func (c *Client) CommonHeaders(ctx context.Context) http.Header {
 	// TODO overwrote by context values
 	if len(c.Cfg.Token) > 0 {
 		headers.Set("X-Auth-Token", c.Cfg.Token)
+		headers.Set("Authorization", "Bearer "+c.Cfg.Token)
 	}
 	return headers
 }
@@ -0,0 +1,62 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+)
+
+const (
+	apiDependenciesURL = "/v4/%s/registry/dependencies"
+	apiProvidersURL    = "/v4/%s/registry/microservices/%s/providers"
+)
+
+// GetProviders returns the providers the consumer depends on
+func (c *Client) GetProviders(ctx context.Context, domain, project, consumerID string) ([]*pb.MicroService, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	dependenciesResp := &pb.GetConDependenciesResponse{}
+	scErr := c.doJSON(ctx, http.MethodGet, fmt.Sprintf(apiProvidersURL, project, consumerID)+"?noSelf=1",
+		headers, nil, dependenciesResp)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return dependenciesResp.Providers, nil
+}
+
+// AddDependencies appends the providers to the consumers' dependencies
+func (c *Client) AddDependencies(ctx context.Context, domain, project string, dependencies []*pb.ConsumerDependency) *errsvc.Error {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	return c.doJSON(ctx, http.MethodPost, fmt.Sprintf(apiDependenciesURL, project), headers,
+		&pb.AddDependenciesRequest{Dependencies: dependencies}, nil)
+}
+
+// UpdateDependencies overrides the consumers' dependencies with the providers
+func (c *Client) UpdateDependencies(ctx context.Context, domain, project string, dependencies []*pb.ConsumerDependency) *errsvc.Error {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	return c.doJSON(ctx, http.MethodPut, fmt.Sprintf(apiDependenciesURL, project), headers,
+		&pb.CreateDependenciesRequest{Dependencies: dependencies}, nil)
+}
@@ -0,0 +1,73 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+)
+
+const (
+	apiGovPoliciesURL = "/v1/%s/gov/%s"
+	apiGovPolicyURL   = "/v1/%s/gov/%s/%s"
+)
+
+// ListPolicies returns the governance policies of the kind, e.g. 'match-group', 'rate-limiting'
+func (c *Client) ListPolicies(ctx context.Context, domain, project, kind string) ([]*gov.Policy, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	var policies []*gov.Policy
+	scErr := c.doJSON(ctx, http.MethodGet, fmt.Sprintf(apiGovPoliciesURL, project, kind), headers, nil, &policies)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return policies, nil
+}
+
+func (c *Client) CreatePolicy(ctx context.Context, domain, project, kind string, policy *gov.Policy) (string, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	created := &gov.Policy{}
+	scErr := c.doJSON(ctx, http.MethodPost, fmt.Sprintf(apiGovPoliciesURL, project, kind), headers, policy, created)
+	if scErr != nil {
+		return "", scErr
+	}
+	if created.GovernancePolicy == nil {
+		return "", nil
+	}
+	return created.ID, nil
+}
+
+func (c *Client) UpdatePolicy(ctx context.Context, domain, project, kind, id string, policy *gov.Policy) *errsvc.Error {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	return c.doJSON(ctx, http.MethodPut, fmt.Sprintf(apiGovPolicyURL, project, kind, id), headers, policy, nil)
+}
+
+func (c *Client) DeletePolicy(ctx context.Context, domain, project, kind, id string) *errsvc.Error {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	return c.doJSON(ctx, http.MethodDelete, fmt.Sprintf(apiGovPolicyURL, project, kind, id), headers, nil, nil)
+}
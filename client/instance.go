@@ -33,6 +33,7 @@ const (
 	apiInstancesURL          = "/v4/%s/registry/microservices/%s/instances"
 	apiInstanceURL           = "/v4/%s/registry/microservices/%s/instances/%s"
 	apiInstanceHeartbeatURL  = "/v4/%s/registry/microservices/%s/instances/%s/heartbeat"
+	apiInstanceStatusURL     = "/v4/%s/registry/microservices/%s/instances/%s/status"
 )
 
 func (c *Client) RegisterInstance(ctx context.Context, domain, project, serviceID string, instance *discovery.MicroServiceInstance) (string, *errsvc.Error) {
@@ -93,6 +94,18 @@ func (c *Client) UnregisterInstance(ctx context.Context, domain, project, servic
 	return nil
 }
 
+// UpdateInstanceStatus updates the instance status, e.g. UP, DOWN or OUTOFSERVICE
+func (c *Client) UpdateInstanceStatus(ctx context.Context, domain, project, serviceID, instanceID, status string) *errsvc.Error {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	query := url.Values{}
+	query.Set("value", status)
+	return c.doJSON(ctx, http.MethodPut,
+		fmt.Sprintf(apiInstanceStatusURL, project, serviceID, instanceID)+"?"+query.Encode(),
+		headers, nil, nil)
+}
+
 func (c *Client) DiscoveryInstances(ctx context.Context, domain, project, consumerID, providerAppID, providerServiceName, providerVersionRule string) ([]*discovery.MicroServiceInstance, *errsvc.Error) {
 	headers := c.CommonHeaders(ctx)
 	headers.Set("X-Domain-Name", domain)
@@ -0,0 +1,70 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	apiMetricsURL    = "/metrics"
+	apiStatisticsURL = "/v4/%s/govern/statistics"
+)
+
+// GetMetrics returns the metric families exported by the prometheus exporter,
+// it requires the server enable the 'metrics.exporter' prometheus
+func (c *Client) GetMetrics(ctx context.Context) (map[string]*dto.MetricFamily, *errsvc.Error) {
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet, apiMetricsURL, c.CommonHeaders(ctx), nil)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, pb.NewError(pb.ErrInternal, err.Error())
+		}
+		return nil, c.toError(body)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	return families, nil
+}
+
+func (c *Client) GetStatistics(ctx context.Context, domain, project string) (*pb.Statistics, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	statisticsResp := &pb.GetServicesInfoStatisticsResponse{}
+	scErr := c.doJSON(ctx, http.MethodGet, fmt.Sprintf(apiStatisticsURL, project), headers, nil, statisticsResp)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return statisticsResp.Statistics, nil
+}
@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	pb "github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/pkg/errsvc"
@@ -68,11 +69,16 @@ func (c *Client) CreateService(ctx context.Context, domain, project string, serv
 }
 
 func (c *Client) DeleteService(ctx context.Context, domain, project, serviceID string) *errsvc.Error {
+	return c.UnregisterService(ctx, domain, project, serviceID, false)
+}
+
+// UnregisterService deletes the service, force means delete the service with its instances
+func (c *Client) UnregisterService(ctx context.Context, domain, project, serviceID string, force bool) *errsvc.Error {
 	headers := c.CommonHeaders(ctx)
 	headers.Set("X-Domain-Name", domain)
 
 	resp, err := c.RestDoWithContext(ctx, http.MethodDelete,
-		fmt.Sprintf(apiMicroServiceURL, project, serviceID),
+		fmt.Sprintf(apiMicroServiceURL, project, serviceID)+"?force="+strconv.FormatBool(force),
 		headers, nil)
 	if err != nil {
 		return pb.NewError(pb.ErrInternal, err.Error())
@@ -91,6 +97,18 @@ func (c *Client) DeleteService(ctx context.Context, domain, project, serviceID s
 	return nil
 }
 
+func (c *Client) GetServices(ctx context.Context, domain, project string) ([]*pb.MicroService, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	servicesResp := &pb.GetServicesResponse{}
+	scErr := c.doJSON(ctx, http.MethodGet, fmt.Sprintf(apiMicroServicesURL, project), headers, nil, servicesResp)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return servicesResp.Services, nil
+}
+
 func (c *Client) ServiceExistence(ctx context.Context, domain, project string, appID, serviceName, versionRule, env string) (string, *errsvc.Error) {
 	query := url.Values{}
 	query.Set("type", "microservice")
@@ -0,0 +1,60 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+)
+
+const (
+	apiRolesURL = "/v4/roles"
+	apiRoleURL  = "/v4/roles/%s"
+)
+
+func (c *Client) ListRoles(ctx context.Context) ([]*rbac.Role, *errsvc.Error) {
+	rolesResp := &rbac.RoleResponse{}
+	scErr := c.doJSON(ctx, http.MethodGet, apiRolesURL, c.CommonHeaders(ctx), nil, rolesResp)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return rolesResp.Roles, nil
+}
+
+func (c *Client) GetRole(ctx context.Context, name string) (*rbac.Role, *errsvc.Error) {
+	role := &rbac.Role{}
+	scErr := c.doJSON(ctx, http.MethodGet, fmt.Sprintf(apiRoleURL, name), c.CommonHeaders(ctx), nil, role)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return role, nil
+}
+
+func (c *Client) CreateRole(ctx context.Context, role *rbac.Role) *errsvc.Error {
+	return c.doJSON(ctx, http.MethodPost, apiRolesURL, c.CommonHeaders(ctx), role, nil)
+}
+
+func (c *Client) UpdateRole(ctx context.Context, role *rbac.Role) *errsvc.Error {
+	return c.doJSON(ctx, http.MethodPut, fmt.Sprintf(apiRoleURL, role.Name), c.CommonHeaders(ctx), role, nil)
+}
+
+func (c *Client) DeleteRole(ctx context.Context, name string) *errsvc.Error {
+	return c.doJSON(ctx, http.MethodDelete, fmt.Sprintf(apiRoleURL, name), c.CommonHeaders(ctx), nil, nil)
+}
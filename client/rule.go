@@ -0,0 +1,131 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+)
+
+const (
+	apiAccessURL = "/v4/%s/registry/microservices/%s/access"
+	apiRulesURL  = "/v4/%s/registry/microservices/%s/rules"
+	apiRuleURL   = "/v4/%s/registry/microservices/%s/rules/%s"
+)
+
+// RuleTrace is the evaluation result of one provider rule
+type RuleTrace struct {
+	RuleID    string            `json:"ruleId,omitempty"`
+	RuleType  string            `json:"ruleType"`
+	Attribute string            `json:"attribute"`
+	Pattern   string            `json:"pattern"`
+	Priority  int               `json:"priority"`
+	Matched   bool              `json:"matched"`
+	Skipped   bool              `json:"skipped,omitempty"`
+	Values    map[string]string `json:"values,omitempty"`
+}
+
+// RuleDecision is the explainable result of the provider rules evaluation
+type RuleDecision struct {
+	Allowed bool         `json:"allowed"`
+	RuleID  string       `json:"ruleId,omitempty"`
+	Reason  string       `json:"reason"`
+	Traces  []*RuleTrace `json:"traces,omitempty"`
+}
+
+// AccessResult is the result of checking whether the consumer can access the provider
+type AccessResult struct {
+	Allowed        bool          `json:"allowed"`
+	Reason         string        `json:"reason"`
+	CrossDimension string        `json:"crossDimension,omitempty"`
+	Rule           *RuleTrace    `json:"rule,omitempty"`
+	Decision       *RuleDecision `json:"decision,omitempty"`
+}
+
+func (c *Client) ExplainAccess(ctx context.Context, domain, project, providerID, consumerID, ip string) (*AccessResult, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	query := url.Values{}
+	query.Set("consumerId", consumerID)
+	if len(ip) > 0 {
+		query.Set("ip", ip)
+	}
+
+	resp, err := c.RestDoWithContext(ctx, http.MethodGet,
+		fmt.Sprintf(apiAccessURL, project, providerID)+"?"+query.Encode(),
+		headers, nil)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.toError(body)
+	}
+
+	result := &AccessResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, pb.NewError(pb.ErrInternal, err.Error())
+	}
+	return result, nil
+}
+
+func (c *Client) GetRules(ctx context.Context, domain, project, serviceID string) ([]*pb.ServiceRule, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	rulesResp := &pb.GetServiceRulesResponse{}
+	scErr := c.doJSON(ctx, http.MethodGet, fmt.Sprintf(apiRulesURL, project, serviceID), headers, nil, rulesResp)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return rulesResp.Rules, nil
+}
+
+// AddRules adds the rules to the service and returns the rule ids
+func (c *Client) AddRules(ctx context.Context, domain, project, serviceID string, rules []*pb.AddOrUpdateServiceRule) ([]string, *errsvc.Error) {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	rulesResp := &pb.AddServiceRulesResponse{}
+	scErr := c.doJSON(ctx, http.MethodPost, fmt.Sprintf(apiRulesURL, project, serviceID), headers,
+		&pb.AddServiceRulesRequest{Rules: rules}, rulesResp)
+	if scErr != nil {
+		return nil, scErr
+	}
+	return rulesResp.RuleIds, nil
+}
+
+func (c *Client) DeleteRule(ctx context.Context, domain, project, serviceID, ruleID string) *errsvc.Error {
+	headers := c.CommonHeaders(ctx)
+	headers.Set("X-Domain-Name", domain)
+
+	return c.doJSON(ctx, http.MethodDelete, fmt.Sprintf(apiRuleURL, project, serviceID, ruleID), headers, nil, nil)
+}
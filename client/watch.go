@@ -19,41 +19,79 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"net/http"
 
 	pb "github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/pkg/errsvc"
 	"github.com/gorilla/websocket"
+
+	"github.com/apache/servicecomb-service-center/pkg/dump"
 )
 
 const (
-	apiWatcherURL = "/v4/%s/registry/microservices/%s/watcher"
+	apiWatcherURL     = "/v4/%s/registry/microservices/%s/watcher"
+	apiSyncerWatchURL = "/v4/syncer/watch"
 )
 
+// Watch watches the instance events of the providers which the self service depends on,
+// it blocks until the connection is broken or ctx is done
 func (c *Client) Watch(ctx context.Context, domain, project, selfServiceID string, callback func(*pb.WatchInstanceResponse)) *errsvc.Error {
 	headers := c.CommonHeaders(ctx)
 	headers.Set("X-Domain-Name", domain)
 
-	conn, err := c.WebsocketDial(ctx, fmt.Sprintf(apiWatcherURL, project, selfServiceID), headers)
+	return c.watch(ctx, fmt.Sprintf(apiWatcherURL, project, selfServiceID), headers, func(message []byte) error {
+		data := &pb.WatchInstanceResponse{}
+		if err := json.Unmarshal(message, data); err != nil {
+			return err
+		}
+		callback(data)
+		return nil
+	})
+}
+
+// WatchAll watches the instance events of all the services,
+// it requires the syncer watch api enabled in service center
+func (c *Client) WatchAll(ctx context.Context, callback func(*dump.WatchInstanceChangedEvent)) *errsvc.Error {
+	return c.watch(ctx, apiSyncerWatchURL, c.CommonHeaders(ctx), func(message []byte) error {
+		data := &dump.WatchInstanceChangedEvent{}
+		if err := json.Unmarshal(message, data); err != nil {
+			return err
+		}
+		callback(data)
+		return nil
+	})
+}
+
+func (c *Client) watch(ctx context.Context, api string, headers http.Header, onMessage func([]byte) error) *errsvc.Error {
+	conn, err := c.WebsocketDial(ctx, api, headers)
 	if err != nil {
 		return pb.NewError(pb.ErrInternal, err.Error())
 	}
+	defer conn.Close()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stopCh:
+		}
+	}()
 
 	for {
 		messageType, message, err := conn.ReadMessage()
 		if err != nil {
-			log.Println(err)
-			break
-		}
-		if messageType == websocket.TextMessage {
-			data := &pb.WatchInstanceResponse{}
-			err := json.Unmarshal(message, data)
-			if err != nil {
-				log.Println(err)
-				break
+			if ctx.Err() != nil {
+				return nil
 			}
-			callback(data)
+			return pb.NewError(pb.ErrInternal, err.Error())
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+		if err := onMessage(message); err != nil {
+			return pb.NewError(pb.ErrInternal, fmt.Sprintf("invalid message %s: %s", message, err.Error()))
 		}
 	}
-	return pb.NewError(pb.ErrInternal, err.Error())
 }
@@ -0,0 +1,41 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package datasource
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrAccountStateNotExist = errors.New("account state not exist")
+
+// AccountStateManager saves the password history and the activity of the accounts,
+// it is used by the password policy
+type AccountStateManager interface {
+	GetAccountState(ctx context.Context, name string) (*AccountState, error)
+	// UpsertAccountState saves or replaces the state of the account
+	UpsertAccountState(ctx context.Context, s *AccountState) error
+	DeleteAccountState(ctx context.Context, name string) error
+}
+
+type AccountState struct {
+	Account string `json:"account,omitempty"`
+	// PasswordHistory is the hashes of the recent passwords, the latest first
+	PasswordHistory   []string `json:"passwordHistory,omitempty" bson:"password_history"`
+	PasswordChangedAt int64    `json:"passwordChangedAt,omitempty" bson:"password_changed_at"`
+	LastLoginAt       int64    `json:"lastLoginAt,omitempty" bson:"last_login_at"`
+}
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datasource_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+)
+
+func TestAccountState(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	s := &datasource.AccountState{Account: "test-state-account", PasswordHistory: []string{"h1"}, PasswordChangedAt: now}
+	t.Run("get not exist state, should return not exist error", func(t *testing.T) {
+		_, err := datasource.GetAccountStateManager().GetAccountState(ctx, s.Account)
+		assert.Equal(t, datasource.ErrAccountStateNotExist, err)
+	})
+	t.Run("upsert and get state", func(t *testing.T) {
+		err := datasource.GetAccountStateManager().UpsertAccountState(ctx, s)
+		assert.NoError(t, err)
+		s.PasswordHistory = []string{"h2", "h1"}
+		s.LastLoginAt = now
+		err = datasource.GetAccountStateManager().UpsertAccountState(ctx, s)
+		assert.NoError(t, err)
+		r, err := datasource.GetAccountStateManager().GetAccountState(ctx, s.Account)
+		assert.NoError(t, err)
+		assert.Equal(t, s, r)
+	})
+	t.Run("delete state", func(t *testing.T) {
+		err := datasource.GetAccountStateManager().DeleteAccountState(ctx, s.Account)
+		assert.NoError(t, err)
+		_, err = datasource.GetAccountStateManager().GetAccountState(ctx, s.Account)
+		assert.Equal(t, datasource.ErrAccountStateNotExist, err)
+	})
+}
@@ -0,0 +1,44 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+)
+
+// AlarmManager saves the alarm history and the current alarm states,
+// which are shared by all the instances
+type AlarmManager interface {
+	AddAlarmHistory(ctx context.Context, h *model.History) error
+	// ListAlarmHistory returns the alarm history matched the filter, the latest first
+	ListAlarmHistory(ctx context.Context, f *model.HistoryFilter) ([]*model.History, error)
+	// DeleteAlarmHistory removes the alarm history before the timestamp in milliseconds
+	DeleteAlarmHistory(ctx context.Context, before int64) error
+	// PutAlarmState saves the state of the alarm ID and instance,
+	// it is skipped if the saved state is newer
+	PutAlarmState(ctx context.Context, s *model.State) error
+	// ListAlarmStates returns the saved state of each alarm ID and instance
+	ListAlarmStates(ctx context.Context) ([]*model.State, error)
+	// AckAlarmState saves the acknowledgement of the state, it returns false
+	// if the saved state is changed since s, by the status or the timestamp
+	AckAlarmState(ctx context.Context, s *model.State, ack *model.Ack) (bool, error)
+	// DeleteAlarmState removes the state, it is skipped if the saved state is changed since s
+	DeleteAlarmState(ctx context.Context, s *model.State) error
+}
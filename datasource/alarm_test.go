@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datasource_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+)
+
+func TestAlarmHistory(t *testing.T) {
+	ctx := context.Background()
+	// far in the past, so the history recorded by other tests is excluded
+	h1 := &model.History{ID: "test-alarm-1", AlarmID: "TestAlarm", Status: "ACTIVATED", Instance: "a", Timestamp: 1000}
+	h2 := &model.History{ID: "test-alarm-2", AlarmID: "TestAlarm", Status: "ACKNOWLEDGED", Actor: "root",
+		Comment: "known issue", Timestamp: 2000}
+	h3 := &model.History{ID: "test-alarm-3", AlarmID: "TestAlarm", Status: "CLEARED", Instance: "a", Timestamp: 3000}
+	t.Run("add and list alarm history", func(t *testing.T) {
+		for _, h := range []*model.History{h1, h2, h3} {
+			assert.NoError(t, datasource.GetAlarmManager().AddAlarmHistory(ctx, h))
+		}
+		hs, err := datasource.GetAlarmManager().ListAlarmHistory(ctx,
+			&model.HistoryFilter{Start: 1000, End: 3000})
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.History{h3, h2, h1}, hs)
+
+		hs, err = datasource.GetAlarmManager().ListAlarmHistory(ctx,
+			&model.HistoryFilter{AlarmID: "TestAlarm", Instance: "a", Start: 1000, End: 3000, Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.History{h3}, hs)
+	})
+	t.Run("delete alarm history", func(t *testing.T) {
+		err := datasource.GetAlarmManager().DeleteAlarmHistory(ctx, 3000)
+		assert.NoError(t, err)
+		hs, err := datasource.GetAlarmManager().ListAlarmHistory(ctx,
+			&model.HistoryFilter{Start: 1000, End: 3000})
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.History{h3}, hs)
+		assert.NoError(t, datasource.GetAlarmManager().DeleteAlarmHistory(ctx, 3001))
+	})
+}
+
+func TestAlarmState(t *testing.T) {
+	ctx := context.Background()
+	ack := &model.Ack{Actor: "root", Comment: "known issue", Timestamp: 2500}
+	activated := &model.State{AlarmID: "TestAlarmState", Status: "ACTIVATED", Instance: "a", Timestamp: 2000}
+	cleared := &model.State{AlarmID: "TestAlarmState", Status: "CLEARED", Instance: "a", Timestamp: 3000}
+	list := func(t *testing.T) *model.State {
+		states, err := datasource.GetAlarmManager().ListAlarmStates(ctx)
+		assert.NoError(t, err)
+		for _, s := range states {
+			if s.AlarmID == "TestAlarmState" && s.Instance == "a" {
+				return s
+			}
+		}
+		return nil
+	}
+	t.Run("put and ack alarm state", func(t *testing.T) {
+		assert.NoError(t, datasource.GetAlarmManager().PutAlarmState(ctx, activated))
+		ok, err := datasource.GetAlarmManager().AckAlarmState(ctx, activated, ack)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		s := list(t)
+		assert.Equal(t, model.Status("ACTIVATED"), s.Status)
+		assert.Equal(t, ack, s.Ack)
+	})
+	t.Run("put the older state, should be skipped", func(t *testing.T) {
+		assert.NoError(t, datasource.GetAlarmManager().PutAlarmState(ctx, cleared))
+		assert.NoError(t, datasource.GetAlarmManager().PutAlarmState(ctx, activated))
+		s := list(t)
+		assert.Equal(t, model.Status("CLEARED"), s.Status)
+		assert.Nil(t, s.Ack)
+
+		ok, err := datasource.GetAlarmManager().AckAlarmState(ctx, activated, ack)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+	t.Run("delete alarm state", func(t *testing.T) {
+		assert.NoError(t, datasource.GetAlarmManager().DeleteAlarmState(ctx, activated))
+		assert.NotNil(t, list(t))
+		assert.NoError(t, datasource.GetAlarmManager().DeleteAlarmState(ctx, cleared))
+		assert.Nil(t, list(t))
+	})
+}
@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package datasource
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrAPIKeyDuplicated = errors.New("api key is duplicated")
+	ErrAPIKeyNotExist   = errors.New("api key not exist")
+)
+
+// APIKeyManager contains the API key CRUD, the key secret is saved as a hash
+type APIKeyManager interface {
+	CreateAPIKey(ctx context.Context, k *APIKey) error
+	GetAPIKey(ctx context.Context, id string) (*APIKey, error)
+	ListAPIKey(ctx context.Context, account string) ([]*APIKey, error)
+	UpdateAPIKey(ctx context.Context, k *APIKey) error
+	DeleteAPIKey(ctx context.Context, id string) error
+}
+
+// APIKey is a long-lived credential of the account
+type APIKey struct {
+	ID      string `json:"id,omitempty"`
+	Account string `json:"account,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// Hash is the sha256 hex of the key secret
+	Hash string `json:"hash,omitempty"`
+	// Roles is the subset of the account roles, empty means all the account roles
+	Roles      []string `json:"roles,omitempty"`
+	ExpireAt   int64    `json:"expireAt,omitempty" bson:"expire_at"`
+	CreateTime string   `json:"createTime,omitempty" bson:"create_time"`
+	UpdateTime string   `json:"updateTime,omitempty" bson:"update_time"`
+}
@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datasource_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+)
+
+func TestAPIKey(t *testing.T) {
+	k := &datasource.APIKey{
+		ID:      "11111-22222-33333-55555",
+		Account: "test-apikey-account",
+		Name:    "ci",
+		Hash:    "hash",
+		Roles:   []string{"developer"},
+	}
+	ctx := context.Background()
+	t.Run("add and get api key", func(t *testing.T) {
+		err := datasource.GetAPIKeyManager().CreateAPIKey(ctx, k)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, k.CreateTime)
+		r, err := datasource.GetAPIKeyManager().GetAPIKey(ctx, k.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, k, r)
+	})
+	t.Run("add duplicated api key, should fail", func(t *testing.T) {
+		err := datasource.GetAPIKeyManager().CreateAPIKey(ctx, &datasource.APIKey{ID: k.ID, Account: k.Account})
+		assert.Equal(t, datasource.ErrAPIKeyDuplicated, err)
+	})
+	t.Run("update and list api keys", func(t *testing.T) {
+		k.Hash = "new-hash"
+		err := datasource.GetAPIKeyManager().UpdateAPIKey(ctx, k)
+		assert.NoError(t, err)
+		keys, err := datasource.GetAPIKeyManager().ListAPIKey(ctx, k.Account)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(keys))
+		assert.Equal(t, "new-hash", keys[0].Hash)
+		keys, err = datasource.GetAPIKeyManager().ListAPIKey(ctx, "not-exist")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(keys))
+	})
+	t.Run("delete api key", func(t *testing.T) {
+		err := datasource.GetAPIKeyManager().DeleteAPIKey(ctx, k.ID)
+		assert.NoError(t, err)
+		_, err = datasource.GetAPIKeyManager().GetAPIKey(ctx, k.ID)
+		assert.Equal(t, datasource.ErrAPIKeyNotExist, err)
+		err = datasource.GetAPIKeyManager().UpdateAPIKey(ctx, k)
+		assert.Equal(t, datasource.ErrAPIKeyNotExist, err)
+	})
+}
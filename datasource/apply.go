@@ -0,0 +1,424 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+// the kinds of the resources in a service document
+const (
+	ApplyKindService  = "service"
+	ApplyKindSchema   = "schema"
+	ApplyKindTag      = "tag"
+	ApplyKindRule     = "rule"
+	ApplyKindInstance = "instance"
+)
+
+// the actions taken on the resources when applying a service document
+const (
+	ApplyActionCreated   = "created"
+	ApplyActionUpdated   = "updated"
+	ApplyActionDeleted   = "deleted"
+	ApplyActionUnchanged = "unchanged"
+	ApplyActionFailed    = "failed"
+)
+
+// ServiceDocument is the declarative description of a micro-service
+// and the resources belong to it
+type ServiceDocument struct {
+	Service *pb.MicroService `json:"service"`
+	Schemas []*pb.Schema     `json:"schemas,omitempty"`
+	// Tags is the whole tags of the service, nil means the tags are not managed by the document
+	Tags      map[string]string            `json:"tags,omitempty"`
+	Rules     []*pb.AddOrUpdateServiceRule `json:"rules,omitempty"`
+	Instances []*pb.MicroServiceInstance   `json:"instances,omitempty"`
+	// DryRun only plans the document, nothing will be written
+	DryRun bool `json:"-"`
+}
+
+type ApplyItemResult struct {
+	Kind    string `json:"kind"`
+	ID      string `json:"id,omitempty"`
+	Action  string `json:"action"`
+	Message string `json:"message,omitempty"`
+}
+
+type ApplyServiceResponse struct {
+	Response  *pb.Response       `json:"-"`
+	ServiceID string             `json:"serviceId,omitempty"`
+	Items     []*ApplyItemResult `json:"items,omitempty"`
+}
+
+// ServiceState is the stored state of the service described by a document,
+// Service is nil if the service does not exist
+type ServiceState struct {
+	Service *pb.MicroService
+	Schemas map[string]*pb.Schema
+	Tags    map[string]string
+	Rules   []*pb.ServiceRule
+	// Instances records the existence of the instances with id in the document
+	Instances map[string]bool
+}
+
+// ServiceApplyPlan contains the writes to apply a document
+type ServiceApplyPlan struct {
+	ServiceID     string
+	CreateService bool
+	// Service is the service to put, nil if unchanged
+	Service *pb.MicroService
+	Schemas []*pb.Schema
+	// Tags is the whole tags to put, nil if unchanged
+	Tags         map[string]string
+	Rules        []*pb.ServiceRule
+	UpdatedRules []*pb.ServiceRule
+	Instances    []*pb.MicroServiceInstance
+	Items        []*ApplyItemResult
+}
+
+// Count returns the number of the items in the kind and taken the action
+func (p *ServiceApplyPlan) Count(kind, action string) int64 {
+	var n int64
+	for _, item := range p.Items {
+		if item.Kind == kind && item.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Abort marks the changed items as failed when the writes can not be committed
+func (p *ServiceApplyPlan) Abort(message string) {
+	for _, item := range p.Items {
+		if item.Action != ApplyActionUnchanged {
+			item.Action = ApplyActionFailed
+			item.Message = message
+		}
+	}
+}
+
+// LoadServiceState loads the stored state of the service described by the document
+func LoadServiceState(ctx context.Context, m MetadataManager, doc *ServiceDocument) (*ServiceState, *errsvc.Error) {
+	service := doc.Service
+	state := &ServiceState{Instances: make(map[string]bool)}
+
+	serviceID := service.ServiceId
+	if len(serviceID) == 0 {
+		resp, err := m.ExistService(ctx, &pb.GetExistenceRequest{
+			Type:        ExistTypeMicroservice,
+			Environment: service.Environment,
+			AppId:       service.AppId,
+			ServiceName: service.ServiceName,
+			Version:     service.Version,
+		})
+		if resp.Response.GetCode() == pb.ErrServiceNotExists {
+			return state, nil
+		}
+		if err := checkResponse(resp.Response, err); err != nil {
+			return nil, err
+		}
+		serviceID = resp.ServiceId
+	}
+
+	svcResp, err := m.GetService(ctx, &pb.GetServiceRequest{ServiceId: serviceID})
+	if svcResp.Response.GetCode() == pb.ErrServiceNotExists {
+		return state, nil
+	}
+	if err := checkResponse(svcResp.Response, err); err != nil {
+		return nil, err
+	}
+	state.Service = svcResp.Service
+
+	schemasResp, err := m.GetAllSchemas(ctx, &pb.GetAllSchemaRequest{ServiceId: serviceID, WithSchema: true})
+	if err := checkResponse(schemasResp.Response, err); err != nil {
+		return nil, err
+	}
+	state.Schemas = make(map[string]*pb.Schema, len(schemasResp.Schemas))
+	for _, schema := range schemasResp.Schemas {
+		state.Schemas[schema.SchemaId] = schema
+	}
+
+	tagsResp, err := m.GetTags(ctx, &pb.GetServiceTagsRequest{ServiceId: serviceID})
+	if err := checkResponse(tagsResp.Response, err); err != nil {
+		return nil, err
+	}
+	state.Tags = tagsResp.Tags
+
+	rulesResp, err := m.GetRules(ctx, &pb.GetServiceRulesRequest{ServiceId: serviceID})
+	if err := checkResponse(rulesResp.Response, err); err != nil {
+		return nil, err
+	}
+	state.Rules = rulesResp.Rules
+
+	for _, instance := range doc.Instances {
+		if len(instance.InstanceId) == 0 {
+			continue
+		}
+		resp, err := m.ExistInstanceByID(ctx, &pb.MicroServiceInstanceKey{
+			ServiceId:  serviceID,
+			InstanceId: instance.InstanceId,
+		})
+		if err := checkResponse(resp.Response, err); err != nil {
+			return nil, err
+		}
+		state.Instances[instance.InstanceId] = resp.Exist
+	}
+	return state, nil
+}
+
+func checkResponse(resp *pb.Response, err error) *errsvc.Error {
+	if err != nil {
+		return pb.NewError(pb.ErrInternal, err.Error())
+	}
+	if !resp.IsSucceed() {
+		return pb.NewError(resp.GetCode(), resp.GetMessage())
+	}
+	return nil
+}
+
+// PlanServiceDocument compares the document with the stored state and
+// returns the writes to apply it, the new service must be assigned an id before planning.
+// Schemas, rules and instances in the document are added or updated, the ones not
+// in the document are kept, while the tags are replaced as a whole.
+func PlanServiceDocument(doc *ServiceDocument, state *ServiceState, schemaEditable bool) (*ServiceApplyPlan, *errsvc.Error) {
+	plan := &ServiceApplyPlan{}
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	var service *pb.MicroService
+	serviceAction := ApplyActionUnchanged
+	if state.Service == nil {
+		service = doc.Service
+		service.Timestamp = now
+		service.ModTimestamp = now
+		plan.CreateService = true
+		serviceAction = ApplyActionCreated
+	} else {
+		copied := *state.Service
+		copied.Schemas = append([]string(nil), state.Service.Schemas...)
+		service = &copied
+		if len(doc.Service.Description) > 0 && doc.Service.Description != service.Description {
+			service.Description = doc.Service.Description
+			serviceAction = ApplyActionUpdated
+		}
+		if doc.Service.Properties != nil && !reflect.DeepEqual(doc.Service.Properties, service.Properties) {
+			service.Properties = doc.Service.Properties
+			serviceAction = ApplyActionUpdated
+		}
+	}
+	plan.ServiceID = service.ServiceId
+
+	schemaItems, err := planSchemas(plan, doc, state, service, schemaEditable)
+	if err != nil {
+		plan.Items = append(plan.Items, &ApplyItemResult{Kind: ApplyKindService, ID: service.ServiceId, Action: serviceAction})
+		plan.Items = append(plan.Items, schemaItems...)
+		return plan, err
+	}
+	if len(plan.Schemas) > 0 && serviceAction == ApplyActionUnchanged &&
+		len(service.Schemas) != len(state.Service.Schemas) {
+		serviceAction = ApplyActionUpdated
+	}
+	if serviceAction != ApplyActionUnchanged {
+		if serviceAction == ApplyActionUpdated {
+			service.ModTimestamp = now
+		}
+		plan.Service = service
+	}
+	plan.Items = append(plan.Items, &ApplyItemResult{Kind: ApplyKindService, ID: service.ServiceId, Action: serviceAction})
+	plan.Items = append(plan.Items, schemaItems...)
+	plan.Items = append(plan.Items, planTags(plan, doc, state)...)
+
+	ruleItems, err := planRules(plan, doc, state, now)
+	plan.Items = append(plan.Items, ruleItems...)
+	if err != nil {
+		return plan, err
+	}
+
+	for _, instance := range doc.Instances {
+		if len(instance.InstanceId) > 0 && state.Instances[instance.InstanceId] {
+			plan.Items = append(plan.Items, &ApplyItemResult{Kind: ApplyKindInstance, ID: instance.InstanceId,
+				Action: ApplyActionUnchanged})
+			continue
+		}
+		instance.ServiceId = service.ServiceId
+		instance.Version = service.Version
+		plan.Instances = append(plan.Instances, instance)
+		plan.Items = append(plan.Items, &ApplyItemResult{Kind: ApplyKindInstance, ID: instance.InstanceId,
+			Action: ApplyActionCreated})
+	}
+	return plan, nil
+}
+
+func planSchemas(plan *ServiceApplyPlan, doc *ServiceDocument, state *ServiceState, service *pb.MicroService,
+	schemaEditable bool) ([]*ApplyItemResult, *errsvc.Error) {
+	items := make([]*ApplyItemResult, 0, len(doc.Schemas))
+	for _, schema := range doc.Schemas {
+		defined := util.SliceHave(service.Schemas, schema.SchemaId)
+		old, ok := state.Schemas[schema.SchemaId]
+		switch {
+		case !ok:
+			if !schemaEditable && state.Service != nil && len(state.Service.Schemas) > 0 && !defined {
+				item := &ApplyItemResult{Kind: ApplyKindSchema, ID: schema.SchemaId}
+				return append(items, item), failItem(item, pb.ErrUndefinedSchemaID, ErrUndefinedSchemaID.Error())
+			}
+			items = append(items, &ApplyItemResult{Kind: ApplyKindSchema, ID: schema.SchemaId, Action: ApplyActionCreated})
+		case old.Schema == schema.Schema && old.Summary == schema.Summary:
+			items = append(items, &ApplyItemResult{Kind: ApplyKindSchema, ID: schema.SchemaId, Action: ApplyActionUnchanged})
+			continue
+		default:
+			if !schemaEditable {
+				item := &ApplyItemResult{Kind: ApplyKindSchema, ID: schema.SchemaId}
+				return append(items, item), failItem(item, pb.ErrModifySchemaNotAllow, ErrModifySchemaNotAllow.Error())
+			}
+			items = append(items, &ApplyItemResult{Kind: ApplyKindSchema, ID: schema.SchemaId, Action: ApplyActionUpdated})
+		}
+		if !defined {
+			service.Schemas = append(service.Schemas, schema.SchemaId)
+		}
+		plan.Schemas = append(plan.Schemas, schema)
+	}
+	return items, nil
+}
+
+func planTags(plan *ServiceApplyPlan, doc *ServiceDocument, state *ServiceState) []*ApplyItemResult {
+	if doc.Tags == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(doc.Tags)+len(state.Tags))
+	for key := range doc.Tags {
+		keys = append(keys, key)
+	}
+	for key := range state.Tags {
+		if _, ok := doc.Tags[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changed := false
+	items := make([]*ApplyItemResult, 0, len(keys))
+	for _, key := range keys {
+		value, ok := doc.Tags[key]
+		old, exist := state.Tags[key]
+		action := ApplyActionUnchanged
+		switch {
+		case !ok:
+			action = ApplyActionDeleted
+		case !exist:
+			action = ApplyActionCreated
+		case old != value:
+			action = ApplyActionUpdated
+		}
+		if action != ApplyActionUnchanged {
+			changed = true
+		}
+		items = append(items, &ApplyItemResult{Kind: ApplyKindTag, ID: key, Action: action})
+	}
+	if changed {
+		plan.Tags = doc.Tags
+	}
+	return items
+}
+
+func planRules(plan *ServiceApplyPlan, doc *ServiceDocument, state *ServiceState, now string) ([]*ApplyItemResult, *errsvc.Error) {
+	items := make([]*ApplyItemResult, 0, len(doc.Rules))
+	final := make(map[string]*pb.ServiceRule, len(state.Rules)+len(doc.Rules))
+	for _, rule := range state.Rules {
+		final[ruleIdentity(rule.Attribute, rule.Pattern)] = rule
+	}
+	planned := make(map[string]struct{}, len(doc.Rules))
+	for _, rule := range doc.Rules {
+		// 同一服务，attribute和pattern确定一个rule
+		id := ruleIdentity(rule.Attribute, rule.Pattern)
+		if _, ok := planned[id]; ok {
+			continue
+		}
+		planned[id] = struct{}{}
+
+		old, ok := final[id]
+		switch {
+		case !ok:
+			ruleAdd := &pb.ServiceRule{
+				RuleId:       util.GenerateUUID(),
+				RuleType:     rule.RuleType,
+				Attribute:    rule.Attribute,
+				Pattern:      rule.Pattern,
+				Description:  rule.Description,
+				Timestamp:    now,
+				ModTimestamp: now,
+			}
+			final[id] = ruleAdd
+			plan.Rules = append(plan.Rules, ruleAdd)
+			items = append(items, &ApplyItemResult{Kind: ApplyKindRule, ID: id, Action: ApplyActionCreated})
+		case old.RuleType == rule.RuleType && old.Description == rule.Description:
+			items = append(items, &ApplyItemResult{Kind: ApplyKindRule, ID: id, Action: ApplyActionUnchanged})
+		default:
+			copied := *old
+			copied.RuleType = rule.RuleType
+			copied.Description = rule.Description
+			copied.ModTimestamp = now
+			final[id] = &copied
+			plan.UpdatedRules = append(plan.UpdatedRules, &copied)
+			items = append(items, &ApplyItemResult{Kind: ApplyKindRule, ID: id, Action: ApplyActionUpdated})
+		}
+	}
+
+	//黑白名单只能存在一种，黑名单 or 白名单
+	var ruleType string
+	for id, rule := range final {
+		// the stored rules not in the document are always in the same type
+		if _, ok := planned[id]; !ok && IsLegacyRuleType(rule.RuleType) {
+			ruleType = rule.RuleType
+			break
+		}
+	}
+	for _, item := range items {
+		rule := final[item.ID]
+		if !IsLegacyRuleType(rule.RuleType) {
+			// ALLOW or DENY rules can be mixed with any rules
+			continue
+		}
+		if len(ruleType) == 0 {
+			ruleType = rule.RuleType
+		} else if ruleType != rule.RuleType {
+			return items, failItem(item, pb.ErrBlackAndWhiteRule, "Service can only contain one rule type, BLACK or WHITE.")
+		}
+	}
+	return items, nil
+}
+
+// failItem marks the item as failed and returns the error points to the item,
+// the error response does not carry the items
+func failItem(item *ApplyItemResult, code int32, message string) *errsvc.Error {
+	item.Action = ApplyActionFailed
+	item.Message = message
+	return pb.NewError(code, fmt.Sprintf("%s[%s]: %s", item.Kind, item.ID, message))
+}
+
+func ruleIdentity(attribute, pattern string) string {
+	return attribute + "=" + pattern
+}
@@ -0,0 +1,166 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource_test
+
+import (
+	"testing"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+)
+
+func actions(items []*datasource.ApplyItemResult) []string {
+	var arr []string
+	for _, item := range items {
+		arr = append(arr, item.Kind+":"+item.ID+":"+item.Action)
+	}
+	return arr
+}
+
+func TestPlanServiceDocument(t *testing.T) {
+	t.Run("new service should create all resources", func(t *testing.T) {
+		doc := &datasource.ServiceDocument{
+			Service:   &discovery.MicroService{ServiceId: "s1", AppId: "a", ServiceName: "s", Version: "1.0.0"},
+			Schemas:   []*discovery.Schema{{SchemaId: "hello", Schema: "hello"}},
+			Tags:      map[string]string{"zone": "z1"},
+			Rules:     []*discovery.AddOrUpdateServiceRule{{RuleType: "WHITE", Attribute: "ServiceName", Pattern: "c"}},
+			Instances: []*discovery.MicroServiceInstance{{InstanceId: "i1", HostName: "h"}},
+		}
+		plan, err := datasource.PlanServiceDocument(doc, &datasource.ServiceState{}, false)
+		assert.Nil(t, err)
+		assert.True(t, plan.CreateService)
+		assert.Equal(t, "s1", plan.ServiceID)
+		assert.Equal(t, []string{"hello"}, plan.Service.Schemas)
+		assert.NotEmpty(t, plan.Service.Timestamp)
+		assert.Equal(t, 1, len(plan.Schemas))
+		assert.Equal(t, doc.Tags, plan.Tags)
+		assert.Equal(t, 1, len(plan.Rules))
+		assert.NotEmpty(t, plan.Rules[0].RuleId)
+		assert.Equal(t, 1, len(plan.Instances))
+		assert.Equal(t, "s1", plan.Instances[0].ServiceId)
+		assert.Equal(t, "1.0.0", plan.Instances[0].Version)
+		assert.Equal(t, []string{
+			"service:s1:created",
+			"schema:hello:created",
+			"tag:zone:created",
+			"rule:ServiceName=c:created",
+			"instance:i1:created",
+		}, actions(plan.Items))
+		assert.Equal(t, int64(1), plan.Count(datasource.ApplyKindSchema, datasource.ApplyActionCreated))
+	})
+
+	state := &datasource.ServiceState{
+		Service: &discovery.MicroService{ServiceId: "s1", AppId: "a", ServiceName: "s", Version: "1.0.0",
+			Schemas: []string{"hello"}, Properties: map[string]string{"a": "b"}},
+		Schemas: map[string]*discovery.Schema{"hello": {SchemaId: "hello", Schema: "hello", Summary: "x"}},
+		Tags:    map[string]string{"zone": "z1", "old": "o"},
+		Rules: []*discovery.ServiceRule{
+			{RuleId: "r1", RuleType: "WHITE", Attribute: "ServiceName", Pattern: "c", Timestamp: "1", ModTimestamp: "1"},
+		},
+		Instances: map[string]bool{"i1": true},
+	}
+	t.Run("unchanged document should not write anything", func(t *testing.T) {
+		doc := &datasource.ServiceDocument{
+			Service:   &discovery.MicroService{AppId: "a", ServiceName: "s", Version: "1.0.0", Properties: map[string]string{"a": "b"}},
+			Schemas:   []*discovery.Schema{{SchemaId: "hello", Schema: "hello", Summary: "x"}},
+			Rules:     []*discovery.AddOrUpdateServiceRule{{RuleType: "WHITE", Attribute: "ServiceName", Pattern: "c"}},
+			Instances: []*discovery.MicroServiceInstance{{InstanceId: "i1"}},
+		}
+		plan, err := datasource.PlanServiceDocument(doc, state, false)
+		assert.Nil(t, err)
+		assert.False(t, plan.CreateService)
+		assert.Nil(t, plan.Service)
+		assert.Empty(t, plan.Schemas)
+		assert.Nil(t, plan.Tags)
+		assert.Empty(t, plan.Rules)
+		assert.Empty(t, plan.UpdatedRules)
+		assert.Empty(t, plan.Instances)
+		assert.Equal(t, []string{
+			"service:s1:unchanged",
+			"schema:hello:unchanged",
+			"rule:ServiceName=c:unchanged",
+			"instance:i1:unchanged",
+		}, actions(plan.Items))
+	})
+
+	t.Run("changed document should update the stored service", func(t *testing.T) {
+		doc := &datasource.ServiceDocument{
+			Service: &discovery.MicroService{AppId: "a", ServiceName: "s", Version: "1.0.0", Properties: map[string]string{"a": "c"}},
+			Schemas: []*discovery.Schema{{SchemaId: "hello", Schema: "hello2"}, {SchemaId: "world", Schema: "world"}},
+			Tags:    map[string]string{"zone": "z2", "new": "n"},
+			Rules: []*discovery.AddOrUpdateServiceRule{
+				{RuleType: "WHITE", Attribute: "ServiceName", Pattern: "c", Description: "desc"},
+				{RuleType: "DENY", Attribute: "ip", Pattern: "10.0.0.0/8"},
+			},
+		}
+		plan, err := datasource.PlanServiceDocument(doc, state, true)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"a": "c"}, plan.Service.Properties)
+		assert.Equal(t, []string{"hello", "world"}, plan.Service.Schemas)
+		assert.Equal(t, []string{"hello"}, state.Service.Schemas)
+		assert.Equal(t, 2, len(plan.Schemas))
+		assert.Equal(t, doc.Tags, plan.Tags)
+		assert.Equal(t, 1, len(plan.Rules))
+		assert.Equal(t, 1, len(plan.UpdatedRules))
+		assert.Equal(t, "r1", plan.UpdatedRules[0].RuleId)
+		assert.Equal(t, []string{
+			"service:s1:updated",
+			"schema:hello:updated",
+			"schema:world:created",
+			"tag:new:created",
+			"tag:old:deleted",
+			"tag:zone:updated",
+			"rule:ServiceName=c:updated",
+			"rule:ip=10.0.0.0/8:created",
+		}, actions(plan.Items))
+	})
+
+	t.Run("schema not editable should fail", func(t *testing.T) {
+		doc := &datasource.ServiceDocument{
+			Service: &discovery.MicroService{AppId: "a", ServiceName: "s", Version: "1.0.0"},
+			Schemas: []*discovery.Schema{{SchemaId: "hello", Schema: "hello2"}},
+		}
+		plan, err := datasource.PlanServiceDocument(doc, state, false)
+		assert.NotNil(t, err)
+		assert.Equal(t, discovery.ErrModifySchemaNotAllow, err.Code)
+		assert.Equal(t, []string{"service:s1:unchanged", "schema:hello:failed"}, actions(plan.Items))
+
+		doc.Schemas = []*discovery.Schema{{SchemaId: "world", Schema: "world"}}
+		_, err = datasource.PlanServiceDocument(doc, state, false)
+		assert.NotNil(t, err)
+		assert.Equal(t, discovery.ErrUndefinedSchemaID, err.Code)
+	})
+
+	t.Run("mix black and white rules should fail", func(t *testing.T) {
+		doc := &datasource.ServiceDocument{
+			Service: &discovery.MicroService{AppId: "a", ServiceName: "s", Version: "1.0.0"},
+			Rules:   []*discovery.AddOrUpdateServiceRule{{RuleType: "BLACK", Attribute: "ServiceName", Pattern: "d"}},
+		}
+		plan, err := datasource.PlanServiceDocument(doc, state, true)
+		assert.NotNil(t, err)
+		assert.Equal(t, discovery.ErrBlackAndWhiteRule, err.Code)
+		assert.Equal(t, []string{"service:s1:unchanged", "rule:ServiceName=d:failed"}, actions(plan.Items))
+
+		// change the type of all the legacy rules
+		doc.Rules = append(doc.Rules, &discovery.AddOrUpdateServiceRule{RuleType: "BLACK", Attribute: "ServiceName", Pattern: "c"})
+		_, err = datasource.PlanServiceDocument(doc, state, true)
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datasource_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+)
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+	// far in the past, so the logs recorded by other tests are excluded
+	l1 := &dump.AuditLog{ID: "test-audit-1", Timestamp: 1000, Actor: "test-audit-actor", Verb: "create", Code: 200}
+	l2 := &dump.AuditLog{ID: "test-audit-2", Timestamp: 2000, Actor: "test-audit-actor", Verb: "delete", Code: 200}
+	l3 := &dump.AuditLog{ID: "test-audit-3", Timestamp: 3000, Actor: "test-audit-other", Verb: "update", Code: 400}
+	t.Run("add and list audit logs", func(t *testing.T) {
+		for _, l := range []*dump.AuditLog{l1, l2, l3} {
+			assert.NoError(t, datasource.GetAuditLogManager().AddAuditLog(ctx, l))
+		}
+		logs, err := datasource.GetAuditLogManager().ListAuditLog(ctx,
+			&dump.AuditLogFilter{Start: 1000, End: 3000})
+		assert.NoError(t, err)
+		assert.Equal(t, []*dump.AuditLog{l3, l2, l1}, logs)
+
+		logs, err = datasource.GetAuditLogManager().ListAuditLog(ctx,
+			&dump.AuditLogFilter{Start: 1000, End: 3000, Actor: "test-audit-actor", Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []*dump.AuditLog{l2}, logs)
+	})
+	t.Run("delete audit logs", func(t *testing.T) {
+		err := datasource.GetAuditLogManager().DeleteAuditLog(ctx, 3000)
+		assert.NoError(t, err)
+		logs, err := datasource.GetAuditLogManager().ListAuditLog(ctx,
+			&dump.AuditLogFilter{Start: 1000, End: 3000})
+		assert.NoError(t, err)
+		assert.Equal(t, []*dump.AuditLog{l3}, logs)
+		assert.NoError(t, datasource.GetAuditLogManager().DeleteAuditLog(ctx, 3001))
+	})
+}
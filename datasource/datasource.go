@@ -22,7 +22,15 @@ type DataSource interface {
 	SystemManager() SystemManager
 	AccountManager() AccountManager
 	AccountLockManager() AccountLockManager
+	AccountStateManager() AccountStateManager
+	TOTPManager() TOTPManager
+	APIKeyManager() APIKeyManager
+	SessionManager() SessionManager
 	RoleManager() RoleManager
+	RoleInheritanceManager() RoleInheritanceManager
+	AuditLogManager() AuditLogManager
+	QuotaOverrideManager() QuotaOverrideManager
+	AlarmManager() AlarmManager
 	DependencyManager() DependencyManager
 	MetadataManager() MetadataManager
 	SCManager() SCManager
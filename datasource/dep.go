@@ -19,15 +19,55 @@ package datasource
 
 import (
 	"context"
+	"time"
 
 	pb "github.com/go-chassis/cari/discovery"
 )
 
+// DependencyDiscovery records the last time the consumer discovered the provider
+type DependencyDiscovery struct {
+	ConsumerID string `json:"consumerId,omitempty" bson:"consumer_id"`
+	ProviderID string `json:"providerId,omitempty" bson:"provider_id"`
+	// Timestamp is the unix time of the last discovery
+	Timestamp int64 `json:"timestamp,omitempty" bson:"timestamp"`
+}
+
+// StaleDependency is the dependency rule which is not discovered for a long time
+type StaleDependency struct {
+	DomainProject string              `json:"domainProject,omitempty"`
+	ConsumerID    string              `json:"consumerId,omitempty"`
+	Consumer      *pb.MicroServiceKey `json:"consumer,omitempty"`
+	Provider      *pb.MicroServiceKey `json:"provider,omitempty"`
+	// LastDiscovered is the unix time of the last discovery
+	LastDiscovered int64 `json:"lastDiscovered,omitempty"`
+}
+
+// ConsumerDependencyResponse is the providers of the consumer with the last discovered time,
+// the key of LastDiscovered is the serviceId of the providers
+type ConsumerDependencyResponse struct {
+	*pb.GetConDependenciesResponse
+	LastDiscovered map[string]int64 `json:"lastDiscovered,omitempty"`
+}
+
+// ProviderDependencyResponse is the consumers of the provider with the last discovered time,
+// the key of LastDiscovered is the serviceId of the consumers
+type ProviderDependencyResponse struct {
+	*pb.GetProDependenciesResponse
+	LastDiscovered map[string]int64 `json:"lastDiscovered,omitempty"`
+}
+
 // DependencyManager contains the CRUD of microservice dependencies
 type DependencyManager interface {
-	SearchProviderDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*pb.GetProDependenciesResponse, error)
-	SearchConsumerDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*pb.GetConDependenciesResponse, error)
+	SearchProviderDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*ProviderDependencyResponse, error)
+	SearchConsumerDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*ConsumerDependencyResponse, error)
 	AddOrUpdateDependencies(ctx context.Context, dependencyInfos []*pb.ConsumerDependency, override bool) (*pb.Response, error)
 	DeleteDependency()
 	DependencyHandle(ctx context.Context) error
+	// RecordDiscovery refreshes the last discovered time of the consumer to the providers
+	RecordDiscovery(ctx context.Context, consumerID string, providerIDs []string) error
+	// ListDiscovery returns the discovery records filtered by consumerID and providerID, empty means any
+	ListDiscovery(ctx context.Context, consumerID, providerID string) ([]*DependencyDiscovery, error)
+	// ClearStaleDependencies removes the dependency rules not discovered in ttl across all domain projects,
+	// the stale rules are reported only and kept if dryRun is true
+	ClearStaleDependencies(ctx context.Context, ttl time.Duration, dryRun bool) ([]*StaleDependency, error)
 }
@@ -20,6 +20,7 @@ package datasource_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/util"
@@ -628,6 +629,91 @@ func Test_Get(t *testing.T) {
 	})
 }
 
+func Test_Discovery(t *testing.T) {
+	var (
+		consumerID string
+		providerID string
+	)
+
+	t.Run("register services should be passed", func(t *testing.T) {
+		resp, err := datasource.GetMetadataManager().RegisterService(depGetContext(), &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				AppId:       "dep_discovery_group",
+				ServiceName: "dep_discovery_consumer",
+				Version:     "1.0.0",
+				Level:       "FRONT",
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+		consumerID = resp.ServiceId
+
+		resp, err = datasource.GetMetadataManager().RegisterService(depGetContext(), &pb.CreateServiceRequest{
+			Service: &pb.MicroService{
+				AppId:       "dep_discovery_group",
+				ServiceName: "dep_discovery_provider",
+				Version:     "1.0.0",
+				Level:       "FRONT",
+				Status:      pb.MS_UP,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+		providerID = resp.ServiceId
+	})
+
+	t.Run("find instances should record the discovery", func(t *testing.T) {
+		resp, err := datasource.GetMetadataManager().FindInstances(depGetContext(), &pb.FindInstancesRequest{
+			ConsumerServiceId: consumerID,
+			AppId:             "dep_discovery_group",
+			ServiceName:       "dep_discovery_provider",
+			VersionRule:       "1.0.0+",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, pb.ResponseSuccess, resp.Response.GetCode())
+
+		records, err := datasource.GetDependencyManager().ListDiscovery(depGetContext(), consumerID, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(records))
+		assert.Equal(t, providerID, records[0].ProviderID)
+		assert.NotEqual(t, int64(0), records[0].Timestamp)
+
+		records, err = datasource.GetDependencyManager().ListDiscovery(depGetContext(), "", providerID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(records))
+		assert.Equal(t, consumerID, records[0].ConsumerID)
+	})
+
+	t.Run("clear stale dependencies should be passed", func(t *testing.T) {
+		err := datasource.GetDependencyManager().DependencyHandle(getContext())
+		assert.NoError(t, err)
+
+		stales, err := datasource.GetDependencyManager().ClearStaleDependencies(getContext(), time.Hour, true)
+		assert.NoError(t, err)
+		for _, stale := range stales {
+			assert.NotEqual(t, consumerID, stale.ConsumerID)
+		}
+
+		time.Sleep(time.Second)
+		stales, err = datasource.GetDependencyManager().ClearStaleDependencies(getContext(), time.Nanosecond, true)
+		assert.NoError(t, err)
+		var found bool
+		for _, stale := range stales {
+			if stale.ConsumerID == consumerID {
+				found = true
+			}
+		}
+		assert.True(t, found)
+
+		respGetC, err := datasource.GetDependencyManager().SearchConsumerDependency(depGetContext(), &pb.GetDependenciesRequest{
+			ServiceId: consumerID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(respGetC.Providers))
+	})
+}
+
 func depGetContext() context.Context {
 	return util.WithNoCache(util.SetDomainProject(context.Background(), "new_default", "new_default"))
 }
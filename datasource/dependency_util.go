@@ -24,12 +24,22 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/patrickmn/go-cache"
 
 	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
 	"github.com/apache/servicecomb-service-center/pkg/log"
-	"github.com/go-chassis/cari/discovery"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 )
 
+// DiscoveryRecordInterval is the minimum interval of refreshing the same discovery record,
+// it avoids writing backend on every find request
+const DiscoveryRecordInterval = 5 * time.Minute
+
+var recentDiscoveries = cache.New(DiscoveryRecordInterval, DiscoveryRecordInterval)
+
 type Dependency struct {
 	DomainProject string
 	// store the consumer Dependency from dep-queue object
@@ -188,3 +198,59 @@ func DiffServiceVersion(serviceA *discovery.MicroServiceKey, serviceB *discovery
 	}
 	return false
 }
+
+// FilterUnrecordedProviders returns the providers whose discovery by the consumer
+// is not recorded in the last DiscoveryRecordInterval
+func FilterUnrecordedProviders(domainProject, consumerID string, providerIDs []string) []string {
+	ids := make([]string, 0, len(providerIDs))
+	for _, providerID := range providerIDs {
+		if _, ok := recentDiscoveries.Get(discoveryCacheKey(domainProject, consumerID, providerID)); ok {
+			continue
+		}
+		ids = append(ids, providerID)
+	}
+	return ids
+}
+
+// MarkRecordedProviders marks the discovery of the providers by the consumer as recorded,
+// call it only after the records are written, so a failed write is retried on the next discovery
+func MarkRecordedProviders(domainProject, consumerID string, providerIDs []string) {
+	for _, providerID := range providerIDs {
+		recentDiscoveries.SetDefault(discoveryCacheKey(domainProject, consumerID, providerID), struct{}{})
+	}
+}
+
+func discoveryCacheKey(domainProject, consumerID, providerID string) string {
+	return util.StringJoin([]string{domainProject, consumerID, providerID}, SPLIT)
+}
+
+// LastDiscovered returns the latest discovered time of the providers in records,
+// records is the map of providerID to the discovered unix time
+func LastDiscovered(records map[string]int64, providerIDs []string) int64 {
+	var last int64
+	for _, providerID := range providerIDs {
+		if t := records[providerID]; t > last {
+			last = t
+		}
+	}
+	return last
+}
+
+// DiscoveredTimes converts the records to the map of serviceId to the discovered unix time,
+// the key is the providerID if byProvider is true, otherwise the consumerID
+func DiscoveredTimes(records []*DependencyDiscovery, byProvider bool) map[string]int64 {
+	times := make(map[string]int64, len(records))
+	for _, record := range records {
+		if byProvider {
+			times[record.ProviderID] = record.Timestamp
+			continue
+		}
+		times[record.ConsumerID] = record.Timestamp
+	}
+	return times
+}
+
+// IsStaleDependency returns true if the last discovered time is before now - ttl
+func IsStaleDependency(lastDiscovered int64, ttl time.Duration, now time.Time) bool {
+	return lastDiscovered > 0 && lastDiscovered < now.Add(-ttl).Unix()
+}
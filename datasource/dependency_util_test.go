@@ -19,6 +19,7 @@ package datasource_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 )
@@ -29,3 +30,55 @@ func TestBadParamsResponse(t *testing.T) {
 		t.Fatalf(`BadParamsResponse failed`)
 	}
 }
+
+func TestFilterUnrecordedProviders(t *testing.T) {
+	ids := datasource.FilterUnrecordedProviders("default/default", "c1", []string{"p1", "p2"})
+	if len(ids) != 2 {
+		t.Fatalf(`FilterUnrecordedProviders failed`)
+	}
+	// not marked as the record write failed
+	ids = datasource.FilterUnrecordedProviders("default/default", "c1", []string{"p1", "p2"})
+	if len(ids) != 2 {
+		t.Fatalf(`FilterUnrecordedProviders failed`)
+	}
+	datasource.MarkRecordedProviders("default/default", "c1", ids)
+	ids = datasource.FilterUnrecordedProviders("default/default", "c1", []string{"p1", "p3"})
+	if len(ids) != 1 || ids[0] != "p3" {
+		t.Fatalf(`FilterUnrecordedProviders failed`)
+	}
+}
+
+func TestIsStaleDependency(t *testing.T) {
+	now := time.Now()
+	records := map[string]int64{"p1": now.Add(-2 * time.Hour).Unix(), "p2": now.Add(-30 * time.Minute).Unix()}
+	if last := datasource.LastDiscovered(records, []string{"p1", "p2", "p3"}); last != records["p2"] {
+		t.Fatalf(`LastDiscovered failed`)
+	}
+	if datasource.LastDiscovered(records, []string{"p3"}) != 0 {
+		t.Fatalf(`LastDiscovered failed`)
+	}
+	if !datasource.IsStaleDependency(records["p1"], time.Hour, now) {
+		t.Fatalf(`IsStaleDependency failed`)
+	}
+	if datasource.IsStaleDependency(records["p2"], time.Hour, now) {
+		t.Fatalf(`IsStaleDependency failed`)
+	}
+	if datasource.IsStaleDependency(0, time.Hour, now) {
+		t.Fatalf(`IsStaleDependency failed`)
+	}
+}
+
+func TestDiscoveredTimes(t *testing.T) {
+	records := []*datasource.DependencyDiscovery{
+		{ConsumerID: "c1", ProviderID: "p1", Timestamp: 1},
+		{ConsumerID: "c2", ProviderID: "p2", Timestamp: 2},
+	}
+	times := datasource.DiscoveredTimes(records, true)
+	if len(times) != 2 || times["p1"] != 1 || times["p2"] != 2 {
+		t.Fatalf(`DiscoveredTimes by provider failed`)
+	}
+	times = datasource.DiscoveredTimes(records, false)
+	if len(times) != 2 || times["c1"] != 1 || times["c2"] != 2 {
+		t.Fatalf(`DiscoveredTimes by consumer failed`)
+	}
+}
@@ -0,0 +1,71 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type AccountStateManager struct {
+}
+
+func (ds *AccountStateManager) GetAccountState(ctx context.Context, name string) (*datasource.AccountState, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateAccountStateKey(name)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrAccountStateNotExist
+	}
+	s := &datasource.AccountState{}
+	err = json.Unmarshal(resp.Kvs[0].Value, s)
+	if err != nil {
+		log.Errorf(err, "account state format invalid")
+		return nil, err
+	}
+	return s, nil
+}
+
+func (ds *AccountStateManager) UpsertAccountState(ctx context.Context, s *datasource.AccountState) error {
+	value, err := json.Marshal(s)
+	if err != nil {
+		log.Errorf(err, "account state is invalid")
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateAccountStateKey(s.Account), value)
+	if err != nil {
+		log.Errorf(err, "can not save account state")
+		return err
+	}
+	return nil
+}
+
+func (ds *AccountStateManager) DeleteAccountState(ctx context.Context, name string) error {
+	_, err := client.Delete(ctx, path.GenerateAccountStateKey(name))
+	if err != nil {
+		log.Error(fmt.Sprintf("remove account state %s failed", name), err)
+		return err
+	}
+	return nil
+}
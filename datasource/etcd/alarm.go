@@ -0,0 +1,198 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+)
+
+// maxAlarmStateRetries is the max times of saving the alarm state
+// when it is changed by the other instances concurrently
+const maxAlarmStateRetries = 3
+
+var ErrAlarmStateConflict = errors.New("alarm state is changed concurrently")
+
+type AlarmManager struct {
+}
+
+func (ds *AlarmManager) AddAlarmHistory(ctx context.Context, h *model.History) error {
+	value, err := json.Marshal(h)
+	if err != nil {
+		log.Errorf(err, "alarm history is invalid")
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateAlarmHistoryKey(h.Timestamp, h.ID), value)
+	if err != nil {
+		log.Errorf(err, "can not save alarm history")
+		return err
+	}
+	return nil
+}
+
+func (ds *AlarmManager) ListAlarmHistory(ctx context.Context, f *model.HistoryFilter) ([]*model.History, error) {
+	kvs, _, err := client.List(ctx, path.GetAlarmHistoryRootKey()+path.SPLIT)
+	if err != nil {
+		return nil, err
+	}
+	hs := make([]*model.History, 0, len(kvs))
+	for _, kv := range kvs {
+		h := &model.History{}
+		err = json.Unmarshal(kv.Value, h)
+		if err != nil {
+			log.Error("alarm history format invalid:", err)
+			continue
+		}
+		if f.Match(h) {
+			hs = append(hs, h)
+		}
+	}
+	sort.SliceStable(hs, func(i, j int) bool {
+		return hs[i].Timestamp > hs[j].Timestamp
+	})
+	if f.Limit > 0 && len(hs) > f.Limit {
+		hs = hs[:f.Limit]
+	}
+	return hs, nil
+}
+
+func (ds *AlarmManager) DeleteAlarmHistory(ctx context.Context, before int64) error {
+	_, err := client.Instance().Do(ctx, client.DEL,
+		client.WithStrKey(path.GetAlarmHistoryRootKey()+path.SPLIT),
+		client.WithStrEndKey(path.GenerateAlarmHistoryKey(before, "")))
+	if err != nil {
+		log.Errorf(err, "remove alarm history before %d failed", before)
+		return err
+	}
+	return nil
+}
+
+func (ds *AlarmManager) PutAlarmState(ctx context.Context, s *model.State) error {
+	key := path.GenerateAlarmStateKey(string(s.AlarmID), s.Instance)
+	value, err := json.Marshal(s)
+	if err != nil {
+		log.Errorf(err, "alarm state is invalid")
+		return err
+	}
+	for i := 0; i < maxAlarmStateRetries; i++ {
+		old, rev, err := getAlarmState(ctx, key)
+		if err != nil {
+			return err
+		}
+		if old != nil && old.Timestamp > s.Timestamp {
+			return nil
+		}
+		ok, err := casAlarmState(ctx, key, rev, client.OpPut(client.WithStrKey(key), client.WithValue(value)))
+		if err != nil || ok {
+			return err
+		}
+	}
+	log.Errorf(ErrAlarmStateConflict, "save alarm[%s] state of instance[%s] failed", s.AlarmID, s.Instance)
+	return ErrAlarmStateConflict
+}
+
+func (ds *AlarmManager) ListAlarmStates(ctx context.Context) ([]*model.State, error) {
+	kvs, _, err := client.List(ctx, path.GetAlarmStateRootKey()+path.SPLIT)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]*model.State, 0, len(kvs))
+	for _, kv := range kvs {
+		s := &model.State{}
+		err = json.Unmarshal(kv.Value, s)
+		if err != nil {
+			log.Error("alarm state format invalid:", err)
+			continue
+		}
+		states = append(states, s)
+	}
+	return states, nil
+}
+
+func (ds *AlarmManager) AckAlarmState(ctx context.Context, s *model.State, ack *model.Ack) (bool, error) {
+	key := path.GenerateAlarmStateKey(string(s.AlarmID), s.Instance)
+	old, rev, err := getAlarmState(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if !sameAlarmState(old, s) {
+		return false, nil
+	}
+	old.Ack = ack
+	value, err := json.Marshal(old)
+	if err != nil {
+		log.Errorf(err, "alarm state is invalid")
+		return false, err
+	}
+	return casAlarmState(ctx, key, rev, client.OpPut(client.WithStrKey(key), client.WithValue(value)))
+}
+
+func (ds *AlarmManager) DeleteAlarmState(ctx context.Context, s *model.State) error {
+	key := path.GenerateAlarmStateKey(string(s.AlarmID), s.Instance)
+	old, rev, err := getAlarmState(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !sameAlarmState(old, s) {
+		return nil
+	}
+	_, err = casAlarmState(ctx, key, rev, client.OpDel(client.WithStrKey(key)))
+	return err
+}
+
+// getAlarmState returns the saved state and its mod revision, nil if not exist
+func getAlarmState(ctx context.Context, key string) (*model.State, int64, error) {
+	resp, err := client.Instance().Do(ctx, client.GET, client.WithStrKey(key))
+	if err != nil {
+		log.Errorf(err, "get alarm state %s failed", key)
+		return nil, 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, 0, nil
+	}
+	s := &model.State{}
+	if err = json.Unmarshal(resp.Kvs[0].Value, s); err != nil {
+		log.Errorf(err, "alarm state %s format invalid", key)
+		return nil, 0, err
+	}
+	return s, resp.Kvs[0].ModRevision, nil
+}
+
+// casAlarmState commits the op if the key is not modified since the revision,
+// revision 0 means the key does not exist
+func casAlarmState(ctx context.Context, key string, rev int64, op client.PluginOp) (bool, error) {
+	cmp := client.OpCmp(client.CmpStrModRev(key), client.CmpEqual, rev)
+	if rev == 0 {
+		cmp = client.OpCmp(client.CmpStrVer(key), client.CmpEqual, 0)
+	}
+	resp, err := client.Instance().TxnWithCmp(ctx, []client.PluginOp{op}, []client.CompareOp{cmp}, nil)
+	if err != nil {
+		log.Errorf(err, "save alarm state %s failed", key)
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
+
+func sameAlarmState(saved, s *model.State) bool {
+	return saved != nil && saved.Status == s.Status && saved.Timestamp == s.Timestamp
+}
@@ -0,0 +1,122 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type APIKeyManager struct {
+}
+
+func (ds *APIKeyManager) CreateAPIKey(ctx context.Context, k *datasource.APIKey) error {
+	key := path.GenerateAPIKeyKey(k.ID)
+	exist, err := client.Exist(ctx, key)
+	if err != nil {
+		log.Errorf(err, "can not save api key")
+		return err
+	}
+	if exist {
+		return datasource.ErrAPIKeyDuplicated
+	}
+	k.CreateTime = strconv.FormatInt(time.Now().Unix(), 10)
+	k.UpdateTime = k.CreateTime
+	return ds.put(ctx, k)
+}
+
+func (ds *APIKeyManager) GetAPIKey(ctx context.Context, id string) (*datasource.APIKey, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateAPIKeyKey(id)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrAPIKeyNotExist
+	}
+	k := &datasource.APIKey{}
+	err = json.Unmarshal(resp.Kvs[0].Value, k)
+	if err != nil {
+		log.Errorf(err, "api key [%s] format invalid", id)
+		return nil, err
+	}
+	return k, nil
+}
+
+func (ds *APIKeyManager) ListAPIKey(ctx context.Context, account string) ([]*datasource.APIKey, error) {
+	kvs, _, err := client.List(ctx, path.GenerateAPIKeyKey(""))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*datasource.APIKey, 0)
+	for _, kv := range kvs {
+		k := &datasource.APIKey{}
+		err = json.Unmarshal(kv.Value, k)
+		if err != nil {
+			log.Error("api key format invalid:", err)
+			continue //do not fail if some key is invalid
+		}
+		if len(account) > 0 && k.Account != account {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func (ds *APIKeyManager) UpdateAPIKey(ctx context.Context, k *datasource.APIKey) error {
+	exist, err := client.Exist(ctx, path.GenerateAPIKeyKey(k.ID))
+	if err != nil {
+		log.Errorf(err, "can not update api key")
+		return err
+	}
+	if !exist {
+		return datasource.ErrAPIKeyNotExist
+	}
+	k.UpdateTime = strconv.FormatInt(time.Now().Unix(), 10)
+	return ds.put(ctx, k)
+}
+
+func (ds *APIKeyManager) DeleteAPIKey(ctx context.Context, id string) error {
+	_, err := client.Delete(ctx, path.GenerateAPIKeyKey(id))
+	if err != nil {
+		log.Error(fmt.Sprintf("remove api key %s failed", id), err)
+		return err
+	}
+	return nil
+}
+
+func (ds *APIKeyManager) put(ctx context.Context, k *datasource.APIKey) error {
+	value, err := json.Marshal(k)
+	if err != nil {
+		log.Errorf(err, "api key is invalid")
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateAPIKeyKey(k.ID), value)
+	if err != nil {
+		log.Errorf(err, "can not save api key")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	serviceUtil "github.com/apache/servicecomb-service-center/datasource/etcd/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
+	"github.com/apache/servicecomb-service-center/server/plugin/uuid"
+)
+
+// ApplyService() implement:
+// 1. compare the document with the stored service, then plan the writes
+// 2. commit all the writes in one etcd txn, the document is rejected if there are too many
+// 3. grant the instance leases just before the txn, and revoke them if the txn does not succeed
+func (ds *MetadataManager) ApplyService(ctx context.Context, doc *datasource.ServiceDocument) (
+	*datasource.ApplyServiceResponse, error) {
+	remoteIP := util.GetIPFromContext(ctx)
+	domainProject := util.ParseDomainProject(ctx)
+	service := doc.Service
+	serviceFlag := util.StringJoin([]string{
+		service.Environment, service.AppId, service.ServiceName, service.Version}, path.SPLIT)
+
+	state, checkErr := datasource.LoadServiceState(ctx, ds, doc)
+	if checkErr != nil {
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, load service failed, operator: %s",
+			serviceFlag, remoteIP), checkErr)
+		return applyFailed(checkErr)
+	}
+
+	serviceKey := &pb.MicroServiceKey{
+		Tenant:      domainProject,
+		Environment: service.Environment,
+		AppId:       service.AppId,
+		ServiceName: service.ServiceName,
+		Alias:       service.Alias,
+		Version:     service.Version,
+	}
+	if state.Service == nil && len(service.ServiceId) == 0 {
+		// 产生全局service id
+		service.ServiceId = uuid.Generator().GetServiceID(
+			util.SetContext(ctx, uuid.ContextKey, path.GenerateServiceIndexKey(serviceKey)))
+	}
+	for _, instance := range doc.Instances {
+		if checkErr := setInstanceDefaultValue(ctx, instance); checkErr != nil {
+			log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), checkErr)
+			return applyFailed(checkErr)
+		}
+	}
+
+	plan, checkErr := datasource.PlanServiceDocument(doc, state, ds.isSchemaEditable())
+	resp := &datasource.ApplyServiceResponse{
+		ServiceID: plan.ServiceID,
+		Items:     plan.Items,
+	}
+	if checkErr != nil {
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), checkErr)
+		resp.Response = pb.CreateResponseWithSCErr(checkErr)
+		return resp, nil
+	}
+
+	if checkErr = checkApplyQuota(ctx, domainProject, plan); checkErr != nil {
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), checkErr)
+		plan.Abort(checkErr.Error())
+		resp.Response = pb.CreateResponseWithSCErr(checkErr)
+		if checkErr.InternalError() {
+			return resp, checkErr
+		}
+		return resp, nil
+	}
+
+	if doc.DryRun {
+		resp.Response = pb.CreateResponse(pb.ResponseSuccess, "Plan service successfully.")
+		return resp, nil
+	}
+
+	opts, cmps, err := ds.applyServiceOps(ctx, domainProject, serviceKey, plan)
+	if err != nil {
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), err)
+		plan.Abort(err.Error())
+		resp.Response = pb.CreateResponse(pb.ErrUnavailableBackend, err.Error())
+		return resp, err
+	}
+	// every instance is written with the instance key and the lease key
+	if count := len(opts) + 2*len(plan.Instances); count > client.MaxTxnNumberOneTime {
+		checkErr = pb.NewError(pb.ErrInvalidParams, fmt.Sprintf(
+			"Too many changes, %d operations exceed the limit %d of one transaction.", count, client.MaxTxnNumberOneTime))
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), checkErr)
+		plan.Abort(checkErr.Error())
+		resp.Response = pb.CreateResponseWithSCErr(checkErr)
+		return resp, nil
+	}
+
+	instanceOpts, leaseIDs, err := ds.applyInstanceOps(ctx, domainProject, plan)
+	if err != nil {
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), err)
+		plan.Abort(err.Error())
+		resp.Response = pb.CreateResponse(pb.ErrUnavailableBackend, err.Error())
+		return resp, err
+	}
+	opts = append(opts, instanceOpts...)
+	if len(opts) == 0 {
+		resp.Response = pb.CreateResponse(pb.ResponseSuccess, "Apply service successfully.")
+		return resp, nil
+	}
+
+	txnResp, err := client.Instance().TxnWithCmp(ctx, opts, cmps, nil)
+	if err != nil {
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), err)
+		revokeLeases(ctx, leaseIDs)
+		plan.Abort(err.Error())
+		resp.Response = pb.CreateResponse(pb.ErrUnavailableBackend, err.Error())
+		return resp, err
+	}
+	if !txnResp.Succeeded {
+		revokeLeases(ctx, leaseIDs)
+		checkErr = pb.NewError(pb.ErrServiceNotExists, "Service does not exist.")
+		if plan.CreateService {
+			checkErr = pb.NewError(pb.ErrServiceAlreadyExists,
+				"ServiceID conflict or found the same service with different id.")
+		}
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), checkErr)
+		plan.Abort(checkErr.Error())
+		resp.Response = pb.CreateResponseWithSCErr(checkErr)
+		return resp, nil
+	}
+
+	log.Info(fmt.Sprintf("apply micro-service[%s][%s] successfully, %d operations, operator: %s",
+		plan.ServiceID, serviceFlag, len(opts), remoteIP))
+	resp.Response = pb.CreateResponse(pb.ResponseSuccess, "Apply service successfully.")
+	return resp, nil
+}
+
+func (ds *MetadataManager) applyServiceOps(ctx context.Context, domainProject string, serviceKey *pb.MicroServiceKey,
+	plan *datasource.ServiceApplyPlan) ([]client.PluginOp, []client.CompareOp, error) {
+	serviceID := plan.ServiceID
+	keyBytes := util.StringToBytesWithNoCopy(path.GenerateServiceKey(domainProject, serviceID))
+
+	var opts []client.PluginOp
+	var cmps []client.CompareOp
+	if plan.CreateService {
+		data, err := json.Marshal(plan.Service)
+		if err != nil {
+			return nil, nil, err
+		}
+		indexBytes := util.StringToBytesWithNoCopy(path.GenerateServiceIndexKey(serviceKey))
+		opts = append(opts,
+			client.OpPut(client.WithKey(keyBytes), client.WithValue(data)),
+			client.OpPut(client.WithKey(indexBytes), client.WithStrValue(serviceID)))
+		cmps = append(cmps,
+			client.OpCmp(client.CmpVer(indexBytes), client.CmpEqual, 0),
+			client.OpCmp(client.CmpVer(keyBytes), client.CmpEqual, 0))
+		if len(serviceKey.Alias) > 0 {
+			aliasBytes := util.StringToBytesWithNoCopy(path.GenerateServiceAliasKey(serviceKey))
+			opts = append(opts, client.OpPut(client.WithKey(aliasBytes), client.WithStrValue(serviceID)))
+			cmps = append(cmps, client.OpCmp(client.CmpVer(aliasBytes), client.CmpEqual, 0))
+		}
+	} else {
+		cmps = append(cmps, client.OpCmp(client.CmpVer(keyBytes), client.CmpNotEqual, 0))
+		if plan.Service != nil {
+			opt, err := serviceUtil.UpdateService(domainProject, serviceID, plan.Service)
+			if err != nil {
+				return nil, nil, err
+			}
+			opts = append(opts, opt)
+		}
+	}
+
+	for _, schema := range plan.Schemas {
+		opts = append(opts, schemaWithDatabaseOpera(client.OpPut, domainProject, serviceID, schema)...)
+	}
+
+	if plan.Tags != nil {
+		data, err := json.Marshal(plan.Tags)
+		if err != nil {
+			return nil, nil, err
+		}
+		key := path.GenerateServiceTagKey(domainProject, serviceID)
+		opts = append(opts, client.OpPut(client.WithStrKey(key), client.WithValue(data)))
+	}
+
+	for _, rule := range append(plan.Rules, plan.UpdatedRules...) {
+		data, err := json.Marshal(rule)
+		if err != nil {
+			return nil, nil, err
+		}
+		key := path.GenerateServiceRuleKey(domainProject, serviceID, rule.RuleId)
+		indexKey := path.GenerateRuleIndexKey(domainProject, serviceID, rule.Attribute, rule.Pattern)
+		opts = append(opts,
+			client.OpPut(client.WithStrKey(key), client.WithValue(data)),
+			client.OpPut(client.WithStrKey(indexKey), client.WithStrValue(rule.RuleId)))
+	}
+	return opts, cmps, nil
+}
+
+// applyInstanceOps grants the leases of the instances, the granted leases are revoked if it fails
+func (ds *MetadataManager) applyInstanceOps(ctx context.Context, domainProject string,
+	plan *datasource.ServiceApplyPlan) ([]client.PluginOp, []int64, error) {
+	serviceID := plan.ServiceID
+	var opts []client.PluginOp
+	var leaseIDs []int64
+	for _, instance := range plan.Instances {
+		data, err := json.Marshal(instance)
+		if err != nil {
+			revokeLeases(ctx, leaseIDs)
+			return nil, nil, err
+		}
+		ttl := int64(instance.HealthCheck.Interval * (instance.HealthCheck.Times + 1))
+		if ds.InstanceTTL > 0 {
+			ttl = ds.InstanceTTL
+		}
+		leaseID, err := client.Instance().LeaseGrant(ctx, ttl)
+		if err != nil {
+			revokeLeases(ctx, leaseIDs)
+			return nil, nil, err
+		}
+		leaseIDs = append(leaseIDs, leaseID)
+		key := path.GenerateInstanceKey(domainProject, serviceID, instance.InstanceId)
+		hbKey := path.GenerateInstanceLeaseKey(domainProject, serviceID, instance.InstanceId)
+		opts = append(opts,
+			client.OpPut(client.WithStrKey(key), client.WithValue(data), client.WithLease(leaseID)),
+			client.OpPut(client.WithStrKey(hbKey), client.WithStrValue(fmt.Sprintf("%d", leaseID)),
+				client.WithLease(leaseID)))
+	}
+	return opts, leaseIDs, nil
+}
+
+func revokeLeases(ctx context.Context, leaseIDs []int64) {
+	for _, leaseID := range leaseIDs {
+		if err := client.Instance().LeaseRevoke(ctx, leaseID); err != nil {
+			log.Error(fmt.Sprintf("revoke lease[%d] failed", leaseID), err)
+		}
+	}
+}
+
+func checkApplyQuota(ctx context.Context, domainProject string, plan *datasource.ServiceApplyPlan) *errsvc.Error {
+	resources := []*quota.ApplyQuotaResource{
+		quota.NewApplyQuotaResource(quota.TypeSchema, domainProject, plan.ServiceID,
+			plan.Count(datasource.ApplyKindSchema, datasource.ApplyActionCreated)),
+		quota.NewApplyQuotaResource(quota.TypeRule, domainProject, plan.ServiceID,
+			plan.Count(datasource.ApplyKindRule, datasource.ApplyActionCreated)),
+	}
+	if !core.IsSCInstance(ctx) {
+		if plan.CreateService {
+			resources = append(resources, quota.NewApplyQuotaResource(quota.TypeService, domainProject, "", 1))
+		}
+		resources = append(resources, quota.NewApplyQuotaResource(quota.TypeInstance, domainProject, plan.ServiceID,
+			int64(len(plan.Instances))))
+	}
+	for _, res := range resources {
+		if res.QuotaSize <= 0 {
+			continue
+		}
+		if err := quota.Apply(ctx, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyFailed(err *errsvc.Error) (*datasource.ApplyServiceResponse, error) {
+	resp := &datasource.ApplyServiceResponse{
+		Response: pb.CreateResponseWithSCErr(err),
+	}
+	if err.InternalError() {
+		return resp, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,81 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type AuditLogManager struct {
+}
+
+func (ds *AuditLogManager) AddAuditLog(ctx context.Context, l *dump.AuditLog) error {
+	value, err := json.Marshal(l)
+	if err != nil {
+		log.Errorf(err, "audit log is invalid")
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateAuditLogKey(l.Timestamp, l.ID), value)
+	if err != nil {
+		log.Errorf(err, "can not save audit log")
+		return err
+	}
+	return nil
+}
+
+func (ds *AuditLogManager) ListAuditLog(ctx context.Context, f *dump.AuditLogFilter) ([]*dump.AuditLog, error) {
+	kvs, _, err := client.List(ctx, path.GetAuditLogRootKey()+path.SPLIT)
+	if err != nil {
+		return nil, err
+	}
+	logs := make([]*dump.AuditLog, 0, len(kvs))
+	for _, kv := range kvs {
+		l := &dump.AuditLog{}
+		err = json.Unmarshal(kv.Value, l)
+		if err != nil {
+			log.Error("audit log format invalid:", err)
+			continue
+		}
+		if f.Match(l) {
+			logs = append(logs, l)
+		}
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Timestamp > logs[j].Timestamp
+	})
+	if f.Limit > 0 && len(logs) > f.Limit {
+		logs = logs[:f.Limit]
+	}
+	return logs, nil
+}
+
+func (ds *AuditLogManager) DeleteAuditLog(ctx context.Context, before int64) error {
+	_, err := client.Instance().Do(ctx, client.DEL,
+		client.WithStrKey(path.GetAuditLogRootKey()+path.SPLIT),
+		client.WithStrEndKey(path.GenerateAuditLogKey(before, "")))
+	if err != nil {
+		log.Errorf(err, "remove audit logs before %d failed", before)
+		return err
+	}
+	return nil
+}
@@ -22,6 +22,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
@@ -37,7 +39,25 @@ import (
 type DepManager struct {
 }
 
-func (dm *DepManager) SearchProviderDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*pb.GetProDependenciesResponse, error) {
+func (dm *DepManager) SearchProviderDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*datasource.ProviderDependencyResponse, error) {
+	resp, err := dm.searchProviderDependency(ctx, request)
+	if resp == nil {
+		return nil, err
+	}
+	result := &datasource.ProviderDependencyResponse{GetProDependenciesResponse: resp}
+	if resp.Response.GetCode() != pb.ResponseSuccess {
+		return result, err
+	}
+	records, err := dm.ListDiscovery(ctx, "", request.ServiceId)
+	if err != nil {
+		log.Error(fmt.Sprintf("list provider[%s] dependency discoveries failed", request.ServiceId), err)
+		return result, nil
+	}
+	result.LastDiscovered = datasource.DiscoveredTimes(records, false)
+	return result, nil
+}
+
+func (dm *DepManager) searchProviderDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*pb.GetProDependenciesResponse, error) {
 	domainProject := util.ParseDomainProject(ctx)
 	providerServiceID := request.ServiceId
 	provider, err := serviceUtil.GetService(ctx, domainProject, providerServiceID)
@@ -70,7 +90,25 @@ func (dm *DepManager) SearchProviderDependency(ctx context.Context, request *pb.
 	}, nil
 }
 
-func (dm *DepManager) SearchConsumerDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*pb.GetConDependenciesResponse, error) {
+func (dm *DepManager) SearchConsumerDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*datasource.ConsumerDependencyResponse, error) {
+	resp, err := dm.searchConsumerDependency(ctx, request)
+	if resp == nil {
+		return nil, err
+	}
+	result := &datasource.ConsumerDependencyResponse{GetConDependenciesResponse: resp}
+	if resp.Response.GetCode() != pb.ResponseSuccess {
+		return result, err
+	}
+	records, err := dm.ListDiscovery(ctx, request.ServiceId, "")
+	if err != nil {
+		log.Error(fmt.Sprintf("list consumer[%s] dependency discoveries failed", request.ServiceId), err)
+		return result, nil
+	}
+	result.LastDiscovered = datasource.DiscoveredTimes(records, true)
+	return result, nil
+}
+
+func (dm *DepManager) searchConsumerDependency(ctx context.Context, request *pb.GetDependenciesRequest) (*pb.GetConDependenciesResponse, error) {
 	consumerID := request.ServiceId
 	domainProject := util.ParseDomainProject(ctx)
 	consumer, err := serviceUtil.GetService(ctx, domainProject, consumerID)
@@ -180,3 +218,191 @@ func (dm *DepManager) AddOrUpdateDependencies(ctx context.Context, dependencyInf
 		override, dependencyInfos, util.GetIPFromContext(ctx))
 	return pb.CreateResponse(pb.ResponseSuccess, "Create dependency successfully."), nil
 }
+
+func (dm *DepManager) RecordDiscovery(ctx context.Context, consumerID string, providerIDs []string) error {
+	domainProject := util.ParseDomainProject(ctx)
+	value := strconv.FormatInt(time.Now().Unix(), 10)
+	opts := make([]client.PluginOp, 0, 2*len(providerIDs))
+	for _, providerID := range providerIDs {
+		opts = append(opts,
+			client.OpPut(client.WithStrKey(path.GenerateDependencyDiscoveryKey(domainProject, consumerID, providerID)),
+				client.WithStrValue(value)),
+			client.OpPut(client.WithStrKey(path.GenerateDependencyDiscoveryIndexKey(domainProject, providerID, consumerID)),
+				client.WithStrValue(value)))
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+	return client.BatchCommit(ctx, opts)
+}
+
+func (dm *DepManager) ListDiscovery(ctx context.Context, consumerID, providerID string) ([]*datasource.DependencyDiscovery, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	key := path.GetServiceDependencyDiscoveryRootKey(domainProject) + path.SPLIT
+	parse := path.GetInfoFromDependencyDiscoveryKV
+	switch {
+	case len(consumerID) > 0:
+		key = path.GenerateDependencyDiscoveryKey(domainProject, consumerID, providerID)
+	case len(providerID) > 0:
+		// list by the provider-keyed index instead of scanning all records of the domain project
+		key = path.GenerateDependencyDiscoveryIndexKey(domainProject, providerID, "")
+		parse = func(key []byte) (string, string, string) {
+			p, c, dp := path.GetInfoFromDependencyDiscoveryIndexKV(key)
+			return c, p, dp
+		}
+	}
+	kvs, _, err := client.List(ctx, key)
+	if err != nil {
+		log.Error(fmt.Sprintf("list dependency discovery[%s] failed", key), err)
+		return nil, err
+	}
+	records := make([]*datasource.DependencyDiscovery, 0, len(kvs))
+	for _, kv := range kvs {
+		c, p, _ := parse(kv.Key)
+		if len(providerID) > 0 && p != providerID {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(util.BytesToStringWithNoCopy(kv.Value), 10, 64)
+		if err != nil {
+			log.Error(fmt.Sprintf("dependency discovery[%s] value is invalid", kv.Key), err)
+			continue
+		}
+		records = append(records, &datasource.DependencyDiscovery{
+			ConsumerID: c,
+			ProviderID: p,
+			Timestamp:  timestamp,
+		})
+	}
+	return records, nil
+}
+
+func (dm *DepManager) ClearStaleDependencies(ctx context.Context, ttl time.Duration,
+	dryRun bool) ([]*datasource.StaleDependency, error) {
+	key := path.GetServiceDependencyRuleRootKey("")
+	resp, err := kv.Store().DependencyRule().Search(ctx, client.WithStrKey(key), client.WithPrefix())
+	if err != nil {
+		log.Error(fmt.Sprintf("search dependency rules[%s] failed", key), err)
+		return nil, err
+	}
+
+	var stales []*datasource.StaleDependency
+	now := time.Now()
+	for _, keyValue := range resp.Kvs {
+		t, consumer := path.GetInfoFromDependencyRuleKV(keyValue.Key)
+		if t != path.DepsConsumer || consumer == nil || consumer.ServiceName == "*" {
+			continue
+		}
+		dctx, err := ctxFromDomainProject(ctx, consumer.Tenant)
+		if err != nil {
+			log.Error("get domain project context failed", err)
+			continue
+		}
+		items, err := dm.clearConsumerStaleDependencies(dctx, consumer,
+			keyValue.Value.(*pb.MicroServiceDependency), ttl, now, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		stales = append(stales, items...)
+	}
+	return stales, nil
+}
+
+func (dm *DepManager) clearConsumerStaleDependencies(ctx context.Context, consumer *pb.MicroServiceKey,
+	dependency *pb.MicroServiceDependency, ttl time.Duration, now time.Time, dryRun bool) ([]*datasource.StaleDependency, error) {
+	consumerFlag := util.StringJoin([]string{consumer.Environment, consumer.AppId, consumer.ServiceName, consumer.Version}, path.SPLIT)
+	consumerID, err := serviceUtil.GetServiceID(ctx, consumer)
+	if err != nil {
+		log.Error(fmt.Sprintf("get consumer[%s] id failed", consumerFlag), err)
+		return nil, err
+	}
+	if len(consumerID) == 0 {
+		// the rules of the deleted consumer are removed by CleanUpDependencyRules
+		return nil, nil
+	}
+
+	discoveries, err := dm.ListDiscovery(ctx, consumerID, "")
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string]int64, len(discoveries))
+	for _, discovery := range discoveries {
+		records[discovery.ProviderID] = discovery.Timestamp
+	}
+
+	var (
+		stales     []*datasource.StaleDependency
+		left       []*pb.MicroServiceKey
+		unrecorded []string
+	)
+	for _, provider := range dependency.Dependency {
+		if provider.ServiceName == "*" {
+			left = append(left, provider)
+			continue
+		}
+		providerIDs, _, err := serviceUtil.FindServiceIds(ctx, provider.Version, provider)
+		if err != nil {
+			log.Error(fmt.Sprintf("find consumer[%s]'s provider[%s/%s/%s/%s] failed", consumerFlag,
+				provider.Environment, provider.AppId, provider.ServiceName, provider.Version), err)
+			return nil, err
+		}
+		last := datasource.LastDiscovered(records, providerIDs)
+		switch {
+		case len(providerIDs) == 0:
+			// the provider no longer resolves, the rule can never be discovered again
+		case last == 0:
+			// never discovered since the tracking enabled, start the ttl from now
+			unrecorded = append(unrecorded, providerIDs...)
+			left = append(left, provider)
+			continue
+		case !datasource.IsStaleDependency(last, ttl, now):
+			left = append(left, provider)
+			continue
+		}
+		stales = append(stales, &datasource.StaleDependency{
+			DomainProject:  consumer.Tenant,
+			ConsumerID:     consumerID,
+			Consumer:       consumer,
+			Provider:       provider,
+			LastDiscovered: last,
+		})
+	}
+
+	if dryRun {
+		// dry run must not touch the datasource
+		return stales, nil
+	}
+	if len(unrecorded) > 0 {
+		if err := dm.RecordDiscovery(ctx, consumerID, unrecorded); err != nil {
+			log.Error(fmt.Sprintf("record consumer[%s] discovery failed", consumerFlag), err)
+			return nil, err
+		}
+	}
+	if len(stales) == 0 {
+		return stales, nil
+	}
+
+	resp, err := dm.AddOrUpdateDependencies(ctx, []*pb.ConsumerDependency{
+		{Consumer: consumer, Providers: left},
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetCode() != pb.ResponseSuccess {
+		return nil, errors.New(resp.GetMessage())
+	}
+	log.Warn(fmt.Sprintf("clear consumer[%s]'s %d stale dependency rules", consumerFlag, len(stales)))
+	return stales, nil
+}
+
+func recordDiscovery(ctx context.Context, consumerID string, providerIDs []string) {
+	domainProject := util.ParseDomainProject(ctx)
+	ids := datasource.FilterUnrecordedProviders(domainProject, consumerID, providerIDs)
+	if len(ids) == 0 {
+		return
+	}
+	if err := (&DepManager{}).RecordDiscovery(ctx, consumerID, ids); err != nil {
+		log.Error(fmt.Sprintf("record consumer[%s] discovery failed", consumerID), err)
+		return
+	}
+	datasource.MarkRecordedProviders(domainProject, consumerID, ids)
+}
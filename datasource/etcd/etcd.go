@@ -41,20 +41,60 @@ func init() {
 }
 
 type DataSource struct {
-	accountLockManager datasource.AccountLockManager
-	accountManager     datasource.AccountManager
-	metadataManager    datasource.MetadataManager
-	roleManager        datasource.RoleManager
-	sysManager         datasource.SystemManager
-	depManager         datasource.DependencyManager
-	scManager          datasource.SCManager
-	metricsManager     datasource.MetricsManager
+	accountLockManager     datasource.AccountLockManager
+	roleInheritanceManager datasource.RoleInheritanceManager
+	auditLogManager        datasource.AuditLogManager
+	quotaOverrideManager   datasource.QuotaOverrideManager
+	alarmManager           datasource.AlarmManager
+	accountStateManager    datasource.AccountStateManager
+	totpManager            datasource.TOTPManager
+	apiKeyManager          datasource.APIKeyManager
+	sessionManager         datasource.SessionManager
+	accountManager         datasource.AccountManager
+	metadataManager        datasource.MetadataManager
+	roleManager            datasource.RoleManager
+	sysManager             datasource.SystemManager
+	depManager             datasource.DependencyManager
+	scManager              datasource.SCManager
+	metricsManager         datasource.MetricsManager
 }
 
 func (ds *DataSource) AccountLockManager() datasource.AccountLockManager {
 	return ds.accountLockManager
 }
 
+func (ds *DataSource) RoleInheritanceManager() datasource.RoleInheritanceManager {
+	return ds.roleInheritanceManager
+}
+
+func (ds *DataSource) AuditLogManager() datasource.AuditLogManager {
+	return ds.auditLogManager
+}
+
+func (ds *DataSource) QuotaOverrideManager() datasource.QuotaOverrideManager {
+	return ds.quotaOverrideManager
+}
+
+func (ds *DataSource) AlarmManager() datasource.AlarmManager {
+	return ds.alarmManager
+}
+
+func (ds *DataSource) AccountStateManager() datasource.AccountStateManager {
+	return ds.accountStateManager
+}
+
+func (ds *DataSource) TOTPManager() datasource.TOTPManager {
+	return ds.totpManager
+}
+
+func (ds *DataSource) APIKeyManager() datasource.APIKeyManager {
+	return ds.apiKeyManager
+}
+
+func (ds *DataSource) SessionManager() datasource.SessionManager {
+	return ds.sessionManager
+}
+
 func (ds *DataSource) SystemManager() datasource.SystemManager {
 	return ds.sysManager
 }
@@ -100,12 +140,21 @@ func NewDataSource(opts datasource.Options) (datasource.DataSource, error) {
 	}
 	inst.accountManager = &AccountManager{}
 	inst.accountLockManager = NewAccountLockManager(opts.ReleaseAccountAfter)
+	inst.roleInheritanceManager = &RoleInheritanceManager{}
+	inst.auditLogManager = &AuditLogManager{}
+	inst.quotaOverrideManager = &QuotaOverrideManager{}
+	inst.alarmManager = &AlarmManager{}
+	inst.accountStateManager = &AccountStateManager{}
+	inst.totpManager = &TOTPManager{}
+	inst.apiKeyManager = &APIKeyManager{}
+	inst.sessionManager = &SessionManager{}
 	inst.roleManager = &RoleManager{}
 	inst.metadataManager = newMetadataManager(opts.SchemaNotEditable, opts.InstanceTTL)
 	inst.sysManager = newSysManager()
 	inst.depManager = &DepManager{}
 	inst.scManager = &SCManager{}
 	inst.metricsManager = &MetricsManager{}
+	inst.autoClearStaleDependencies(opts)
 	return inst, nil
 }
 
@@ -181,3 +230,40 @@ func (ds *DataSource) autoCompact() {
 		}
 	})
 }
+
+func (ds *DataSource) autoClearStaleDependencies(opts datasource.Options) {
+	ttl, interval := opts.DependencyStaleTTL, opts.DependencyClearInterval
+	if ttl <= 0 || interval <= 0 {
+		return
+	}
+	gopool.Go(func(ctx context.Context) {
+		log.Infof("enabled the automatic stale dependencies clear mechanism, check once every %s, ttl %s, dry run %t",
+			interval, ttl, opts.DependencyClearDryRun)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+				lock, err := mux.Try(mux.DepClearLock)
+				if err != nil {
+					log.Errorf(err, "can not clear stale dependencies by this service center instance now")
+					continue
+				}
+
+				stales, err := ds.depManager.ClearStaleDependencies(ctx, ttl, opts.DependencyClearDryRun)
+				if err != nil {
+					log.Error("clear stale dependencies failed", err)
+				}
+				for _, stale := range stales {
+					log.Warnf("stale dependency rule: consumer[%s] provider[%s/%s/%s/%s], last discovered at %d, dry run %t",
+						stale.ConsumerID, stale.Provider.Environment, stale.Provider.AppId, stale.Provider.ServiceName,
+						stale.Provider.Version, stale.LastDiscovered, opts.DependencyClearDryRun)
+				}
+
+				if err := lock.Unlock(); err != nil {
+					log.Error("", err)
+				}
+			}
+		}
+	})
+}
@@ -999,6 +999,10 @@ func (ds *MetadataManager) findInstance(ctx context.Context, request *pb.FindIns
 		}
 	}
 
+	if len(request.ConsumerServiceId) > 0 && len(item.ServiceIds) > 0 {
+		recordDiscovery(ctx, request.ConsumerServiceId, item.ServiceIds)
+	}
+
 	return ds.genFindResult(ctx, rev, item)
 }
 
@@ -1832,6 +1836,11 @@ func (ds *MetadataManager) DeleteTags(ctx context.Context, request *pb.DeleteSer
 }
 
 func (ds *MetadataManager) AddRule(ctx context.Context, request *pb.AddServiceRulesRequest) (
+	*pb.AddServiceRulesResponse, error) {
+	return ds.AddPriorityRules(ctx, request, nil)
+}
+
+func (ds *MetadataManager) AddPriorityRules(ctx context.Context, request *pb.AddServiceRulesRequest, priorities []int32) (
 	*pb.AddServiceRulesResponse, error) {
 	remoteIP := util.GetIPFromContext(ctx)
 	domainProject := util.ParseDomainProject(ctx)
@@ -1864,26 +1873,44 @@ func (ds *MetadataManager) AddRule(ctx context.Context, request *pb.AddServiceRu
 		}, err
 	}
 	ruleIDs := make([]string, 0, len(request.Rules))
-	opts := make([]client.PluginOp, 0, 2*len(request.Rules))
-	for _, rule := range request.Rules {
-		//黑白名单只能存在一种，黑名单 or 白名单
-		if len(ruleType) == 0 {
-			ruleType = rule.RuleType
-		} else if ruleType != rule.RuleType {
-			log.Errorf(nil,
-				"add service[%s] rule failed, can not add different RuleType at the same time, operator: %s",
-				request.ServiceId, remoteIP)
-			return &pb.AddServiceRulesResponse{
-				Response: pb.CreateResponse(pb.ErrBlackAndWhiteRule,
-					"Service can only contain one rule type, BLACK or WHITE."),
-			}, nil
-
+	opts := make([]client.PluginOp, 0, 3*len(request.Rules))
+	for i, rule := range request.Rules {
+		var priority int32
+		if i < len(priorities) {
+			priority = priorities[i]
+		}
+		//黑白名单只能存在一种，黑名单 or 白名单，ALLOW or DENY rules can be mixed with any rules
+		if datasource.IsLegacyRuleType(rule.RuleType) {
+			if len(ruleType) == 0 {
+				ruleType = rule.RuleType
+			} else if ruleType != rule.RuleType {
+				log.Errorf(nil,
+					"add service[%s] rule failed, can not add different RuleType at the same time, operator: %s",
+					request.ServiceId, remoteIP)
+				return &pb.AddServiceRulesResponse{
+					Response: pb.CreateResponse(pb.ErrBlackAndWhiteRule,
+						"Service can only contain one rule type, BLACK or WHITE."),
+				}, nil
+			}
 		}
 
 		//同一服务，attribute和pattern确定一个rule
-		if serviceUtil.RuleExist(ctx, domainProject, request.ServiceId, rule.Attribute, rule.Pattern) {
+		existID, err := serviceUtil.GetRuleIDByIndex(ctx, domainProject, request.ServiceId, rule.Attribute, rule.Pattern)
+		if err != nil {
+			log.Errorf(err, "add service[%s] rule failed, get rule[%s/%s] failed, operator: %s",
+				request.ServiceId, rule.Attribute, rule.Pattern, remoteIP)
+			return &pb.AddServiceRulesResponse{
+				Response: pb.CreateResponse(pb.ErrInternal, err.Error()),
+			}, err
+		}
+		if len(existID) > 0 {
 			log.Infof("service[%s] rule[%s/%s] already exists, operator: %s",
 				request.ServiceId, rule.Attribute, rule.Pattern, remoteIP)
+			if priority != 0 {
+				opts = append(opts, client.OpPut(
+					client.WithStrKey(path.GenerateRulePriorityKey(domainProject, request.ServiceId, existID)),
+					client.WithStrValue(strconv.Itoa(int(priority)))))
+			}
 			continue
 		}
 
@@ -1914,6 +1941,11 @@ func (ds *MetadataManager) AddRule(ctx context.Context, request *pb.AddServiceRu
 
 		opts = append(opts, client.OpPut(client.WithStrKey(key), client.WithValue(data)))
 		opts = append(opts, client.OpPut(client.WithStrKey(indexKey), client.WithStrValue(ruleAdd.RuleId)))
+		if priority != 0 {
+			opts = append(opts, client.OpPut(
+				client.WithStrKey(path.GenerateRulePriorityKey(domainProject, request.ServiceId, ruleAdd.RuleId)),
+				client.WithStrValue(strconv.Itoa(int(priority)))))
+		}
 	}
 	if len(opts) <= 0 {
 		log.Infof("add service[%s] rule successfully, no rules to add, operator: %s",
@@ -1998,7 +2030,7 @@ func (ds *MetadataManager) UpdateRule(ctx context.Context, request *pb.UpdateSer
 			Response: pb.CreateResponse(pb.ErrInternal, err.Error()),
 		}, err
 	}
-	if ruleNum >= 1 && ruleType != request.Rule.RuleType {
+	if ruleNum >= 1 && datasource.IsLegacyRuleType(request.Rule.RuleType) && ruleType != request.Rule.RuleType {
 		log.Errorf(err, "update service rule[%s/%s] failed, can only exist one type, current type is %s, operator: %s",
 			request.ServiceId, request.RuleId, ruleType, remoteIP)
 		return &pb.UpdateServiceRuleResponse{
@@ -2122,7 +2154,8 @@ func (ds *MetadataManager) DeleteRule(ctx context.Context, request *pb.DeleteSer
 		indexKey = path.GenerateRuleIndexKey(domainProject, request.ServiceId, data.Attribute, data.Pattern)
 		opts = append(opts,
 			client.OpDel(client.WithStrKey(key)),
-			client.OpDel(client.WithStrKey(indexKey)))
+			client.OpDel(client.WithStrKey(indexKey)),
+			client.OpDel(client.WithStrKey(path.GenerateRulePriorityKey(domainProject, request.ServiceId, ruleID))))
 	}
 	if len(opts) <= 0 {
 		log.Errorf(nil, "delete service[%s] rules %v failed, no rule has been deleted, operator: %s",
@@ -2157,6 +2190,39 @@ func (ds *MetadataManager) DeleteRule(ctx context.Context, request *pb.DeleteSer
 	}, nil
 }
 
+func (ds *MetadataManager) GetRulePriorities(ctx context.Context, serviceID string) (map[string]int32, error) {
+	return serviceUtil.GetRulePriorities(ctx, util.ParseDomainProject(ctx), serviceID)
+}
+
+func (ds *MetadataManager) UpdateRulePriorities(ctx context.Context, serviceID string, priorities map[string]int32) error {
+	domainProject := util.ParseDomainProject(ctx)
+	opts := make([]client.PluginOp, 0, len(priorities))
+	for ruleID, priority := range priorities {
+		key := path.GenerateRulePriorityKey(domainProject, serviceID, ruleID)
+		if priority == 0 {
+			opts = append(opts, client.OpDel(client.WithStrKey(key)))
+			continue
+		}
+		opts = append(opts, client.OpPut(client.WithStrKey(key), client.WithStrValue(strconv.Itoa(int(priority)))))
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+	resp, err := client.BatchCommitWithCmp(ctx, opts,
+		[]client.CompareOp{client.OpCmp(
+			client.CmpVer(util.StringToBytesWithNoCopy(path.GenerateServiceKey(domainProject, serviceID))),
+			client.CmpNotEqual, 0)},
+		nil)
+	if err != nil {
+		log.Errorf(err, "update service[%s] rule priorities failed", serviceID)
+		return err
+	}
+	if !resp.Succeeded {
+		return datasource.ErrServiceNotExists
+	}
+	return nil
+}
+
 func (ds *MetadataManager) modifySchemas(ctx context.Context, domainProject string, service *pb.MicroService,
 	schemas []*pb.Schema) *errsvc.Error {
 	remoteIP := util.GetIPFromContext(ctx)
@@ -2454,10 +2520,47 @@ func (ds *MetadataManager) DeleteServicePri(ctx context.Context, serviceID strin
 	}
 	opts = append(opts, optDeleteDep)
 
+	//删除依赖发现记录
+	opts = append(opts,
+		client.OpDel(client.WithStrKey(path.GenerateDependencyDiscoveryKey(domainProject, serviceID, "")),
+			client.WithPrefix()),
+		client.OpDel(client.WithStrKey(path.GenerateDependencyDiscoveryIndexKey(domainProject, serviceID, "")),
+			client.WithPrefix()))
+	dm := &DepManager{}
+	consumerDiscoveries, err := dm.ListDiscovery(ctx, serviceID, "")
+	if err != nil {
+		log.Errorf(err, "%s micro-service[%s] failed, list dependency discoveries failed, operator: %s",
+			title, serviceID, remoteIP)
+		return pb.CreateResponse(pb.ErrInternal, err.Error()), err
+	}
+	providerDiscoveries, err := dm.ListDiscovery(ctx, "", serviceID)
+	if err != nil {
+		log.Errorf(err, "%s micro-service[%s] failed, list dependency discoveries failed, operator: %s",
+			title, serviceID, remoteIP)
+		return pb.CreateResponse(pb.ErrInternal, err.Error()), err
+	}
+	for _, discovery := range consumerDiscoveries {
+		if discovery.ProviderID == serviceID {
+			continue
+		}
+		opts = append(opts, client.OpDel(client.WithStrKey(
+			path.GenerateDependencyDiscoveryIndexKey(domainProject, discovery.ProviderID, serviceID))))
+	}
+	for _, discovery := range providerDiscoveries {
+		if discovery.ConsumerID == serviceID {
+			continue
+		}
+		opts = append(opts, client.OpDel(client.WithStrKey(
+			path.GenerateDependencyDiscoveryKey(domainProject, discovery.ConsumerID, serviceID))))
+	}
+
 	//删除黑白名单
 	opts = append(opts, client.OpDel(
 		client.WithStrKey(path.GenerateServiceRuleKey(domainProject, serviceID, "")),
 		client.WithPrefix()))
+	opts = append(opts, client.OpDel(
+		client.WithStrKey(path.GenerateRulePriorityKey(domainProject, serviceID, "")),
+		client.WithPrefix()))
 	opts = append(opts, client.OpDel(client.WithStrKey(
 		util.StringJoin([]string{path.GetServiceRuleIndexRootKey(domainProject), serviceID, ""}, path.SPLIT)),
 		client.WithPrefix()))
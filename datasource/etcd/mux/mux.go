@@ -38,6 +38,7 @@ const (
 	GlobalLock       Type = "/cse-sr/lock/global"
 	DepQueueLock     Type = "/cse-sr/lock/dep-queue"
 	ServiceClearLock Type = "/cse-sr/lock/service-clear"
+	DepClearLock     Type = "/cse-sr/lock/dep-clear"
 )
 
 func Lock(t Type) (*etcdsync.DLock, error) {
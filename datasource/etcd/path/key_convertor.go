@@ -146,6 +146,30 @@ func GetInfoFromDependencyQueueKV(key []byte) (consumerID, domainProject, uuid s
 	return
 }
 
+func GetInfoFromDependencyDiscoveryKV(key []byte) (consumerID, providerID, domainProject string) {
+	keys := ToResponse(key)
+	l := len(keys)
+	if l < 4 {
+		return
+	}
+	consumerID = keys[l-2]
+	providerID = keys[l-1]
+	domainProject = fmt.Sprintf("%s/%s", keys[l-4], keys[l-3])
+	return
+}
+
+func GetInfoFromDependencyDiscoveryIndexKV(key []byte) (providerID, consumerID, domainProject string) {
+	keys := ToResponse(key)
+	l := len(keys)
+	if l < 4 {
+		return
+	}
+	providerID = keys[l-2]
+	consumerID = keys[l-1]
+	domainProject = fmt.Sprintf("%s/%s", keys[l-4], keys[l-3])
+	return
+}
+
 func GetInfoFromDependencyRuleKV(key []byte) (t string, _ *discovery.MicroServiceKey) {
 	keys := ToResponse(key)
 	l := len(keys)
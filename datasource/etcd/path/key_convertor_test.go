@@ -102,6 +102,15 @@ func TestGetInfoFromKV(t *testing.T) {
 	s, d, u = path.GetInfoFromDependencyQueueKV([]byte("sdf"))
 	assert.False(t, s != "" || d != "" || u != "")
 
+	c, p, dp := path.GetInfoFromDependencyDiscoveryKV([]byte(path.GenerateDependencyDiscoveryKey("a/b", "c", "d")))
+	assert.False(t, c != "c" || p != "d" || dp != "a/b")
+
+	c, p, dp = path.GetInfoFromDependencyDiscoveryKV([]byte("sdf"))
+	assert.False(t, c != "" || p != "" || dp != "")
+
+	p, c, dp = path.GetInfoFromDependencyDiscoveryIndexKV([]byte(path.GenerateDependencyDiscoveryIndexKey("a/b", "d", "c")))
+	assert.False(t, c != "c" || p != "d" || dp != "a/b")
+
 	dt, k := path.GetInfoFromDependencyRuleKV([]byte(path.GenerateProviderDependencyRuleKey("a/b", &discovery.MicroServiceKey{
 		Tenant:      "a/b",
 		AppId:       "c",
@@ -18,6 +18,8 @@
 package path
 
 import (
+	"fmt"
+
 	"github.com/go-chassis/cari/discovery"
 
 	"github.com/apache/servicecomb-service-center/pkg/util"
@@ -33,6 +35,7 @@ const (
 	RegistryIndex            = "indexes"
 	RegistryRuleKey          = "rules"
 	RegistryRuleIndexKey     = "rule-indexes"
+	RegistryRulePriorityKey  = "rule-priorities"
 	RegistryDomainKey        = "domains"
 	RegistryProjectKey       = "projects"
 	RegistryAliasKey         = "alias"
@@ -43,6 +46,8 @@ const (
 	RegistryDependencyKey    = "deps"
 	RegistryDepsRuleKey      = "dep-rules"
 	RegistryDepsQueueKey     = "dep-queue"
+	RegistryDepsDiscoveryKey = "dep-discovery"
+	RegistryDepsProviderKey  = "dep-discovery-providers"
 	RegistryMetricsKey       = "metrics"
 	DepsQueueUUID            = "0"
 	DepsConsumer             = "c"
@@ -165,6 +170,15 @@ func GetServiceRuleIndexRootKey(domainProject string) string {
 	}, SPLIT)
 }
 
+func GetServiceRulePriorityRootKey(domainProject string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		RegistryServiceKey,
+		RegistryRulePriorityKey,
+		domainProject,
+	}, SPLIT)
+}
+
 func GetServiceTagRootKey(domainProject string) string {
 	return util.StringJoin([]string{
 		GetRootKey(),
@@ -238,6 +252,14 @@ func GenerateServiceRuleKey(domainProject string, serviceID string, ruleID strin
 	}, SPLIT)
 }
 
+func GenerateRulePriorityKey(domainProject string, serviceID string, ruleID string) string {
+	return util.StringJoin([]string{
+		GetServiceRulePriorityRootKey(domainProject),
+		serviceID,
+		ruleID,
+	}, SPLIT)
+}
+
 func GenerateServiceTagKey(domainProject string, serviceID string) string {
 	return util.StringJoin([]string{
 		GetServiceTagRootKey(domainProject),
@@ -345,6 +367,42 @@ func GenerateConsumerDependencyQueueKey(domainProject, consumerID, uuid string)
 	}, SPLIT)
 }
 
+func GetServiceDependencyDiscoveryRootKey(domainProject string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		RegistryServiceKey,
+		RegistryDepsDiscoveryKey,
+		domainProject,
+	}, SPLIT)
+}
+
+func GenerateDependencyDiscoveryKey(domainProject, consumerID, providerID string) string {
+	return util.StringJoin([]string{
+		GetServiceDependencyDiscoveryRootKey(domainProject),
+		consumerID,
+		providerID,
+	}, SPLIT)
+}
+
+func GetServiceDependencyDiscoveryIndexRootKey(domainProject string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		RegistryServiceKey,
+		RegistryDepsProviderKey,
+		domainProject,
+	}, SPLIT)
+}
+
+// GenerateDependencyDiscoveryIndexKey is the provider-keyed index of the discovery record,
+// it is used to list the consumers discovered the provider
+func GenerateDependencyDiscoveryIndexKey(domainProject, providerID, consumerID string) string {
+	return util.StringJoin([]string{
+		GetServiceDependencyDiscoveryIndexRootKey(domainProject),
+		providerID,
+		consumerID,
+	}, SPLIT)
+}
+
 func GetServiceDependencyRootKey(domainProject string) string {
 	return util.StringJoin([]string{
 		GetRootKey(),
@@ -368,6 +426,112 @@ func GenerateAccountLockKey(key string) string {
 		key,
 	}, SPLIT)
 }
+func GetQuotaOverrideRootKey() string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"quota-overrides",
+	}, SPLIT)
+}
+
+// GenerateQuotaOverrideKey generates the key of the domain override if project is empty
+func GenerateQuotaOverrideKey(domain, project string) string {
+	return util.StringJoin([]string{
+		GetQuotaOverrideRootKey(),
+		domain,
+		project,
+	}, SPLIT)
+}
+
+func GetAuditLogRootKey() string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"audit-logs",
+	}, SPLIT)
+}
+
+// GenerateAuditLogKey generates the key ordered by the timestamp in milliseconds
+func GenerateAuditLogKey(timestamp int64, id string) string {
+	return util.StringJoin([]string{
+		GetAuditLogRootKey(),
+		fmt.Sprintf("%016d", timestamp),
+		id,
+	}, SPLIT)
+}
+
+func GetAlarmHistoryRootKey() string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"alarms",
+	}, SPLIT)
+}
+
+// GenerateAlarmHistoryKey generates the key ordered by the timestamp in milliseconds
+func GenerateAlarmHistoryKey(timestamp int64, id string) string {
+	return util.StringJoin([]string{
+		GetAlarmHistoryRootKey(),
+		fmt.Sprintf("%016d", timestamp),
+		id,
+	}, SPLIT)
+}
+
+func GetAlarmStateRootKey() string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"alarm-states",
+	}, SPLIT)
+}
+
+func GenerateAlarmStateKey(alarmID, instance string) string {
+	return util.StringJoin([]string{
+		GetAlarmStateRootKey(),
+		alarmID,
+		instance,
+	}, SPLIT)
+}
+
+func GenerateRoleInheritanceKey(role string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"role-inheritances",
+		role,
+	}, SPLIT)
+}
+func GenerateAccountStateKey(name string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"account-states",
+		name,
+	}, SPLIT)
+}
+func GenerateTOTPKey(account string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"totps",
+		account,
+	}, SPLIT)
+}
+func GenerateAPIKeyKey(id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"api-keys",
+		id,
+	}, SPLIT)
+}
+func GenerateSessionKey(account, id string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"sessions",
+		account,
+		id,
+	}, SPLIT)
+}
+func GenerateTokenRevocationKey(key string) string {
+	return util.StringJoin([]string{
+		GetRootKey(),
+		"token-revocations",
+		key,
+	}, SPLIT)
+}
 func GenerateRBACSecretKey() string {
 	return util.StringJoin([]string{
 		GetRootKey(),
@@ -44,6 +44,15 @@ func TestGenerateAccountKey(t *testing.T) {
 func TestGenerateAccountSecretKey(t *testing.T) {
 	assert.Equal(t, "/cse-sr/rbac/secret", path.GenerateRBACSecretKey())
 }
+func TestGenerateDependencyDiscoveryKey(t *testing.T) {
+	assert.Equal(t, "/cse-sr/ms/dep-discovery/a/b", path.GetServiceDependencyDiscoveryRootKey("a/b"))
+	assert.Equal(t, "/cse-sr/ms/dep-discovery/a/b/c/d", path.GenerateDependencyDiscoveryKey("a/b", "c", "d"))
+	assert.Equal(t, "/cse-sr/ms/dep-discovery-providers/a/b/d/c", path.GenerateDependencyDiscoveryIndexKey("a/b", "d", "c"))
+}
+func TestGenerateRulePriorityKey(t *testing.T) {
+	assert.Equal(t, "/cse-sr/ms/rule-priorities/a/b", path.GetServiceRulePriorityRootKey("a/b"))
+	assert.Equal(t, "/cse-sr/ms/rule-priorities/a/b/c/d", path.GenerateRulePriorityKey("a/b", "c", "d"))
+}
 func TestGenerateDependencyRuleKey(t *testing.T) {
 	// consumer
 	k := path.GenerateConsumerDependencyRuleKey("a", nil)
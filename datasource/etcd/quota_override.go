@@ -0,0 +1,89 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type QuotaOverrideManager struct {
+}
+
+func (ds *QuotaOverrideManager) GetQuotaOverride(ctx context.Context, domain, project string) (*datasource.QuotaOverride, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateQuotaOverrideKey(domain, project)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrQuotaOverrideNotExist
+	}
+	q := &datasource.QuotaOverride{}
+	err = json.Unmarshal(resp.Kvs[0].Value, q)
+	if err != nil {
+		log.Errorf(err, "quota override format invalid")
+		return nil, err
+	}
+	return q, nil
+}
+
+func (ds *QuotaOverrideManager) ListQuotaOverride(ctx context.Context) ([]*datasource.QuotaOverride, error) {
+	kvs, _, err := client.List(ctx, path.GetQuotaOverrideRootKey()+path.SPLIT)
+	if err != nil {
+		return nil, err
+	}
+	qs := make([]*datasource.QuotaOverride, 0, len(kvs))
+	for _, kv := range kvs {
+		q := &datasource.QuotaOverride{}
+		err = json.Unmarshal(kv.Value, q)
+		if err != nil {
+			log.Error("quota override format invalid:", err)
+			continue
+		}
+		qs = append(qs, q)
+	}
+	return qs, nil
+}
+
+func (ds *QuotaOverrideManager) UpsertQuotaOverride(ctx context.Context, q *datasource.QuotaOverride) error {
+	value, err := json.Marshal(q)
+	if err != nil {
+		log.Errorf(err, "quota override is invalid")
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateQuotaOverrideKey(q.Domain, q.Project), value)
+	if err != nil {
+		log.Errorf(err, "can not save quota override")
+		return err
+	}
+	return nil
+}
+
+func (ds *QuotaOverrideManager) DeleteQuotaOverride(ctx context.Context, domain, project string) error {
+	_, err := client.Delete(ctx, path.GenerateQuotaOverrideKey(domain, project))
+	if err != nil {
+		log.Error(fmt.Sprintf("remove quota override %s/%s failed", domain, project), err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type RoleInheritanceManager struct {
+}
+
+func (ds *RoleInheritanceManager) GetRoleInheritance(ctx context.Context, role string) (*datasource.RoleInheritance, error) {
+	resp, err := client.Instance().Do(ctx, client.GET,
+		client.WithStrKey(path.GenerateRoleInheritanceKey(role)))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return nil, datasource.ErrRoleInheritanceNotExist
+	}
+	r := &datasource.RoleInheritance{}
+	err = json.Unmarshal(resp.Kvs[0].Value, r)
+	if err != nil {
+		log.Errorf(err, "role inheritance format invalid")
+		return nil, err
+	}
+	return r, nil
+}
+
+func (ds *RoleInheritanceManager) ListRoleInheritance(ctx context.Context) ([]*datasource.RoleInheritance, error) {
+	kvs, _, err := client.List(ctx, path.GenerateRoleInheritanceKey(""))
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]*datasource.RoleInheritance, 0, len(kvs))
+	for _, kv := range kvs {
+		r := &datasource.RoleInheritance{}
+		err = json.Unmarshal(kv.Value, r)
+		if err != nil {
+			log.Error("role inheritance format invalid:", err)
+			continue
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+func (ds *RoleInheritanceManager) UpsertRoleInheritance(ctx context.Context, r *datasource.RoleInheritance) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		log.Errorf(err, "role inheritance is invalid")
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateRoleInheritanceKey(r.Role), value)
+	if err != nil {
+		log.Errorf(err, "can not save role inheritance")
+		return err
+	}
+	return nil
+}
+
+func (ds *RoleInheritanceManager) DeleteRoleInheritance(ctx context.Context, role string) error {
+	_, err := client.Delete(ctx, path.GenerateRoleInheritanceKey(role))
+	if err != nil {
+		log.Error(fmt.Sprintf("remove role inheritance %s failed", role), err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/client"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type SessionManager struct {
+}
+
+func (ds *SessionManager) CreateSession(ctx context.Context, s *datasource.Session) error {
+	value, err := json.Marshal(s)
+	if err != nil {
+		log.Errorf(err, "session is invalid")
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateSessionKey(s.Account, s.ID), value)
+	if err != nil {
+		log.Errorf(err, "can not save session")
+		return err
+	}
+	return nil
+}
+
+func (ds *SessionManager) ListSession(ctx context.Context, account string) ([]*datasource.Session, error) {
+	// the prefix ends with '/', so the other accounts with the same name prefix are excluded
+	prefix := path.GenerateSessionKey(account, "")
+	if len(account) == 0 {
+		prefix = strings.TrimSuffix(prefix, path.SPLIT)
+	}
+	kvs, _, err := client.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*datasource.Session, 0, len(kvs))
+	for _, kv := range kvs {
+		s := &datasource.Session{}
+		err = json.Unmarshal(kv.Value, s)
+		if err != nil {
+			log.Error("session format invalid:", err)
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+func (ds *SessionManager) DeleteSession(ctx context.Context, account, id string) error {
+	_, err := client.Delete(ctx, path.GenerateSessionKey(account, id))
+	if err != nil {
+		log.Error(fmt.Sprintf("remove session %s failed", id), err)
+		return err
+	}
+	return nil
+}
+
+func (ds *SessionManager) Revoke(ctx context.Context, r *datasource.Revocation) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		log.Errorf(err, "revocation is invalid")
+		return err
+	}
+	err = client.PutBytes(ctx, path.GenerateTokenRevocationKey(r.Key), value)
+	if err != nil {
+		log.Errorf(err, "can not save revocation")
+		return err
+	}
+	return nil
+}
+
+func (ds *SessionManager) ListRevocation(ctx context.Context) ([]*datasource.Revocation, error) {
+	kvs, _, err := client.List(ctx, path.GenerateTokenRevocationKey(""))
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]*datasource.Revocation, 0, len(kvs))
+	for _, kv := range kvs {
+		r := &datasource.Revocation{}
+		err = json.Unmarshal(kv.Value, r)
+		if err != nil {
+			log.Error("revocation format invalid:", err)
+			continue
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+func (ds *SessionManager) DeleteRevocation(ctx context.Context, key string) error {
+	_, err := client.Delete(ctx, path.GenerateTokenRevocationKey(key))
+	if err != nil {
+		log.Error(fmt.Sprintf("remove revocation %s failed", key), err)
+		return err
+	}
+	return nil
+}
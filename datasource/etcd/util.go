@@ -155,6 +155,20 @@ func commitSchemaInfo(domainProject string, serviceID string, schema *pb.Schema)
 
 // instance util
 func preProcessRegisterInstance(ctx context.Context, instance *pb.MicroServiceInstance) *errsvc.Error {
+	if err := setInstanceDefaultValue(ctx, instance); err != nil {
+		return err
+	}
+	domainProject := util.ParseDomainProject(ctx)
+	microservice, err := serviceUtil.GetService(ctx, domainProject, instance.ServiceId)
+	if err != nil {
+		return pb.NewError(pb.ErrServiceNotExists, "Invalid 'serviceID' in request body.")
+	}
+	instance.Version = microservice.Version
+	return nil
+}
+
+// setInstanceDefaultValue sets the default status, id, timestamps and health check of the instance
+func setInstanceDefaultValue(ctx context.Context, instance *pb.MicroServiceInstance) *errsvc.Error {
 	if len(instance.Status) == 0 {
 		instance.Status = pb.MSI_UP
 	}
@@ -189,13 +203,6 @@ func preProcessRegisterInstance(ctx context.Context, instance *pb.MicroServiceIn
 			instance.HealthCheck.Times = retryTimes
 		}
 	}
-
-	domainProject := util.ParseDomainProject(ctx)
-	microservice, err := serviceUtil.GetService(ctx, domainProject, instance.ServiceId)
-	if err != nil {
-		return pb.NewError(pb.ErrServiceNotExists, "Invalid 'serviceID' in request body.")
-	}
-	instance.Version = microservice.Version
 	return nil
 }
 
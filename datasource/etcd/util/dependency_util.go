@@ -70,6 +70,12 @@ func GetAllConsumerIds(ctx context.Context, domainProject string, provider *pb.M
 		DomainProject: domainProject,
 		ProviderRules: providerRules,
 	}
+	if len(providerRules) > 0 {
+		rf.Priorities, err = GetRulePriorities(ctx, domainProject, provider.ServiceId)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 
 	allow, deny, err = GetConsumerIdsWithFilter(ctx, domainProject, provider, rf)
 	if err != nil {
@@ -109,6 +115,10 @@ func GetAllProviderIds(ctx context.Context, domainProject string, service *pb.Mi
 			continue
 		}
 		rf.ProviderRules = providerRules
+		rf.Priorities, err = GetRulePriorities(copyCtx, domainProject, providerID)
+		if err != nil {
+			return nil, nil, err
+		}
 		ok, err := rf.Filter(ctx, service.ServiceId)
 		if err != nil {
 			return nil, nil, err
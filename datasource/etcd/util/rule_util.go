@@ -21,8 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
-	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/go-chassis/cari/discovery"
@@ -39,6 +38,8 @@ import (
 type RuleFilter struct {
 	DomainProject string
 	ProviderRules []*discovery.ServiceRule
+	// Priorities are the priorities of the provider rules, the key is the ruleId
+	Priorities map[string]int32
 }
 
 func (rf *RuleFilter) Filter(ctx context.Context, consumerID string) (bool, error) {
@@ -56,7 +57,8 @@ func (rf *RuleFilter) Filter(ctx context.Context, consumerID string) (bool, erro
 	if err != nil {
 		return false, err
 	}
-	matchErr := MatchRules(rf.ProviderRules, consumer, tags)
+	matchErr := matchRules(datasource.NewRules(rf.ProviderRules, rf.Priorities),
+		&datasource.RuleSubject{Consumer: consumer, Tags: tags, IP: util.GetIPFromContext(ctx)})
 	if matchErr != nil {
 		if matchErr.Code == discovery.ErrPermissionDeny {
 			return false, nil
@@ -110,6 +112,25 @@ func GetRulesUtil(ctx context.Context, domainProject string, serviceID string) (
 	return rules, nil
 }
 
+// GetRulePriorities returns the priorities of the service rules, the key is the ruleId
+func GetRulePriorities(ctx context.Context, domainProject string, serviceID string) (map[string]int32, error) {
+	kvs, _, err := client.List(ctx, path.GenerateRulePriorityKey(domainProject, serviceID, ""))
+	if err != nil {
+		return nil, err
+	}
+	priorities := make(map[string]int32, len(kvs))
+	for _, kv := range kvs {
+		key := util.BytesToStringWithNoCopy(kv.Key)
+		priority, err := strconv.ParseInt(util.BytesToStringWithNoCopy(kv.Value), 10, 32)
+		if err != nil {
+			log.Errorf(err, "rule priority[%s] value is invalid", key)
+			continue
+		}
+		priorities[key[strings.LastIndex(key, path.SPLIT)+1:]] = int32(priority)
+	}
+	return priorities, nil
+}
+
 func RuleExist(ctx context.Context, domainProject string, serviceID string, attr string, pattern string) bool {
 	opts := append(FromContext(ctx),
 		client.WithStrKey(path.GenerateRuleIndexKey(domainProject, serviceID, attr, pattern)),
@@ -121,6 +142,22 @@ func RuleExist(ctx context.Context, domainProject string, serviceID string, attr
 	return true
 }
 
+// GetRuleIDByIndex returns the id of the service rule with the attribute and pattern, empty if not exist
+func GetRuleIDByIndex(ctx context.Context, domainProject string, serviceID string, attr string, pattern string) (string, error) {
+	opts := append(FromContext(ctx),
+		client.WithStrKey(path.GenerateRuleIndexKey(domainProject, serviceID, attr, pattern)))
+	resp, err := kv.Store().RuleIndex().Search(ctx, opts...)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Kvs) == 0 {
+		return "", nil
+	}
+	return resp.Kvs[0].Value.(string), nil
+}
+
+// GetServiceRuleType returns the type and the number of the service legacy rules,
+// the rules of type ALLOW or DENY are ignored
 func GetServiceRuleType(ctx context.Context, domainProject string, serviceID string) (string, int, error) {
 	key := path.GenerateServiceRuleKey(domainProject, serviceID, "")
 	opts := append(FromContext(ctx),
@@ -131,10 +168,19 @@ func GetServiceRuleType(ctx context.Context, domainProject string, serviceID str
 		log.Errorf(err, "get service[%s] rule failed", serviceID)
 		return "", 0, err
 	}
-	if len(resp.Kvs) == 0 {
-		return "", 0, nil
+	var (
+		ruleType string
+		ruleNum  int
+	)
+	for _, kv := range resp.Kvs {
+		rule := kv.Value.(*discovery.ServiceRule)
+		if !datasource.IsLegacyRuleType(rule.RuleType) {
+			continue
+		}
+		ruleType = rule.RuleType
+		ruleNum++
 	}
-	return resp.Kvs[0].Value.(*discovery.ServiceRule).RuleType, len(resp.Kvs), nil
+	return ruleType, ruleNum, nil
 }
 
 func GetOneRule(ctx context.Context, domainProject, serviceID, ruleID string) (*discovery.ServiceRule, error) {
@@ -153,100 +199,37 @@ func GetOneRule(ctx context.Context, domainProject, serviceID, ruleID string) (*
 }
 
 func AllowAcrossDimension(ctx context.Context, providerService *discovery.MicroService, consumerService *discovery.MicroService) error {
-	if providerService.AppId != consumerService.AppId {
-		if len(providerService.Properties) == 0 {
-			return fmt.Errorf("not allow across app access")
-		}
-
-		if allowCrossApp, ok := providerService.Properties[discovery.PropAllowCrossApp]; !ok || strings.ToLower(allowCrossApp) != "true" {
-			return fmt.Errorf("not allow across app access")
-		}
-	}
-
-	if !datasource.IsGlobal(discovery.MicroServiceToKey(util.ParseTargetDomainProject(ctx), providerService)) &&
-		providerService.Environment != consumerService.Environment {
-		return fmt.Errorf("not allow across environment access")
-	}
-
-	return nil
+	return datasource.AllowAcrossDimension(ctx, providerService, consumerService)
 }
 
 func MatchRules(rulesOfProvider []*discovery.ServiceRule, consumer *discovery.MicroService, tagsOfConsumer map[string]string) *errsvc.Error {
-	if consumer == nil {
+	return matchRules(datasource.NewRules(rulesOfProvider, nil), &datasource.RuleSubject{Consumer: consumer, Tags: tagsOfConsumer})
+}
+
+func matchRules(rulesOfProvider []*datasource.Rule, subject *datasource.RuleSubject) *errsvc.Error {
+	if subject.Consumer == nil {
 		return discovery.NewError(discovery.ErrInvalidParams, "consumer is nil")
 	}
 
 	if len(rulesOfProvider) <= 0 {
 		return nil
 	}
-	if rulesOfProvider[0].RuleType == "WHITE" {
-		return patternWhiteList(rulesOfProvider, tagsOfConsumer, consumer)
-	}
-	return patternBlackList(rulesOfProvider, tagsOfConsumer, consumer)
-}
-
-func patternWhiteList(rulesOfProvider []*discovery.ServiceRule, tagsOfConsumer map[string]string, consumer *discovery.MicroService) *errsvc.Error {
-	v := reflect.Indirect(reflect.ValueOf(consumer))
-	consumerID := consumer.ServiceId
-	for _, rule := range rulesOfProvider {
-		value, err := parsePattern(v, rule, tagsOfConsumer, consumerID)
-		if err != nil {
-			return err
-		}
-		if len(value) == 0 {
-			continue
-		}
-
-		match, _ := regexp.MatchString(rule.Pattern, value)
-		if match {
-			log.Infof("consumer[%s][%s/%s/%s/%s] match white list, rule.Pattern is %s, value is %s",
-				consumerID, consumer.Environment, consumer.AppId, consumer.ServiceName, consumer.Version,
-				rule.Pattern, value)
-			return nil
-		}
-	}
-	return discovery.NewError(discovery.ErrPermissionDeny, "Not found in white list")
-}
-
-func parsePattern(v reflect.Value, rule *discovery.ServiceRule, tagsOfConsumer map[string]string, consumerID string) (string, *errsvc.Error) {
-	if strings.HasPrefix(rule.Attribute, "tag_") {
-		key := rule.Attribute[4:]
-		value := tagsOfConsumer[key]
-		if len(value) == 0 {
-			log.Infof("can not find service[%s] tag[%s]", consumerID, key)
-		}
-		return value, nil
+	decision, err := datasource.EvaluateRules(rulesOfProvider, subject, false)
+	if err != nil {
+		log.Errorf(err, "evaluate consumer[%s] rules failed", subject.Consumer.ServiceId)
+		return discovery.NewError(discovery.ErrInternal, err.Error())
 	}
-	key := v.FieldByName(rule.Attribute)
-	if !key.IsValid() {
-		log.Errorf(nil, "can not find service[%] field[%s], ruleID is %s",
-			consumerID, rule.Attribute, rule.RuleId)
-		return "", discovery.NewError(discovery.ErrInternal, fmt.Sprintf("Can not find field '%s'", rule.Attribute))
+	consumer := subject.Consumer
+	if !decision.Allowed {
+		log.Warnf("no permission to access, consumer[%s][%s/%s/%s/%s] ip[%s], rule[%s]: %s",
+			consumer.ServiceId, consumer.Environment, consumer.AppId, consumer.ServiceName, consumer.Version,
+			subject.IP, decision.RuleID, decision.Reason)
+		return discovery.NewError(discovery.ErrPermissionDeny, decision.Reason)
 	}
-	return key.String(), nil
-
-}
-
-func patternBlackList(rulesOfProvider []*discovery.ServiceRule, tagsOfConsumer map[string]string, consumer *discovery.MicroService) *errsvc.Error {
-	v := reflect.Indirect(reflect.ValueOf(consumer))
-	consumerID := consumer.ServiceId
-	for _, rule := range rulesOfProvider {
-		var value string
-		value, err := parsePattern(v, rule, tagsOfConsumer, consumerID)
-		if err != nil {
-			return err
-		}
-		if len(value) == 0 {
-			continue
-		}
-
-		match, _ := regexp.MatchString(rule.Pattern, value)
-		if match {
-			log.Warnf("no permission to access, consumer[%s][%s/%s/%s/%s] match black list, rule.Pattern is %s, value is %s",
-				consumerID, consumer.Environment, consumer.AppId, consumer.ServiceName, consumer.Version,
-				rule.Pattern, value)
-			return discovery.NewError(discovery.ErrPermissionDeny, "Found in black list")
-		}
+	if len(decision.RuleID) > 0 {
+		log.Infof("consumer[%s][%s/%s/%s/%s] ip[%s] is allowed, rule[%s]: %s",
+			consumer.ServiceId, consumer.Environment, consumer.AppId, consumer.ServiceName, consumer.Version,
+			subject.IP, decision.RuleID, decision.Reason)
 	}
 	return nil
 }
@@ -298,5 +281,14 @@ func Accessible(ctx context.Context, consumerID string, providerID string) *errs
 		return discovery.NewError(discovery.ErrInternal, fmt.Sprintf("An error occurred in query consumer tags(%s)", err.Error()))
 	}
 
-	return MatchRules(rules, consumerService, validateTags)
+	priorities, err := GetRulePriorities(ctx, targetDomainProject, providerID)
+	if err != nil {
+		return discovery.NewError(discovery.ErrInternal, fmt.Sprintf("An error occurred in query provider rule priorities(%s)", err.Error()))
+	}
+
+	return matchRules(datasource.NewRules(rules, priorities), &datasource.RuleSubject{
+		Consumer: consumerService,
+		Tags:     validateTags,
+		IP:       util.GetIPFromContext(ctx),
+	})
 }
@@ -84,6 +84,30 @@ func GetAccountManager() AccountManager {
 func GetAccountLockManager() AccountLockManager {
 	return dataSourceInst.AccountLockManager()
 }
+func GetRoleInheritanceManager() RoleInheritanceManager {
+	return dataSourceInst.RoleInheritanceManager()
+}
+func GetAuditLogManager() AuditLogManager {
+	return dataSourceInst.AuditLogManager()
+}
+func GetQuotaOverrideManager() QuotaOverrideManager {
+	return dataSourceInst.QuotaOverrideManager()
+}
+func GetAlarmManager() AlarmManager {
+	return dataSourceInst.AlarmManager()
+}
+func GetAccountStateManager() AccountStateManager {
+	return dataSourceInst.AccountStateManager()
+}
+func GetTOTPManager() TOTPManager {
+	return dataSourceInst.TOTPManager()
+}
+func GetAPIKeyManager() APIKeyManager {
+	return dataSourceInst.APIKeyManager()
+}
+func GetSessionManager() SessionManager {
+	return dataSourceInst.SessionManager()
+}
 func GetDependencyManager() DependencyManager {
 	return dataSourceInst.DependencyManager()
 }
@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type AccountStateManager struct {
+}
+
+func (ds *AccountStateManager) GetAccountState(ctx context.Context, name string) (*datasource.AccountState, error) {
+	filter := mutil.NewFilter(mutil.AccountStateAccount(name))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionAccountState, filter)
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			return nil, datasource.ErrAccountStateNotExist
+		}
+		return nil, result.Err()
+	}
+	var s datasource.AccountState
+	err = result.Decode(&s)
+	if err != nil {
+		log.Error("failed to decode account state", err)
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (ds *AccountStateManager) UpsertAccountState(ctx context.Context, s *datasource.AccountState) error {
+	filter := mutil.NewFilter(mutil.AccountStateAccount(s.Account))
+	result, err := client.GetMongoClient().FindOneAndUpdate(ctx, model.CollectionAccountState, filter,
+		mutil.NewFilter(mutil.Set(s)), options.FindOneAndUpdate().SetUpsert(true))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save account state %s", s.Account), err)
+		return err
+	}
+	if result.Err() != nil && result.Err() != mongo.ErrNoDocuments {
+		log.Error(fmt.Sprintf("can not save account state %s", s.Account), result.Err())
+		return result.Err()
+	}
+	return nil
+}
+
+func (ds *AccountStateManager) DeleteAccountState(ctx context.Context, name string) error {
+	filter := mutil.NewFilter(mutil.AccountStateAccount(name))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionAccountState, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("remove account state %s failed", name), err)
+		return err
+	}
+	return nil
+}
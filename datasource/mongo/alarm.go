@@ -0,0 +1,165 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	alarmmodel "github.com/apache/servicecomb-service-center/server/alarm/model"
+)
+
+type AlarmManager struct {
+}
+
+func (ds *AlarmManager) AddAlarmHistory(ctx context.Context, h *alarmmodel.History) error {
+	_, err := client.GetMongoClient().Insert(ctx, model.CollectionAlarmHistory, h)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save alarm history %s", h.ID), err)
+		return err
+	}
+	return nil
+}
+
+func (ds *AlarmManager) ListAlarmHistory(ctx context.Context, f *alarmmodel.HistoryFilter) ([]*alarmmodel.History, error) {
+	var opts []mutil.Option
+	if len(f.AlarmID) > 0 {
+		opts = append(opts, mutil.AlarmID(string(f.AlarmID)))
+	}
+	if len(f.Status) > 0 {
+		opts = append(opts, mutil.Status(string(f.Status)))
+	}
+	if len(f.Instance) > 0 {
+		opts = append(opts, mutil.AlarmInstance(f.Instance))
+	}
+	timestamp := bson.M{}
+	if f.Start > 0 {
+		timestamp["$gte"] = f.Start
+	}
+	if f.End > 0 {
+		timestamp["$lte"] = f.End
+	}
+	if len(timestamp) > 0 {
+		opts = append(opts, mutil.Timestamp(timestamp))
+	}
+	filter := mutil.NewFilter(opts...)
+	opt := options.Find().SetSort(bson.M{model.ColumnTimestamp: -1})
+	if f.Limit > 0 {
+		opt.SetLimit(int64(f.Limit))
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAlarmHistory, filter, opt)
+	if err != nil {
+		return nil, err
+	}
+	hs := make([]*alarmmodel.History, 0)
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var h alarmmodel.History
+		err = cursor.Decode(&h)
+		if err != nil {
+			log.Error("failed to decode alarm history", err)
+			continue
+		}
+		hs = append(hs, &h)
+	}
+	return hs, nil
+}
+
+func (ds *AlarmManager) DeleteAlarmHistory(ctx context.Context, before int64) error {
+	filter := mutil.NewFilter(mutil.Timestamp(bson.M{"$lt": before}))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionAlarmHistory, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("remove alarm history before %d failed", before), err)
+		return err
+	}
+	return nil
+}
+
+func (ds *AlarmManager) PutAlarmState(ctx context.Context, s *alarmmodel.State) error {
+	filter := mutil.NewFilter(
+		mutil.AlarmID(string(s.AlarmID)),
+		mutil.AlarmInstance(s.Instance),
+		mutil.Timestamp(bson.M{"$lte": s.Timestamp}),
+	)
+	_, err := client.GetMongoClient().Update(ctx, model.CollectionAlarmState, filter,
+		mutil.NewFilter(mutil.Set(s)), options.Update().SetUpsert(true))
+	if err != nil {
+		if client.IsDuplicateKey(err) {
+			// the saved state is newer
+			return nil
+		}
+		log.Error(fmt.Sprintf("can not save alarm[%s] state of instance[%s]", s.AlarmID, s.Instance), err)
+		return err
+	}
+	return nil
+}
+
+func (ds *AlarmManager) ListAlarmStates(ctx context.Context) ([]*alarmmodel.State, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAlarmState, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	states := make([]*alarmmodel.State, 0)
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var s alarmmodel.State
+		err = cursor.Decode(&s)
+		if err != nil {
+			log.Error("failed to decode alarm state", err)
+			continue
+		}
+		states = append(states, &s)
+	}
+	return states, nil
+}
+
+func (ds *AlarmManager) AckAlarmState(ctx context.Context, s *alarmmodel.State, ack *alarmmodel.Ack) (bool, error) {
+	result, err := client.GetMongoClient().Update(ctx, model.CollectionAlarmState, alarmStateFilter(s),
+		mutil.NewFilter(mutil.Set(bson.M{model.ColumnAlarmAck: ack})))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not ack alarm[%s] of instance[%s]", s.AlarmID, s.Instance), err)
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (ds *AlarmManager) DeleteAlarmState(ctx context.Context, s *alarmmodel.State) error {
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionAlarmState, alarmStateFilter(s))
+	if err != nil {
+		log.Error(fmt.Sprintf("remove alarm[%s] state of instance[%s] failed", s.AlarmID, s.Instance), err)
+		return err
+	}
+	return nil
+}
+
+// alarmStateFilter matches the saved state if it is not changed since s
+func alarmStateFilter(s *alarmmodel.State) bson.M {
+	return mutil.NewFilter(
+		mutil.AlarmID(string(s.AlarmID)),
+		mutil.AlarmInstance(s.Instance),
+		mutil.Status(string(s.Status)),
+		mutil.Timestamp(s.Timestamp),
+	)
+}
@@ -0,0 +1,126 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type APIKeyManager struct {
+}
+
+func (ds *APIKeyManager) CreateAPIKey(ctx context.Context, k *datasource.APIKey) error {
+	k.CreateTime = strconv.FormatInt(time.Now().Unix(), 10)
+	k.UpdateTime = k.CreateTime
+	_, err := client.GetMongoClient().Insert(ctx, model.CollectionAPIKey, k)
+	if err != nil {
+		if client.IsDuplicateKey(err) {
+			return datasource.ErrAPIKeyDuplicated
+		}
+		return err
+	}
+	return nil
+}
+
+func (ds *APIKeyManager) GetAPIKey(ctx context.Context, id string) (*datasource.APIKey, error) {
+	filter := mutil.NewFilter(mutil.ID(id))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionAPIKey, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to query api key %s", id), err)
+		return nil, err
+	}
+	if err = result.Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, datasource.ErrAPIKeyNotExist
+		}
+		log.Error(fmt.Sprintf("failed to query api key %s", id), err)
+		return nil, err
+	}
+	var k datasource.APIKey
+	err = result.Decode(&k)
+	if err != nil {
+		log.Error("failed to decode api key", err)
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (ds *APIKeyManager) ListAPIKey(ctx context.Context, account string) ([]*datasource.APIKey, error) {
+	filter := mutil.NewFilter()
+	if len(account) > 0 {
+		filter = mutil.NewFilter(mutil.APIKeyAccount(account))
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAPIKey, filter)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*datasource.APIKey, 0)
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var k datasource.APIKey
+		err = cursor.Decode(&k)
+		if err != nil {
+			log.Error("failed to decode api key", err)
+			continue
+		}
+		keys = append(keys, &k)
+	}
+	return keys, nil
+}
+
+func (ds *APIKeyManager) UpdateAPIKey(ctx context.Context, k *datasource.APIKey) error {
+	filter := mutil.NewFilter(mutil.ID(k.ID))
+	k.UpdateTime = strconv.FormatInt(time.Now().Unix(), 10)
+	setFilter := mutil.NewFilter(
+		mutil.APIKeyName(k.Name),
+		mutil.APIKeyHash(k.Hash),
+		mutil.Roles(k.Roles),
+		mutil.APIKeyExpireAt(k.ExpireAt),
+		mutil.APIKeyUpdateTime(k.UpdateTime),
+	)
+	updateFilter := mutil.NewFilter(mutil.Set(setFilter))
+	res, err := client.GetMongoClient().Update(ctx, model.CollectionAPIKey, filter, updateFilter)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return datasource.ErrAPIKeyNotExist
+	}
+	return nil
+}
+
+func (ds *APIKeyManager) DeleteAPIKey(ctx context.Context, id string) error {
+	filter := mutil.NewFilter(mutil.ID(id))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionAPIKey, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("remove api key %s failed", id), err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/dao"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/heartbeat"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	apt "github.com/apache/servicecomb-service-center/server/core"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
+	"github.com/apache/servicecomb-service-center/server/plugin/uuid"
+)
+
+// ApplyService compares the document with the stored service and applies the changes in one mongo transaction
+func (ds *MetadataManager) ApplyService(ctx context.Context, doc *datasource.ServiceDocument) (*datasource.ApplyServiceResponse, error) {
+	remoteIP := util.GetIPFromContext(ctx)
+	domain := util.ParseDomain(ctx)
+	project := util.ParseProject(ctx)
+	service := doc.Service
+	serviceFlag := util.StringJoin([]string{
+		service.Environment, service.AppId, service.ServiceName, service.Version}, "/")
+
+	state, checkErr := datasource.LoadServiceState(ctx, ds, doc)
+	if checkErr != nil {
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, load service failed, operator: %s",
+			serviceFlag, remoteIP), checkErr)
+		return applyFailed(checkErr)
+	}
+	if state.Service == nil && len(service.ServiceId) == 0 {
+		ctx := util.SetContext(ctx, uuid.ContextKey, util.StringJoin([]string{domain, project, service.Environment, service.AppId, service.ServiceName, service.Alias, service.Version}, "/"))
+		service.ServiceId = uuid.Generator().GetServiceID(ctx)
+	}
+	for _, instance := range doc.Instances {
+		if checkErr := setInstanceDefaultValue(ctx, instance); checkErr != nil {
+			log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), checkErr)
+			return applyFailed(checkErr)
+		}
+	}
+
+	plan, checkErr := datasource.PlanServiceDocument(doc, state, ds.isSchemaEditable())
+	resp := &datasource.ApplyServiceResponse{
+		ServiceID: plan.ServiceID,
+		Items:     plan.Items,
+	}
+	if checkErr != nil {
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), checkErr)
+		resp.Response = discovery.CreateResponseWithSCErr(checkErr)
+		return resp, nil
+	}
+
+	if checkErr = checkApplyQuota(ctx, util.ParseDomainProject(ctx), plan); checkErr != nil {
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), checkErr)
+		plan.Abort(checkErr.Error())
+		resp.Response = discovery.CreateResponseWithSCErr(checkErr)
+		if checkErr.InternalError() {
+			return resp, checkErr
+		}
+		return resp, nil
+	}
+
+	if doc.DryRun {
+		resp.Response = discovery.CreateResponse(discovery.ResponseSuccess, "Plan service successfully.")
+		return resp, nil
+	}
+
+	err := client.GetMongoClient().ExecTxn(ctx, func(sc mongo.SessionContext) error {
+		return applyServicePlan(sc, domain, project, plan)
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP), err)
+		plan.Abort(err.Error())
+		if client.IsDuplicateKey(err) {
+			resp.Response = discovery.CreateResponse(discovery.ErrServiceAlreadyExists,
+				"ServiceID conflict or found the same service with different id.")
+			return resp, nil
+		}
+		if err == client.ErrNoDocuments {
+			resp.Response = discovery.CreateResponse(discovery.ErrServiceNotExists, "Service does not exist.")
+			return resp, nil
+		}
+		resp.Response = discovery.CreateResponse(discovery.ErrUnavailableBackend, err.Error())
+		return resp, err
+	}
+
+	// need to complete the instance offline function in time, so you need to check the heartbeat after registering the instance
+	for _, instance := range plan.Instances {
+		if err := heartbeat.Instance().CheckInstance(ctx, instance); err != nil {
+			log.Error(fmt.Sprintf("fail to check instance, instance[%s]. operator %s", instance.InstanceId, remoteIP), err)
+		}
+	}
+
+	log.Info(fmt.Sprintf("apply micro-service[%s][%s] successfully, operator: %s", plan.ServiceID, serviceFlag, remoteIP))
+	resp.Response = discovery.CreateResponse(discovery.ResponseSuccess, "Apply service successfully.")
+	return resp, nil
+}
+
+func applyServicePlan(ctx context.Context, domain, project string, plan *datasource.ServiceApplyPlan) error {
+	serviceID := plan.ServiceID
+	if plan.CreateService {
+		_, err := client.GetMongoClient().Insert(ctx, model.CollectionService,
+			&model.Service{Domain: domain, Project: project, Tags: plan.Tags, Service: plan.Service})
+		if err != nil {
+			return err
+		}
+	} else {
+		setFilter := bson.M{}
+		if plan.Service != nil {
+			setFilter[model.ColumnService] = plan.Service
+		}
+		if plan.Tags != nil {
+			setFilter[model.ColumnTag] = plan.Tags
+		}
+		if len(setFilter) > 0 {
+			filter := mutil.NewDomainProjectFilter(domain, project, mutil.ServiceServiceID(serviceID))
+			if err := dao.UpdateService(ctx, filter, mutil.NewFilter(mutil.Set(setFilter))); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, schema := range plan.Schemas {
+		filter := mutil.NewDomainProjectFilter(domain, project, mutil.ServiceID(serviceID), mutil.SchemaID(schema.SchemaId))
+		setFilter := mutil.NewFilter(
+			mutil.Schema(schema.Schema),
+			mutil.SchemaSummary(schema.Summary),
+		)
+		updateFilter := mutil.NewFilter(mutil.Set(setFilter))
+		err := dao.UpdateSchema(ctx, filter, updateFilter, options.FindOneAndUpdate().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range plan.UpdatedRules {
+		filter := mutil.NewDomainProjectFilter(domain, project, mutil.ServiceID(serviceID), mutil.RuleRuleID(rule.RuleId))
+		setFilter := mutil.NewFilter(
+			mutil.RuleRuleType(rule.RuleType),
+			mutil.RuleDescription(rule.Description),
+			mutil.RuleModTime(rule.ModTimestamp),
+		)
+		if err := dao.UpdateRule(ctx, filter, mutil.NewFilter(mutil.Set(setFilter))); err != nil {
+			return err
+		}
+	}
+	for _, rule := range plan.Rules {
+		_, err := client.GetMongoClient().Insert(ctx, model.CollectionRule,
+			&model.Rule{Domain: domain, Project: project, ServiceID: serviceID, Rule: rule})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, instance := range plan.Instances {
+		_, err := client.GetMongoClient().Insert(ctx, model.CollectionInstance, &model.Instance{
+			Domain:      domain,
+			Project:     project,
+			RefreshTime: time.Now(),
+			Instance:    instance,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkApplyQuota(ctx context.Context, domainProject string, plan *datasource.ServiceApplyPlan) *errsvc.Error {
+	resources := []*quota.ApplyQuotaResource{
+		quota.NewApplyQuotaResource(quota.TypeSchema, domainProject, plan.ServiceID,
+			plan.Count(datasource.ApplyKindSchema, datasource.ApplyActionCreated)),
+		quota.NewApplyQuotaResource(quota.TypeRule, domainProject, plan.ServiceID,
+			plan.Count(datasource.ApplyKindRule, datasource.ApplyActionCreated)),
+	}
+	if !apt.IsSCInstance(ctx) {
+		if plan.CreateService {
+			resources = append(resources, quota.NewApplyQuotaResource(quota.TypeService, domainProject, "", 1))
+		}
+		resources = append(resources, quota.NewApplyQuotaResource(quota.TypeInstance, domainProject, plan.ServiceID,
+			int64(len(plan.Instances))))
+	}
+	for _, res := range resources {
+		if res.QuotaSize <= 0 {
+			continue
+		}
+		if err := quota.Apply(ctx, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyFailed(err *errsvc.Error) (*datasource.ApplyServiceResponse, error) {
+	resp := &datasource.ApplyServiceResponse{
+		Response: discovery.CreateResponseWithSCErr(err),
+	}
+	if err.InternalError() {
+		return resp, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type AuditLogManager struct {
+}
+
+func (ds *AuditLogManager) AddAuditLog(ctx context.Context, l *dump.AuditLog) error {
+	_, err := client.GetMongoClient().Insert(ctx, model.CollectionAuditLog, l)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save audit log %s", l.ID), err)
+		return err
+	}
+	return nil
+}
+
+func (ds *AuditLogManager) ListAuditLog(ctx context.Context, f *dump.AuditLogFilter) ([]*dump.AuditLog, error) {
+	var opts []mutil.Option
+	if len(f.Actor) > 0 {
+		opts = append(opts, mutil.AuditLogActor(f.Actor))
+	}
+	timestamp := bson.M{}
+	if f.Start > 0 {
+		timestamp["$gte"] = f.Start
+	}
+	if f.End > 0 {
+		timestamp["$lte"] = f.End
+	}
+	if len(timestamp) > 0 {
+		opts = append(opts, mutil.Timestamp(timestamp))
+	}
+	filter := mutil.NewFilter(opts...)
+	opt := options.Find().SetSort(bson.M{model.ColumnTimestamp: -1})
+	if f.Limit > 0 {
+		opt.SetLimit(int64(f.Limit))
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionAuditLog, filter, opt)
+	if err != nil {
+		return nil, err
+	}
+	logs := make([]*dump.AuditLog, 0)
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var l dump.AuditLog
+		err = cursor.Decode(&l)
+		if err != nil {
+			log.Error("failed to decode audit log", err)
+			continue
+		}
+		logs = append(logs, &l)
+	}
+	return logs, nil
+}
+
+func (ds *AuditLogManager) DeleteAuditLog(ctx context.Context, before int64) error {
+	filter := mutil.NewFilter(mutil.Timestamp(bson.M{"$lt": before}))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionAuditLog, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("remove audit logs before %d failed", before), err)
+		return err
+	}
+	return nil
+}
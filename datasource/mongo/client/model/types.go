@@ -24,16 +24,28 @@ import (
 )
 
 const (
-	CollectionAccount     = "account"
-	CollectionAccountLock = "account_lock"
-	CollectionService     = "service"
-	CollectionSchema      = "schema"
-	CollectionRule        = "rule"
-	CollectionInstance    = "instance"
-	CollectionDep         = "dependency"
-	CollectionRole        = "role"
-	CollectionDomain      = "domain"
-	CollectionProject     = "project"
+	CollectionAccount         = "account"
+	CollectionAccountLock     = "account_lock"
+	CollectionAPIKey          = "api_key"
+	CollectionAccountState    = "account_state"
+	CollectionRoleInheritance = "role_inheritance"
+	CollectionAuditLog        = "audit_log"
+	CollectionQuotaOverride   = "quota_override"
+	CollectionAlarmHistory    = "alarm_history"
+	CollectionAlarmState      = "alarm_state"
+	CollectionTOTP            = "totp"
+	CollectionSession         = "session"
+	CollectionRevocation      = "token_revocation"
+	CollectionService         = "service"
+	CollectionSchema          = "schema"
+	CollectionRule            = "rule"
+	CollectionInstance        = "instance"
+	CollectionDep             = "dependency"
+	CollectionDepDiscovery    = "dependency_discovery"
+	CollectionJobLock         = "job_lock"
+	CollectionRole            = "role"
+	CollectionDomain          = "domain"
+	CollectionProject         = "project"
 )
 
 const (
@@ -61,6 +73,7 @@ const (
 	ColumnDep                  = "dep"
 	ColumnDependency           = "dependency"
 	ColumnRule                 = "rule"
+	ColumnRulePriority         = "priority"
 	ColumnInstance             = "instance"
 	ColumnInstanceID           = "instance_id"
 	ColumnTenant               = "tenant"
@@ -81,6 +94,26 @@ const (
 	ColumnAccountLockKey       = "key"
 	ColumnAccountLockStatus    = "status"
 	ColumnAccountLockReleaseAt = "release_at"
+	ColumnAccountStateAccount  = "account"
+	ColumnRoleInheritanceRole  = "role"
+	ColumnAuditLogActor        = "actor"
+	ColumnAlarmID              = "alarm_id"
+	ColumnAlarmInstance        = "instance"
+	ColumnAlarmAck             = "ack"
+	ColumnTOTPAccount          = "account"
+	ColumnAPIKeyAccount        = "account"
+	ColumnAPIKeyName           = "name"
+	ColumnAPIKeyHash           = "hash"
+	ColumnAPIKeyExpireAt       = "expire_at"
+	ColumnAPIKeyUpdateTime     = "update_time"
+	ColumnSessionAccount       = "account"
+	ColumnRevocationKey        = "key"
+	ColumnConsumerID           = "consumer_id"
+	ColumnProviderID           = "provider_id"
+	ColumnTimestamp            = "timestamp"
+	ColumnJobLockKey           = "key"
+	ColumnJobLockOwner         = "owner"
+	ColumnJobLockExpireAt      = "expire_at"
 )
 
 type Service struct {
@@ -104,6 +137,7 @@ type Rule struct {
 	Project   string          `json:"project,omitempty"`
 	ServiceID string          `json:"serviceID,omitempty" bson:"service_id"`
 	Rule      *pb.ServiceRule `json:"rule,omitempty"`
+	Priority  int32           `json:"priority,omitempty" bson:"priority,omitempty"`
 }
 
 type Instance struct {
@@ -129,6 +163,14 @@ type DependencyRule struct {
 	Dep        *pb.MicroServiceDependency `json:"dep,omitempty"`
 }
 
+type DependencyDiscovery struct {
+	Domain     string `json:"domain,omitempty"`
+	Project    string `json:"project,omitempty"`
+	ConsumerID string `json:"consumerID,omitempty" bson:"consumer_id"`
+	ProviderID string `json:"providerID,omitempty" bson:"provider_id"`
+	Timestamp  int64  `json:"timestamp,omitempty"`
+}
+
 type DelDepCacheKey struct {
 	Key  *pb.MicroServiceKey
 	Type string
@@ -105,16 +105,14 @@ func (mc *MongoClient) ExecTxn(ctx context.Context, cmd func(sessionContext mong
 	}
 	defer session.EndSession(ctx)
 	if err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
-		if err = cmd(sc); err != nil {
-			if err = session.AbortTransaction(sc); err != nil {
-				return err
-			}
-		} else {
-			if err = session.CommitTransaction(sc); err != nil {
-				return err
+		if err := cmd(sc); err != nil {
+			if abortErr := session.AbortTransaction(sc); abortErr != nil {
+				return abortErr
 			}
+			// the transaction is aborted, return the cause to the caller
+			return err
 		}
-		return nil
+		return session.CommitTransaction(sc)
 	}); err != nil {
 		return err
 	}
@@ -36,7 +36,18 @@ func EnsureDB() {
 	EnsureRule()
 	EnsureSchema()
 	EnsureDep()
+	EnsureDepDiscovery()
+	EnsureJobLock()
 	EnsureAccountLock()
+	EnsureRoleInheritance()
+	EnsureAuditLog()
+	EnsureQuotaOverride()
+	EnsureAlarmHistory()
+	EnsureAlarmState()
+	EnsureAccountState()
+	EnsureTOTP()
+	EnsureAPIKey()
+	EnsureSession()
 }
 
 func EnsureService() {
@@ -94,11 +105,83 @@ func EnsureDep() {
 		model.ColumnServiceKey)})
 }
 
+func EnsureDepDiscovery() {
+	EnsureCollection(model.CollectionDepDiscovery, []mongo.IndexModel{mutil.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject,
+		model.ColumnConsumerID,
+		model.ColumnProviderID), mutil.BuildIndexDoc(
+		model.ColumnDomain,
+		model.ColumnProject,
+		model.ColumnProviderID)})
+}
+
+func EnsureJobLock() {
+	keyIndex := mutil.BuildIndexDoc(model.ColumnJobLockKey)
+	keyIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionJobLock, []mongo.IndexModel{keyIndex})
+}
+
 func EnsureAccountLock() {
 	EnsureCollection(model.CollectionAccountLock, []mongo.IndexModel{
 		mutil.BuildIndexDoc(model.ColumnAccountLockKey)})
 }
 
+func EnsureRoleInheritance() {
+	roleIndex := mutil.BuildIndexDoc(model.ColumnRoleInheritanceRole)
+	roleIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionRoleInheritance, []mongo.IndexModel{roleIndex})
+}
+
+func EnsureAuditLog() {
+	EnsureCollection(model.CollectionAuditLog, []mongo.IndexModel{
+		mutil.BuildIndexDoc(model.ColumnTimestamp), mutil.BuildIndexDoc(model.ColumnAuditLogActor)})
+}
+
+func EnsureAlarmHistory() {
+	EnsureCollection(model.CollectionAlarmHistory, []mongo.IndexModel{
+		mutil.BuildIndexDoc(model.ColumnTimestamp), mutil.BuildIndexDoc(model.ColumnAlarmID)})
+}
+
+func EnsureAlarmState() {
+	stateIndex := mutil.BuildIndexDoc(model.ColumnAlarmID, model.ColumnAlarmInstance)
+	stateIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionAlarmState, []mongo.IndexModel{stateIndex})
+}
+
+func EnsureQuotaOverride() {
+	tenantIndex := mutil.BuildIndexDoc(model.ColumnDomain, model.ColumnProject)
+	tenantIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionQuotaOverride, []mongo.IndexModel{tenantIndex})
+}
+
+func EnsureAccountState() {
+	accountIndex := mutil.BuildIndexDoc(model.ColumnAccountStateAccount)
+	accountIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionAccountState, []mongo.IndexModel{accountIndex})
+}
+
+func EnsureTOTP() {
+	accountIndex := mutil.BuildIndexDoc(model.ColumnTOTPAccount)
+	accountIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionTOTP, []mongo.IndexModel{accountIndex})
+}
+
+func EnsureAPIKey() {
+	idIndex := mutil.BuildIndexDoc(model.ColumnID)
+	idIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionAPIKey, []mongo.IndexModel{
+		idIndex, mutil.BuildIndexDoc(model.ColumnAPIKeyAccount)})
+}
+
+func EnsureSession() {
+	EnsureCollection(model.CollectionSession, []mongo.IndexModel{
+		mutil.BuildIndexDoc(model.ColumnSessionAccount, model.ColumnID)})
+	keyIndex := mutil.BuildIndexDoc(model.ColumnRevocationKey)
+	keyIndex.Options = options.Index().SetUnique(true)
+	EnsureCollection(model.CollectionRevocation, []mongo.IndexModel{keyIndex})
+}
+
 func EnsureCollection(col string, indexes []mongo.IndexModel) {
 	err := client.GetMongoClient().GetDB().CreateCollection(context.Background(), col, options.CreateCollection().SetValidator(nil))
 	wrapCreateCollectionError(err)
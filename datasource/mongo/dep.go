@@ -21,9 +21,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-chassis/cari/discovery"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/apache/servicecomb-service-center/datasource"
@@ -39,7 +42,25 @@ import (
 type DepManager struct {
 }
 
-func (ds *DepManager) SearchProviderDependency(ctx context.Context, request *discovery.GetDependenciesRequest) (*discovery.GetProDependenciesResponse, error) {
+func (ds *DepManager) SearchProviderDependency(ctx context.Context, request *discovery.GetDependenciesRequest) (*datasource.ProviderDependencyResponse, error) {
+	resp, err := ds.searchProviderDependency(ctx, request)
+	if resp == nil {
+		return nil, err
+	}
+	result := &datasource.ProviderDependencyResponse{GetProDependenciesResponse: resp}
+	if resp.Response.GetCode() != discovery.ResponseSuccess {
+		return result, err
+	}
+	records, err := ds.ListDiscovery(ctx, "", request.ServiceId)
+	if err != nil {
+		log.Error(fmt.Sprintf("list provider[%s] dependency discoveries failed", request.ServiceId), err)
+		return result, nil
+	}
+	result.LastDiscovered = datasource.DiscoveredTimes(records, false)
+	return result, nil
+}
+
+func (ds *DepManager) searchProviderDependency(ctx context.Context, request *discovery.GetDependenciesRequest) (*discovery.GetProDependenciesResponse, error) {
 	domainProject := util.ParseDomainProject(ctx)
 	providerServiceID := request.ServiceId
 	filter := mutil.NewBasicFilter(ctx, mutil.ServiceServiceID(providerServiceID))
@@ -77,7 +98,25 @@ func (ds *DepManager) SearchProviderDependency(ctx context.Context, request *dis
 	}, nil
 }
 
-func (ds *DepManager) SearchConsumerDependency(ctx context.Context, request *discovery.GetDependenciesRequest) (*discovery.GetConDependenciesResponse, error) {
+func (ds *DepManager) SearchConsumerDependency(ctx context.Context, request *discovery.GetDependenciesRequest) (*datasource.ConsumerDependencyResponse, error) {
+	resp, err := ds.searchConsumerDependency(ctx, request)
+	if resp == nil {
+		return nil, err
+	}
+	result := &datasource.ConsumerDependencyResponse{GetConDependenciesResponse: resp}
+	if resp.Response.GetCode() != discovery.ResponseSuccess {
+		return result, err
+	}
+	records, err := ds.ListDiscovery(ctx, request.ServiceId, "")
+	if err != nil {
+		log.Error(fmt.Sprintf("list consumer[%s] dependency discoveries failed", request.ServiceId), err)
+		return result, nil
+	}
+	result.LastDiscovered = datasource.DiscoveredTimes(records, true)
+	return result, nil
+}
+
+func (ds *DepManager) searchConsumerDependency(ctx context.Context, request *discovery.GetDependenciesRequest) (*discovery.GetConDependenciesResponse, error) {
 	domainProject := util.ParseDomainProject(ctx)
 	consumerID := request.ServiceId
 	filter := mutil.NewBasicFilter(ctx, mutil.ServiceServiceID(consumerID))
@@ -493,3 +532,177 @@ func getServiceID(ctx context.Context, filter bson.M) (serviceID string, err err
 	}
 	return
 }
+
+func (ds *DepManager) RecordDiscovery(ctx context.Context, consumerID string, providerIDs []string) error {
+	if len(providerIDs) == 0 {
+		return nil
+	}
+	timestamp := time.Now().Unix()
+	ops := make([]mongo.WriteModel, 0, len(providerIDs))
+	for _, providerID := range providerIDs {
+		filter := mutil.NewBasicFilter(ctx, mutil.ConsumerID(consumerID), mutil.ProviderID(providerID))
+		update := mutil.NewFilter(mutil.Set(mutil.NewFilter(mutil.Timestamp(timestamp))))
+		ops = append(ops, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+	_, err := client.GetMongoClient().BatchUpdate(ctx, model.CollectionDepDiscovery, ops)
+	return err
+}
+
+func (ds *DepManager) ListDiscovery(ctx context.Context, consumerID, providerID string) ([]*datasource.DependencyDiscovery, error) {
+	filter := mutil.NewBasicFilter(ctx)
+	if len(consumerID) > 0 {
+		mutil.ConsumerID(consumerID)(filter)
+	}
+	if len(providerID) > 0 {
+		mutil.ProviderID(providerID)(filter)
+	}
+	findRes, err := client.GetMongoClient().Find(ctx, model.CollectionDepDiscovery, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("list dependency discovery[%v] failed", filter), err)
+		return nil, err
+	}
+	var records []*datasource.DependencyDiscovery
+	for findRes.Next(ctx) {
+		var record model.DependencyDiscovery
+		err := findRes.Decode(&record)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &datasource.DependencyDiscovery{
+			ConsumerID: record.ConsumerID,
+			ProviderID: record.ProviderID,
+			Timestamp:  record.Timestamp,
+		})
+	}
+	return records, nil
+}
+
+func (ds *DepManager) ClearStaleDependencies(ctx context.Context, ttl time.Duration,
+	dryRun bool) ([]*datasource.StaleDependency, error) {
+	depRules, err := GetDepRules(ctx, mutil.NewFilter(mutil.ServiceType(path.DepsConsumer)))
+	if err != nil {
+		log.Error("get consumer dependency rules failed", err)
+		return nil, err
+	}
+
+	var stales []*datasource.StaleDependency
+	now := time.Now()
+	for _, depRule := range depRules {
+		consumer := depRule.ServiceKey
+		if consumer == nil || depRule.Dep == nil || consumer.ServiceName == "*" {
+			continue
+		}
+		tenant := strings.Split(consumer.Tenant, path.SPLIT)
+		if len(tenant) != 2 {
+			log.Error(fmt.Sprintf("invalid consumer tenant[%s]", consumer.Tenant), mutil.ErrInvalidDomainProject)
+			continue
+		}
+		dctx := util.SetDomainProject(ctx, tenant[0], tenant[1])
+		items, err := ds.clearConsumerStaleDependencies(dctx, consumer, depRule.Dep, ttl, now, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		stales = append(stales, items...)
+	}
+	return stales, nil
+}
+
+func (ds *DepManager) clearConsumerStaleDependencies(ctx context.Context, consumer *discovery.MicroServiceKey,
+	dependency *discovery.MicroServiceDependency, ttl time.Duration, now time.Time, dryRun bool) ([]*datasource.StaleDependency, error) {
+	consumerFlag := util.StringJoin([]string{consumer.Environment, consumer.AppId, consumer.ServiceName, consumer.Version}, path.SPLIT)
+	consumerID, err := GetServiceID(ctx, consumer)
+	if err != nil && !errors.Is(err, datasource.ErrNoData) {
+		log.Error(fmt.Sprintf("get consumer[%s] id failed", consumerFlag), err)
+		return nil, err
+	}
+	if len(consumerID) == 0 {
+		// the rules of the deleted consumer are removed by DeleteDependencyForDeleteService
+		return nil, nil
+	}
+
+	discoveries, err := ds.ListDiscovery(ctx, consumerID, "")
+	if err != nil {
+		return nil, err
+	}
+	records := make(map[string]int64, len(discoveries))
+	for _, record := range discoveries {
+		records[record.ProviderID] = record.Timestamp
+	}
+
+	var (
+		stales     []*datasource.StaleDependency
+		left       []*discovery.MicroServiceKey
+		unrecorded []string
+	)
+	for _, provider := range dependency.Dependency {
+		if provider.ServiceName == "*" {
+			left = append(left, provider)
+			continue
+		}
+		providerIDs, _, err := FindServiceIds(ctx, provider.Version, provider)
+		if err != nil {
+			log.Error(fmt.Sprintf("find consumer[%s]'s provider[%s/%s/%s/%s] failed", consumerFlag,
+				provider.Environment, provider.AppId, provider.ServiceName, provider.Version), err)
+			return nil, err
+		}
+		last := datasource.LastDiscovered(records, providerIDs)
+		switch {
+		case len(providerIDs) == 0:
+			// the provider no longer resolves, the rule can never be discovered again
+		case last == 0:
+			// never discovered since the tracking enabled, start the ttl from now
+			unrecorded = append(unrecorded, providerIDs...)
+			left = append(left, provider)
+			continue
+		case !datasource.IsStaleDependency(last, ttl, now):
+			left = append(left, provider)
+			continue
+		}
+		stales = append(stales, &datasource.StaleDependency{
+			DomainProject:  consumer.Tenant,
+			ConsumerID:     consumerID,
+			Consumer:       consumer,
+			Provider:       provider,
+			LastDiscovered: last,
+		})
+	}
+
+	if dryRun {
+		// dry run must not touch the datasource
+		return stales, nil
+	}
+	if len(unrecorded) > 0 {
+		if err := ds.RecordDiscovery(ctx, consumerID, unrecorded); err != nil {
+			log.Error(fmt.Sprintf("record consumer[%s] discovery failed", consumerFlag), err)
+			return nil, err
+		}
+	}
+	if len(stales) == 0 {
+		return stales, nil
+	}
+
+	resp, err := ds.AddOrUpdateDependencies(ctx, []*discovery.ConsumerDependency{
+		{Consumer: consumer, Providers: left},
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetCode() != discovery.ResponseSuccess {
+		return nil, errors.New(resp.GetMessage())
+	}
+	log.Warn(fmt.Sprintf("clear consumer[%s]'s %d stale dependency rules", consumerFlag, len(stales)))
+	return stales, nil
+}
+
+func recordDiscovery(ctx context.Context, consumerID string, providerIDs []string) {
+	domainProject := util.ParseDomainProject(ctx)
+	ids := datasource.FilterUnrecordedProviders(domainProject, consumerID, providerIDs)
+	if len(ids) == 0 {
+		return
+	}
+	if err := (&DepManager{}).RecordDiscovery(ctx, consumerID, ids); err != nil {
+		log.Error(fmt.Sprintf("record consumer[%s] discovery failed", consumerID), err)
+		return
+	}
+	datasource.MarkRecordedProviders(domainProject, consumerID, ids)
+}
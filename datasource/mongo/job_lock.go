@@ -0,0 +1,75 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const DepClearLock = "dep-clear"
+
+// JobLock is a lease based lock that keeps a background job running
+// on one service center instance at a time
+type JobLock struct {
+	key   string
+	owner string
+}
+
+// TryJobLock acquires the lock without waiting, the lock expires after
+// ttl in case the holder exits without unlocking
+func TryJobLock(ctx context.Context, key string, ttl time.Duration) (*JobLock, error) {
+	now := time.Now()
+	lock := &JobLock{key: key, owner: util.GenerateUUID()}
+	filter := mutil.NewFilter(
+		mutil.JobLockKey(key),
+		mutil.JobLockExpireAt(bson.M{"$lt": now.Unix()}),
+	)
+	update := mutil.NewFilter(mutil.Set(mutil.NewFilter(
+		mutil.JobLockKey(key),
+		mutil.JobLockOwner(lock.owner),
+		mutil.JobLockExpireAt(now.Add(ttl).Unix()),
+	)))
+	_, err := client.GetMongoClient().Update(ctx, model.CollectionJobLock, filter, update,
+		options.Update().SetUpsert(true))
+	if err != nil {
+		if client.IsDuplicateKey(err) {
+			return nil, fmt.Errorf("lock %s is held by another instance", key)
+		}
+		log.Error(fmt.Sprintf("can not acquire lock %s", key), err)
+		return nil, err
+	}
+	return lock, nil
+}
+
+func (l *JobLock) Unlock(ctx context.Context) error {
+	filter := mutil.NewFilter(mutil.JobLockKey(l.key), mutil.JobLockOwner(l.owner))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionJobLock, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("release lock %s failed", l.key), err)
+		return err
+	}
+	return nil
+}
@@ -23,8 +23,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"reflect"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -353,8 +351,11 @@ func (ds *MetadataManager) DelServicePri(ctx context.Context, serviceID string,
 	rulesOps := client.MongoOperation{Table: model.CollectionRule, Models: []mongo.WriteModel{mongo.NewDeleteManyModel().SetFilter(bson.M{model.ColumnServiceID: serviceID})}}
 	instanceOps := client.MongoOperation{Table: model.CollectionInstance, Models: []mongo.WriteModel{mongo.NewDeleteManyModel().SetFilter(bson.M{mutil.ConnectWithDot([]string{model.ColumnInstance, model.ColumnServiceID}): serviceID})}}
 	serviceOps := client.MongoOperation{Table: model.CollectionService, Models: []mongo.WriteModel{mongo.NewDeleteOneModel().SetFilter(bson.M{mutil.ConnectWithDot([]string{model.ColumnService, model.ColumnServiceID}): serviceID})}}
+	discoveryOps := client.MongoOperation{Table: model.CollectionDepDiscovery, Models: []mongo.WriteModel{
+		mongo.NewDeleteManyModel().SetFilter(bson.M{model.ColumnConsumerID: serviceID}),
+		mongo.NewDeleteManyModel().SetFilter(bson.M{model.ColumnProviderID: serviceID})}}
 
-	err = client.GetMongoClient().MultiTableBatchUpdate(ctx, []client.MongoOperation{schemaOps, rulesOps, instanceOps, serviceOps})
+	err = client.GetMongoClient().MultiTableBatchUpdate(ctx, []client.MongoOperation{schemaOps, rulesOps, instanceOps, serviceOps, discoveryOps})
 	if err != nil {
 		log.Error(fmt.Sprintf("micro-service[%s] failed, operator: %s", serviceID, remoteIP), err)
 		return discovery.CreateResponse(discovery.ErrUnavailableBackend, err.Error()), err
@@ -1121,6 +1122,11 @@ func (ds *MetadataManager) modifySchema(ctx context.Context, serviceID string, s
 }
 
 func (ds *MetadataManager) AddRule(ctx context.Context, request *discovery.AddServiceRulesRequest) (*discovery.AddServiceRulesResponse, error) {
+	return ds.AddPriorityRules(ctx, request, nil)
+}
+
+func (ds *MetadataManager) AddPriorityRules(ctx context.Context, request *discovery.AddServiceRulesRequest,
+	priorities []int32) (*discovery.AddServiceRulesResponse, error) {
 	remoteIP := util.GetIPFromContext(ctx)
 	domain := util.ParseDomain(ctx)
 	project := util.ParseProject(ctx)
@@ -1153,18 +1159,26 @@ func (ds *MetadataManager) AddRule(ctx context.Context, request *discovery.AddSe
 			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
 		}, err
 	}
-	var ruleType string
-	if len(rules) != 0 {
-		ruleType = rules[0].RuleType
-	}
+	ruleType := legacyRuleType(rules)
 	ruleIDs := make([]string, 0, len(request.Rules))
-	for _, rule := range request.Rules {
-		if len(ruleType) == 0 {
-			ruleType = rule.RuleType
-		} else if ruleType != rule.RuleType {
-			return &discovery.AddServiceRulesResponse{
-				Response: discovery.CreateResponse(discovery.ErrBlackAndWhiteRule, "Service can only contain one rule type,Black or white."),
-			}, nil
+	var (
+		inserts []*model.Rule
+		updates []mongo.WriteModel
+	)
+	for i, rule := range request.Rules {
+		var priority int32
+		if i < len(priorities) {
+			priority = priorities[i]
+		}
+		// ALLOW or DENY rules can be mixed with any rules
+		if datasource.IsLegacyRuleType(rule.RuleType) {
+			if len(ruleType) == 0 {
+				ruleType = rule.RuleType
+			} else if ruleType != rule.RuleType {
+				return &discovery.AddServiceRulesResponse{
+					Response: discovery.CreateResponse(discovery.ErrBlackAndWhiteRule, "Service can only contain one rule type,Black or white."),
+				}, nil
+			}
 		}
 		//the rule unique index is (serviceid,attribute,pattern)
 		filter = mutil.NewFilter(
@@ -1179,6 +1193,10 @@ func (ds *MetadataManager) AddRule(ctx context.Context, request *discovery.AddSe
 			}, nil
 		}
 		if exist {
+			if priority != 0 {
+				update := mutil.NewFilter(mutil.Set(mutil.NewFilter(mutil.RulePriority(priority))))
+				updates = append(updates, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update))
+			}
 			continue
 		}
 		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
@@ -1195,14 +1213,29 @@ func (ds *MetadataManager) AddRule(ctx context.Context, request *discovery.AddSe
 				Timestamp:    timestamp,
 				ModTimestamp: timestamp,
 			},
+			Priority: priority,
 		}
 		ruleIDs = append(ruleIDs, ruleAdd.Rule.RuleId)
-		_, err = client.GetMongoClient().Insert(ctx, model.CollectionRule, ruleAdd)
-		if err != nil {
-			return &discovery.AddServiceRulesResponse{
-				Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
-			}, err
+		inserts = append(inserts, ruleAdd)
+	}
+	// the rules and the priorities are saved at once
+	err = client.GetMongoClient().ExecTxn(ctx, func(sc mongo.SessionContext) error {
+		for _, ruleAdd := range inserts {
+			if _, err := client.GetMongoClient().Insert(sc, model.CollectionRule, ruleAdd); err != nil {
+				return err
+			}
+		}
+		if len(updates) == 0 {
+			return nil
 		}
+		_, err := client.GetMongoClient().BatchUpdate(sc, model.CollectionRule, updates)
+		return err
+	})
+	if err != nil {
+		log.Error(fmt.Sprintf("add service[%s] rule failed, operator: %s", request.ServiceId, remoteIP), err)
+		return &discovery.AddServiceRulesResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, err
 	}
 	return &discovery.AddServiceRulesResponse{
 		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Add service rules successfully."),
@@ -1299,9 +1332,10 @@ func (ds *MetadataManager) UpdateRule(ctx context.Context, request *discovery.Up
 			Response: discovery.CreateResponse(discovery.ErrUnavailableBackend, "UpdateRule failed for get rule."),
 		}, nil
 	}
-	if len(rules) >= 1 && rules[0].RuleType != request.Rule.RuleType {
+	if ruleType := legacyRuleType(rules); len(ruleType) > 0 &&
+		datasource.IsLegacyRuleType(request.Rule.RuleType) && ruleType != request.Rule.RuleType {
 		return &discovery.UpdateServiceRuleResponse{
-			Response: discovery.CreateResponse(discovery.ErrModifyRuleNotAllow, "Exist multiple rules, can not change rule type. Rule type is ."+rules[0].RuleType),
+			Response: discovery.CreateResponse(discovery.ErrModifyRuleNotAllow, "Exist multiple rules, can not change rule type. Rule type is ."+ruleType),
 		}, nil
 	}
 	filter = mutil.NewDomainProjectFilter(domain, project, mutil.ServiceID(request.ServiceId), mutil.RuleRuleID(request.RuleId))
@@ -1336,6 +1370,40 @@ func (ds *MetadataManager) UpdateRule(ctx context.Context, request *discovery.Up
 	}, nil
 }
 
+func (ds *MetadataManager) GetRulePriorities(ctx context.Context, serviceID string) (map[string]int32, error) {
+	filter := mutil.NewBasicFilter(ctx, mutil.ServiceID(serviceID))
+	rules, err := dao.GetRules(ctx, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("get service[%s] rules failed", serviceID), err)
+		return nil, err
+	}
+	priorities := make(map[string]int32, len(rules))
+	for _, rule := range rules {
+		if rule.Priority != 0 {
+			priorities[rule.Rule.RuleId] = rule.Priority
+		}
+	}
+	return priorities, nil
+}
+
+func (ds *MetadataManager) UpdateRulePriorities(ctx context.Context, serviceID string, priorities map[string]int32) error {
+	models := make([]mongo.WriteModel, 0, len(priorities))
+	for ruleID, priority := range priorities {
+		filter := mutil.NewBasicFilter(ctx, mutil.ServiceID(serviceID), mutil.RuleRuleID(ruleID))
+		update := mutil.NewFilter(mutil.Set(mutil.NewFilter(mutil.RulePriority(priority))))
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update))
+	}
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := client.GetMongoClient().BatchUpdate(ctx, model.CollectionRule, models)
+	if err != nil {
+		log.Error(fmt.Sprintf("update service[%s] rule priorities failed", serviceID), err)
+		return err
+	}
+	return nil
+}
+
 func (ds *MetadataManager) isSchemaEditable() bool {
 	return !ds.SchemaNotEditable
 }
@@ -2250,6 +2318,9 @@ func (ds *MetadataManager) findInstance(ctx context.Context, request *discovery.
 			}, err
 		}
 	}
+	if len(request.ConsumerServiceId) > 0 && len(serviceIDs) > 0 {
+		recordDiscovery(ctx, request.ConsumerServiceId, serviceIDs)
+	}
 	newRev, _ := formatRevision(request.ConsumerServiceId, instances)
 	if rev == newRev {
 		instances = nil // for gRPC
@@ -2450,6 +2521,30 @@ func AppendFindResponse(ctx context.Context, index int64, resp *discovery.Respon
 }
 
 func preProcessRegisterInstance(ctx context.Context, instance *discovery.MicroServiceInstance) *errsvc.Error {
+	if err := setInstanceDefaultValue(ctx, instance); err != nil {
+		return err
+	}
+	cacheService, ok := cache.GetServiceByID(ctx, instance.ServiceId)
+
+	var microService *discovery.MicroService
+	if ok {
+		microService = cacheService.Service
+		instance.Version = microService.Version
+	} else {
+		filter := mutil.NewBasicFilter(ctx, mutil.ServiceServiceID(instance.ServiceId))
+		microservice, err := dao.GetService(ctx, filter)
+		if err != nil {
+			log.Error("Get service failed", err)
+			return discovery.NewError(discovery.ErrServiceNotExists, "invalid 'serviceID' in request body.")
+		}
+		instance.Version = microservice.Service.Version
+	}
+
+	return nil
+}
+
+// setInstanceDefaultValue sets the default status, id, timestamps and health check of the instance
+func setInstanceDefaultValue(ctx context.Context, instance *discovery.MicroServiceInstance) *errsvc.Error {
 	if len(instance.Status) == 0 {
 		instance.Status = discovery.MSI_UP
 	}
@@ -2484,23 +2579,6 @@ func preProcessRegisterInstance(ctx context.Context, instance *discovery.MicroSe
 			instance.HealthCheck.Times = retryTimes
 		}
 	}
-
-	cacheService, ok := cache.GetServiceByID(ctx, instance.ServiceId)
-
-	var microService *discovery.MicroService
-	if ok {
-		microService = cacheService.Service
-		instance.Version = microService.Version
-	} else {
-		filter := mutil.NewBasicFilter(ctx, mutil.ServiceServiceID(instance.ServiceId))
-		microservice, err := dao.GetService(ctx, filter)
-		if err != nil {
-			log.Error("Get service failed", err)
-			return discovery.NewError(discovery.ErrServiceNotExists, "invalid 'serviceID' in request body.")
-		}
-		instance.Version = microservice.Service.Version
-	}
-
 	return nil
 }
 
@@ -2656,103 +2734,15 @@ func accessible(ctx context.Context, consumerID string, providerID string) *errs
 	if len(rules) == 0 {
 		return nil
 	}
-	return MatchRules(rules, consumerService.Service, consumerService.Tags)
-}
-
-func MatchRules(rulesOfProvider []*model.Rule, consumer *discovery.MicroService, tagsOfConsumer map[string]string) *errsvc.Error {
-	if consumer == nil {
-		return discovery.NewError(discovery.ErrInvalidParams, "consumer is nil")
-	}
-
-	if len(rulesOfProvider) <= 0 {
-		return nil
-	}
-	if rulesOfProvider[0].Rule.RuleType == "WHITE" {
-		return patternWhiteList(rulesOfProvider, tagsOfConsumer, consumer)
-	}
-	return patternBlackList(rulesOfProvider, tagsOfConsumer, consumer)
-}
-
-func parsePattern(v reflect.Value, rule *discovery.ServiceRule, tagsOfConsumer map[string]string, consumerID string) (string, *errsvc.Error) {
-	if strings.HasPrefix(rule.Attribute, "tag_") {
-		key := rule.Attribute[4:]
-		value := tagsOfConsumer[key]
-		if len(value) == 0 {
-			log.Info(fmt.Sprintf("can not find service[%s] tag[%s]", consumerID, key))
-		}
-		return value, nil
-	}
-	key := v.FieldByName(rule.Attribute)
-	if !key.IsValid() {
-		log.Error(fmt.Sprintf("can not find service[%s] field[%s], ruleID is %s",
-			consumerID, rule.Attribute, rule.RuleId), nil)
-		return "", discovery.NewError(discovery.ErrInternal, fmt.Sprintf("can not find field '%s'", rule.Attribute))
-	}
-	return key.String(), nil
-
-}
-
-func patternWhiteList(rulesOfProvider []*model.Rule, tagsOfConsumer map[string]string, consumer *discovery.MicroService) *errsvc.Error {
-	v := reflect.Indirect(reflect.ValueOf(consumer))
-	consumerID := consumer.ServiceId
-	for _, rule := range rulesOfProvider {
-		value, err := parsePattern(v, rule.Rule, tagsOfConsumer, consumerID)
-		if err != nil {
-			return err
-		}
-		if len(value) == 0 {
-			continue
-		}
-
-		match, _ := regexp.MatchString(rule.Rule.Pattern, value)
-		if match {
-			log.Info(fmt.Sprintf("consumer[%s][%s/%s/%s/%s] match white list, rule.Pattern is %s, value is %s",
-				consumerID, consumer.Environment, consumer.AppId, consumer.ServiceName, consumer.Version,
-				rule.Rule.Pattern, value))
-			return nil
-		}
-	}
-	return discovery.NewError(discovery.ErrPermissionDeny, "not found in white list")
+	return matchRules(rules, &datasource.RuleSubject{
+		Consumer: consumerService.Service,
+		Tags:     consumerService.Tags,
+		IP:       util.GetIPFromContext(ctx),
+	})
 }
 
-func patternBlackList(rulesOfProvider []*model.Rule, tagsOfConsumer map[string]string, consumer *discovery.MicroService) *errsvc.Error {
-	v := reflect.Indirect(reflect.ValueOf(consumer))
-	consumerID := consumer.ServiceId
-	for _, rule := range rulesOfProvider {
-		var value string
-		value, err := parsePattern(v, rule.Rule, tagsOfConsumer, consumerID)
-		if err != nil {
-			return err
-		}
-		if len(value) == 0 {
-			continue
-		}
-
-		match, _ := regexp.MatchString(rule.Rule.Pattern, value)
-		if match {
-			log.Warn(fmt.Sprintf("no permission to access, consumer[%s][%s/%s/%s/%s] match black list, rule.Pattern is %s, value is %s",
-				consumerID, consumer.Environment, consumer.AppId, consumer.ServiceName, consumer.Version,
-				rule.Rule.Pattern, value))
-			return discovery.NewError(discovery.ErrPermissionDeny, "found in black list")
-		}
-	}
-	return nil
-}
 func allowAcrossDimension(ctx context.Context, providerService *model.Service, consumerService *model.Service) error {
-	if providerService.Service.AppId != consumerService.Service.AppId {
-		if len(providerService.Service.Properties) == 0 {
-			return fmt.Errorf("not allow across app access")
-		}
-
-		if allowCrossApp, ok := providerService.Service.Properties[discovery.PropAllowCrossApp]; !ok || strings.ToLower(allowCrossApp) != "true" {
-			return fmt.Errorf("not allow across app access")
-		}
-	}
-	if !datasource.IsGlobal(discovery.MicroServiceToKey(util.ParseTargetDomainProject(ctx), providerService.Service)) &&
-		providerService.Service.Environment != consumerService.Service.Environment {
-		return fmt.Errorf("not allow across environment access")
-	}
-	return nil
+	return datasource.AllowAcrossDimension(ctx, providerService.Service, consumerService.Service)
 }
 
 func DeleteDependencyForDeleteService(domainProject string, serviceID string, service *discovery.MicroServiceKey) error {
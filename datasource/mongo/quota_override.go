@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type QuotaOverrideManager struct {
+}
+
+func (ds *QuotaOverrideManager) GetQuotaOverride(ctx context.Context, domain, project string) (*datasource.QuotaOverride, error) {
+	filter := mutil.NewFilter(mutil.Domain(domain), mutil.Project(project))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionQuotaOverride, filter)
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			return nil, datasource.ErrQuotaOverrideNotExist
+		}
+		return nil, result.Err()
+	}
+	var q datasource.QuotaOverride
+	err = result.Decode(&q)
+	if err != nil {
+		log.Error("failed to decode quota override", err)
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (ds *QuotaOverrideManager) ListQuotaOverride(ctx context.Context) ([]*datasource.QuotaOverride, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionQuotaOverride, mutil.NewFilter())
+	if err != nil {
+		return nil, err
+	}
+	qs := make([]*datasource.QuotaOverride, 0)
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var q datasource.QuotaOverride
+		err = cursor.Decode(&q)
+		if err != nil {
+			log.Error("failed to decode quota override", err)
+			continue
+		}
+		qs = append(qs, &q)
+	}
+	return qs, nil
+}
+
+func (ds *QuotaOverrideManager) UpsertQuotaOverride(ctx context.Context, q *datasource.QuotaOverride) error {
+	filter := mutil.NewFilter(mutil.Domain(q.Domain), mutil.Project(q.Project))
+	result, err := client.GetMongoClient().FindOneAndUpdate(ctx, model.CollectionQuotaOverride, filter,
+		mutil.NewFilter(mutil.Set(q)), options.FindOneAndUpdate().SetUpsert(true))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save quota override %s/%s", q.Domain, q.Project), err)
+		return err
+	}
+	if result.Err() != nil && result.Err() != mongo.ErrNoDocuments {
+		log.Error(fmt.Sprintf("can not save quota override %s/%s", q.Domain, q.Project), result.Err())
+		return result.Err()
+	}
+	return nil
+}
+
+func (ds *QuotaOverrideManager) DeleteQuotaOverride(ctx context.Context, domain, project string) error {
+	filter := mutil.NewFilter(mutil.Domain(domain), mutil.Project(project))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionQuotaOverride, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("remove quota override %s/%s failed", domain, project), err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type RoleInheritanceManager struct {
+}
+
+func (ds *RoleInheritanceManager) GetRoleInheritance(ctx context.Context, role string) (*datasource.RoleInheritance, error) {
+	filter := mutil.NewFilter(mutil.RoleInheritanceRole(role))
+	result, err := client.GetMongoClient().FindOne(ctx, model.CollectionRoleInheritance, filter)
+	if err != nil {
+		return nil, err
+	}
+	if result.Err() != nil {
+		if result.Err() == mongo.ErrNoDocuments {
+			return nil, datasource.ErrRoleInheritanceNotExist
+		}
+		return nil, result.Err()
+	}
+	var r datasource.RoleInheritance
+	err = result.Decode(&r)
+	if err != nil {
+		log.Error("failed to decode role inheritance", err)
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (ds *RoleInheritanceManager) ListRoleInheritance(ctx context.Context) ([]*datasource.RoleInheritance, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionRoleInheritance, mutil.NewFilter())
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]*datasource.RoleInheritance, 0)
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var r datasource.RoleInheritance
+		err = cursor.Decode(&r)
+		if err != nil {
+			log.Error("failed to decode role inheritance", err)
+			continue
+		}
+		rs = append(rs, &r)
+	}
+	return rs, nil
+}
+
+func (ds *RoleInheritanceManager) UpsertRoleInheritance(ctx context.Context, r *datasource.RoleInheritance) error {
+	filter := mutil.NewFilter(mutil.RoleInheritanceRole(r.Role))
+	result, err := client.GetMongoClient().FindOneAndUpdate(ctx, model.CollectionRoleInheritance, filter,
+		mutil.NewFilter(mutil.Set(r)), options.FindOneAndUpdate().SetUpsert(true))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save role inheritance %s", r.Role), err)
+		return err
+	}
+	if result.Err() != nil && result.Err() != mongo.ErrNoDocuments {
+		log.Error(fmt.Sprintf("can not save role inheritance %s", r.Role), result.Err())
+		return result.Err()
+	}
+	return nil
+}
+
+func (ds *RoleInheritanceManager) DeleteRoleInheritance(ctx context.Context, role string) error {
+	filter := mutil.NewFilter(mutil.RoleInheritanceRole(role))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionRoleInheritance, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("remove role inheritance %s failed", role), err)
+		return err
+	}
+	return nil
+}
@@ -19,13 +19,18 @@ package mongo
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/apache/servicecomb-service-center/datasource/cache"
 	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/cache"
 
 	"github.com/apache/servicecomb-service-center/datasource/mongo/client/dao"
 	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
 	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 )
 
@@ -49,7 +54,7 @@ func Filter(ctx context.Context, rules []*model.Rule, consumerID string) (bool,
 	if err != nil {
 		return false, err
 	}
-	matchErr := MatchRules(rules, consumer.Service, tags)
+	matchErr := matchRules(rules, &datasource.RuleSubject{Consumer: consumer.Service, Tags: tags, IP: util.GetIPFromContext(ctx)})
 	if matchErr != nil {
 		if matchErr.Code == discovery.ErrPermissionDeny {
 			return false, nil
@@ -82,3 +87,63 @@ func FilterAll(ctx context.Context, consumerIDs []string, rules []*model.Rule) (
 	}
 	return consumers[:allowIdx], consumers[denyIdx:], nil
 }
+
+func MatchRules(rulesOfProvider []*model.Rule, consumer *discovery.MicroService, tagsOfConsumer map[string]string) *errsvc.Error {
+	return matchRules(rulesOfProvider, &datasource.RuleSubject{Consumer: consumer, Tags: tagsOfConsumer})
+}
+
+func matchRules(rulesOfProvider []*model.Rule, subject *datasource.RuleSubject) *errsvc.Error {
+	if subject.Consumer == nil {
+		return discovery.NewError(discovery.ErrInvalidParams, "consumer is nil")
+	}
+
+	if len(rulesOfProvider) <= 0 {
+		return nil
+	}
+	decision, err := datasource.EvaluateRules(toRules(rulesOfProvider), subject, false)
+	if err != nil {
+		log.Error(fmt.Sprintf("evaluate consumer[%s] rules failed", subject.Consumer.ServiceId), err)
+		return discovery.NewError(discovery.ErrInternal, err.Error())
+	}
+	consumer := subject.Consumer
+	if !decision.Allowed {
+		log.Warn(fmt.Sprintf("no permission to access, consumer[%s][%s/%s/%s/%s] ip[%s], rule[%s]: %s",
+			consumer.ServiceId, consumer.Environment, consumer.AppId, consumer.ServiceName, consumer.Version,
+			subject.IP, decision.RuleID, decision.Reason))
+		reason := decision.Reason
+		if r, ok := legacyDenyReasons[reason]; ok {
+			reason = r
+		}
+		return discovery.NewError(discovery.ErrPermissionDeny, reason)
+	}
+	if len(decision.RuleID) > 0 {
+		log.Info(fmt.Sprintf("consumer[%s][%s/%s/%s/%s] ip[%s] is allowed, rule[%s]: %s",
+			consumer.ServiceId, consumer.Environment, consumer.AppId, consumer.ServiceName, consumer.Version,
+			subject.IP, decision.RuleID, decision.Reason))
+	}
+	return nil
+}
+
+// legacyDenyReasons are the deny messages of the legacy rules returned by mongo
+var legacyDenyReasons = map[string]string{
+	datasource.RuleReasonInBlackList:    "found in black list",
+	datasource.RuleReasonNotInWhiteList: "not found in white list",
+}
+
+func toRules(rules []*model.Rule) []*datasource.Rule {
+	wrapped := make([]*datasource.Rule, 0, len(rules))
+	for _, rule := range rules {
+		wrapped = append(wrapped, &datasource.Rule{ServiceRule: rule.Rule, Priority: rule.Priority})
+	}
+	return wrapped
+}
+
+// legacyRuleType returns the type of the legacy rules, the rules of type ALLOW or DENY are ignored
+func legacyRuleType(rules []*discovery.ServiceRule) string {
+	for _, rule := range rules {
+		if datasource.IsLegacyRuleType(rule.RuleType) {
+			return rule.RuleType
+		}
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+type SessionManager struct {
+}
+
+func (ds *SessionManager) CreateSession(ctx context.Context, s *datasource.Session) error {
+	_, err := client.GetMongoClient().Insert(ctx, model.CollectionSession, s)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save session %s", s.ID), err)
+		return err
+	}
+	return nil
+}
+
+func (ds *SessionManager) ListSession(ctx context.Context, account string) ([]*datasource.Session, error) {
+	filter := mutil.NewFilter()
+	if len(account) > 0 {
+		filter = mutil.NewFilter(mutil.SessionAccount(account))
+	}
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionSession, filter)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*datasource.Session, 0)
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var s datasource.Session
+		err = cursor.Decode(&s)
+		if err != nil {
+			log.Error("failed to decode session", err)
+			continue
+		}
+		sessions = append(sessions, &s)
+	}
+	return sessions, nil
+}
+
+func (ds *SessionManager) DeleteSession(ctx context.Context, account, id string) error {
+	filter := mutil.NewFilter(mutil.SessionAccount(account), mutil.ID(id))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionSession, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("remove session %s failed", id), err)
+		return err
+	}
+	return nil
+}
+
+func (ds *SessionManager) Revoke(ctx context.Context, r *datasource.Revocation) error {
+	filter := mutil.NewFilter(mutil.RevocationKey(r.Key))
+	result, err := client.GetMongoClient().FindOneAndUpdate(ctx, model.CollectionRevocation, filter,
+		mutil.NewFilter(mutil.Set(r)), options.FindOneAndUpdate().SetUpsert(true))
+	if err != nil {
+		log.Error(fmt.Sprintf("can not save revocation %s", r.Key), err)
+		return err
+	}
+	if result.Err() != nil && result.Err() != mongo.ErrNoDocuments {
+		log.Error(fmt.Sprintf("can not save revocation %s", r.Key), result.Err())
+		return result.Err()
+	}
+	return nil
+}
+
+func (ds *SessionManager) ListRevocation(ctx context.Context) ([]*datasource.Revocation, error) {
+	cursor, err := client.GetMongoClient().Find(ctx, model.CollectionRevocation, mutil.NewFilter())
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]*datasource.Revocation, 0)
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var r datasource.Revocation
+		err = cursor.Decode(&r)
+		if err != nil {
+			log.Error("failed to decode revocation", err)
+			continue
+		}
+		rs = append(rs, &r)
+	}
+	return rs, nil
+}
+
+func (ds *SessionManager) DeleteRevocation(ctx context.Context, key string) error {
+	filter := mutil.NewFilter(mutil.RevocationKey(key))
+	_, err := client.GetMongoClient().Delete(ctx, model.CollectionRevocation, filter)
+	if err != nil {
+		log.Error(fmt.Sprintf("remove revocation %s failed", key), err)
+		return err
+	}
+	return nil
+}
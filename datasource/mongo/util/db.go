@@ -110,6 +110,84 @@ func RoleUpdateTime(dt interface{}) Option {
 	}
 }
 
+func AuditLogActor(actor string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAuditLogActor] = actor
+	}
+}
+
+func AlarmID(id string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAlarmID] = id
+	}
+}
+
+func AlarmInstance(instance string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAlarmInstance] = instance
+	}
+}
+
+func RoleInheritanceRole(role string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnRoleInheritanceRole] = role
+	}
+}
+
+func AccountStateAccount(name string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAccountStateAccount] = name
+	}
+}
+
+func TOTPAccount(account string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnTOTPAccount] = account
+	}
+}
+
+func APIKeyAccount(account string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAPIKeyAccount] = account
+	}
+}
+
+func APIKeyName(name string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAPIKeyName] = name
+	}
+}
+
+func APIKeyHash(hash string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAPIKeyHash] = hash
+	}
+}
+
+func APIKeyExpireAt(expireAt int64) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAPIKeyExpireAt] = expireAt
+	}
+}
+
+func APIKeyUpdateTime(dt interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnAPIKeyUpdateTime] = dt
+	}
+}
+
+func SessionAccount(account string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnSessionAccount] = account
+	}
+}
+
+func RevocationKey(key string) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnRevocationKey] = key
+	}
+}
+
 func AccountLockKey(key interface{}) Option {
 	return func(filter bson.M) {
 		filter[model.ColumnAccountLockKey] = key
@@ -128,6 +206,42 @@ func AccountLockReleaseAt(releaseAt interface{}) Option {
 	}
 }
 
+func JobLockKey(key interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnJobLockKey] = key
+	}
+}
+
+func JobLockOwner(owner interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnJobLockOwner] = owner
+	}
+}
+
+func JobLockExpireAt(expireAt interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnJobLockExpireAt] = expireAt
+	}
+}
+
+func ConsumerID(consumerID interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnConsumerID] = consumerID
+	}
+}
+
+func ProviderID(providerID interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnProviderID] = providerID
+	}
+}
+
+func Timestamp(timestamp interface{}) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnTimestamp] = timestamp
+	}
+}
+
 func In(data interface{}) Option {
 	return func(filter bson.M) {
 		filter["$in"] = data
@@ -322,6 +436,12 @@ func RuleAttribute(attribute string) Option {
 	}
 }
 
+func RulePriority(priority int32) Option {
+	return func(filter bson.M) {
+		filter[model.ColumnRulePriority] = priority
+	}
+}
+
 func RuleRuleID(ruleID string) Option {
 	return func(filter bson.M) {
 		filter[ConnectWithDot([]string{model.ColumnRule, model.ColumnRuleID})] = ruleID
@@ -57,6 +57,9 @@ type MetadataManager interface {
 		serviceRespChan chan<- *pb.DelServicesRspInfo) func(context.Context)
 	GetServiceCount(ctx context.Context,
 		request *pb.GetServiceCountRequest) (*pb.GetServiceCountResponse, error)
+	// ApplyService creates or updates the service, schemas, tags, rules and instances
+	// described by the document, atomically if the backend allows it
+	ApplyService(ctx context.Context, doc *ServiceDocument) (*ApplyServiceResponse, error)
 
 	// Instance management
 	RegisterInstance(ctx context.Context, request *pb.RegisterInstanceRequest) (*pb.RegisterInstanceResponse, error)
@@ -104,4 +107,11 @@ type MetadataManager interface {
 	GetRules(ctx context.Context, request *pb.GetServiceRulesRequest) (*pb.GetServiceRulesResponse, error)
 	UpdateRule(ctx context.Context, request *pb.UpdateServiceRuleRequest) (*pb.UpdateServiceRuleResponse, error)
 	DeleteRule(ctx context.Context, request *pb.DeleteServiceRulesRequest) (*pb.DeleteServiceRulesResponse, error)
+	// GetRulePriorities returns the priorities of the service rules, the key is the ruleId
+	GetRulePriorities(ctx context.Context, serviceID string) (map[string]int32, error)
+	// UpdateRulePriorities saves the priorities of the service rules, the key is the ruleId
+	UpdateRulePriorities(ctx context.Context, serviceID string, priorities map[string]int32) error
+	// AddPriorityRules adds the rules and saves their priorities at once, priorities[i] is the priority of
+	// request.Rules[i], the priorities of the existing rules are updated if not 0
+	AddPriorityRules(ctx context.Context, request *pb.AddServiceRulesRequest, priorities []int32) (*pb.AddServiceRulesResponse, error)
 }
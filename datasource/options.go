@@ -27,5 +27,11 @@ type Options struct {
 	// InstanceTTL: the default ttl of instance lease
 	InstanceTTL         int64
 	ReleaseAccountAfter time.Duration
+	// DependencyStaleTTL: the dependency rules not discovered in the ttl are stale, 0 means never
+	DependencyStaleTTL time.Duration
+	// DependencyClearInterval: the interval of checking the stale dependency rules
+	DependencyClearInterval time.Duration
+	// DependencyClearDryRun: only report the stale dependency rules without deleting them
+	DependencyClearDryRun bool
 	// TODO: pay attention to more net config like TLSConfig when coding
 }
@@ -0,0 +1,202 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+)
+
+// the ownership of the service is stored in the reserved service properties
+const (
+	PropOwnerTeam       = "owner.team"
+	PropOwnerContacts   = "owner.contacts"
+	PropOwnerRepository = "owner.repository"
+	PropOwnerOnCall     = "owner.onCall"
+	PropOwnerTier       = "owner.tier"
+)
+
+const (
+	OwnershipTeam       = "team"
+	OwnershipContacts   = "contacts"
+	OwnershipRepository = "repository"
+	OwnershipOnCall     = "onCall"
+	OwnershipTier       = "tier"
+)
+
+// the fields of the alarm raised about a service
+const (
+	AlarmFieldServiceID     = "serviceId"
+	AlarmFieldOwnerTeam     = "ownerTeam"
+	AlarmFieldOwnerContacts = "ownerContacts"
+	AlarmFieldOwnerOnCall   = "ownerOnCall"
+	AlarmFieldOwnerTier     = "ownerTier"
+)
+
+var ownershipProps = map[string]string{
+	OwnershipTeam:       PropOwnerTeam,
+	OwnershipContacts:   PropOwnerContacts,
+	OwnershipRepository: PropOwnerRepository,
+	OwnershipOnCall:     PropOwnerOnCall,
+	OwnershipTier:       PropOwnerTier,
+}
+
+// Ownership is the owner information of the service
+type Ownership struct {
+	Team string `json:"team,omitempty"`
+	// Contacts are stored as a comma separated list
+	Contacts   []string `json:"contacts,omitempty"`
+	Repository string   `json:"repository,omitempty"`
+	OnCall     string   `json:"onCall,omitempty"`
+	Tier       string   `json:"tier,omitempty"`
+}
+
+// OwnershipSchema is the validation schema of the service ownership
+type OwnershipSchema struct {
+	// Required are the ownership fields must be specified, e.g. team, tier
+	Required []string
+	// Tiers are the allowed tier values, empty means any value
+	Tiers []string
+}
+
+// OwnershipFilter is the condition of searching the services by ownership
+type OwnershipFilter struct {
+	Team string
+	Tier string
+	// NoInstance only matches the services without any instance
+	NoInstance bool
+}
+
+// GetOwnership returns nil if the service has no ownership
+func GetOwnership(service *discovery.MicroService) *Ownership {
+	if service == nil || len(service.Properties) == 0 {
+		return nil
+	}
+	props := service.Properties
+	o := &Ownership{
+		Team:       props[PropOwnerTeam],
+		Contacts:   splitContacts(props[PropOwnerContacts]),
+		Repository: props[PropOwnerRepository],
+		OnCall:     props[PropOwnerOnCall],
+		Tier:       props[PropOwnerTier],
+	}
+	if len(o.Team) == 0 && len(o.Contacts) == 0 && len(o.Repository) == 0 && len(o.OnCall) == 0 && len(o.Tier) == 0 {
+		return nil
+	}
+	return o
+}
+
+func splitContacts(s string) []string {
+	var contacts []string
+	for _, c := range strings.Split(s, ",") {
+		if c = strings.TrimSpace(c); len(c) > 0 {
+			contacts = append(contacts, c)
+		}
+	}
+	return contacts
+}
+
+// Validate checks the ownership in the service properties
+func (s *OwnershipSchema) Validate(properties map[string]string) error {
+	for _, field := range s.Required {
+		prop, ok := ownershipProps[field]
+		if !ok {
+			return fmt.Errorf("unknown ownership field '%s'", field)
+		}
+		if len(strings.TrimSpace(properties[prop])) == 0 {
+			return fmt.Errorf("required property '%s'", prop)
+		}
+	}
+	if v, ok := properties[PropOwnerContacts]; ok && len(v) > 0 && len(splitContacts(v)) == 0 {
+		return fmt.Errorf("invalid property '%s'", PropOwnerContacts)
+	}
+	for _, prop := range []string{PropOwnerRepository, PropOwnerOnCall} {
+		if v := properties[prop]; len(v) > 0 && !isURL(v) {
+			return fmt.Errorf("property '%s' must be a url", prop)
+		}
+	}
+	if v := properties[PropOwnerTier]; len(v) > 0 && len(s.Tiers) > 0 && !containsString(s.Tiers, v) {
+		return fmt.Errorf("property '%s' must be one of %s", PropOwnerTier, strings.Join(s.Tiers, ","))
+	}
+	return nil
+}
+
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && len(u.Scheme) > 0 && len(u.Host) > 0
+}
+
+func containsString(arr []string, s string) bool {
+	for _, v := range arr {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns true if the service matches the filter
+func (f *OwnershipFilter) Match(o *Ownership, instanceCount int64) bool {
+	if f.NoInstance && instanceCount > 0 {
+		return false
+	}
+	if len(f.Team) == 0 && len(f.Tier) == 0 {
+		return true
+	}
+	if o == nil {
+		return false
+	}
+	if len(f.Team) > 0 && f.Team != o.Team {
+		return false
+	}
+	if len(f.Tier) > 0 && f.Tier != o.Tier {
+		return false
+	}
+	return true
+}
+
+// OwnerAlarmFields returns the alarm fields of the service and its owner,
+// then the alarm raised about the service can be routed to the owner
+func OwnerAlarmFields(service *discovery.MicroService) []model.Field {
+	if service == nil {
+		return nil
+	}
+	fields := []model.Field{{Key: AlarmFieldServiceID, Value: service.ServiceId}}
+	o := GetOwnership(service)
+	if o == nil {
+		return fields
+	}
+	if len(o.Team) > 0 {
+		fields = append(fields, model.Field{Key: AlarmFieldOwnerTeam, Value: o.Team})
+	}
+	if len(o.Contacts) > 0 {
+		fields = append(fields, model.Field{Key: AlarmFieldOwnerContacts, Value: strings.Join(o.Contacts, ",")})
+	}
+	if len(o.OnCall) > 0 {
+		fields = append(fields, model.Field{Key: AlarmFieldOwnerOnCall, Value: o.OnCall})
+	}
+	if len(o.Tier) > 0 {
+		fields = append(fields, model.Field{Key: AlarmFieldOwnerTier, Value: o.Tier})
+	}
+	return fields
+}
@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package datasource_test
+
+import (
+	"testing"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+)
+
+func TestGetOwnership(t *testing.T) {
+	assert.Nil(t, datasource.GetOwnership(&discovery.MicroService{}))
+	assert.Nil(t, datasource.GetOwnership(&discovery.MicroService{Properties: map[string]string{"a": "b"}}))
+
+	o := datasource.GetOwnership(&discovery.MicroService{Properties: map[string]string{
+		datasource.PropOwnerTeam:     "team-a",
+		datasource.PropOwnerContacts: "a@example.com, ,b@example.com",
+		datasource.PropOwnerTier:     "tier-1",
+	}})
+	assert.Equal(t, &datasource.Ownership{
+		Team:     "team-a",
+		Contacts: []string{"a@example.com", "b@example.com"},
+		Tier:     "tier-1",
+	}, o)
+}
+
+func TestOwnershipSchema_Validate(t *testing.T) {
+	schema := &datasource.OwnershipSchema{}
+	assert.NoError(t, schema.Validate(nil))
+	assert.NoError(t, schema.Validate(map[string]string{datasource.PropOwnerRepository: "https://github.com/apache/servicecomb-service-center"}))
+	assert.Error(t, schema.Validate(map[string]string{datasource.PropOwnerRepository: "servicecomb-service-center"}))
+	assert.Error(t, schema.Validate(map[string]string{datasource.PropOwnerOnCall: "/oncall"}))
+	assert.Error(t, schema.Validate(map[string]string{datasource.PropOwnerContacts: " , "}))
+
+	schema = &datasource.OwnershipSchema{Required: []string{"team", "tier"}, Tiers: []string{"tier-1", "tier-2"}}
+	assert.Error(t, schema.Validate(map[string]string{datasource.PropOwnerTeam: "team-a"}))
+	assert.Error(t, schema.Validate(map[string]string{datasource.PropOwnerTeam: "team-a", datasource.PropOwnerTier: "tier-3"}))
+	assert.NoError(t, schema.Validate(map[string]string{datasource.PropOwnerTeam: "team-a", datasource.PropOwnerTier: "tier-2"}))
+
+	schema = &datasource.OwnershipSchema{Required: []string{"unknown"}}
+	assert.Error(t, schema.Validate(nil))
+}
+
+func TestOwnershipFilter_Match(t *testing.T) {
+	o := &datasource.Ownership{Team: "team-a", Tier: "tier-1"}
+	assert.True(t, (&datasource.OwnershipFilter{}).Match(nil, 1))
+	assert.True(t, (&datasource.OwnershipFilter{Team: "team-a"}).Match(o, 1))
+	assert.False(t, (&datasource.OwnershipFilter{Team: "team-b"}).Match(o, 1))
+	assert.False(t, (&datasource.OwnershipFilter{Team: "team-a"}).Match(nil, 1))
+	assert.True(t, (&datasource.OwnershipFilter{Tier: "tier-1", NoInstance: true}).Match(o, 0))
+	assert.False(t, (&datasource.OwnershipFilter{Tier: "tier-1", NoInstance: true}).Match(o, 1))
+}
+
+func TestOwnerAlarmFields(t *testing.T) {
+	fields := datasource.OwnerAlarmFields(&discovery.MicroService{ServiceId: "1", Properties: map[string]string{
+		datasource.PropOwnerTeam:   "team-a",
+		datasource.PropOwnerOnCall: "https://oncall.example.com/team-a",
+	}})
+	assert.Equal(t, 3, len(fields))
+	assert.Equal(t, datasource.AlarmFieldServiceID, fields[0].Key)
+	assert.Equal(t, "team-a", fields[1].Value)
+	assert.Equal(t, datasource.AlarmFieldOwnerOnCall, fields[2].Key)
+}
@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+var ErrQuotaOverrideNotExist = errors.New("quota override not exist")
+
+// QuotaOverrideCacheTTL is how long the override is cached for the quota checks,
+// the change made by other instances takes effect in the ttl
+const QuotaOverrideCacheTTL = 30 * time.Second
+
+// quotaOverrides caches the override of domain/project, nil if not exist
+var quotaOverrides = cache.New(QuotaOverrideCacheTTL, QuotaOverrideCacheTTL)
+
+// QuotaOverrideManager saves the quota limits of the domains and projects,
+// which override the global quota configuration
+type QuotaOverrideManager interface {
+	// GetQuotaOverride returns the override of the project,
+	// or the override of the domain if project is empty
+	GetQuotaOverride(ctx context.Context, domain, project string) (*QuotaOverride, error)
+	ListQuotaOverride(ctx context.Context) ([]*QuotaOverride, error)
+	// UpsertQuotaOverride saves or replaces the override with the same domain and project
+	UpsertQuotaOverride(ctx context.Context, q *QuotaOverride) error
+	DeleteQuotaOverride(ctx context.Context, domain, project string) error
+}
+
+// QuotaOverride is the quota limits of the domain if Project is empty,
+// otherwise of the project, Limits is the resource type name to the limit
+type QuotaOverride struct {
+	Domain     string           `json:"domain,omitempty"`
+	Project    string           `json:"project,omitempty"`
+	Limits     map[string]int64 `json:"limits,omitempty"`
+	UpdateTime string           `json:"updateTime,omitempty" bson:"update_time"`
+}
+
+// GetCachedQuotaOverride is GetQuotaOverride with the cache of QuotaOverrideCacheTTL,
+// the not exist result is cached as well
+func GetCachedQuotaOverride(ctx context.Context, domain, project string) (*QuotaOverride, error) {
+	key := util.StringJoin([]string{domain, project}, SPLIT)
+	if v, ok := quotaOverrides.Get(key); ok {
+		if v == nil {
+			return nil, ErrQuotaOverrideNotExist
+		}
+		return v.(*QuotaOverride), nil
+	}
+	override, err := GetQuotaOverrideManager().GetQuotaOverride(ctx, domain, project)
+	if err != nil {
+		if errors.Is(err, ErrQuotaOverrideNotExist) {
+			quotaOverrides.SetDefault(key, nil)
+		}
+		return nil, err
+	}
+	quotaOverrides.SetDefault(key, override)
+	return override, nil
+}
+
+// InvalidateQuotaOverride removes the cached override after it is changed
+func InvalidateQuotaOverride(domain, project string) {
+	quotaOverrides.Delete(util.StringJoin([]string{domain, project}, SPLIT))
+}
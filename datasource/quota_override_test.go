@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datasource_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+)
+
+func TestQuotaOverride(t *testing.T) {
+	ctx := context.Background()
+	domainOverride := &datasource.QuotaOverride{Domain: "test-quota-domain",
+		Limits: map[string]int64{"service": 10}}
+	projectOverride := &datasource.QuotaOverride{Domain: "test-quota-domain", Project: "test-quota-project",
+		Limits: map[string]int64{"service": 5, "instance": 20}}
+	t.Run("upsert and get quota overrides", func(t *testing.T) {
+		assert.NoError(t, datasource.GetQuotaOverrideManager().UpsertQuotaOverride(ctx, domainOverride))
+		assert.NoError(t, datasource.GetQuotaOverrideManager().UpsertQuotaOverride(ctx, projectOverride))
+
+		q, err := datasource.GetQuotaOverrideManager().GetQuotaOverride(ctx, "test-quota-domain", "")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), q.Limits["service"])
+		q, err = datasource.GetQuotaOverrideManager().GetQuotaOverride(ctx, "test-quota-domain", "test-quota-project")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(20), q.Limits["instance"])
+
+		projectOverride.Limits = map[string]int64{"service": 6}
+		assert.NoError(t, datasource.GetQuotaOverrideManager().UpsertQuotaOverride(ctx, projectOverride))
+		q, err = datasource.GetQuotaOverrideManager().GetQuotaOverride(ctx, "test-quota-domain", "test-quota-project")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int64{"service": 6}, q.Limits)
+
+		qs, err := datasource.GetQuotaOverrideManager().ListQuotaOverride(ctx)
+		assert.NoError(t, err)
+		assert.True(t, len(qs) >= 2)
+	})
+	t.Run("delete quota overrides", func(t *testing.T) {
+		assert.NoError(t, datasource.GetQuotaOverrideManager().DeleteQuotaOverride(ctx, "test-quota-domain", "test-quota-project"))
+		_, err := datasource.GetQuotaOverrideManager().GetQuotaOverride(ctx, "test-quota-domain", "test-quota-project")
+		assert.Equal(t, datasource.ErrQuotaOverrideNotExist, err)
+		_, err = datasource.GetQuotaOverrideManager().GetQuotaOverride(ctx, "test-quota-domain", "")
+		assert.NoError(t, err)
+		assert.NoError(t, datasource.GetQuotaOverrideManager().DeleteQuotaOverride(ctx, "test-quota-domain", ""))
+	})
+}
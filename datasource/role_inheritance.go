@@ -0,0 +1,39 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package datasource
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrRoleInheritanceNotExist = errors.New("role inheritance not exist")
+
+// RoleInheritanceManager saves the parent roles of the roles,
+// a role inherits all the permissions of its parents
+type RoleInheritanceManager interface {
+	GetRoleInheritance(ctx context.Context, role string) (*RoleInheritance, error)
+	ListRoleInheritance(ctx context.Context) ([]*RoleInheritance, error)
+	// UpsertRoleInheritance saves or replaces the parents of the role
+	UpsertRoleInheritance(ctx context.Context, r *RoleInheritance) error
+	DeleteRoleInheritance(ctx context.Context, role string) error
+}
+
+type RoleInheritance struct {
+	Role    string   `json:"role,omitempty"`
+	Parents []string `json:"parents,omitempty"`
+}
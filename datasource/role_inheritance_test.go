@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datasource_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+)
+
+func TestRoleInheritance(t *testing.T) {
+	ctx := context.Background()
+	r := &datasource.RoleInheritance{Role: "test-inheritance-role", Parents: []string{"developer"}}
+	t.Run("get not exist inheritance, should return not exist error", func(t *testing.T) {
+		_, err := datasource.GetRoleInheritanceManager().GetRoleInheritance(ctx, r.Role)
+		assert.Equal(t, datasource.ErrRoleInheritanceNotExist, err)
+	})
+	t.Run("upsert, get and list inheritance", func(t *testing.T) {
+		err := datasource.GetRoleInheritanceManager().UpsertRoleInheritance(ctx, r)
+		assert.NoError(t, err)
+		r.Parents = []string{"developer", "test-parent"}
+		err = datasource.GetRoleInheritanceManager().UpsertRoleInheritance(ctx, r)
+		assert.NoError(t, err)
+		got, err := datasource.GetRoleInheritanceManager().GetRoleInheritance(ctx, r.Role)
+		assert.NoError(t, err)
+		assert.Equal(t, r, got)
+		rs, err := datasource.GetRoleInheritanceManager().ListRoleInheritance(ctx)
+		assert.NoError(t, err)
+		assert.Contains(t, rs, r)
+	})
+	t.Run("delete inheritance", func(t *testing.T) {
+		err := datasource.GetRoleInheritanceManager().DeleteRoleInheritance(ctx, r.Role)
+		assert.NoError(t, err)
+		_, err = datasource.GetRoleInheritanceManager().GetRoleInheritance(ctx, r.Role)
+		assert.Equal(t, datasource.ErrRoleInheritanceNotExist, err)
+	})
+}
@@ -0,0 +1,597 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-chassis/cari/discovery"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+const (
+	// RuleTypeWhite and RuleTypeBlack are the legacy rule types,
+	// a service can only contain one of them
+	RuleTypeWhite = "WHITE"
+	RuleTypeBlack = "BLACK"
+	// RuleTypeAllow and RuleTypeDeny are the rule types with an explicit effect,
+	// they can be mixed with each other and the legacy rules
+	RuleTypeAllow = "ALLOW"
+	RuleTypeDeny  = "DENY"
+
+	// RuleAttrExpression is the attribute of the rule whose pattern is a condition expression
+	RuleAttrExpression = "expr"
+	// RuleAttrIP is the attribute of the consumer source ip,
+	// the pattern is a comma separated list of ip or CIDR
+	RuleAttrIP = "ip"
+
+	ruleAttrTagPrefix = "tag_"
+)
+
+// the decision reasons of the legacy rules
+const (
+	RuleReasonInWhiteList    = "Found in white list"
+	RuleReasonInBlackList    = "Found in black list"
+	RuleReasonNotInWhiteList = "Not found in white list"
+)
+
+// ErrRuleIPUnavailable means the rule compares the consumer ip which is unknown
+var ErrRuleIPUnavailable = errors.New("consumer ip is unavailable")
+
+// Rule is the service rule with its evaluation priority
+type Rule struct {
+	*discovery.ServiceRule
+	// Priority is the evaluation order of the rule, the higher the earlier
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// NewRules wraps the service rules with the priorities, the key of priorities is the ruleId
+func NewRules(rules []*discovery.ServiceRule, priorities map[string]int32) []*Rule {
+	wrapped := make([]*Rule, 0, len(rules))
+	for _, rule := range rules {
+		wrapped = append(wrapped, &Rule{ServiceRule: rule, Priority: priorities[rule.RuleId]})
+	}
+	return wrapped
+}
+
+// RuleSubject is the consumer evaluated by the provider rules
+type RuleSubject struct {
+	Consumer *discovery.MicroService
+	Tags     map[string]string
+	// IP is the source ip of the consumer, if it is empty, the allow rules comparing the ip
+	// are not matched and the deny rules comparing the ip are matched
+	IP string
+}
+
+// RuleTrace is the evaluation result of one rule
+type RuleTrace struct {
+	RuleID    string `json:"ruleId,omitempty"`
+	RuleType  string `json:"ruleType"`
+	Attribute string `json:"attribute"`
+	Pattern   string `json:"pattern"`
+	Priority  int32  `json:"priority"`
+	Matched   bool   `json:"matched"`
+	// Skipped is true if the rule compares the consumer ip which is unavailable,
+	// the deny rule is treated as matched and the allow rule is not
+	Skipped bool `json:"skipped,omitempty"`
+	// Values are the consumer values of the attributes compared by the rule
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// RuleDecision is the explainable result of the rules evaluation
+type RuleDecision struct {
+	Allowed bool `json:"allowed"`
+	// RuleID is the id of the rule which makes the decision, empty means the default decision
+	RuleID string `json:"ruleId,omitempty"`
+	Reason string `json:"reason"`
+	// Traces are the evaluation results of the rules in the evaluation order
+	Traces []*RuleTrace `json:"traces,omitempty"`
+}
+
+type ruleExpr interface {
+	match(subject *RuleSubject) (bool, error)
+}
+
+type orExpr []ruleExpr
+
+// orExpr and andExpr treat the unavailable ip as unknown, the result is unknown
+// only if it depends on the ip condition
+func (e orExpr) match(subject *RuleSubject) (bool, error) {
+	var unknown error
+	for _, sub := range e {
+		ok, err := sub.match(subject)
+		if errors.Is(err, ErrRuleIPUnavailable) {
+			unknown = err
+			continue
+		}
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, unknown
+}
+
+type andExpr []ruleExpr
+
+func (e andExpr) match(subject *RuleSubject) (bool, error) {
+	var unknown error
+	for _, sub := range e {
+		ok, err := sub.match(subject)
+		if errors.Is(err, ErrRuleIPUnavailable) {
+			unknown = err
+			continue
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return unknown == nil, unknown
+}
+
+type notExpr struct {
+	expr ruleExpr
+}
+
+func (e notExpr) match(subject *RuleSubject) (bool, error) {
+	ok, err := e.expr.match(subject)
+	return !ok && err == nil, err
+}
+
+type condExpr struct {
+	attr  string
+	op    string
+	value string
+	// legacy is the condition of the rule with a single attribute,
+	// it never matches the empty attribute value
+	legacy bool
+}
+
+func (e condExpr) match(subject *RuleSubject) (bool, error) {
+	if e.attr == RuleAttrIP {
+		if len(subject.IP) == 0 {
+			return false, ErrRuleIPUnavailable
+		}
+		ok := matchIP(subject.IP, e.value)
+		if e.op == "!=" || e.op == "!~" {
+			return !ok, nil
+		}
+		return ok, nil
+	}
+	value, err := attributeValue(subject, e.attr)
+	if err != nil {
+		return false, err
+	}
+	if e.legacy && len(value) == 0 {
+		return false, nil
+	}
+	switch e.op {
+	case "=":
+		return value == e.value, nil
+	case "!=":
+		return value != e.value, nil
+	case "!~":
+		ok, _ := regexp.MatchString(e.value, value)
+		return !ok, nil
+	default:
+		ok, _ := regexp.MatchString(e.value, value)
+		return ok, nil
+	}
+}
+
+// collectValues returns the consumer values of the attributes referenced by the expression
+func collectValues(expr ruleExpr, subject *RuleSubject, values map[string]string) {
+	switch e := expr.(type) {
+	case orExpr:
+		for _, sub := range e {
+			collectValues(sub, subject, values)
+		}
+	case andExpr:
+		for _, sub := range e {
+			collectValues(sub, subject, values)
+		}
+	case notExpr:
+		collectValues(e.expr, subject, values)
+	case condExpr:
+		if e.attr == RuleAttrIP {
+			values[e.attr] = subject.IP
+			return
+		}
+		value, err := attributeValue(subject, e.attr)
+		if err == nil {
+			values[e.attr] = value
+		}
+	}
+}
+
+func attributeValue(subject *RuleSubject, attr string) (string, error) {
+	if strings.HasPrefix(attr, ruleAttrTagPrefix) {
+		return subject.Tags[attr[len(ruleAttrTagPrefix):]], nil
+	}
+	field := reflect.Indirect(reflect.ValueOf(subject.Consumer)).FieldByName(attr)
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return "", fmt.Errorf("can not find field '%s'", attr)
+	}
+	return field.String(), nil
+}
+
+func matchIP(ip string, patterns string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if _, ipNet, err := net.ParseCIDR(pattern); err == nil {
+			if ipNet.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if target := net.ParseIP(pattern); target != nil && target.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleExprParser parses the condition expression, the syntax is:
+//
+//	expr := and ('||' and)*
+//	and  := unary ('&&' unary)*
+//	unary := '!' unary | '(' expr ')' | cond
+//	cond := attribute ('=' | '!=' | '~' | '!~') value
+//
+// the value can be quoted by '"' if it contains spaces or the characters '(', ')', '&', '|'
+type ruleExprParser struct {
+	s   string
+	pos int
+}
+
+func parseRuleExpression(s string) (ruleExpr, error) {
+	p := &ruleExprParser{s: s}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected '%s'", p.s[p.pos:])
+	}
+	return expr, nil
+}
+
+func (p *ruleExprParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid expression at %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *ruleExprParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *ruleExprParser) consume(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.s[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *ruleExprParser) parseOr() (ruleExpr, error) {
+	var exprs orExpr
+	for {
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+		if !p.consume("||") {
+			break
+		}
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *ruleExprParser) parseAnd() (ruleExpr, error) {
+	var exprs andExpr
+	for {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+		if !p.consume("&&") {
+			break
+		}
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *ruleExprParser) parseUnary() (ruleExpr, error) {
+	if p.consume("!") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: expr}, nil
+	}
+	if p.consume("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, p.errorf("missing ')'")
+		}
+		return expr, nil
+	}
+	return p.parseCond()
+}
+
+func (p *ruleExprParser) parseCond() (ruleExpr, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) && isAttributeChar(p.s[p.pos]) {
+		p.pos++
+	}
+	attr := p.s[start:p.pos]
+	if len(attr) == 0 {
+		return nil, p.errorf("missing attribute")
+	}
+	if err := validateRuleAttribute(attr); err != nil {
+		return nil, err
+	}
+
+	var op string
+	for _, token := range []string{"!=", "!~", "=", "~"} {
+		if p.consume(token) {
+			op = token
+			break
+		}
+	}
+	if len(op) == 0 {
+		return nil, p.errorf("missing operator after '%s'", attr)
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if attr == RuleAttrIP {
+		if err := validateIPPatterns(value); err != nil {
+			return nil, err
+		}
+	}
+	return condExpr{attr: attr, op: op, value: value}, nil
+}
+
+func (p *ruleExprParser) parseValue() (string, error) {
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == '"' {
+		var b strings.Builder
+		for p.pos++; p.pos < len(p.s); p.pos++ {
+			c := p.s[p.pos]
+			if c == '\\' && p.pos+1 < len(p.s) && p.s[p.pos+1] == '"' {
+				p.pos++
+				c = '"'
+			} else if c == '"' {
+				p.pos++
+				return b.String(), nil
+			}
+			b.WriteByte(c)
+		}
+		return "", p.errorf("missing '\"'")
+	}
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune(" ()&|", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("missing value")
+	}
+	return p.s[start:p.pos], nil
+}
+
+func isAttributeChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.'
+}
+
+func validateRuleAttribute(attr string) error {
+	if attr == RuleAttrIP || (strings.HasPrefix(attr, ruleAttrTagPrefix) && len(attr) > len(ruleAttrTagPrefix)) {
+		return nil
+	}
+	_, err := attributeValue(&RuleSubject{Consumer: &discovery.MicroService{}}, attr)
+	return err
+}
+
+func validateIPPatterns(patterns string) error {
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if _, _, err := net.ParseCIDR(pattern); err == nil {
+			continue
+		}
+		if net.ParseIP(pattern) == nil {
+			return fmt.Errorf("invalid ip or CIDR '%s'", pattern)
+		}
+	}
+	return nil
+}
+
+// IsLegacyRuleType returns true if the rule type is WHITE or BLACK
+func IsLegacyRuleType(ruleType string) bool {
+	return ruleType == RuleTypeWhite || ruleType == RuleTypeBlack
+}
+
+func isExpressionRule(rule *discovery.ServiceRule) bool {
+	return rule.Attribute == RuleAttrExpression
+}
+
+func isAllowRule(rule *discovery.ServiceRule) bool {
+	return rule.RuleType == RuleTypeWhite || rule.RuleType == RuleTypeAllow
+}
+
+func toRuleExpr(rule *discovery.ServiceRule) (ruleExpr, error) {
+	if isExpressionRule(rule) {
+		return parseRuleExpression(rule.Pattern)
+	}
+	return condExpr{attr: rule.Attribute, op: "~", value: rule.Pattern, legacy: true}, nil
+}
+
+// AllowAcrossDimension checks whether the consumer can access the provider
+// in the other app or environment
+func AllowAcrossDimension(ctx context.Context, provider *discovery.MicroService, consumer *discovery.MicroService) error {
+	if provider.AppId != consumer.AppId {
+		if len(provider.Properties) == 0 {
+			return fmt.Errorf("not allow across app access")
+		}
+
+		if allowCrossApp, ok := provider.Properties[discovery.PropAllowCrossApp]; !ok || strings.ToLower(allowCrossApp) != "true" {
+			return fmt.Errorf("not allow across app access")
+		}
+	}
+
+	if !IsGlobal(discovery.MicroServiceToKey(util.ParseTargetDomainProject(ctx), provider)) &&
+		provider.Environment != consumer.Environment {
+		return fmt.Errorf("not allow across environment access")
+	}
+	return nil
+}
+
+// ValidateRule checks the attribute and pattern of the rule
+func ValidateRule(rule *discovery.ServiceRule) error {
+	if !isExpressionRule(rule) {
+		if err := validateRuleAttribute(rule.Attribute); err != nil {
+			return err
+		}
+		if rule.Attribute == RuleAttrIP {
+			return validateIPPatterns(rule.Pattern)
+		}
+		return nil
+	}
+	if IsLegacyRuleType(rule.RuleType) {
+		return fmt.Errorf("the attribute '%s' is only supported by the rule type %s or %s",
+			rule.Attribute, RuleTypeAllow, RuleTypeDeny)
+	}
+	_, err := parseRuleExpression(rule.Pattern)
+	return err
+}
+
+// sortRules sorts the rules in the evaluation order: the higher priority first,
+// the deny rules first in the same priority, then the earlier created first
+func sortRules(rules []*Rule) []*Rule {
+	sorted := make([]*Rule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		pi, pj := sorted[i].Priority, sorted[j].Priority
+		if pi != pj {
+			return pi > pj
+		}
+		ai, aj := isAllowRule(sorted[i].ServiceRule), isAllowRule(sorted[j].ServiceRule)
+		if ai != aj {
+			return aj
+		}
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+	return sorted
+}
+
+// EvaluateRules evaluates the provider rules in the priority order, the first matched rule
+// makes the decision. If no rule matched, the consumer is denied if any allow rule exists.
+// The rules comparing the consumer ip fail closed if the ip is unavailable, that is
+// the deny rules are matched and the allow rules are not.
+// If explain is true, all the rules are evaluated and traced in the decision.
+func EvaluateRules(rules []*Rule, subject *RuleSubject, explain bool) (*RuleDecision, error) {
+	if subject == nil || subject.Consumer == nil {
+		return nil, fmt.Errorf("consumer is nil")
+	}
+	decision := &RuleDecision{Allowed: true, Reason: "no rule matched"}
+	var (
+		decided  bool
+		hasAllow bool
+	)
+	for _, rule := range sortRules(rules) {
+		allow := isAllowRule(rule.ServiceRule)
+		hasAllow = hasAllow || allow
+		if decided && !explain {
+			continue
+		}
+		expr, err := toRuleExpr(rule.ServiceRule)
+		if err != nil {
+			return nil, err
+		}
+		matched, err := expr.match(subject)
+		skipped := errors.Is(err, ErrRuleIPUnavailable)
+		if err != nil && !skipped {
+			return nil, err
+		}
+		if skipped && !allow {
+			matched = true
+		}
+		if explain {
+			values := make(map[string]string)
+			collectValues(expr, subject, values)
+			decision.Traces = append(decision.Traces, &RuleTrace{
+				RuleID:    rule.RuleId,
+				RuleType:  rule.RuleType,
+				Attribute: rule.Attribute,
+				Pattern:   rule.Pattern,
+				Priority:  rule.Priority,
+				Matched:   matched,
+				Skipped:   skipped,
+				Values:    values,
+			})
+		}
+		if !matched || decided {
+			continue
+		}
+		decided = true
+		decision.Allowed = allow
+		decision.RuleID = rule.RuleId
+		switch {
+		case skipped:
+			decision.Reason = fmt.Sprintf("Matched %s rule, %s", rule.RuleType, ErrRuleIPUnavailable.Error())
+		case rule.RuleType == RuleTypeWhite:
+			decision.Reason = RuleReasonInWhiteList
+		case rule.RuleType == RuleTypeBlack:
+			decision.Reason = RuleReasonInBlackList
+		default:
+			decision.Reason = fmt.Sprintf("Matched %s rule", rule.RuleType)
+		}
+	}
+	if !decided && hasAllow {
+		decision.Allowed = false
+		decision.Reason = RuleReasonNotInWhiteList
+	}
+	return decision, nil
+}
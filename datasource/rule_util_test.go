@@ -0,0 +1,182 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package datasource_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+func TestEvaluateRules(t *testing.T) {
+	subject := &datasource.RuleSubject{
+		Consumer: &discovery.MicroService{
+			AppId:       "app",
+			ServiceName: "consumer",
+			Environment: "production",
+		},
+		Tags: map[string]string{"team": "a", "zone": "z1"},
+		IP:   "10.0.1.2",
+	}
+
+	t.Run("legacy rules should be compatible", func(t *testing.T) {
+		decision, err := datasource.EvaluateRules(datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "WHITE", Attribute: "ServiceName", Pattern: "^other$"},
+		}, nil), subject, false)
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+
+		decision, err = datasource.EvaluateRules(datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "BLACK", Attribute: "tag_team", Pattern: "^a$"},
+		}, nil), subject, false)
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, "1", decision.RuleID)
+
+		_, err = datasource.EvaluateRules(datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "BLACK", Attribute: "Unknown", Pattern: "^a$"},
+		}, nil), subject, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("expression rules should be evaluated by priority", func(t *testing.T) {
+		rules := datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "DENY", Attribute: "expr", Pattern: "tag_zone=z1"},
+			{RuleId: "2", RuleType: "ALLOW", Attribute: "expr",
+				Pattern: `tag_team=a && (Environment=production || AppId~"^(x|y)$") && ip=10.0.0.0/16`},
+		}, map[string]int32{"2": 10})
+		decision, err := datasource.EvaluateRules(rules, subject, true)
+		assert.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, "2", decision.RuleID)
+		assert.Equal(t, 2, len(decision.Traces))
+		assert.Equal(t, int32(10), decision.Traces[0].Priority)
+		assert.Equal(t, map[string]string{
+			"tag_team": "a", "Environment": "production", "AppId": "app", "ip": "10.0.1.2",
+		}, decision.Traces[0].Values)
+		assert.True(t, decision.Traces[1].Matched)
+		assert.Equal(t, map[string]string{"tag_zone": "z1"}, decision.Traces[1].Values)
+
+		decision, err = datasource.EvaluateRules(rules, &datasource.RuleSubject{
+			Consumer: subject.Consumer,
+			Tags:     subject.Tags,
+			IP:       "192.168.0.1",
+		}, false)
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, "1", decision.RuleID)
+	})
+
+	t.Run("deny rule should win in the same priority", func(t *testing.T) {
+		decision, err := datasource.EvaluateRules(datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "ALLOW", Attribute: "AppId", Pattern: "^app$"},
+			{RuleId: "2", RuleType: "DENY", Attribute: "ip", Pattern: "10.0.1.2,172.16.0.0/12"},
+		}, nil), subject, false)
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, "2", decision.RuleID)
+	})
+
+	t.Run("no rule matched should deny if any allow rule exists", func(t *testing.T) {
+		decision, err := datasource.EvaluateRules(datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "ALLOW", Attribute: "expr", Pattern: "!(tag_team=a)"},
+		}, nil), subject, false)
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Empty(t, decision.RuleID)
+
+		decision, err = datasource.EvaluateRules(datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "DENY", Attribute: "expr", Pattern: "tag_team!=a"},
+		}, nil), subject, false)
+		assert.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	})
+
+	t.Run("ip rules should fail closed if the ip is unavailable", func(t *testing.T) {
+		noIP := &datasource.RuleSubject{Consumer: subject.Consumer, Tags: subject.Tags}
+		decision, err := datasource.EvaluateRules(datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "DENY", Attribute: "expr", Pattern: "ip!=10.0.0.0/8"},
+		}, nil), noIP, true)
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, "1", decision.RuleID)
+		assert.True(t, decision.Traces[0].Skipped)
+		assert.True(t, decision.Traces[0].Matched)
+
+		decision, err = datasource.EvaluateRules(datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "DENY", Attribute: "ip", Pattern: "10.0.0.0/8"},
+			{RuleId: "2", RuleType: "ALLOW", Attribute: "AppId", Pattern: "^app$"},
+		}, nil), noIP, false)
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, "1", decision.RuleID)
+
+		decision, err = datasource.EvaluateRules(datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "DENY", Attribute: "expr", Pattern: "tag_team=a || ip=10.0.0.0/8"},
+		}, nil), noIP, false)
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+
+		decision, err = datasource.EvaluateRules(datasource.NewRules([]*discovery.ServiceRule{
+			{RuleId: "1", RuleType: "ALLOW", Attribute: "ip", Pattern: "10.0.0.0/8"},
+		}, nil), noIP, false)
+		assert.NoError(t, err)
+		assert.False(t, decision.Allowed)
+		assert.Empty(t, decision.RuleID)
+	})
+}
+
+func TestAllowAcrossDimension(t *testing.T) {
+	ctx := util.SetDomainProject(context.Background(), "default", "default")
+	provider := &discovery.MicroService{AppId: "app", ServiceName: "provider", Environment: "production"}
+
+	err := datasource.AllowAcrossDimension(ctx, provider, &discovery.MicroService{AppId: "app", Environment: "production"})
+	assert.NoError(t, err)
+
+	err = datasource.AllowAcrossDimension(ctx, provider, &discovery.MicroService{AppId: "other", Environment: "production"})
+	assert.EqualError(t, err, "not allow across app access")
+
+	err = datasource.AllowAcrossDimension(ctx, provider, &discovery.MicroService{AppId: "app", Environment: "development"})
+	assert.EqualError(t, err, "not allow across environment access")
+
+	provider.Properties = map[string]string{discovery.PropAllowCrossApp: "true"}
+	err = datasource.AllowAcrossDimension(ctx, provider, &discovery.MicroService{AppId: "other", Environment: "production"})
+	assert.NoError(t, err)
+}
+
+func TestValidateRule(t *testing.T) {
+	assert.NoError(t, datasource.ValidateRule(&discovery.ServiceRule{
+		RuleType: "ALLOW", Attribute: "expr", Pattern: `tag_a=b || (ip=10.0.0.0/8 && ServiceName~"^a|b$")`}))
+	assert.NoError(t, datasource.ValidateRule(&discovery.ServiceRule{
+		RuleType: "DENY", Attribute: "ip", Pattern: "10.0.0.1, 192.168.0.0/16"}))
+	assert.Error(t, datasource.ValidateRule(&discovery.ServiceRule{
+		RuleType: "WHITE", Attribute: "expr", Pattern: "tag_a=b"}))
+	assert.Error(t, datasource.ValidateRule(&discovery.ServiceRule{
+		RuleType: "ALLOW", Attribute: "expr", Pattern: "tag_a=b && (ip=10.0.0.0/8"}))
+	assert.Error(t, datasource.ValidateRule(&discovery.ServiceRule{
+		RuleType: "ALLOW", Attribute: "expr", Pattern: "Unknown=b"}))
+	assert.Error(t, datasource.ValidateRule(&discovery.ServiceRule{
+		RuleType: "DENY", Attribute: "ip", Pattern: "10.0.0.300"}))
+	assert.Error(t, datasource.ValidateRule(&discovery.ServiceRule{
+		RuleType: "ALLOW", Attribute: "expr_1", Pattern: "tag_a=b"}))
+}
@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package datasource
+
+import (
+	"context"
+)
+
+// SessionManager saves the issued tokens and the token revocation list
+type SessionManager interface {
+	CreateSession(ctx context.Context, s *Session) error
+	// ListSession returns the sessions of the account, all sessions if account is empty
+	ListSession(ctx context.Context, account string) ([]*Session, error)
+	DeleteSession(ctx context.Context, account, id string) error
+	// Revoke saves or replaces the revocation with the same key
+	Revoke(ctx context.Context, r *Revocation) error
+	ListRevocation(ctx context.Context) ([]*Revocation, error)
+	DeleteRevocation(ctx context.Context, key string) error
+}
+
+// Session is a token issued by service center, ID is the jti claim
+type Session struct {
+	ID       string `json:"id,omitempty"`
+	Account  string `json:"account,omitempty"`
+	IP       string `json:"ip,omitempty"`
+	IssuedAt int64  `json:"issuedAt,omitempty" bson:"issued_at"`
+	ExpireAt int64  `json:"expireAt,omitempty" bson:"expire_at"`
+}
+
+// Revocation revokes the token of TokenID, or all the tokens of
+// Account issued not after RevokedAt if TokenID is empty, RevokedAt is
+// the unix time in milliseconds
+type Revocation struct {
+	Key       string `json:"key,omitempty"`
+	Account   string `json:"account,omitempty"`
+	TokenID   string `json:"tokenID,omitempty" bson:"token_id"`
+	RevokedAt int64  `json:"revokedAt,omitempty" bson:"revoked_at"`
+	// ExpireAt is the expiry of the revoked token, the revocation can be removed after it
+	ExpireAt int64 `json:"expireAt,omitempty" bson:"expire_at"`
+}
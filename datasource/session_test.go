@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datasource_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+)
+
+func TestSession(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	s := &datasource.Session{ID: "test-session-1", Account: "test-session-account", IssuedAt: now, ExpireAt: now + 60}
+	t.Run("add and list sessions", func(t *testing.T) {
+		err := datasource.GetSessionManager().CreateSession(ctx, s)
+		assert.NoError(t, err)
+		err = datasource.GetSessionManager().CreateSession(ctx,
+			&datasource.Session{ID: "test-session-2", Account: "test-session-account2"})
+		assert.NoError(t, err)
+		sessions, err := datasource.GetSessionManager().ListSession(ctx, s.Account)
+		assert.NoError(t, err)
+		assert.Equal(t, []*datasource.Session{s}, sessions)
+		sessions, err = datasource.GetSessionManager().ListSession(ctx, "")
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, len(sessions), 2)
+	})
+	t.Run("delete sessions", func(t *testing.T) {
+		err := datasource.GetSessionManager().DeleteSession(ctx, s.Account, s.ID)
+		assert.NoError(t, err)
+		err = datasource.GetSessionManager().DeleteSession(ctx, "test-session-account2", "test-session-2")
+		assert.NoError(t, err)
+		sessions, err := datasource.GetSessionManager().ListSession(ctx, s.Account)
+		assert.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+	t.Run("revoke and list revocations", func(t *testing.T) {
+		r := &datasource.Revocation{Key: "test-revocation", Account: s.Account, RevokedAt: now}
+		err := datasource.GetSessionManager().Revoke(ctx, r)
+		assert.NoError(t, err)
+		r.RevokedAt = now + 1
+		err = datasource.GetSessionManager().Revoke(ctx, r)
+		assert.NoError(t, err)
+		rs, err := datasource.GetSessionManager().ListRevocation(ctx)
+		assert.NoError(t, err)
+		var found *datasource.Revocation
+		for _, item := range rs {
+			if item.Key == r.Key {
+				found = item
+			}
+		}
+		assert.Equal(t, r, found)
+		err = datasource.GetSessionManager().DeleteRevocation(ctx, r.Key)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,45 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package datasource
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrTOTPNotExist = errors.New("totp not exist")
+
+// TOTPManager saves the TOTP second factor enrolments of the accounts
+type TOTPManager interface {
+	GetTOTP(ctx context.Context, account string) (*TOTP, error)
+	// UpsertTOTP saves or replaces the enrolment of the account
+	UpsertTOTP(ctx context.Context, t *TOTP) error
+	DeleteTOTP(ctx context.Context, account string) error
+}
+
+type TOTP struct {
+	Account string `json:"account,omitempty"`
+	// Secret is encrypted by the cipher plugin
+	Secret string `json:"secret,omitempty"`
+	// Enabled is true after the first code is verified
+	Enabled bool `json:"enabled,omitempty"`
+	// RecoveryCodes is the hashes of the unused recovery codes
+	RecoveryCodes []string `json:"recoveryCodes,omitempty" bson:"recovery_codes"`
+	// LastStep is the time step of the last used code, it can not be replayed
+	LastStep   int64  `json:"lastStep,omitempty" bson:"last_step"`
+	CreateTime string `json:"createTime,omitempty" bson:"create_time"`
+}
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package datasource_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+)
+
+func TestTOTP(t *testing.T) {
+	ctx := context.Background()
+	r := &datasource.TOTP{Account: "test-totp-account", Secret: "secret", RecoveryCodes: []string{"h1", "h2"}}
+	t.Run("get not exist totp, should return not exist error", func(t *testing.T) {
+		_, err := datasource.GetTOTPManager().GetTOTP(ctx, r.Account)
+		assert.Equal(t, datasource.ErrTOTPNotExist, err)
+	})
+	t.Run("upsert and get totp", func(t *testing.T) {
+		err := datasource.GetTOTPManager().UpsertTOTP(ctx, r)
+		assert.NoError(t, err)
+		r.Enabled = true
+		r.RecoveryCodes = []string{"h2"}
+		r.LastStep = time.Now().Unix() / 30
+		err = datasource.GetTOTPManager().UpsertTOTP(ctx, r)
+		assert.NoError(t, err)
+		got, err := datasource.GetTOTPManager().GetTOTP(ctx, r.Account)
+		assert.NoError(t, err)
+		assert.Equal(t, r, got)
+	})
+	t.Run("delete totp", func(t *testing.T) {
+		err := datasource.GetTOTPManager().DeleteTOTP(ctx, r.Account)
+		assert.NoError(t, err)
+		_, err = datasource.GetTOTPManager().GetTOTP(ctx, r.Account)
+		assert.Equal(t, datasource.ErrTOTPNotExist, err)
+	})
+}
@@ -20,6 +20,7 @@ require (
 	github.com/go-chassis/go-archaius v1.5.1
 	github.com/go-chassis/go-chassis/v2 v2.2.1-0.20210630123055-6b4c31c5ad02
 	github.com/go-chassis/kie-client v0.1.0
+	github.com/go-ldap/ldap/v3 v3.4.1
 	github.com/golang/protobuf v1.4.3
 	github.com/gorilla/websocket v1.4.3-0.20210424162022-e8629af678b7
 	github.com/hashicorp/serf v0.8.3
@@ -27,6 +28,7 @@ require (
 	github.com/jinzhu/copier v0.3.0
 	github.com/karlseguin/ccache v2.0.3-0.20170217060820-3ba9789cfd2c+incompatible
 	github.com/labstack/echo/v4 v4.1.18-0.20201218141459-936c48a17e97
+	github.com/lor00x/goldap v0.0.0-20180618054307-a546dffdd1a3
 	github.com/natefinch/lumberjack v0.0.0-20170531160350-a96e63847dc3
 	github.com/olekukonko/tablewriter v0.0.5
 	github.com/onsi/ginkgo v1.15.0
@@ -38,6 +40,7 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/prometheus/client_golang v1.8.0
 	github.com/prometheus/client_model v0.2.0
+	github.com/prometheus/common v0.14.0
 	github.com/prometheus/procfs v0.2.0
 	github.com/rs/cors v1.7.0 // v1.1
 	github.com/satori/go.uuid v1.1.0
@@ -45,6 +48,7 @@ require (
 	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.7.0
 	github.com/urfave/cli v1.22.4
+	github.com/vjeantet/ldapserver v1.0.1
 	github.com/widuu/gojson v0.0.0-20170212122013-7da9d2cd949b
 	go.mongodb.org/mongo-driver v1.4.2
 	go.uber.org/zap v1.13.0
@@ -21,7 +21,9 @@ import (
 	"github.com/go-chassis/cari/discovery"
 )
 
+// AlarmListRequest lists the current alarms, Filter is optional
 type AlarmListRequest struct {
+	Filter *model.HistoryFilter
 }
 
 type AlarmListResponse struct {
@@ -43,3 +45,69 @@ type ClearAlarmRequest struct {
 type ClearAlarmResponse struct {
 	Response *discovery.Response `json:"-"`
 }
+
+type AlarmHistoryRequest struct {
+	Filter *model.HistoryFilter
+}
+
+type AlarmHistoryResponse struct {
+	Response *discovery.Response `json:"-"`
+	History  []*model.History    `json:"history,omitempty"`
+}
+
+// AckAlarmRequest acknowledges the alarm raised by the Instance,
+// or by all instances if Instance is empty
+type AckAlarmRequest struct {
+	ID       model.ID `json:"-"`
+	Instance string   `json:"-"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+type AckAlarmResponse struct {
+	Response *discovery.Response `json:"-"`
+}
+
+// AuditLog is the record of a mutating API call, Timestamp is in milliseconds
+type AuditLog struct {
+	ID        string   `json:"id,omitempty"`
+	Timestamp int64    `json:"timestamp,omitempty"`
+	Actor     string   `json:"actor,omitempty"`
+	Domain    string   `json:"domain,omitempty"`
+	Project   string   `json:"project,omitempty"`
+	Resource  string   `json:"resource,omitempty"`
+	Verb      string   `json:"verb,omitempty"`
+	Method    string   `json:"method,omitempty"`
+	API       string   `json:"api,omitempty"`
+	TargetIDs []string `json:"targetIds,omitempty" bson:"target_ids"`
+	Code      int      `json:"code,omitempty"`
+	Latency   int64    `json:"latency,omitempty"`
+	SourceIP  string   `json:"sourceIp,omitempty" bson:"source_ip"`
+}
+
+// AuditLogFilter filters the audit logs in [Start, End], zero means unlimited
+type AuditLogFilter struct {
+	Start int64
+	End   int64
+	Actor string
+	Limit int
+}
+
+// Match returns true if the audit log matches the filter, Limit is ignored
+func (f *AuditLogFilter) Match(l *AuditLog) bool {
+	if f.Start > 0 && l.Timestamp < f.Start {
+		return false
+	}
+	if f.End > 0 && l.Timestamp > f.End {
+		return false
+	}
+	return len(f.Actor) == 0 || l.Actor == f.Actor
+}
+
+type AuditLogRequest struct {
+	Filter *AuditLogFilter
+}
+
+type AuditLogResponse struct {
+	Response *discovery.Response `json:"-"`
+	Logs     []*AuditLog         `json:"logs,omitempty"`
+}
@@ -0,0 +1,48 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+// HeaderAPIKey is the request header carrying the API key,
+// it can be used instead of the Authorization token
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeyRequest creates an API key of the account, Roles must be
+// the subset of the account roles, empty means all the account roles.
+// ExpireAfter is a duration like 720h, empty means never expire
+type APIKeyRequest struct {
+	Name        string   `json:"name,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	ExpireAfter string   `json:"expireAfter,omitempty"`
+}
+
+// APIKey is the API key info, Key is the plain key which is
+// only returned once when the key is created or rotated
+type APIKey struct {
+	ID         string   `json:"id"`
+	Account    string   `json:"account"`
+	Name       string   `json:"name,omitempty"`
+	Roles      []string `json:"roles,omitempty"`
+	ExpireAt   int64    `json:"expireAt,omitempty"`
+	CreateTime string   `json:"createTime,omitempty"`
+	UpdateTime string   `json:"updateTime,omitempty"`
+	Key        string   `json:"key,omitempty"`
+}
+
+type APIKeyResponse struct {
+	Total   int64     `json:"total"`
+	APIKeys []*APIKey `json:"apiKeys"`
+}
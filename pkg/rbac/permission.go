@@ -0,0 +1,42 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+import "github.com/go-chassis/cari/rbac"
+
+// PermissionCheckRequest asks whether the subject may apply the Verb
+// to the Resource which has the Labels, the subject is the account of
+// the request token if Account is empty
+type PermissionCheckRequest struct {
+	Account  string            `json:"account,omitempty"`
+	Resource string            `json:"resource"`
+	Verb     string            `json:"verb"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+// PermissionCheckResponse is the decision of the PermissionCheckRequest,
+// Role and Permission are the first one granted the request, or the one
+// matched the resource and verb but denied by labels.
+// Labels are the permission labels matched the request
+type PermissionCheckResponse struct {
+	Account    string              `json:"account,omitempty"`
+	Allowed    bool                `json:"allowed"`
+	Role       string              `json:"role,omitempty"`
+	Permission *rbac.Permission    `json:"permission,omitempty"`
+	Labels     []map[string]string `json:"labels,omitempty"`
+	Reason     string              `json:"reason,omitempty"`
+}
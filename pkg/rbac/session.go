@@ -0,0 +1,33 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+// Session is a token issued by service center, Current is true
+// if it is the token of the request
+type Session struct {
+	ID       string `json:"id"`
+	Account  string `json:"account"`
+	IP       string `json:"ip,omitempty"`
+	IssuedAt int64  `json:"issuedAt,omitempty"`
+	ExpireAt int64  `json:"expireAt,omitempty"`
+	Current  bool   `json:"current,omitempty"`
+}
+
+type SessionResponse struct {
+	Total    int64      `json:"total"`
+	Sessions []*Session `json:"sessions"`
+}
@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+// TOTPRequest verifies the code of the authenticator app to enable the enrolment
+type TOTPRequest struct {
+	Code string `json:"code"`
+}
+
+// TOTPLogin is the second factor in the login request body,
+// the code of the authenticator app or a recovery code
+type TOTPLogin struct {
+	Code string `json:"totpCode,omitempty"`
+}
+
+// TOTPEnrollment is returned once when enrolling, URI is the otpauth uri for the QR code
+type TOTPEnrollment struct {
+	Secret        string   `json:"secret"`
+	URI           string   `json:"uri"`
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type TOTPStatus struct {
+	Account string `json:"account"`
+	Enabled bool   `json:"enabled"`
+	// RecoveryCodes is the number of the unused recovery codes
+	RecoveryCodes int    `json:"recoveryCodes"`
+	CreateTime    string `json:"createTime,omitempty"`
+}
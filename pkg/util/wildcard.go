@@ -20,10 +20,24 @@ package util
 import (
 	"regexp"
 	"strings"
+	"sync"
 )
 
+// wildcards caches the compiled regexp of the patterns
+var wildcards sync.Map
+
 func WildcardMatch(pattern, dist string) bool {
-	regx := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), "\\*", ".*") + "$"
-	matched, _ := regexp.MatchString(regx, dist)
-	return matched
+	if !strings.Contains(pattern, "*") {
+		return pattern == dist
+	}
+	return compileWildcard(pattern).MatchString(dist)
+}
+
+func compileWildcard(pattern string) *regexp.Regexp {
+	if r, ok := wildcards.Load(pattern); ok {
+		return r.(*regexp.Regexp)
+	}
+	r := regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), "\\*", ".*") + "$")
+	wildcards.Store(pattern, r)
+	return r
 }
@@ -19,19 +19,31 @@ package validate
 
 import "unicode"
 
+const (
+	DefaultPasswordMinLength = 8
+	DefaultPasswordMaxLength = 32
+	// DefaultPasswordMinClasses requires all of the upper, lower, number and special characters
+	DefaultPasswordMinClasses = 4
+)
+
+// PasswordChecker checks the length and the character classes of the password,
+// the zero value fields use the defaults
 type PasswordChecker struct {
+	MinLength int
+	MaxLength int
+	// MinClasses is the least kinds of the upper, lower, number and special characters
+	MinClasses int
 }
 
 func (p *PasswordChecker) MatchString(s string) bool {
 	var (
-		hasValidLen = false
-		hasUpper    = false
-		hasLower    = false
-		hasNumber   = false
-		hasSpecial  = false
+		hasUpper   = false
+		hasLower   = false
+		hasNumber  = false
+		hasSpecial = false
 	)
-	if len(s) >= 8 && len(s) <= 32 {
-		hasValidLen = true
+	if len(s) < p.minLength() || len(s) > p.maxLength() {
+		return false
 	}
 	for _, char := range s {
 		switch {
@@ -45,8 +57,35 @@ func (p *PasswordChecker) MatchString(s string) bool {
 			hasSpecial = true
 		}
 	}
-	return hasValidLen && hasUpper && hasLower && hasNumber && hasSpecial
+	classes := 0
+	for _, has := range []bool{hasUpper, hasLower, hasNumber, hasSpecial} {
+		if has {
+			classes++
+		}
+	}
+	return classes >= p.minClasses()
 }
 func (p *PasswordChecker) String() string {
 	return "password"
 }
+
+func (p *PasswordChecker) minLength() int {
+	if p.MinLength > 0 {
+		return p.MinLength
+	}
+	return DefaultPasswordMinLength
+}
+
+func (p *PasswordChecker) maxLength() int {
+	if p.MaxLength > 0 {
+		return p.MaxLength
+	}
+	return DefaultPasswordMaxLength
+}
+
+func (p *PasswordChecker) minClasses() int {
+	if p.MinClasses > 0 {
+		return p.MinClasses
+	}
+	return DefaultPasswordMinClasses
+}
@@ -0,0 +1,49 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"testing"
+)
+
+func TestPasswordChecker_MatchString(t *testing.T) {
+	checker := PasswordChecker{}
+	if !checker.MatchString("Pwd0000_1") {
+		t.Fail()
+	}
+	if checker.MatchString("Pwd00_1") {
+		t.Fatalf("Pwd00_1 is shorter than 8")
+	}
+	if checker.MatchString("Pwd00001") {
+		t.Fatalf("Pwd00001 has no special character")
+	}
+
+	checker = PasswordChecker{MinLength: 12, MaxLength: 16, MinClasses: 3}
+	if !checker.MatchString("Pwd000000001") {
+		t.Fatalf("Pwd000000001 has 3 classes")
+	}
+	if checker.MatchString("Pwd0000_1") {
+		t.Fatalf("Pwd0000_1 is shorter than 12")
+	}
+	if checker.MatchString("pwd000000001") {
+		t.Fatalf("pwd000000001 has 2 classes")
+	}
+	if checker.MatchString("Pwd00000000000001") {
+		t.Fatalf("Pwd00000000000001 is longer than 16")
+	}
+}
@@ -29,4 +29,26 @@ import (
 	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/get/cluster"
 
 	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/health"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/apply"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/delete"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/set"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/tag"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/rule"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/watch"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/top"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/account"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/role"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/auth"
+
+	_ "github.com/apache/servicecomb-service-center/scctl/pkg/plugin/audit"
 )
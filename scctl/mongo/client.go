@@ -0,0 +1,82 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-chassis/go-chassis/v2/storage"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/plugin"
+	"github.com/apache/servicecomb-service-center/server/plugin/security/cipher"
+)
+
+const (
+	poolSize    = 10
+	pingTimeout = 10 * time.Second
+)
+
+var registerOnce sync.Once
+
+// plainCipher does not decrypt the uri, the mongo client requires a cipher plugin
+type plainCipher struct {
+}
+
+func (c *plainCipher) Encrypt(src string) (string, error) {
+	return src, nil
+}
+
+func (c *plainCipher) Decrypt(src string) (string, error) {
+	return src, nil
+}
+
+func NewMongoClient(config Config) (*client.MongoClient, error) {
+	registerOnce.Do(func() {
+		// the errors are returned, the logs of the mongo client are useless for scctl
+		cfg := log.Configure()
+		cfg.LoggerLevel = "FATAL"
+		log.SetGlobal(cfg)
+		plugin.RegisterPlugin(plugin.Plugin{Kind: cipher.CIPHER, Name: plugin.Buildin,
+			New: func() plugin.Instance { return &plainCipher{} }})
+	})
+
+	mc := &client.MongoClient{}
+	err := mc.Initialize(storage.Options{
+		URI:        config.URI,
+		PoolSize:   poolSize,
+		SSLEnabled: config.SSLEnabled,
+		RootCA:     config.RootCA,
+		CertFile:   config.CertFile,
+		KeyFile:    config.KeyFile,
+		VerifyPeer: config.VerifyPeer,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the connection is lazy, ping to make sure mongo is available
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := mc.GetDB().Client().Ping(ctx, nil); err != nil {
+		mc.Close()
+		return nil, err
+	}
+	return mc, nil
+}
@@ -35,7 +35,11 @@ var rootCmd = &cobra.Command{
 	Use:   version.TOOL_NAME + " <command>",
 	Short: "The admin control command of service center",
 }
-var ScClientConfig client.Config
+var (
+	ScClientConfig client.Config
+	// ConfigPath is the local config file path, see LocalConfig
+	ConfigPath string
+)
 
 func init() {
 	var timeout string
@@ -49,6 +53,12 @@ func init() {
 		if d, err := time.ParseDuration(timeout); err == nil && d > 0 {
 			ScClientConfig.RequestTimeout = d
 		}
+		if len(ScClientConfig.Token) == 0 {
+			// use the token saved by login command
+			if c, err := LoadLocalConfig(ConfigPath); err == nil {
+				ScClientConfig.Token = c.Token(ScClientConfig.Endpoints)
+			}
+		}
 	}
 
 	rootCmd.PersistentFlags().StringSliceVar(&ScClientConfig.Endpoints, "addr",
@@ -56,7 +66,9 @@ func init() {
 		"the http host and port of service center, can be overrode by env HOSTING_SERVER_IP.")
 
 	rootCmd.PersistentFlags().StringVar(&ScClientConfig.Token, "token", "",
-		"the auth token string to access service center.")
+		"the auth token string to access service center, default is the token saved by login command.")
+	rootCmd.PersistentFlags().StringVar(&ConfigPath, "config", DefaultConfigPath(),
+		"the scctl config file path, can be overrode by env SCCTL_CONFIG.")
 
 	rootCmd.PersistentFlags().BoolVarP(&ScClientConfig.VerifyPeer, "peer", "p", false,
 		"verify service center certificates.")
@@ -0,0 +1,95 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	configDir  = ".scctl"
+	configFile = "config"
+)
+
+// LocalConfig is the scctl config saved in the local file,
+// it keeps the tokens of service center logged in by 'scctl login'
+type LocalConfig struct {
+	// Tokens is the map of service center address to the token
+	Tokens map[string]string `json:"tokens,omitempty"`
+}
+
+// DefaultConfigPath returns the config file path, default is ~/.scctl/config
+func DefaultConfigPath() string {
+	if p := os.Getenv("SCCTL_CONFIG"); len(p) > 0 {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(configDir, configFile)
+	}
+	return filepath.Join(home, configDir, configFile)
+}
+
+// LoadLocalConfig reads the config file, returns an empty config if the file does not exist
+func LoadLocalConfig(path string) (*LocalConfig, error) {
+	c := &LocalConfig{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Save writes the config file, which is only readable by the current user
+func (c *LocalConfig) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func (c *LocalConfig) Token(endpoints []string) string {
+	return c.Tokens[endpointsKey(endpoints)]
+}
+
+func (c *LocalConfig) SetToken(endpoints []string, token string) {
+	if c.Tokens == nil {
+		c.Tokens = make(map[string]string)
+	}
+	c.Tokens[endpointsKey(endpoints)] = token
+}
+
+func (c *LocalConfig) RemoveToken(endpoints []string) {
+	delete(c.Tokens, endpointsKey(endpoints))
+}
+
+func endpointsKey(endpoints []string) string {
+	return strings.Join(endpoints, ",")
+}
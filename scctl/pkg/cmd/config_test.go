@@ -0,0 +1,59 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scctl")
+	if err != nil {
+		t.Fatalf("TestLocalConfig failed, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, configDir, configFile)
+
+	c, err := LoadLocalConfig(path)
+	if err != nil || len(c.Tokens) != 0 {
+		t.Fatalf("TestLocalConfig failed, load not exist file: %v", err)
+	}
+
+	endpoints := []string{"http://127.0.0.1:30100"}
+	c.SetToken(endpoints, "token")
+	if err := c.Save(path); err != nil {
+		t.Fatalf("TestLocalConfig failed, %v", err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil || fi.Mode().Perm() != 0600 {
+		t.Fatalf("TestLocalConfig failed, file mode %v", fi)
+	}
+
+	c, err = LoadLocalConfig(path)
+	if err != nil || c.Token(endpoints) != "token" {
+		t.Fatalf("TestLocalConfig failed, load %v", c)
+	}
+	if c.Token([]string{"http://127.0.0.2:30100"}) != "" {
+		t.Fatalf("TestLocalConfig failed, token of the other address")
+	}
+	c.RemoveToken(endpoints)
+	if c.Token(endpoints) != "" {
+		t.Fatalf("TestLocalConfig failed, remove token")
+	}
+}
@@ -0,0 +1,194 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Yes      bool
+	Roles    []string
+	Password string
+	Current  string
+	Reset    bool
+)
+
+var accountTableHeader = []string{"NAME", "ROLES", "STATUS", "AGE"}
+
+func init() {
+	NewAccountCommand(cmd.RootCmd())
+}
+
+func NewAccountCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account <command> [options]",
+		Short: "Manage the accounts of service center",
+	}
+
+	parent.AddCommand(cmd)
+
+	createCmd := &cobra.Command{
+		Use:   "create NAME [options]",
+		Short: "Create the account, the password is asked if not specified",
+		Args:  cobra.ExactArgs(1),
+		Run:   CreateCommandFunc,
+		Example: cmd.CommandPath() + ` create dev1 --role developer;
+` + cmd.CommandPath() + ` create ops1 --role admin --password 'Complicated_password1'`,
+	}
+	createCmd.Flags().StringSliceVar(&Roles, "role", nil, "the roles of the account")
+	createCmd.Flags().StringVar(&Password, "password", "", "the password of the account")
+	cmd.AddCommand(createCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the accounts",
+		Args:    cobra.NoArgs,
+		Run:     ListCommandFunc,
+		Example: cmd.CommandPath() + ` list`,
+	})
+
+	deleteCmd := &cobra.Command{
+		Use:     "delete NAME... [options]",
+		Aliases: []string{"rm"},
+		Short:   "Delete the accounts",
+		Args:    cobra.MinimumNArgs(1),
+		Run:     DeleteCommandFunc,
+		Example: cmd.CommandPath() + ` delete dev1 dev2 -y`,
+	}
+	deleteCmd.Flags().BoolVarP(&Yes, "yes", "y", false, "delete without the confirmation prompt")
+	cmd.AddCommand(deleteCmd)
+
+	passwdCmd := &cobra.Command{
+		Use:   "passwd NAME [options]",
+		Short: "Change the password of the account, the passwords are asked if not specified",
+		Args:  cobra.ExactArgs(1),
+		Run:   PasswdCommandFunc,
+		Example: cmd.CommandPath() + ` passwd dev1;
+` + cmd.CommandPath() + ` passwd dev1 --reset --password 'Complicated_password2'`,
+	}
+	passwdCmd.Flags().StringVar(&Current, "current", "", "the current password of the account")
+	passwdCmd.Flags().StringVar(&Password, "password", "", "the new password of the account")
+	passwdCmd.Flags().BoolVar(&Reset, "reset", false,
+		"reset the password of the other account without the current password, admin role is required")
+	cmd.AddCommand(passwdCmd)
+	return cmd
+}
+
+func newClient() *client.Client {
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	return scClient
+}
+
+func CreateCommandFunc(_ *cobra.Command, args []string) {
+	password, err := NewPassword(Password)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	account := &rbac.Account{Name: args[0], Password: password, Roles: Roles}
+	if scErr := newClient().CreateAccount(context.Background(), account); scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	fmt.Printf("account %s created\n", account.Name)
+}
+
+func ListCommandFunc(_ *cobra.Command, _ []string) {
+	accounts, scErr := newClient().ListAccounts(context.Background())
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	writer.MakeTable(accountTableHeader, AccountTableBody(accounts))
+}
+
+func AccountTableBody(accounts []*rbac.Account) [][]string {
+	body := make([][]string, 0, len(accounts))
+	for _, a := range accounts {
+		status := a.Status
+		if len(status) == 0 {
+			status = "-"
+		}
+		body = append(body, []string{a.Name, strings.Join(a.Roles, ","), status, writer.AgeFormat(a.CreateTime)})
+	}
+	return body
+}
+
+func DeleteCommandFunc(_ *cobra.Command, args []string) {
+	cmd.ConfirmOrExit(Yes, fmt.Sprintf("Delete the accounts %s?", strings.Join(args, ",")))
+	scClient := newClient()
+	failed := 0
+	for _, name := range args {
+		if scErr := scClient.DeleteAccount(context.Background(), name); scErr != nil {
+			fmt.Printf("delete account %s failed: %s\n", name, scErr.Message)
+			failed++
+			continue
+		}
+		fmt.Printf("account %s deleted\n", name)
+	}
+	if failed > 0 {
+		cmd.StopAndExit(cmd.ExitError)
+	}
+}
+
+func PasswdCommandFunc(_ *cobra.Command, args []string) {
+	current := Current
+	if !Reset && len(current) == 0 {
+		var err error
+		current, err = cmd.ReadPassword("Current password: ")
+		if err != nil {
+			cmd.StopAndExit(cmd.ExitError, err)
+		}
+	}
+	password, err := NewPassword(Password)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	if scErr := newClient().ChangePassword(context.Background(), args[0], current, password); scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	fmt.Printf("password of account %s changed\n", args[0])
+}
+
+// NewPassword returns the password if not empty, otherwise asks the new password twice
+func NewPassword(password string) (string, error) {
+	if len(password) > 0 {
+		return password, nil
+	}
+	password, err := cmd.ReadPassword("New password: ")
+	if err != nil {
+		return "", err
+	}
+	retyped, err := cmd.ReadPassword("Retype new password: ")
+	if err != nil {
+		return "", err
+	}
+	if password != retyped {
+		return "", errors.New("passwords do not match")
+	}
+	return password, nil
+}
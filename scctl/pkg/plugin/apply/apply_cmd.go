@@ -0,0 +1,136 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Domain     string
+	Project    string
+	FileName   string
+	DryRun     bool
+	Prune      bool
+	RolePrefix string
+	Yes        bool
+)
+
+var planTableHeader = []string{"KIND", "NAME", "ACTION", "DETAIL"}
+
+func init() {
+	NewApplyCommand(cmd.RootCmd())
+}
+
+func NewApplyCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply -f FILENAME [options]",
+		Short: "Apply the manifests to service center",
+		Run:   ApplyCommandFunc,
+		Example: parent.CommandPath() + ` apply -f service.yaml;
+` + parent.CommandPath() + ` apply -f manifests/ --dry-run;
+` + parent.CommandPath() + ` apply -f manifests/ -d test --prune`,
+	}
+
+	cmd.Flags().StringVarP(&FileName, "filename", "f", "",
+		"the YAML or JSON file contains the manifests separated by '---', or the directory of the files")
+	cmd.Flags().StringVarP(&Domain, "domain", "d", "default", "the domain of the objects")
+	cmd.Flags().StringVar(&Project, "project", "default", "the project of the objects")
+	cmd.Flags().BoolVar(&DryRun, "dry-run", false, "only print the plan, nothing will be changed")
+	cmd.Flags().BoolVar(&Prune, "prune", false, "delete the objects missing from the manifests")
+	cmd.Flags().StringVar(&RolePrefix, "role-prefix", "",
+		"with --prune, only delete the roles named with the prefix, no role is deleted if it is empty")
+	cmd.Flags().BoolVarP(&Yes, "yes", "y", false, "delete without the confirmation prompt")
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func ApplyCommandFunc(_ *cobra.Command, args []string) {
+	if len(FileName) == 0 {
+		cmd.StopAndExit(cmd.ExitError, "required --filename")
+	}
+	manifests, err := LoadManifests(FileName)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	ctx := context.Background()
+	planner := &Planner{Client: scClient, Domain: Domain, Project: Project, Prune: Prune, RolePrefix: RolePrefix}
+	changes, err := planner.Plan(ctx, manifests)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+
+	writer.MakeTable(planTableHeader, planTableBody(changes))
+	fmt.Println(planSummary(changes))
+	if DryRun {
+		return
+	}
+	if deletes := countAction(changes, ActionDelete); deletes > 0 {
+		cmd.ConfirmOrExit(Yes, fmt.Sprintf("Delete %d objects?", deletes))
+	}
+
+	failed := 0
+	for _, change := range changes {
+		if change.apply == nil {
+			continue
+		}
+		if scErr := change.apply(ctx); scErr != nil {
+			failed++
+			fmt.Printf("%s %s %s failed: %s\n", change.Kind, change.Name, change.Action, scErr.Error())
+			continue
+		}
+		fmt.Printf("%s %s %s done\n", change.Kind, change.Name, change.Action)
+	}
+	if failed > 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Sprintf("error: %d changes apply failed", failed))
+	}
+}
+
+func planTableBody(changes []*Change) [][]string {
+	body := make([][]string, 0, len(changes))
+	for _, change := range changes {
+		body = append(body, writer.Reshape(60, []string{change.Kind, change.Name, change.Action, change.Detail}))
+	}
+	return body
+}
+
+func planSummary(changes []*Change) string {
+	return fmt.Sprintf("Plan: %d to create, %d to update, %d to delete, %d unchanged.",
+		countAction(changes, ActionCreate), countAction(changes, ActionUpdate),
+		countAction(changes, ActionDelete), countAction(changes, ActionUnchanged))
+}
+
+func countAction(changes []*Change, action string) int {
+	n := 0
+	for _, change := range changes {
+		if change.Action == action {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,176 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	"github.com/ghodss/yaml"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+)
+
+// the kinds of the manifests, the document without kind is a MicroService
+const (
+	KindMicroService = "MicroService"
+	KindDependency   = "Dependency"
+	KindPolicy       = "Policy"
+	KindRole         = "Role"
+)
+
+var (
+	documentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+	manifestExts      = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+)
+
+// DependencyManifest declares the providers the consumer depends on
+type DependencyManifest struct {
+	Consumer  *pb.MicroServiceKey   `json:"consumer"`
+	Providers []*pb.MicroServiceKey `json:"providers"`
+}
+
+// PolicyManifest declares a governance policy,
+// Type is the kind of the policy, e.g. 'match-group', 'rate-limiting'
+type PolicyManifest struct {
+	Type     string        `json:"type"`
+	Name     string        `json:"name"`
+	Selector *gov.Selector `json:"selector,omitempty"`
+	Spec     interface{}   `json:"spec"`
+}
+
+// Manifests are all the objects declared in the manifest files
+type Manifests struct {
+	Services     []*client.ServiceDocument
+	Dependencies []*DependencyManifest
+	Policies     []*PolicyManifest
+	Roles        []*rbac.Role
+}
+
+type manifestHeader struct {
+	Kind string `json:"kind"`
+}
+
+// LoadManifests loads the manifests from the file,
+// or all the YAML and JSON files in the directory recursively
+func LoadManifests(name string) (*Manifests, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{name}
+	if fi.IsDir() {
+		files = files[:0]
+		err = filepath.Walk(name, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && manifestExts[strings.ToLower(filepath.Ext(path))] {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m := &Manifests{}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := ParseManifests(data, m); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+	}
+	if m.Empty() {
+		return nil, fmt.Errorf("no manifest found in %s", name)
+	}
+	return m, nil
+}
+
+// ParseManifests parses the YAML or JSON documents separated by '---' into m
+func ParseManifests(data []byte, m *Manifests) error {
+	for i, part := range documentSeparator.Split(string(data), -1) {
+		if len(strings.TrimSpace(part)) == 0 {
+			continue
+		}
+		if err := m.add([]byte(part)); err != nil {
+			return fmt.Errorf("invalid document #%d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (m *Manifests) add(data []byte) error {
+	header := &manifestHeader{}
+	if err := yaml.Unmarshal(data, header); err != nil {
+		return err
+	}
+	switch header.Kind {
+	case "", KindMicroService:
+		doc := &client.ServiceDocument{}
+		if err := yaml.Unmarshal(data, doc); err != nil {
+			return err
+		}
+		if doc.Service == nil {
+			return fmt.Errorf("'service' is required")
+		}
+		m.Services = append(m.Services, doc)
+	case KindDependency:
+		dep := &DependencyManifest{}
+		if err := yaml.Unmarshal(data, dep); err != nil {
+			return err
+		}
+		if dep.Consumer == nil || len(dep.Providers) == 0 {
+			return fmt.Errorf("'consumer' and 'providers' are required")
+		}
+		m.Dependencies = append(m.Dependencies, dep)
+	case KindPolicy:
+		policy := &PolicyManifest{}
+		if err := yaml.Unmarshal(data, policy); err != nil {
+			return err
+		}
+		if len(policy.Type) == 0 || len(policy.Name) == 0 {
+			return fmt.Errorf("'type' and 'name' are required")
+		}
+		m.Policies = append(m.Policies, policy)
+	case KindRole:
+		role := &rbac.Role{}
+		if err := yaml.Unmarshal(data, role); err != nil {
+			return err
+		}
+		if len(role.Name) == 0 {
+			return fmt.Errorf("'name' is required")
+		}
+		m.Roles = append(m.Roles, role)
+	default:
+		return fmt.Errorf("unknown kind '%s'", header.Kind)
+	}
+	return nil
+}
+
+func (m *Manifests) Empty() bool {
+	return len(m.Services) == 0 && len(m.Dependencies) == 0 && len(m.Policies) == 0 && len(m.Roles) == 0
+}
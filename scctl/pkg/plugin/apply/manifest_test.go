@@ -0,0 +1,144 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseManifests(t *testing.T) {
+	m := &Manifests{}
+	err := ParseManifests([]byte(`
+service:
+  appId: default
+  serviceName: provider
+  version: 1.0.0
+schemas:
+  - schemaId: hello
+    schema: "swagger: 2.0"
+tags:
+  zone: z1
+---
+{"kind": "MicroService", "service": {"serviceName": "consumer", "version": "1.0.0"}, "rules": [{"ruleType": "ALLOW", "attribute": "ServiceName", "pattern": "^provider$"}]}
+---
+kind: Dependency
+consumer:
+  appId: default
+  serviceName: consumer
+  version: 1.0.0
+providers:
+  - appId: default
+    serviceName: provider
+    version: 1.0.0+
+---
+kind: Policy
+type: rate-limiting
+name: limit-hello
+selector:
+  app: default
+spec:
+  rate: 10
+---
+kind: Role
+name: tester
+perms:
+  - resources:
+      - type: service
+    verbs: ["get"]
+`), m)
+	if err != nil {
+		t.Fatalf("TestParseManifests failed, %v", err)
+	}
+	if len(m.Services) != 2 || len(m.Dependencies) != 1 || len(m.Policies) != 1 || len(m.Roles) != 1 {
+		t.Fatalf("TestParseManifests failed, %v", m)
+	}
+	if m.Services[0].Service.ServiceName != "provider" || len(m.Services[0].Schemas) != 1 || m.Services[0].Tags["zone"] != "z1" {
+		t.Fatalf("TestParseManifests failed, %v", m.Services[0])
+	}
+	if m.Services[1].Service.ServiceName != "consumer" || len(m.Services[1].Rules) != 1 || m.Services[1].Rules[0].RuleType != "ALLOW" {
+		t.Fatalf("TestParseManifests failed, %v", m.Services[1])
+	}
+	if m.Dependencies[0].Providers[0].Version != "1.0.0+" {
+		t.Fatalf("TestParseManifests failed, %v", m.Dependencies[0])
+	}
+	if m.Policies[0].Type != "rate-limiting" || m.Policies[0].Selector.App != "default" || m.Policies[0].Spec == nil {
+		t.Fatalf("TestParseManifests failed, %v", m.Policies[0])
+	}
+	if m.Roles[0].Name != "tester" || len(m.Roles[0].Perms) != 1 || m.Roles[0].Perms[0].Resources[0].Type != "service" {
+		t.Fatalf("TestParseManifests failed, %v", m.Roles[0])
+	}
+
+	for _, invalid := range []string{
+		"tags:\n  zone: z1\n",
+		"kind: Dependency\nconsumer:\n  serviceName: consumer\n",
+		"kind: Policy\nname: limit-hello\n",
+		"kind: Unknown\nname: x\n",
+	} {
+		if err := ParseManifests([]byte(invalid), &Manifests{}); err == nil {
+			t.Fatalf("TestParseManifests failed, %q should be invalid", invalid)
+		}
+	}
+}
+
+func TestLoadManifests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifests")
+	if err != nil {
+		t.Fatalf("TestLoadManifests failed, %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, err = LoadManifests(dir)
+	if err == nil {
+		t.Fatalf("TestLoadManifests failed, empty directory")
+	}
+
+	files := map[string]string{
+		"provider.yaml":       "service:\n  serviceName: provider\n  version: 1.0.0\n",
+		"roles/tester.yml":    "kind: Role\nname: tester\n",
+		"consumer.json":       `{"service": {"serviceName": "consumer", "version": "1.0.0"}}`,
+		"README.md":           "# not a manifest",
+		"roles/invalid.txt":   "kind: Unknown",
+		"policies/empty.yaml": "---\n",
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0750); err != nil {
+			t.Fatalf("TestLoadManifests failed, %v", err)
+		}
+		if err := ioutil.WriteFile(file, []byte(content), 0640); err != nil {
+			t.Fatalf("TestLoadManifests failed, %v", err)
+		}
+	}
+	m, err := LoadManifests(dir)
+	if err != nil {
+		t.Fatalf("TestLoadManifests failed, %v", err)
+	}
+	if len(m.Services) != 2 || len(m.Roles) != 1 {
+		t.Fatalf("TestLoadManifests failed, %v", m)
+	}
+	// walk in lexical order
+	if m.Services[0].Service.ServiceName != "consumer" {
+		t.Fatalf("TestLoadManifests failed, %v", m.Services[0])
+	}
+
+	m, err = LoadManifests(filepath.Join(dir, "provider.yaml"))
+	if err != nil || len(m.Services) != 1 || len(m.Roles) != 0 {
+		t.Fatalf("TestLoadManifests failed, %v, %v", m, err)
+	}
+}
@@ -0,0 +1,470 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+)
+
+// the actions of the planned changes
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionDelete    = "delete"
+	ActionUnchanged = "unchanged"
+)
+
+const registryServiceName = "SERVICECENTER"
+
+// Change is the planned change of one object
+type Change struct {
+	Kind   string
+	Name   string
+	Action string
+	Detail string
+
+	apply func(ctx context.Context) *errsvc.Error
+}
+
+// Planner diffs the manifests against the objects in the domain/project of service center,
+// objects missing from the manifests are deleted only if Prune is true:
+// services and their schemas and rules in the apps declared by the manifests,
+// providers of the consumers declared by the manifests, policies of the types declared by the manifests
+// and the custom roles owned by the manifests, i.e. named with RolePrefix.
+// The roles are shared by all the domains, so no role is pruned if RolePrefix is empty,
+// and the dependencies of the consumers not declared are never pruned
+type Planner struct {
+	Client     *client.Client
+	Domain     string
+	Project    string
+	Prune      bool
+	RolePrefix string
+}
+
+// Plan returns the changes in the applying order
+func (p *Planner) Plan(ctx context.Context, m *Manifests) ([]*Change, error) {
+	var changes []*Change
+	if len(m.Roles) > 0 {
+		remote, scErr := p.Client.ListRoles(ctx)
+		if scErr != nil {
+			return nil, fmt.Errorf("list roles failed, %s", scErr.Error())
+		}
+		changes = append(changes, p.planRoles(m.Roles, remote)...)
+	}
+
+	for _, doc := range m.Services {
+		serviceChanges, err := p.planService(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, serviceChanges...)
+	}
+
+	policies := make(map[string][]*PolicyManifest)
+	var types []string
+	for _, policy := range m.Policies {
+		if _, ok := policies[policy.Type]; !ok {
+			types = append(types, policy.Type)
+		}
+		policies[policy.Type] = append(policies[policy.Type], policy)
+	}
+	for _, t := range types {
+		remote, scErr := p.Client.ListPolicies(ctx, p.Domain, p.Project, t)
+		if scErr != nil {
+			return nil, fmt.Errorf("list %s policies failed, %s", t, scErr.Error())
+		}
+		changes = append(changes, p.planPolicies(t, policies[t], remote)...)
+	}
+
+	for _, dep := range m.Dependencies {
+		change, err := p.planDependency(ctx, dep)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	if p.Prune && len(m.Services) > 0 {
+		pruned, err := p.pruneServices(ctx, m.Services)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, pruned...)
+	}
+	return changes, nil
+}
+
+func (p *Planner) planRoles(roles []*rbac.Role, remote []*rbac.Role) []*Change {
+	exists := make(map[string]*rbac.Role, len(remote))
+	for _, role := range remote {
+		exists[role.Name] = role
+	}
+	changes := make([]*Change, 0, len(roles))
+	declared := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		role := role
+		declared[role.Name] = true
+		change := &Change{Kind: KindRole, Name: role.Name}
+		old, ok := exists[role.Name]
+		switch {
+		case !ok:
+			change.Action = ActionCreate
+			change.apply = func(ctx context.Context) *errsvc.Error {
+				return p.Client.CreateRole(ctx, role)
+			}
+		case !sameJSON(old.Perms, role.Perms):
+			change.Action = ActionUpdate
+			change.Detail = "perms changed"
+			change.apply = func(ctx context.Context) *errsvc.Error {
+				return p.Client.UpdateRole(ctx, role)
+			}
+		default:
+			change.Action = ActionUnchanged
+		}
+		changes = append(changes, change)
+	}
+	if !p.Prune || len(p.RolePrefix) == 0 {
+		return changes
+	}
+	for _, role := range remote {
+		name := role.Name
+		if declared[name] || !strings.HasPrefix(name, p.RolePrefix) ||
+			name == rbac.RoleAdmin || name == rbac.RoleDeveloper {
+			continue
+		}
+		changes = append(changes, &Change{Kind: KindRole, Name: name, Action: ActionDelete,
+			apply: func(ctx context.Context) *errsvc.Error {
+				return p.Client.DeleteRole(ctx, name)
+			}})
+	}
+	return changes
+}
+
+func (p *Planner) planService(ctx context.Context, doc *client.ServiceDocument) ([]*Change, error) {
+	name := serviceName(doc.Service)
+	result, scErr := p.Client.PlanService(ctx, p.Domain, p.Project, doc)
+	if scErr != nil {
+		return nil, fmt.Errorf("plan service %s failed, %s", name, scErr.Error())
+	}
+
+	change := &Change{Kind: KindMicroService, Name: name, Action: ActionUnchanged}
+	var details []string
+	for _, item := range result.Items {
+		if item.Action == ActionUnchanged {
+			continue
+		}
+		if item.Kind == "service" && item.Action == "created" {
+			change.Action = ActionCreate
+			continue
+		}
+		if change.Action == ActionUnchanged {
+			change.Action = ActionUpdate
+		}
+		details = append(details, fmt.Sprintf("%s %s %s", item.Kind, item.ID, item.Action))
+	}
+	change.Detail = strings.Join(details, ", ")
+	if change.Action != ActionUnchanged {
+		change.apply = func(ctx context.Context) *errsvc.Error {
+			_, scErr := p.Client.ApplyService(ctx, p.Domain, p.Project, doc)
+			return scErr
+		}
+	}
+	changes := []*Change{change}
+	if !p.Prune || change.Action == ActionCreate {
+		return changes, nil
+	}
+
+	pruned, err := p.pruneServiceResources(ctx, result.ServiceID, doc)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes, pruned...), nil
+}
+
+func (p *Planner) pruneServiceResources(ctx context.Context, serviceID string, doc *client.ServiceDocument) ([]*Change, error) {
+	name := serviceName(doc.Service)
+	var changes []*Change
+
+	schemas, scErr := p.Client.GetSchemasByServiceID(ctx, p.Domain, p.Project, serviceID)
+	if scErr != nil {
+		return nil, fmt.Errorf("get service %s schemas failed, %s", name, scErr.Error())
+	}
+	declared := make(map[string]bool, len(doc.Schemas))
+	for _, schema := range doc.Schemas {
+		declared[schema.SchemaId] = true
+	}
+	for _, schema := range schemas {
+		schemaID := schema.SchemaId
+		if declared[schemaID] {
+			continue
+		}
+		changes = append(changes, &Change{Kind: "Schema", Name: name + ":" + schemaID, Action: ActionDelete,
+			apply: func(ctx context.Context) *errsvc.Error {
+				return p.Client.DeleteSchema(ctx, p.Domain, p.Project, serviceID, schemaID)
+			}})
+	}
+
+	rules, scErr := p.Client.GetRules(ctx, p.Domain, p.Project, serviceID)
+	if scErr != nil {
+		return nil, fmt.Errorf("get service %s rules failed, %s", name, scErr.Error())
+	}
+	declared = make(map[string]bool, len(doc.Rules))
+	for _, rule := range doc.Rules {
+		declared[rule.Attribute+"="+rule.Pattern] = true
+	}
+	for _, rule := range rules {
+		ruleID, identity := rule.RuleId, rule.Attribute+"="+rule.Pattern
+		if declared[identity] {
+			continue
+		}
+		changes = append(changes, &Change{Kind: "Rule", Name: name + ":" + identity, Action: ActionDelete,
+			apply: func(ctx context.Context) *errsvc.Error {
+				return p.Client.DeleteRule(ctx, p.Domain, p.Project, serviceID, ruleID)
+			}})
+	}
+	return changes, nil
+}
+
+func (p *Planner) pruneServices(ctx context.Context, docs []*client.ServiceDocument) ([]*Change, error) {
+	services, scErr := p.Client.GetServices(ctx, p.Domain, p.Project)
+	if scErr != nil {
+		return nil, fmt.Errorf("list services failed, %s", scErr.Error())
+	}
+	return p.planPrunedServices(docs, services), nil
+}
+
+func (p *Planner) planPrunedServices(docs []*client.ServiceDocument, services []*pb.MicroService) []*Change {
+	apps := make(map[string]bool, len(docs))
+	declared := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		apps[doc.Service.AppId] = true
+		declared[serviceKey(doc.Service)] = true
+	}
+	var changes []*Change
+	for _, service := range services {
+		serviceID := service.ServiceId
+		if !apps[service.AppId] || declared[serviceKey(service)] || service.ServiceName == registryServiceName {
+			continue
+		}
+		changes = append(changes, &Change{Kind: KindMicroService, Name: serviceName(service), Action: ActionDelete,
+			Detail: "serviceId " + serviceID,
+			apply: func(ctx context.Context) *errsvc.Error {
+				return p.Client.DeleteService(ctx, p.Domain, p.Project, serviceID)
+			}})
+	}
+	return changes
+}
+
+func (p *Planner) planPolicies(kind string, policies []*PolicyManifest, remote []*gov.Policy) []*Change {
+	exists := make(map[string]*gov.Policy, len(remote))
+	for _, policy := range remote {
+		if policy.GovernancePolicy != nil {
+			exists[policy.Name] = policy
+		}
+	}
+	changes := make([]*Change, 0, len(policies))
+	declared := make(map[string]bool, len(policies))
+	for _, manifest := range policies {
+		declared[manifest.Name] = true
+		policy := &gov.Policy{
+			GovernancePolicy: &gov.GovernancePolicy{Name: manifest.Name, Selector: manifest.Selector},
+			Spec:             manifest.Spec,
+		}
+		if policy.Selector == nil {
+			policy.Selector = &gov.Selector{}
+		}
+		change := &Change{Kind: KindPolicy, Name: kind + "/" + manifest.Name}
+		old, ok := exists[manifest.Name]
+		switch {
+		case !ok:
+			change.Action = ActionCreate
+			change.apply = func(ctx context.Context) *errsvc.Error {
+				_, scErr := p.Client.CreatePolicy(ctx, p.Domain, p.Project, kind, policy)
+				return scErr
+			}
+		case !containsJSON(old.Spec, policy.Spec) || !sameSelector(old.Selector, policy.Selector):
+			id := old.ID
+			change.Action = ActionUpdate
+			change.Detail = "id " + id
+			change.apply = func(ctx context.Context) *errsvc.Error {
+				return p.Client.UpdatePolicy(ctx, p.Domain, p.Project, kind, id, policy)
+			}
+		default:
+			change.Action = ActionUnchanged
+		}
+		changes = append(changes, change)
+	}
+	if !p.Prune {
+		return changes
+	}
+	for _, policy := range remote {
+		if policy.GovernancePolicy == nil || declared[policy.Name] {
+			continue
+		}
+		id := policy.ID
+		changes = append(changes, &Change{Kind: KindPolicy, Name: kind + "/" + policy.Name, Action: ActionDelete,
+			Detail: "id " + id,
+			apply: func(ctx context.Context) *errsvc.Error {
+				return p.Client.DeletePolicy(ctx, p.Domain, p.Project, kind, id)
+			}})
+	}
+	return changes
+}
+
+func (p *Planner) planDependency(ctx context.Context, dep *DependencyManifest) (*Change, error) {
+	consumer := dep.Consumer
+	name := serviceName(&pb.MicroService{AppId: consumer.AppId, ServiceName: consumer.ServiceName, Version: consumer.Version})
+	var providers []*pb.MicroService
+	consumerID, scErr := p.Client.ServiceExistence(ctx, p.Domain, p.Project,
+		consumer.AppId, consumer.ServiceName, consumer.Version, consumer.Environment)
+	if scErr != nil && scErr.Code != pb.ErrServiceNotExists {
+		return nil, fmt.Errorf("get consumer %s failed, %s", name, scErr.Error())
+	}
+	if scErr == nil {
+		providers, scErr = p.Client.GetProviders(ctx, p.Domain, p.Project, consumerID)
+		if scErr != nil {
+			return nil, fmt.Errorf("get consumer %s providers failed, %s", name, scErr.Error())
+		}
+	}
+	return p.planProviders(name, dep, consumerID, providers), nil
+}
+
+func (p *Planner) planProviders(name string, dep *DependencyManifest, consumerID string, providers []*pb.MicroService) *Change {
+	added, removed := diffProviders(dep.Providers, providers)
+	change := &Change{Kind: KindDependency, Name: name, Action: ActionUnchanged}
+	var details []string
+	if len(added) > 0 {
+		details = append(details, "add "+strings.Join(added, ","))
+	}
+	if p.Prune && len(removed) > 0 {
+		details = append(details, "remove "+strings.Join(removed, ","))
+	}
+	if len(details) == 0 {
+		return change
+	}
+	change.Action = ActionUpdate
+	if len(consumerID) == 0 {
+		change.Action = ActionCreate
+	}
+	change.Detail = strings.Join(details, ", ")
+	deps := []*pb.ConsumerDependency{{Consumer: dep.Consumer, Providers: dep.Providers}}
+	change.apply = func(ctx context.Context) *errsvc.Error {
+		if p.Prune {
+			return p.Client.UpdateDependencies(ctx, p.Domain, p.Project, deps)
+		}
+		return p.Client.AddDependencies(ctx, p.Domain, p.Project, deps)
+	}
+	return change
+}
+
+// diffProviders compares the declared providers with the actual providers,
+// the declared provider with version rule, e.g. 'latest' or '1.0.0+',
+// matches any version of the provider
+func diffProviders(declared []*pb.MicroServiceKey, actual []*pb.MicroService) (added []string, removed []string) {
+	matched := make([]bool, len(actual))
+	for _, key := range declared {
+		found := false
+		for i, service := range actual {
+			if key.AppId != service.AppId || key.ServiceName != service.ServiceName {
+				continue
+			}
+			if key.Version == service.Version || !isExactVersion(key.Version) {
+				matched[i], found = true, true
+			}
+		}
+		if !found {
+			added = append(added, key.AppId+"/"+key.ServiceName+"/"+key.Version)
+		}
+	}
+	for i, service := range actual {
+		if !matched[i] {
+			removed = append(removed, serviceName(service))
+		}
+	}
+	sort.Strings(removed)
+	return
+}
+
+func isExactVersion(version string) bool {
+	return len(version) > 0 && version != "latest" && !strings.ContainsAny(version, "+-")
+}
+
+func serviceName(service *pb.MicroService) string {
+	return service.AppId + "/" + service.ServiceName + "/" + service.Version
+}
+
+func serviceKey(service *pb.MicroService) string {
+	return service.Environment + "/" + serviceName(service)
+}
+
+func sameSelector(actual, desired *gov.Selector) bool {
+	if actual == nil {
+		actual = &gov.Selector{}
+	}
+	return *actual == *desired
+}
+
+func sameJSON(a, b interface{}) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && string(x) == string(y)
+}
+
+// containsJSON returns true if all the fields of desired are the same in actual,
+// the fields only in actual, e.g. filled by server, are ignored
+func containsJSON(actual, desired interface{}) bool {
+	var a, d interface{}
+	if !normalizeJSON(actual, &a) || !normalizeJSON(desired, &d) {
+		return false
+	}
+	return contains(a, d)
+}
+
+func normalizeJSON(v interface{}, out *interface{}) bool {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(b, out) == nil
+}
+
+func contains(actual, desired interface{}) bool {
+	dm, ok := desired.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(actual, desired)
+	}
+	am, ok := actual.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for k, v := range dm {
+		if !contains(am[k], v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,162 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/pkg/gov"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+)
+
+func actions(changes []*Change) map[string]string {
+	m := make(map[string]string, len(changes))
+	for _, change := range changes {
+		m[change.Kind+" "+change.Name] = change.Action
+		if (change.apply == nil) != (change.Action == ActionUnchanged) {
+			panic("unexpected apply func of " + change.Name)
+		}
+	}
+	return m
+}
+
+func TestPlanner_planRoles(t *testing.T) {
+	perms := []*rbac.Permission{{Resources: []*rbac.Resource{{Type: "service"}}, Verbs: []string{"get"}}}
+	roles := []*rbac.Role{
+		{Name: "tester", Perms: perms},
+		{Name: "viewer", Perms: perms},
+		{Name: "new"},
+	}
+	remote := []*rbac.Role{
+		{Name: rbac.RoleAdmin},
+		{Name: "tester", Perms: perms, CreateTime: "1"},
+		{Name: "viewer"},
+		{Name: "legacy"},
+		{Name: "team-a-legacy"},
+	}
+
+	p := &Planner{}
+	m := actions(p.planRoles(roles, remote))
+	if len(m) != 3 || m["Role tester"] != ActionUnchanged || m["Role viewer"] != ActionUpdate || m["Role new"] != ActionCreate {
+		t.Fatalf("TestPlanner_planRoles failed, %v", m)
+	}
+
+	p.Prune = true
+	m = actions(p.planRoles(roles, remote))
+	if len(m) != 3 {
+		t.Fatalf("TestPlanner_planRoles failed, %v", m)
+	}
+
+	p.RolePrefix = "team-a-"
+	m = actions(p.planRoles(roles, remote))
+	if len(m) != 4 || m["Role team-a-legacy"] != ActionDelete {
+		t.Fatalf("TestPlanner_planRoles failed, %v", m)
+	}
+}
+
+func TestPlanner_planPolicies(t *testing.T) {
+	policies := []*PolicyManifest{
+		{Type: "rate-limiting", Name: "same", Spec: map[string]interface{}{"rate": 10}},
+		{Type: "rate-limiting", Name: "changed", Spec: map[string]interface{}{"rate": 10}},
+		{Type: "rate-limiting", Name: "moved", Selector: &gov.Selector{App: "a"}, Spec: map[string]interface{}{"rate": 10}},
+		{Type: "rate-limiting", Name: "new", Spec: map[string]interface{}{"rate": 10}},
+	}
+	remote := []*gov.Policy{
+		{GovernancePolicy: &gov.GovernancePolicy{ID: "1", Name: "same", Selector: &gov.Selector{}},
+			Spec: map[string]interface{}{"rate": float64(10), "alias": "same"}},
+		{GovernancePolicy: &gov.GovernancePolicy{ID: "2", Name: "changed", Selector: &gov.Selector{}},
+			Spec: map[string]interface{}{"rate": float64(20)}},
+		{GovernancePolicy: &gov.GovernancePolicy{ID: "3", Name: "moved", Selector: &gov.Selector{App: "b"}},
+			Spec: map[string]interface{}{"rate": float64(10)}},
+		{GovernancePolicy: &gov.GovernancePolicy{ID: "4", Name: "legacy", Selector: &gov.Selector{}}},
+	}
+
+	p := &Planner{}
+	m := actions(p.planPolicies("rate-limiting", policies, remote))
+	if len(m) != 4 || m["Policy rate-limiting/same"] != ActionUnchanged ||
+		m["Policy rate-limiting/changed"] != ActionUpdate || m["Policy rate-limiting/moved"] != ActionUpdate ||
+		m["Policy rate-limiting/new"] != ActionCreate {
+		t.Fatalf("TestPlanner_planPolicies failed, %v", m)
+	}
+
+	p.Prune = true
+	m = actions(p.planPolicies("rate-limiting", policies, remote))
+	if len(m) != 5 || m["Policy rate-limiting/legacy"] != ActionDelete {
+		t.Fatalf("TestPlanner_planPolicies failed, %v", m)
+	}
+}
+
+func TestPlanner_planProviders(t *testing.T) {
+	dep := &DependencyManifest{
+		Consumer: &pb.MicroServiceKey{AppId: "a", ServiceName: "consumer", Version: "1.0.0"},
+		Providers: []*pb.MicroServiceKey{
+			{AppId: "a", ServiceName: "p1", Version: "1.0.0"},
+			{AppId: "a", ServiceName: "p2", Version: "latest"},
+		},
+	}
+	p := &Planner{}
+	change := p.planProviders("a/consumer/1.0.0", dep, "", nil)
+	if change.Action != ActionCreate || change.Detail != "add a/p1/1.0.0,a/p2/latest" {
+		t.Fatalf("TestPlanner_planProviders failed, %v", change)
+	}
+
+	providers := []*pb.MicroService{
+		{AppId: "a", ServiceName: "p1", Version: "1.0.0"},
+		{AppId: "a", ServiceName: "p2", Version: "2.0.0"},
+		{AppId: "a", ServiceName: "p3", Version: "1.0.0"},
+	}
+	change = p.planProviders("a/consumer/1.0.0", dep, "id", providers)
+	if change.Action != ActionUnchanged || change.apply != nil {
+		t.Fatalf("TestPlanner_planProviders failed, %v", change)
+	}
+
+	p.Prune = true
+	change = p.planProviders("a/consumer/1.0.0", dep, "id", providers)
+	if change.Action != ActionUpdate || change.Detail != "remove a/p3/1.0.0" {
+		t.Fatalf("TestPlanner_planProviders failed, %v", change)
+	}
+}
+
+func TestPlanner_planPrunedServices(t *testing.T) {
+	docs := []*client.ServiceDocument{
+		{Service: &pb.MicroService{AppId: "a", ServiceName: "s1", Version: "1.0.0"}},
+	}
+	services := []*pb.MicroService{
+		{ServiceId: "1", AppId: "a", ServiceName: "s1", Version: "1.0.0"},
+		{ServiceId: "2", AppId: "a", ServiceName: "s1", Version: "0.0.1"},
+		{ServiceId: "3", AppId: "b", ServiceName: "s2", Version: "1.0.0"},
+		{ServiceId: "4", AppId: "a", ServiceName: registryServiceName, Version: "1.0.0"},
+	}
+	m := actions((&Planner{}).planPrunedServices(docs, services))
+	if len(m) != 1 || m["MicroService a/s1/0.0.1"] != ActionDelete {
+		t.Fatalf("TestPlanner_planPrunedServices failed, %v", m)
+	}
+}
+
+func TestContainsJSON(t *testing.T) {
+	actual := map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "x", "d": []int{1}}}
+	if !containsJSON(actual, map[string]interface{}{"b": map[string]interface{}{"d": []int{1}}}) {
+		t.Fatalf("TestContainsJSON failed")
+	}
+	if containsJSON(actual, map[string]interface{}{"b": map[string]interface{}{"d": []int{2}}}) {
+		t.Fatalf("TestContainsJSON failed")
+	}
+	if containsJSON(actual, map[string]interface{}{"e": 1}) {
+		t.Fatalf("TestContainsJSON failed")
+	}
+}
@@ -0,0 +1,118 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Since time.Duration
+	Start string
+	End   string
+	Actor string
+	Limit int
+)
+
+var auditTableHeader = []string{"TIME", "ACTOR", "METHOD", "API", "TARGETS", "CODE", "LATENCY", "SOURCE"}
+
+func init() {
+	NewAuditCommand(cmd.RootCmd())
+}
+
+func NewAuditCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit [options]",
+		Short: "Query the audit logs of the mutating API calls, the latest first",
+		Args:  cobra.NoArgs,
+		Run:   AuditCommandFunc,
+	}
+	cmd.Flags().DurationVar(&Since, "since", 0, "only the audit logs newer than the duration, e.g. 30m, 24h")
+	cmd.Flags().StringVar(&Start, "start", "", "the start time in RFC3339 format, can not be used with --since")
+	cmd.Flags().StringVar(&End, "end", "", "the end time in RFC3339 format")
+	cmd.Flags().StringVar(&Actor, "actor", "", "the account name of the actor")
+	cmd.Flags().IntVar(&Limit, "limit", 100, "the max number of the audit logs")
+
+	parent.AddCommand(cmd)
+	cmd.Example = cmd.CommandPath() + ` --since 1h;
+` + cmd.CommandPath() + ` --actor root --start 2021-01-01T00:00:00Z --end 2021-01-02T00:00:00Z --limit 20`
+	return cmd
+}
+
+func AuditCommandFunc(_ *cobra.Command, _ []string) {
+	start, end, err := TimeRange(time.Now(), Since, Start, End)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	logs, scErr := scClient.ListAuditLogs(context.Background(), start, end, Actor, Limit)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	writer.MakeTable(auditTableHeader, AuditTableBody(logs))
+}
+
+// TimeRange resolves the time range from --since or --start and --end
+func TimeRange(now time.Time, since time.Duration, start, end string) (s time.Time, e time.Time, err error) {
+	if since > 0 && len(start) > 0 {
+		return s, e, errors.New("--since can not be used with --start")
+	}
+	if since > 0 {
+		s = now.Add(-since)
+	}
+	if len(start) > 0 {
+		if s, err = time.Parse(time.RFC3339, start); err != nil {
+			return s, e, fmt.Errorf("invalid --start: %v", err)
+		}
+	}
+	if len(end) > 0 {
+		if e, err = time.Parse(time.RFC3339, end); err != nil {
+			return s, e, fmt.Errorf("invalid --end: %v", err)
+		}
+	}
+	return s, e, nil
+}
+
+func AuditTableBody(logs []*dump.AuditLog) [][]string {
+	body := make([][]string, 0, len(logs))
+	for _, l := range logs {
+		body = append(body, []string{
+			time.Unix(0, l.Timestamp*int64(time.Millisecond)).Format(time.RFC3339),
+			l.Actor,
+			l.Method,
+			l.API,
+			strings.Join(l.TargetIDs, ","),
+			strconv.Itoa(l.Code),
+			fmt.Sprintf("%dms", l.Latency),
+			l.SourceIP,
+		})
+	}
+	return body
+}
@@ -0,0 +1,62 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+)
+
+func TestTimeRange(t *testing.T) {
+	now := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	s, e, err := TimeRange(now, time.Hour, "", "")
+	if err != nil || !s.Equal(now.Add(-time.Hour)) || !e.IsZero() {
+		t.Fatalf("TestTimeRange failed, %v %v %v", s, e, err)
+	}
+	s, e, err = TimeRange(now, 0, "2021-01-01T00:00:00Z", "2021-01-01T12:00:00Z")
+	if err != nil || !s.Equal(now.Add(-24*time.Hour)) || !e.Equal(now.Add(-12*time.Hour)) {
+		t.Fatalf("TestTimeRange failed, %v %v %v", s, e, err)
+	}
+	if _, _, err = TimeRange(now, time.Hour, "2021-01-01T00:00:00Z", ""); err == nil {
+		t.Fatalf("TestTimeRange failed, --since with --start")
+	}
+	if _, _, err = TimeRange(now, 0, "", "yesterday"); err == nil {
+		t.Fatalf("TestTimeRange failed, invalid --end")
+	}
+}
+
+func TestAuditTableBody(t *testing.T) {
+	body := AuditTableBody([]*dump.AuditLog{{
+		Timestamp: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond),
+		Actor:     "root",
+		Method:    "DELETE",
+		API:       "/v4/:project/registry/microservices/:serviceId",
+		TargetIDs: []string{"serviceId=1"},
+		Code:      200,
+		Latency:   3,
+		SourceIP:  "127.0.0.1",
+	}})
+	if len(body) != 1 {
+		t.Fatalf("TestAuditTableBody failed, %v", body)
+	}
+	expected := []string{"root", "DELETE", "/v4/:project/registry/microservices/:serviceId", "serviceId=1", "200", "3ms", "127.0.0.1"}
+	if !reflect.DeepEqual(body[0][1:], expected) {
+		t.Fatalf("TestAuditTableBody failed, %v", body[0])
+	}
+}
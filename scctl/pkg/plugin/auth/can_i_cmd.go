@@ -0,0 +1,139 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/client"
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Labels  map[string]string
+	As      string
+	Explain bool
+)
+
+func init() {
+	NewAuthCommand(cmd.RootCmd())
+}
+
+func NewAuthCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth <command> [options]",
+		Short: "Inspect the authorization of the current token",
+	}
+
+	parent.AddCommand(cmd)
+
+	canICmd := &cobra.Command{
+		Use:   "can-i VERB RESOURCE [options]",
+		Short: "Check whether the current token is allowed to apply the verb to the resource",
+		Long: `Check whether the current token is allowed to apply the verb to the resource.
+The permission is evaluated by service center with the roles of the token,
+prints 'yes' and exits with 0 if allowed, otherwise prints 'no' and exits with 1.
+The admin can check the permissions of the other account by the --as option.`,
+		Args: cobra.ExactArgs(2),
+		Run:  CanICommandFunc,
+		Example: cmd.CommandPath() + ` can-i get service;
+` + cmd.CommandPath() + ` can-i delete service --label app=x,environment=production;
+` + cmd.CommandPath() + ` can-i create account --as dev1 --explain`,
+	}
+	canICmd.Flags().StringToStringVar(&Labels, "label", nil, "the labels of the resource, e.g. app=x,environment=production")
+	canICmd.Flags().StringVar(&As, "as", "", "check the permissions of the account instead of the current token, admin role is required")
+	canICmd.Flags().BoolVar(&Explain, "explain", false, "print the role and permission which granted or denied the request")
+	cmd.AddCommand(canICmd)
+	return cmd
+}
+
+func CanICommandFunc(_ *cobra.Command, args []string) {
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	resp, scErr := scClient.CheckPermission(context.Background(), &rbacpkg.PermissionCheckRequest{
+		Account:  As,
+		Verb:     args[0],
+		Resource: args[1],
+		Labels:   Labels,
+	})
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	fmt.Println(Decision(resp))
+	if Explain {
+		for _, line := range Explanation(resp) {
+			fmt.Println(line)
+		}
+	}
+	if !resp.Allowed {
+		cmd.StopAndExit(cmd.ExitError)
+	}
+}
+
+// Decision formats the allowed response, shows the matched labels if the permission is limited by labels
+func Decision(resp *rbacpkg.PermissionCheckResponse) string {
+	if !resp.Allowed {
+		return "no"
+	}
+	if len(resp.Labels) == 0 {
+		return "yes"
+	}
+	labels := make([]string, 0, len(resp.Labels))
+	for _, m := range resp.Labels {
+		labels = append(labels, labelsString(m))
+	}
+	return "yes, limited to the labels: " + strings.Join(labels, "; ")
+}
+
+// Explanation formats the account, role, permission and reason of the response
+func Explanation(resp *rbacpkg.PermissionCheckResponse) []string {
+	lines := []string{"account: " + resp.Account}
+	if len(resp.Role) > 0 {
+		lines = append(lines, "role: "+resp.Role)
+	}
+	if resp.Permission != nil {
+		resources := make([]string, 0, len(resp.Permission.Resources))
+		for _, r := range resp.Permission.Resources {
+			if len(r.Labels) == 0 {
+				resources = append(resources, r.Type)
+				continue
+			}
+			resources = append(resources, r.Type+"("+labelsString(r.Labels)+")")
+		}
+		lines = append(lines, fmt.Sprintf("permission: %s [%s]",
+			strings.Join(resources, ","), strings.Join(resp.Permission.Verbs, ",")))
+	}
+	if len(resp.Reason) > 0 {
+		lines = append(lines, "reason: "+resp.Reason)
+	}
+	return lines
+}
+
+func labelsString(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
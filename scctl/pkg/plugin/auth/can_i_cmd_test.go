@@ -0,0 +1,64 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-chassis/cari/rbac"
+
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
+)
+
+func TestDecision(t *testing.T) {
+	cases := map[string]*rbacpkg.PermissionCheckResponse{
+		"no":  {Allowed: false, Labels: []map[string]string{{"app": "x"}}},
+		"yes": {Allowed: true},
+		"yes, limited to the labels: app=x,env=prod; app=y": {Allowed: true, Labels: []map[string]string{
+			{"env": "prod", "app": "x"}, {"app": "y"},
+		}},
+	}
+	for expected, resp := range cases {
+		if s := Decision(resp); s != expected {
+			t.Fatalf("TestDecision failed, %s", s)
+		}
+	}
+}
+
+func TestExplanation(t *testing.T) {
+	resp := &rbacpkg.PermissionCheckResponse{
+		Account: "dev1",
+		Role:    "app-x",
+		Permission: &rbac.Permission{
+			Resources: []*rbac.Resource{{Type: "service", Labels: map[string]string{"app": "x"}}, {Type: "governance"}},
+			Verbs:     []string{"get", "delete"},
+		},
+		Reason: "the labels do not match the permission of role [app-x]",
+	}
+	expected := []string{
+		"account: dev1",
+		"role: app-x",
+		"permission: service(app=x),governance [get,delete]",
+		"reason: the labels do not match the permission of role [app-x]",
+	}
+	if lines := Explanation(resp); !reflect.DeepEqual(lines, expected) {
+		t.Fatalf("TestExplanation failed, %v", lines)
+	}
+	if lines := Explanation(&rbacpkg.PermissionCheckResponse{Account: "dev1"}); len(lines) != 1 {
+		t.Fatalf("TestExplanation failed, %v", lines)
+	}
+}
@@ -0,0 +1,128 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/spf13/cobra"
+)
+
+var (
+	User     string
+	Password string
+	Expire   string
+	TOTPCode string
+)
+
+func init() {
+	NewLoginCommand(cmd.RootCmd())
+	NewLogoutCommand(cmd.RootCmd())
+}
+
+func NewLoginCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login [options]",
+		Short: "Log in to service center and save the token in the scctl config",
+		Args:  cobra.NoArgs,
+		Run:   LoginCommandFunc,
+	}
+	cmd.Example = cmd.CommandPath() + ` --user root;
+` + cmd.CommandPath() + ` --user root --password 'Complicated_password1' --expire 30m --addr https://10.0.0.1:30100`
+	cmd.Flags().StringVarP(&User, "user", "u", "", "the account name")
+	cmd.Flags().StringVar(&Password, "password", "", "the password of the account, asked if not specified")
+	cmd.Flags().StringVar(&Expire, "expire", "12h", "the token expiration time, e.g. 30m, 12h")
+	cmd.Flags().StringVar(&TOTPCode, "totp-code", "", "the TOTP code or a recovery code, required if the account enables TOTP")
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func NewLogoutCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Revoke the token of service center and remove it from the scctl config",
+		Args:  cobra.NoArgs,
+		Run:   LogoutCommandFunc,
+	}
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func LoginCommandFunc(_ *cobra.Command, _ []string) {
+	if len(User) == 0 {
+		cmd.StopAndExit(cmd.ExitError, "--user is required")
+	}
+	password := Password
+	if len(password) == 0 {
+		var err error
+		password, err = cmd.ReadPassword("Password: ")
+		if err != nil {
+			cmd.StopAndExit(cmd.ExitError, err)
+		}
+	}
+
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	token, scErr := scClient.LoginWithTOTP(context.Background(), User, password, Expire, TOTPCode)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+
+	c, err := cmd.LoadLocalConfig(cmd.ConfigPath)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	c.SetToken(cmd.ScClientConfig.Endpoints, token)
+	if err := c.Save(cmd.ConfigPath); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	fmt.Printf("logged in as %s, token saved in %s\n", User, cmd.ConfigPath)
+}
+
+func LogoutCommandFunc(_ *cobra.Command, _ []string) {
+	c, err := cmd.LoadLocalConfig(cmd.ConfigPath)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	if len(cmd.ScClientConfig.Token) > 0 {
+		revokeToken()
+	}
+	c.RemoveToken(cmd.ScClientConfig.Endpoints)
+	if err := c.Save(cmd.ConfigPath); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	fmt.Println("logged out")
+}
+
+// revokeToken revokes the token in service center, the local token is
+// removed even if the revocation failed, e.g. the token is expired
+func revokeToken() {
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "revoke token failed:", err)
+		return
+	}
+	if scErr := scClient.Logout(context.Background()); scErr != nil {
+		fmt.Fprintln(os.Stderr, "revoke token failed:", scErr)
+	}
+}
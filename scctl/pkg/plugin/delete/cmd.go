@@ -0,0 +1,46 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delete
+
+import (
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/selector"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Selector selector.Selector
+	Yes      bool
+)
+
+func init() {
+	NewDeleteCommand(cmd.RootCmd())
+}
+
+func NewDeleteCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <command> [options]",
+		Short: "Delete the resources of service center",
+	}
+	Selector.BindFlags(cmd.PersistentFlags())
+	cmd.PersistentFlags().BoolVarP(&Yes, "yes", "y", false, "delete without the confirmation prompt")
+
+	parent.AddCommand(cmd)
+
+	NewServiceCommand(cmd)
+	NewInstanceCommand(cmd)
+	return cmd
+}
@@ -0,0 +1,74 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delete
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/selector"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/spf13/cobra"
+)
+
+var InstanceID string
+
+func NewInstanceCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "instance [options]",
+		Aliases: []string{"inst"},
+		Short:   "Unregister the microservice instances",
+		Run:     InstanceCommandFunc,
+		Example: parent.CommandPath() + ` instance --service-id 2a0b2f6a1c8e11ecb1a10242ac110002 --instance-id 7a6be9f861a811e9b3f6fa163eca30e0;
+` + parent.CommandPath() + ` instance --app springmvc --name provider -y`,
+	}
+	cmd.Flags().StringVar(&InstanceID, "instance-id", "", "select the instance by id, empty means all the instances")
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func InstanceCommandFunc(_ *cobra.Command, args []string) {
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	ctx := context.Background()
+	targets, err := Selector.Instances(ctx, scClient, InstanceID)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+
+	writer.MakeTable(selector.InstanceTableHeader, selector.InstanceTableBody(targets))
+	cmd.ConfirmOrExit(Yes, fmt.Sprintf("Unregister %d instances?", len(targets)))
+
+	failed := 0
+	for _, target := range targets {
+		scErr := scClient.UnregisterInstance(ctx, Selector.Domain, Selector.Project,
+			target.Service.ServiceId, target.Instance.InstanceId)
+		if scErr != nil {
+			failed++
+			fmt.Printf("instance %s unregister failed: %s\n", target.Instance.InstanceId, scErr.Error())
+			continue
+		}
+		fmt.Printf("instance %s unregistered\n", target.Instance.InstanceId)
+	}
+	if failed > 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Sprintf("error: %d of %d instances unregister failed", failed, len(targets)))
+	}
+}
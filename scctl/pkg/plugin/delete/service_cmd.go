@@ -0,0 +1,49 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delete
+
+import (
+	"context"
+
+	"github.com/apache/servicecomb-service-center/client"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/spf13/cobra"
+)
+
+var Force bool
+
+func NewServiceCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "service [options]",
+		Aliases: []string{"svc"},
+		Short:   "Delete the microservices",
+		Run:     ServiceCommandFunc,
+		Example: parent.CommandPath() + ` service --service-id 2a0b2f6a1c8e11ecb1a10242ac110002;
+` + parent.CommandPath() + ` service --app springmvc --name provider --version 0.0.1 --force -y`,
+	}
+	cmd.Flags().BoolVar(&Force, "force", false, "delete the services with their instances")
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func ServiceCommandFunc(_ *cobra.Command, args []string) {
+	Selector.ForEachService(Yes, "Delete", "delete",
+		func(ctx context.Context, c *client.Client, service *pb.MicroService) *errsvc.Error {
+			return c.UnregisterService(ctx, Selector.Domain, Selector.Project, service.ServiceId, Force)
+		})
+}
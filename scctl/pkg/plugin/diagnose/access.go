@@ -0,0 +1,125 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/spf13/cobra"
+)
+
+var (
+	accessDomain   string
+	accessProject  string
+	accessProvider string
+	accessConsumer string
+	accessIP       string
+)
+
+func NewAccessCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "access [options]",
+		Short: "Explain why the consumer is allowed or denied to access the provider",
+		Run:   AccessCommandFunc,
+		Example: parent.CommandPath() + ` access --provider "a3b3f5e4c3a711e8b9fc286ed488de36" --consumer "b5c6e0a2c3a711e8b9fc286ed488de36";
+` + parent.CommandPath() + ` access --provider "a3b3f5e4c3a711e8b9fc286ed488de36" --consumer "b5c6e0a2c3a711e8b9fc286ed488de36" --ip "10.0.0.1"`,
+	}
+
+	cmd.Flags().StringVarP(&accessDomain, "domain", "d", "default", "the domain of the services")
+	cmd.Flags().StringVar(&accessProject, "project", "default", "the project of the services")
+	cmd.Flags().StringVar(&accessProvider, "provider", "", "the provider service id")
+	cmd.Flags().StringVar(&accessConsumer, "consumer", "", "the consumer service id")
+	cmd.Flags().StringVar(&accessIP, "ip", "", "the source ip of the consumer, the ip rules never match if it is empty")
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func AccessCommandFunc(_ *cobra.Command, args []string) {
+	if len(accessProvider) == 0 || len(accessConsumer) == 0 {
+		cmd.StopAndExit(cmd.ExitError, "required --provider and --consumer")
+	}
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	result, scErr := scClient.ExplainAccess(context.Background(),
+		accessDomain, accessProject, accessProvider, accessConsumer, accessIP)
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+
+	report := writeAccessResult(result)
+	if !result.Allowed {
+		fmt.Print(report)                                         // stdout
+		cmd.StopAndExit(cmd.ExitError, "error: access is denied") // stderr
+	}
+	cmd.StopAndExit(cmd.ExitSuccess, report)
+}
+
+func writeAccessResult(result *client.AccessResult) string {
+	var b bytes.Buffer
+	if result.Allowed {
+		b.WriteString("ALLOWED: ")
+	} else {
+		b.WriteString("DENIED: ")
+	}
+	b.WriteString(result.Reason)
+	b.WriteString("\n")
+	if len(result.CrossDimension) > 0 {
+		b.WriteString(fmt.Sprintf("  cross dimension: %s\n", result.CrossDimension))
+	}
+	if result.Rule != nil {
+		b.WriteString(fmt.Sprintf("  matched rule: %s\n", formatRuleTrace(result.Rule)))
+	}
+	if result.Decision == nil || len(result.Decision.Traces) == 0 {
+		return b.String()
+	}
+	b.WriteString("  evaluated rules:\n")
+	for i, trace := range result.Decision.Traces {
+		mark := " "
+		switch {
+		case trace.Matched:
+			mark = "*"
+		case trace.Skipped:
+			mark = "?"
+		}
+		b.WriteString(fmt.Sprintf("  %s %d. %s\n", mark, i+1, formatRuleTrace(trace)))
+	}
+	return b.String()
+}
+
+func formatRuleTrace(trace *client.RuleTrace) string {
+	s := fmt.Sprintf("[%s] %s %s='%s' priority=%d", trace.RuleID, trace.RuleType, trace.Attribute, trace.Pattern, trace.Priority)
+	if len(trace.Values) == 0 {
+		return s
+	}
+	keys := make([]string, 0, len(trace.Values))
+	for k := range trace.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	s += ", consumer"
+	for _, k := range keys {
+		s += fmt.Sprintf(" %s='%s'", k, trace.Values[k])
+	}
+	return s
+}
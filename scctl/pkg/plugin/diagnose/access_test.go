@@ -0,0 +1,49 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/servicecomb-service-center/client"
+)
+
+func TestWriteAccessResult(t *testing.T) {
+	report := writeAccessResult(&client.AccessResult{
+		Reason:         "not allow across app access",
+		CrossDimension: "not allow across app access",
+	})
+	if report != "DENIED: not allow across app access\n  cross dimension: not allow across app access\n" {
+		t.Fatalf("TestWriteAccessResult failed, %s", report)
+	}
+
+	rule := &client.RuleTrace{RuleID: "1", RuleType: "DENY", Attribute: "expr", Pattern: "tag_zone=z1",
+		Matched: true, Values: map[string]string{"tag_zone": "z1"}}
+	report = writeAccessResult(&client.AccessResult{
+		Reason: "Matched DENY rule",
+		Rule:   rule,
+		Decision: &client.RuleDecision{RuleID: "1", Reason: "Matched DENY rule", Traces: []*client.RuleTrace{
+			rule,
+			{RuleID: "2", RuleType: "ALLOW", Attribute: "ServiceName", Pattern: "^a$", Values: map[string]string{"ServiceName": "b"}},
+		}},
+	})
+	if !strings.HasPrefix(report, "DENIED: Matched DENY rule\n  matched rule: [1] DENY expr='tag_zone=z1' priority=0, consumer tag_zone='z1'\n") ||
+		!strings.Contains(report, "  * 1. [1]") ||
+		!strings.Contains(report, "    2. [2] ALLOW ServiceName='^a$' priority=0, consumer ServiceName='b'") {
+		t.Fatalf("TestWriteAccessResult failed, %s", report)
+	}
+}
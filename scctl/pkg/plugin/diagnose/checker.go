@@ -0,0 +1,269 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/value"
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+// the classes of the inconsistent data
+const (
+	ClassCacheDivergence    = "cache divergence"
+	ClassOrphanInstance     = "orphan instance"
+	ClassExpiredLease       = "expired lease"
+	ClassDanglingIndex      = "dangling service index"
+	ClassDanglingAlias      = "dangling service alias"
+	ClassSchemaSummary      = "schema summary without content"
+	ClassDanglingDependency = "dependency rule of deleted service"
+)
+
+var classOrder = []string{ClassCacheDivergence, ClassOrphanInstance, ClassExpiredLease,
+	ClassDanglingIndex, ClassDanglingAlias, ClassSchemaSummary, ClassDanglingDependency}
+
+// Issue is an inconsistent data found in etcd or in the cache of service center
+type Issue struct {
+	Class  string
+	Key    string
+	Detail string
+	// Cmps are the conditions that the keys are not changed since loaded,
+	// the issue is skipped if any of them fails
+	Cmps []clientv3.Cmp
+	// Ops are the etcd operations to repair the issue, executed in one transaction
+	Ops []clientv3.Op
+	// Manual is the action to repair the issue if it has no safe operations
+	Manual string
+}
+
+// unchanged returns the condition that the key is not modified since loaded
+func unchanged(kv *mvccpb.KeyValue) clientv3.Cmp {
+	return clientv3.Compare(clientv3.ModRevision(string(kv.Key)), "=", kv.ModRevision)
+}
+
+// absent returns the condition that the key does not exist
+func absent(key string) clientv3.Cmp {
+	return clientv3.Compare(clientv3.CreateRevision(key), "=", 0)
+}
+
+// check finds the inconsistent data in etcd or mongo, leases are the alive leases of etcd
+func check(etcdResp etcdResponse, leases map[int64]bool) []*Issue {
+	var issues []*Issue
+	services := make(map[string]bool, len(etcdResp[service]))
+	for _, kv := range etcdResp[service] {
+		serviceID, domainProject := path.GetInfoFromSvcKV(kv.Key)
+		// the key of the service converted from mongo is the document id
+		if v, err := value.ServiceParser.Unmarshal(kv.Value); err == nil && len(v.(*pb.MicroService).ServiceId) > 0 {
+			serviceID = v.(*pb.MicroService).ServiceId
+		}
+		services[domainProject+path.SPLIT+serviceID] = true
+	}
+
+	for _, kv := range etcdResp[instance] {
+		key := string(kv.Key)
+		serviceID, instanceID, domainProject := path.GetInfoFromInstKV(kv.Key)
+		ops := []clientv3.Op{
+			clientv3.OpDelete(key),
+			clientv3.OpDelete(path.GenerateInstanceLeaseKey(domainProject, serviceID, instanceID)),
+		}
+		switch {
+		case !services[domainProject+path.SPLIT+serviceID]:
+			issues = append(issues, &Issue{Class: ClassOrphanInstance, Key: key, Ops: ops,
+				Cmps:   []clientv3.Cmp{unchanged(kv), absent(path.GenerateServiceKey(domainProject, serviceID))},
+				Detail: fmt.Sprintf("service %s does not exist", serviceID)})
+		case kv.Lease == 0:
+			issues = append(issues, &Issue{Class: ClassExpiredLease, Key: key, Ops: ops,
+				Cmps:   []clientv3.Cmp{unchanged(kv)},
+				Detail: "instance is not attached to any lease"})
+		case !leases[kv.Lease]:
+			issues = append(issues, &Issue{Class: ClassExpiredLease, Key: key, Ops: ops,
+				Cmps:   []clientv3.Cmp{unchanged(kv)},
+				Detail: fmt.Sprintf("lease %x is expired", kv.Lease)})
+		}
+	}
+
+	indexes := make(map[string]bool, len(etcdResp[serviceIndex]))
+	checkIndex := func(class, t string) {
+		for _, kv := range etcdResp[t] {
+			key := path.GetInfoFromSvcIndexKV(kv.Key)
+			if key == nil {
+				continue
+			}
+			serviceID := string(kv.Value)
+			if services[key.Tenant+path.SPLIT+serviceID] {
+				indexes[string(kv.Key)] = true
+				continue
+			}
+			issues = append(issues, &Issue{Class: class, Key: string(kv.Key),
+				Cmps:   []clientv3.Cmp{unchanged(kv), absent(path.GenerateServiceKey(key.Tenant, serviceID))},
+				Ops:    []clientv3.Op{clientv3.OpDelete(string(kv.Key))},
+				Detail: fmt.Sprintf("service %s does not exist", serviceID)})
+		}
+	}
+	checkIndex(ClassDanglingIndex, serviceIndex)
+	checkIndex(ClassDanglingAlias, serviceAlias)
+
+	schemas := make(map[string]bool, len(etcdResp[schema]))
+	for _, kv := range etcdResp[schema] {
+		schemas[string(kv.Key)] = true
+	}
+	for _, kv := range etcdResp[schemaSummary] {
+		domainProject, serviceID, schemaID := path.GetInfoFromSchemaSummaryKV(kv.Key)
+		schemaKey := path.GenerateServiceSchemaKey(domainProject, serviceID, schemaID)
+		if schemas[schemaKey] {
+			continue
+		}
+		issues = append(issues, &Issue{Class: ClassSchemaSummary, Key: string(kv.Key),
+			Cmps:   []clientv3.Cmp{unchanged(kv), absent(schemaKey)},
+			Ops:    []clientv3.Op{clientv3.OpDelete(string(kv.Key))},
+			Detail: fmt.Sprintf("schema %s of service %s has no content", schemaID, serviceID)})
+	}
+
+	exist := func(key *pb.MicroServiceKey) bool {
+		return indexes[path.GenerateServiceIndexKey(key)]
+	}
+	for _, kv := range etcdResp[dependencyRule] {
+		if issue := checkDependencyRule(kv, exist); issue != nil {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// checkDependencyRule checks the consumer of the rule exists,
+// the provider may be registered later, so it is not checked
+func checkDependencyRule(kv *mvccpb.KeyValue, exist func(key *pb.MicroServiceKey) bool) *Issue {
+	key := string(kv.Key)
+	t, ruleKey := path.GetInfoFromDependencyRuleKV(kv.Key)
+	if ruleKey == nil {
+		return nil
+	}
+	if t == path.DepsConsumer {
+		if exist(ruleKey) {
+			return nil
+		}
+		return &Issue{Class: ClassDanglingDependency, Key: key,
+			Cmps:   []clientv3.Cmp{unchanged(kv), absent(path.GenerateServiceIndexKey(ruleKey))},
+			Ops:    []clientv3.Op{clientv3.OpDelete(key)},
+			Detail: fmt.Sprintf("consumer %s does not exist", toServiceName(ruleKey))}
+	}
+
+	// the value of the provider rule is the consumers list
+	v, err := value.DependencyRuleParser.Unmarshal(kv.Value)
+	if err != nil {
+		return nil
+	}
+	rule := v.(*pb.MicroServiceDependency)
+	var (
+		kept    []*pb.MicroServiceKey
+		deleted []string
+	)
+	cmps := []clientv3.Cmp{unchanged(kv)}
+	for _, consumer := range rule.Dependency {
+		if len(consumer.Tenant) == 0 {
+			consumer.Tenant = ruleKey.Tenant
+		}
+		if exist(consumer) {
+			kept = append(kept, consumer)
+			continue
+		}
+		deleted = append(deleted, toServiceName(consumer))
+		cmps = append(cmps, absent(path.GenerateServiceIndexKey(consumer)))
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+	issue := &Issue{Class: ClassDanglingDependency, Key: key, Cmps: cmps,
+		Detail: fmt.Sprintf("consumers %v do not exist", deleted)}
+	if len(kept) == 0 {
+		issue.Ops = []clientv3.Op{clientv3.OpDelete(key)}
+		return issue
+	}
+	data, err := json.Marshal(&pb.MicroServiceDependency{Dependency: kept})
+	if err != nil {
+		return nil
+	}
+	issue.Ops = []clientv3.Op{clientv3.OpPut(key, string(data))}
+	return issue
+}
+
+// cacheIssues converts the differences between the cache of member and etcd to issues,
+// they are repaired by rewriting the keys to make the member receive the events again,
+// the keys only found in cache are not written back, the member must be restarted
+func cacheIssues(member string, etcdResp etcdResponse, rss ...*CompareResult) []*Issue {
+	kvs := make(map[string]*mvccpb.KeyValue)
+	for _, t := range []string{service, instance} {
+		for _, kv := range etcdResp[t] {
+			kvs[string(kv.Key)] = kv
+		}
+	}
+
+	var issues []*Issue
+	for _, rs := range rss {
+		for t, keys := range rs.Keys {
+			for _, key := range keys {
+				issue := &Issue{Class: ClassCacheDivergence, Key: key}
+				switch t {
+				case greater:
+					issue.Detail = fmt.Sprintf("%s found in cache of %s but not in etcd", rs.Name, member)
+					issue.Manual = fmt.Sprintf("restart %s to reload the cache", member)
+				default:
+					kv := kvs[key]
+					if kv == nil {
+						continue
+					}
+					issue.Detail = fmt.Sprintf("%s in cache of %s is different from etcd", rs.Name, member)
+					if t == less {
+						issue.Detail = fmt.Sprintf("%s found in etcd but not in cache of %s", rs.Name, member)
+					}
+					// rewrite the same value, keep the lease of the instance
+					var opts []clientv3.OpOption
+					if kv.Lease != 0 {
+						opts = append(opts, clientv3.WithIgnoreLease())
+					}
+					issue.Cmps = []clientv3.Cmp{unchanged(kv)}
+					issue.Ops = []clientv3.Op{clientv3.OpPut(key, string(kv.Value), opts...)}
+				}
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues
+}
+
+func toServiceName(key *pb.MicroServiceKey) string {
+	return fmt.Sprintf("%s/%s/%s", key.AppId, key.ServiceName, key.Version)
+}
+
+// sortIssues sorts the issues by class and key
+func sortIssues(issues []*Issue) {
+	order := make(map[string]int, len(classOrder))
+	for i, c := range classOrder {
+		order[c] = i
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Class != issues[j].Class {
+			return order[issues[i].Class] < order[issues[j].Class]
+		}
+		return issues[i].Key < issues[j].Key
+	})
+}
@@ -0,0 +1,206 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+)
+
+func newKV(key, value string, lease int64) *mvccpb.KeyValue {
+	return &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value), Lease: lease, ModRevision: 1}
+}
+
+func TestCheck(t *testing.T) {
+	etcdResp := etcdResponse{
+		service: {
+			newKV("/cse-sr/ms/files/default/default/s1", `{"serviceId":"s1"}`, 0),
+			newKV("/cse-sr/ms/files/default/default/s2", `{"serviceId":"s2"}`, 0),
+		},
+		instance: {
+			newKV("/cse-sr/inst/files/default/default/s1/i1", `{}`, 1), // pass
+			newKV("/cse-sr/inst/files/default/default/s0/i2", `{}`, 1), // orphan
+			newKV("/cse-sr/inst/files/default/default/s1/i3", `{}`, 2), // expired
+			newKV("/cse-sr/inst/files/default/default/s2/i4", `{}`, 0), // no lease
+		},
+		serviceIndex: {
+			newKV("/cse-sr/ms/indexes/default/default/dev/app/c1/1.0.0", "s1", 0),
+			newKV("/cse-sr/ms/indexes/default/default/dev/app/p1/1.0.0", "s2", 0),
+			newKV("/cse-sr/ms/indexes/default/default/dev/app/c0/1.0.0", "s0", 0), // dangling
+		},
+		serviceAlias: {
+			newKV("/cse-sr/ms/alias/default/default/dev/app/a0/1.0.0", "s0", 0), // dangling
+		},
+		schema: {
+			newKV("/cse-sr/ms/schemas/default/default/s1/hello", "", 0),
+		},
+		schemaSummary: {
+			newKV("/cse-sr/ms/schema-sum/default/default/s1/hello", "x", 0),
+			newKV("/cse-sr/ms/schema-sum/default/default/s1/world", "x", 0), // no content
+		},
+		dependencyRule: {
+			newKV("/cse-sr/ms/dep-rules/default/default/c/dev/app/c1/1.0.0", `{}`, 0),
+			newKV("/cse-sr/ms/dep-rules/default/default/c/dev/app/c0/1.0.0", `{}`, 0), // deleted consumer
+			newKV("/cse-sr/ms/dep-rules/default/default/p/dev/app/p1/1.0.0",
+				`{"Dependency":[{"environment":"dev","appId":"app","serviceName":"c1","version":"1.0.0"},{"environment":"dev","appId":"app","serviceName":"c0","version":"1.0.0"}]}`, 0), // update
+			newKV("/cse-sr/ms/dep-rules/default/default/p/dev/app/p0/1.0.0",
+				`{"Dependency":[{"tenant":"default/default","environment":"dev","appId":"app","serviceName":"c0","version":"1.0.0"}]}`, 0), // delete
+		},
+	}
+	issues := check(etcdResp, map[int64]bool{1: true, 2: false})
+	sortIssues(issues)
+
+	classes := make(map[string]int)
+	for _, issue := range issues {
+		classes[issue.Class]++
+		if len(issue.Ops) == 0 || len(issue.Cmps) == 0 {
+			t.Fatalf("TestCheck failed, %s has no fix", issue.Key)
+		}
+	}
+	expected := map[string]int{
+		ClassOrphanInstance:     1,
+		ClassExpiredLease:       2,
+		ClassDanglingIndex:      1,
+		ClassDanglingAlias:      1,
+		ClassSchemaSummary:      1,
+		ClassDanglingDependency: 3,
+	}
+	for class, n := range expected {
+		if classes[class] != n {
+			t.Fatalf("TestCheck failed, %s expected %d but %d", class, n, classes[class])
+		}
+	}
+	if issues[0].Class != ClassOrphanInstance || len(issues[0].Ops) != 2 ||
+		string(issues[0].Ops[1].KeyBytes()) != "/cse-sr/inst/leases/default/default/s0/i2" {
+		t.Fatalf("TestCheck failed, %v", issues[0])
+	}
+	// the instance is not modified and the service is not registered since diagnosed
+	if cmps := issues[0].Cmps; len(cmps) != 2 ||
+		string(cmps[0].Key) != "/cse-sr/inst/files/default/default/s0/i2" ||
+		cmps[0].Target != etcdserverpb.Compare_MOD || cmps[0].TargetUnion.(*etcdserverpb.Compare_ModRevision).ModRevision != 1 ||
+		string(cmps[1].Key) != "/cse-sr/ms/files/default/default/s0" ||
+		cmps[1].Target != etcdserverpb.Compare_CREATE {
+		t.Fatalf("TestCheck failed, %v", issues[0].Cmps)
+	}
+	for _, issue := range issues {
+		if issue.Key != "/cse-sr/ms/dep-rules/default/default/p/dev/app/p1/1.0.0" {
+			continue
+		}
+		op := issue.Ops[0]
+		if !op.IsPut() || strings.Contains(string(op.ValueBytes()), "c0") ||
+			!strings.Contains(string(op.ValueBytes()), "c1") {
+			t.Fatalf("TestCheck failed, %s", op.ValueBytes())
+		}
+		if len(issue.Cmps) != 2 || string(issue.Cmps[1].Key) != "/cse-sr/ms/indexes/default/default/dev/app/c0/1.0.0" {
+			t.Fatalf("TestCheck failed, %v", issue.Cmps)
+		}
+	}
+
+	var b, full bytes.Buffer
+	writeIssues(&b, &full, 0, issues)
+	trim(&b, &full)
+	if !strings.HasPrefix(b.String(), "1. found orphan instance 2. found expired lease") ||
+		!strings.Contains(full.String(), "/cse-sr/ms/alias/default/default/dev/app/a0/1.0.0: service s0 does not exist") {
+		t.Fatalf("TestCheck failed, %s\n%s", b.String(), full.String())
+	}
+}
+
+func TestFindMembers(t *testing.T) {
+	etcdResp := etcdResponse{
+		service: {
+			newKV("/cse-sr/ms/files/default/default/sc", `{"serviceId":"sc","appId":"default","serviceName":"SERVICECENTER"}`, 0),
+			newKV("/cse-sr/ms/files/default/default/s1", `{"serviceId":"s1","appId":"default","serviceName":"other"}`, 0),
+		},
+		instance: {
+			newKV("/cse-sr/inst/files/default/default/sc/i1", `{"serviceId":"sc","endpoints":["rest://10.0.0.2:30100/","grpc://10.0.0.2:30101"]}`, 1),
+			newKV("/cse-sr/inst/files/default/default/sc/i2", `{"serviceId":"sc","endpoints":["rest://10.0.0.1:30100?sslEnabled=true"]}`, 1),
+			newKV("/cse-sr/inst/files/default/default/s1/i3", `{"serviceId":"s1","endpoints":["rest://10.0.0.3:8080"]}`, 1),
+		},
+	}
+	members := findMembers(etcdResp)
+	if len(members) != 2 || members[0] != "http://10.0.0.2:30100" || members[1] != "https://10.0.0.1:30100" {
+		t.Fatalf("TestFindMembers failed, %v", members)
+	}
+}
+
+func TestCacheIssues(t *testing.T) {
+	etcdResp := etcdResponse{
+		instance: {newKV("/i2", `{"instanceId":"2"}`, 1)},
+	}
+	rs := &CompareResult{Name: instance, Keys: map[int][]string{greater: {"/i1"}, less: {"/i2"}}}
+	issues := cacheIssues("http://127.0.0.1:30100", etcdResp, rs)
+	sortIssues(issues)
+	if len(issues) != 2 {
+		t.Fatalf("TestCacheIssues failed, %v", issues)
+	}
+	// the key only in cache is not written back to etcd
+	if issues[0].Key != "/i1" || len(issues[0].Ops) != 0 || len(issues[0].Manual) == 0 {
+		t.Fatalf("TestCacheIssues failed, %v", issues[0])
+	}
+	if ops := issues[1].Ops; len(ops) != 1 || string(ops[0].ValueBytes()) != `{"instanceId":"2"}` ||
+		len(issues[1].Cmps) != 1 {
+		t.Fatalf("TestCacheIssues failed, %v", issues[1])
+	}
+}
+
+type mockStore struct {
+	Store
+	failKey     string
+	conflictKey string
+	ops         []clientv3.Op
+}
+
+func (m *mockStore) Txn(_ context.Context, cmps []clientv3.Cmp, ops []clientv3.Op) (bool, error) {
+	for _, cmp := range cmps {
+		if string(cmp.Key) == m.conflictKey {
+			return false, nil
+		}
+	}
+	for _, op := range ops {
+		if string(op.KeyBytes()) == m.failKey {
+			return false, errors.New("mock error")
+		}
+	}
+	m.ops = append(m.ops, ops...)
+	return true, nil
+}
+
+func TestFix(t *testing.T) {
+	store := &mockStore{failKey: "/b", conflictKey: "/c"}
+	issues := []*Issue{
+		{Class: ClassOrphanInstance, Key: "/a", Ops: []clientv3.Op{clientv3.OpDelete("/a"), clientv3.OpDelete("/a/lease")}},
+		{Class: ClassDanglingIndex, Key: "/b", Ops: []clientv3.Op{clientv3.OpDelete("/b")}},
+		{Class: ClassDanglingIndex, Key: "/c", Cmps: []clientv3.Cmp{absent("/c")}, Ops: []clientv3.Op{clientv3.OpDelete("/c")}},
+		{Class: ClassCacheDivergence, Key: "/d", Manual: "restart"},
+	}
+	var w bytes.Buffer
+	if failed := fix(context.Background(), store, issues, &w); failed != 1 || len(store.ops) != 2 {
+		t.Fatalf("TestFix failed, %d %v", failed, store.ops)
+	}
+	if !strings.Contains(w.String(), "orphan instance: 1 fixed, 0 skipped, 0 failed") ||
+		!strings.Contains(w.String(), "dangling service index: 0 fixed, 1 skipped, 1 failed") ||
+		!strings.Contains(w.String(), "cache divergence: 0 fixed, 1 skipped, 0 failed") ||
+		!strings.Contains(w.String(), "skipped dangling service index /c: changed after diagnosed") {
+		t.Fatalf("TestFix failed, %s", w.String())
+	}
+}
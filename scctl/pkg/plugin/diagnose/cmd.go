@@ -20,11 +20,19 @@ import (
 
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/scctl/etcd"
+	"github.com/apache/servicecomb-service-center/scctl/mongo"
 	root "github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
 	"github.com/spf13/cobra"
 )
 
-var EtcdClientConfig etcd.Config
+var (
+	RegistryKind      string
+	EtcdClientConfig  etcd.Config
+	MongoClientConfig mongo.Config
+	Fix               bool
+	Yes               bool
+	AllMembers        bool
+)
 
 func init() {
 	root.RootCmd().AddCommand(NewDiagnoseCommand(root.RootCmd()))
@@ -32,12 +40,20 @@ func init() {
 
 func NewDiagnoseCommand(parent *cobra.Command) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "diagnose [options]",
-		Short:   "Output the service center diagnostic report",
-		Run:     DiagnoseCommandFunc,
-		Example: parent.CommandPath() + ` diagnose --addr "http://127.0.0.1:30100" --etcd-addr "http://127.0.0.1:2379";`,
+		Use:   "diagnose [options]",
+		Short: "Output the service center diagnostic report",
+		Long: `Output the service center diagnostic report, it checks the cache of service center
+is consistent with etcd, and finds the orphan instances, the instances with expired leases,
+the dangling service indexes and aliases, the schema summaries without content and
+the dependency rules of the deleted services in etcd or mongo.`,
+		Run: DiagnoseCommandFunc,
+		Example: parent.CommandPath() + ` diagnose --addr "http://127.0.0.1:30100" --etcd-addr "http://127.0.0.1:2379";
+` + parent.CommandPath() + ` diagnose --all-members --fix;
+` + parent.CommandPath() + ` diagnose --registry-kind mongo --mongo-uri "mongodb://127.0.0.1:27017"`,
 	}
 
+	cmd.Flags().StringVar(&RegistryKind, "registry-kind", KindEtcd,
+		"the registry storage of service center, etcd or mongo")
 	cmd.Flags().StringVar(&EtcdClientConfig.Addrs, "etcd-addr",
 		util.GetEnvString("CSE_REGISTRY_ADDRESS", "http://127.0.0.1:2379"),
 		"the http addr and port of etcd endpoints")
@@ -55,6 +71,24 @@ func NewDiagnoseCommand(parent *cobra.Command) *cobra.Command {
 		"the passphase file path to decrypt key file, can be overrode by env $SSL_ROOT/cert_pwd.")
 	cmd.Flags().StringVar(&EtcdClientConfig.CertKeyPWD, "etcd-pass", "",
 		"the passphase string to decrypt key file.")
+	cmd.Flags().StringVar(&MongoClientConfig.URI, "mongo-uri",
+		util.GetEnvString("MONGO_URI", "mongodb://127.0.0.1:27017"),
+		"the uri of mongo with --registry-kind mongo")
+	cmd.Flags().BoolVar(&MongoClientConfig.SSLEnabled, "mongo-ssl", false, "enable ssl to access mongo")
+	cmd.Flags().StringVar(&MongoClientConfig.RootCA, "mongo-ca",
+		filepath.Join(util.GetEnvString("SSL_ROOT", "."), "trust.cer"),
+		"the CA file path to access mongo, can be overrode by env $SSL_ROOT/trust.cer.")
+	cmd.Flags().StringVar(&MongoClientConfig.CertFile, "mongo-cert", "",
+		"the certificate file path to access mongo")
+	cmd.Flags().StringVar(&MongoClientConfig.KeyFile, "mongo-key", "",
+		"the key file path to access mongo")
+	cmd.Flags().BoolVar(&MongoClientConfig.VerifyPeer, "mongo-verify-peer", false,
+		"verify the certificate of mongo")
+	cmd.Flags().BoolVar(&Fix, "fix", false, "repair the issues found and print the report")
+	cmd.Flags().BoolVarP(&Yes, "yes", "y", false, "fix without confirmation")
+	cmd.Flags().BoolVar(&AllMembers, "all-members", false,
+		"check the caches of all the service center members registered in etcd")
 
+	NewAccessCommand(cmd)
 	return cmd
 }
@@ -47,6 +47,8 @@ func (d *DataStore) ForEach(f func(i int, v *dump.KV) bool) {
 type CompareResult struct {
 	Name    string
 	Results map[int][]string
+	// Keys are the etcd keys of the Results
+	Keys map[int][]string
 }
 
 type abstractCompareHolder struct {
@@ -67,6 +69,7 @@ func (h *abstractCompareHolder) toMap(getter dump.Getter) map[string]*dump.KV {
 func (h *abstractCompareHolder) Compare() *CompareResult {
 	result := &CompareResult{
 		Results: make(map[int][]string),
+		Keys:    make(map[int][]string),
 	}
 	leftCh := make(chan map[string]*dump.KV, 2)
 	rightCh := make(chan map[string]*dump.KV, 2)
@@ -75,6 +78,10 @@ func (h *abstractCompareHolder) Compare() *CompareResult {
 		add    []string
 		update []string
 		del    []string
+
+		addKeys    []string
+		updateKeys []string
+		delKeys    []string
 	)
 
 	gopool.New(context.Background(), gopool.Configure().Workers(3)).
@@ -96,10 +103,12 @@ func (h *abstractCompareHolder) Compare() *CompareResult {
 				rkv, ok := right[lk]
 				if !ok {
 					add = append(add, h.MismatchFunc(lkv))
+					addKeys = append(addKeys, lk)
 					continue
 				}
 				if rkv.Rev != lkv.Rev {
 					update = append(update, h.MismatchFunc(lkv))
+					updateKeys = append(updateKeys, lk)
 				}
 			}
 		}).
@@ -110,6 +119,7 @@ func (h *abstractCompareHolder) Compare() *CompareResult {
 			for rk, rkv := range right {
 				if _, ok := left[rk]; !ok {
 					del = append(del, h.MismatchFunc(rkv))
+					delKeys = append(delKeys, rk)
 				}
 			}
 		}).
@@ -117,12 +127,15 @@ func (h *abstractCompareHolder) Compare() *CompareResult {
 
 	if len(add) > 0 {
 		result.Results[greater] = add
+		result.Keys[greater] = addKeys
 	}
 	if len(update) > 0 {
 		result.Results[mismatch] = update
+		result.Keys[mismatch] = updateKeys
 	}
 	if len(del) > 0 {
 		result.Results[less] = del
+		result.Keys[less] = delKeys
 	}
 	return result
 }
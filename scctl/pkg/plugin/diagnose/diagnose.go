@@ -18,20 +18,31 @@ package diagnose
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/apache/servicecomb-service-center/client"
 	"github.com/apache/servicecomb-service-center/pkg/dump"
-	"github.com/apache/servicecomb-service-center/scctl/etcd"
 	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
 	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/mvcc/mvccpb"
+	pb "github.com/go-chassis/cari/discovery"
 	"github.com/spf13/cobra"
 )
 
 const (
-	service  = "service"
-	instance = "instance"
+	service        = "service"
+	instance       = "instance"
+	serviceIndex   = "serviceIndex"
+	serviceAlias   = "serviceAlias"
+	schema         = "schema"
+	schemaSummary  = "schemaSummary"
+	dependencyRule = "dependencyRule"
 )
 
 const (
@@ -40,85 +51,272 @@ const (
 	less
 )
 
+const registryServiceName = "SERVICECENTER"
+
 var typeMap = map[string]string{
-	service:  "/cse-sr/ms/files/",
-	instance: "/cse-sr/inst/files/",
+	service:        "/cse-sr/ms/files/",
+	instance:       "/cse-sr/inst/files/",
+	serviceIndex:   "/cse-sr/ms/indexes/",
+	serviceAlias:   "/cse-sr/ms/alias/",
+	schema:         "/cse-sr/ms/schemas/",
+	schemaSummary:  "/cse-sr/ms/schema-sum/",
+	dependencyRule: "/cse-sr/ms/dep-rules/",
+}
+
+// the schema contents are large and only the keys are checked
+var keysOnlyTypes = map[string]bool{
+	schema: true,
 }
 
 type etcdResponse map[string][]*mvccpb.KeyValue
 
 func DiagnoseCommandFunc(_ *cobra.Command, args []string) {
-	// initialize sc/etcd clients
-	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	ctx := context.Background()
+	// initialize etcd/mongo clients
+	store, err := NewStore(RegistryKind)
 	if err != nil {
 		cmd.StopAndExit(cmd.ExitError, err)
 	}
-	etcdClient, err := etcd.NewEtcdClient(EtcdClientConfig)
+	defer store.Close()
+
+	// query etcd/mongo
+	etcdResp, leases, err := store.Load(ctx)
 	if err != nil {
 		cmd.StopAndExit(cmd.ExitError, err)
 	}
-	defer etcdClient.Close()
 
-	// query etcd
-	etcdResp, err := getEtcdResponse(context.Background(), etcdClient)
+	// query sc
+	caches, err := getCaches(ctx, etcdResp)
 	if err != nil {
 		cmd.StopAndExit(cmd.ExitError, err)
 	}
 
-	// query sc
-	cache, scErr := scClient.GetScCache(context.Background())
-	if scErr != nil {
-		cmd.StopAndExit(cmd.ExitError, scErr)
+	// diagnose go...
+	var (
+		b      bytes.Buffer
+		full   bytes.Buffer
+		issues []*Issue
+		i      int
+	)
+	members := make([]string, 0, len(caches))
+	for member := range caches {
+		members = append(members, member)
 	}
+	sort.Strings(members)
+	for _, member := range members {
+		rss := compare(caches[member], etcdResp)
+		label := ""
+		if AllMembers {
+			label = member
+		}
+		i = writeResult(&b, &full, i, label, rss...)
+		issues = append(issues, cacheIssues(member, etcdResp, rss...)...)
+	}
+	found := check(etcdResp, leases)
+	sortIssues(found)
+	writeIssues(&b, &full, i, found)
+	issues = append(issues, found...)
+	if b.Len() == 0 {
+		return
+	}
+	trim(&b, &full)
 
-	// diagnose go...
-	err, details := diagnose(cache, etcdResp)
-	if err != nil {
-		fmt.Println(details)                // stdout
-		cmd.StopAndExit(cmd.ExitError, err) // stderr
+	fmt.Println(full.String()) // stdout
+	if !Fix {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("error: %s", b.String())) // stderr
+	}
+	cmd.ConfirmOrExit(Yes, fmt.Sprintf("Fix the %d issues?", len(issues)))
+	if failed := fix(ctx, store, issues, os.Stdout); failed > 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("error: failed to fix %d issues", failed))
 	}
 }
 
+// getEtcdResponse reads all the prefixes at the revision of the first read,
+// so the data of different prefixes are from the same snapshot
 func getEtcdResponse(ctx context.Context, etcdClient *clientv3.Client) (etcdResponse, error) {
 	etcdResp := make(etcdResponse)
+	var rev int64
 	for t, prefix := range typeMap {
-		if err := setResponse(ctx, etcdClient, t, prefix, etcdResp); err != nil {
+		current, err := setResponse(ctx, etcdClient, t, prefix, rev, etcdResp)
+		if err != nil {
 			return nil, err
 		}
+		if rev == 0 {
+			rev = current
+		}
 	}
 	return etcdResp, nil
 }
 
-func setResponse(ctx context.Context, etcdClient *clientv3.Client, key, prefix string, etcdResp etcdResponse) error {
-	resp, err := etcdClient.Get(ctx, prefix, clientv3.WithPrefix())
+// setResponse reads the prefix at rev, the latest if rev is 0, and returns the current revision
+func setResponse(ctx context.Context, etcdClient *clientv3.Client, key, prefix string, rev int64, etcdResp etcdResponse) (int64, error) {
+	opts := []clientv3.OpOption{clientv3.WithPrefix()}
+	if keysOnlyTypes[key] {
+		opts = append(opts, clientv3.WithKeysOnly())
+	}
+	if rev > 0 {
+		opts = append(opts, clientv3.WithRev(rev))
+	}
+	resp, err := etcdClient.Get(ctx, prefix, opts...)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	etcdResp[key] = resp.Kvs
-	return nil
+	return resp.Header.Revision, nil
 }
 
-func diagnose(cache *dump.Cache, etcdResp etcdResponse) (err error, details string) {
+// getLeases returns the alive leases which the instances attached to
+func getLeases(ctx context.Context, etcdClient *clientv3.Client, kvs []*mvccpb.KeyValue) (map[int64]bool, error) {
+	leases := make(map[int64]bool)
+	for _, kv := range kvs {
+		if kv.Lease == 0 {
+			continue
+		}
+		if _, ok := leases[kv.Lease]; ok {
+			continue
+		}
+		resp, err := etcdClient.TimeToLive(ctx, clientv3.LeaseID(kv.Lease))
+		if err != nil {
+			return nil, err
+		}
+		leases[kv.Lease] = resp.TTL > 0
+	}
+	return leases, nil
+}
+
+// getCaches returns the caches of the service center members,
+// only the configured service center is queried without --all-members
+func getCaches(ctx context.Context, etcdResp etcdResponse) (map[string]*dump.Cache, error) {
+	addrs := []string{strings.Join(cmd.ScClientConfig.Endpoints, ",")}
+	if AllMembers {
+		addrs = findMembers(etcdResp)
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("no service center member found in etcd")
+		}
+	}
+	caches := make(map[string]*dump.Cache, len(addrs))
+	for _, addr := range addrs {
+		cfg := cmd.ScClientConfig
+		if AllMembers {
+			cfg.Endpoints = []string{addr}
+		}
+		scClient, err := client.NewSCClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		cache, scErr := scClient.GetScCache(ctx)
+		if scErr != nil {
+			return nil, fmt.Errorf("query cache of %s failed, %s", addr, scErr.Error())
+		}
+		caches[addr] = cache
+	}
+	return caches, nil
+}
+
+// findMembers returns the addresses of the service center instances registered in etcd
+func findMembers(etcdResp etcdResponse) []string {
+	ids := make(map[string]bool)
+	for _, kv := range etcdResp[service] {
+		s := &pb.MicroService{}
+		if err := json.Unmarshal(kv.Value, s); err != nil {
+			continue
+		}
+		if s.AppId == "default" && s.ServiceName == registryServiceName {
+			ids[s.ServiceId] = true
+		}
+	}
+	set := make(map[string]bool)
+	for _, kv := range etcdResp[instance] {
+		inst := &pb.MicroServiceInstance{}
+		if err := json.Unmarshal(kv.Value, inst); err != nil || !ids[inst.ServiceId] {
+			continue
+		}
+		for _, endpoint := range inst.Endpoints {
+			if addr, ok := toMemberAddr(endpoint); ok {
+				set[addr] = true
+			}
+		}
+	}
+	addrs := make([]string, 0, len(set))
+	for addr := range set {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+// toMemberAddr converts the rest endpoint, e.g. rest://127.0.0.1:30100?sslEnabled=true, to the http address
+func toMemberAddr(endpoint string) (string, bool) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "rest" || len(u.Host) == 0 {
+		return "", false
+	}
+	if u.Query().Get("sslEnabled") == "true" {
+		return "https://" + u.Host, true
+	}
+	return "http://" + u.Host, true
+}
+
+func compare(cache *dump.Cache, etcdResp etcdResponse) []*CompareResult {
 	var (
 		service  = ServiceCompareHolder{Cache: cache.Microservices, Kvs: etcdResp[service]}
 		instance = InstanceCompareHolder{Cache: cache.Instances, Kvs: etcdResp[instance]}
 	)
+	return []*CompareResult{service.Compare(), instance.Compare()}
+}
 
-	sr := service.Compare()
-	ir := instance.Compare()
-
+func diagnose(cache *dump.Cache, etcdResp etcdResponse) (err error, details string) {
 	var (
 		b    bytes.Buffer
 		full bytes.Buffer
 	)
-	writeResult(&b, &full, sr, ir)
+	writeResult(&b, &full, 0, "", compare(cache, etcdResp)...)
 	if b.Len() > 0 {
+		trim(&b, &full)
 		return fmt.Errorf("error: %s", b.String()), full.String()
 	}
 	return nil, ""
 }
 
-func writeResult(b *bytes.Buffer, full *bytes.Buffer, rss ...*CompareResult) {
+// fix executes the operations of the issues, prints the report and returns the number of failures,
+// the issues changed since diagnosed or without safe operations are skipped
+func fix(ctx context.Context, store Store, issues []*Issue, w io.Writer) (failed int) {
+	fixed := make(map[string]int)
+	skipped := make(map[string]int)
+	failures := make(map[string]int)
+	for _, issue := range issues {
+		if len(issue.Ops) == 0 {
+			skipped[issue.Class]++
+			fmt.Fprintf(w, "skipped %s %s: %s\n", issue.Class, issue.Key, issue.Manual)
+			continue
+		}
+		ok, err := store.Txn(ctx, issue.Cmps, issue.Ops)
+		if err != nil {
+			failures[issue.Class]++
+			failed++
+			fmt.Fprintf(w, "failed to fix %s %s: %s\n", issue.Class, issue.Key, err.Error())
+			continue
+		}
+		if !ok {
+			skipped[issue.Class]++
+			fmt.Fprintf(w, "skipped %s %s: changed after diagnosed\n", issue.Class, issue.Key)
+			continue
+		}
+		fixed[issue.Class]++
+		fmt.Fprintf(w, "fixed %s %s\n", issue.Class, issue.Key)
+	}
+
+	fmt.Fprintln(w, "Fix report:")
+	for _, class := range classOrder {
+		if fixed[class]+skipped[class]+failures[class] == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "  %s: %d fixed, %d skipped, %d failed\n", class, fixed[class], skipped[class], failures[class])
+	}
+	return
+}
+
+func writeResult(b *bytes.Buffer, full *bytes.Buffer, i int, member string, rss ...*CompareResult) int {
 	g, m, l := make(map[string][]string), make(map[string][]string), make(map[string][]string)
 	for _, rs := range rss {
 		for t, arr := range rs.Results {
@@ -133,28 +331,53 @@ func writeResult(b *bytes.Buffer, full *bytes.Buffer, rss ...*CompareResult) {
 		}
 	}
 
-	i := 0
+	cache := "cache"
+	if len(member) > 0 {
+		cache = "cache of " + member
+	}
 	if s := len(g); s > 0 {
 		i++
-		header := fmt.Sprintf("%d. found in cache but not in etcd ", i)
+		header := fmt.Sprintf("%d. found in %s but not in etcd ", i, cache)
 		b.WriteString(header)
 		full.WriteString(header)
 		writeBody(full, g)
 	}
 	if s := len(m); s > 0 {
 		i++
-		header := fmt.Sprintf("%d. found different between cache and etcd ", i)
+		header := fmt.Sprintf("%d. found different between %s and etcd ", i, cache)
 		b.WriteString(header)
 		full.WriteString(header)
 		writeBody(full, m)
 	}
 	if s := len(l); s > 0 {
 		i++
-		header := fmt.Sprintf("%d. found in etcd but not in cache ", i)
+		header := fmt.Sprintf("%d. found in etcd but not in %s ", i, cache)
 		b.WriteString(header)
 		full.WriteString(header)
 		writeBody(full, l)
 	}
+	return i
+}
+
+// writeIssues writes the sorted issues grouped by class
+func writeIssues(b *bytes.Buffer, full *bytes.Buffer, i int, issues []*Issue) int {
+	for j := 0; j < len(issues); {
+		class := issues[j].Class
+		i++
+		header := fmt.Sprintf("%d. found %s ", i, class)
+		b.WriteString(header)
+		full.WriteString(header)
+		full.WriteString("\b, details:\n")
+		for ; j < len(issues) && issues[j].Class == class; j++ {
+			writeSection(full, issues[j].Key)
+			full.WriteString(issues[j].Detail)
+			full.WriteRune('\n')
+		}
+	}
+	return i
+}
+
+func trim(b *bytes.Buffer, full *bytes.Buffer) {
 	if l := b.Len(); l > 0 {
 		b.Truncate(l - 1)
 		full.Truncate(full.Len() - 1)
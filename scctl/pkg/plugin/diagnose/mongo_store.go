@@ -0,0 +1,277 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/datasource/etcd/path"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client"
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	mutil "github.com/apache/servicecomb-service-center/datasource/mongo/util"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	pb "github.com/go-chassis/cari/discovery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// the same as the default ttl of the mongo heartbeat
+const defaultInstanceTTL = 30
+
+type serviceDocument struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	model.Service `bson:",inline"`
+}
+
+type instanceDocument struct {
+	ID             primitive.ObjectID `bson:"_id"`
+	model.Instance `bson:",inline"`
+}
+
+type schemaDocument struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	model.Schema `bson:",inline"`
+}
+
+type ruleDocument struct {
+	ID                   primitive.ObjectID `bson:"_id"`
+	model.DependencyRule `bson:",inline"`
+}
+
+// documentRef is the mongo document which the etcd KV converted from
+type documentRef struct {
+	collection string
+	id         primitive.ObjectID
+	// loaded are the values of the document when loaded, it is repaired only if they are not changed
+	loaded bson.M
+}
+
+// mongoStore converts the mongo documents to the etcd KVs, so the same checks can be run,
+// the keys of services and instances are the same as the cache dump of service center
+type mongoStore struct {
+	client *client.MongoClient
+	refs   map[string]*documentRef
+}
+
+func (s *mongoStore) Load(ctx context.Context) (etcdResponse, map[int64]bool, error) {
+	var (
+		services  []*serviceDocument
+		instances []*instanceDocument
+		schemas   []*schemaDocument
+		rules     []*ruleDocument
+	)
+	// the documents referring to the services are loaded before the services,
+	// then a service registered during loading is never mistaken for a deleted one
+	for _, c := range []struct {
+		table  string
+		result interface{}
+	}{
+		{model.CollectionInstance, &instances},
+		{model.CollectionSchema, &schemas},
+		{model.CollectionDep, &rules},
+		{model.CollectionService, &services},
+	} {
+		if err := s.find(ctx, c.table, c.result); err != nil {
+			return nil, nil, fmt.Errorf("query collection %s failed, %s", c.table, err.Error())
+		}
+	}
+
+	etcdResp, leases, refs := toKVs(time.Now(), services, instances, schemas, rules)
+	s.refs = refs
+	return etcdResp, leases, nil
+}
+
+func (s *mongoStore) find(ctx context.Context, table string, result interface{}) error {
+	cursor, err := s.client.Find(ctx, table, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, result)
+}
+
+// Txn translates the etcd operations to the mongo operations by the keys, the documents
+// have no revision, so the conditions are checked by the values of the loaded documents,
+// the keys not stored in mongo, e.g. the lease keys, are ignored
+func (s *mongoStore) Txn(ctx context.Context, _ []clientv3.Cmp, ops []clientv3.Op) (bool, error) {
+	for _, op := range ops {
+		ok, err := s.do(ctx, op)
+		if err != nil || !ok {
+			return ok, err
+		}
+	}
+	return true, nil
+}
+
+func (s *mongoStore) do(ctx context.Context, op clientv3.Op) (bool, error) {
+	ref, ok := s.refs[string(op.KeyBytes())]
+	if !ok {
+		return true, nil
+	}
+	filter := bson.M{"_id": ref.id}
+	for k, v := range ref.loaded {
+		filter[k] = v
+	}
+	var (
+		matched int64
+		err     error
+	)
+	switch {
+	case op.IsDelete() && ref.collection == model.CollectionSchema:
+		matched, err = s.update(ctx, ref.collection, filter, bson.M{"$unset": bson.M{model.ColumnSchemaSummary: ""}})
+	case op.IsDelete():
+		var resp *mongo.DeleteResult
+		resp, err = s.client.DeleteOne(ctx, ref.collection, filter)
+		if err == nil {
+			matched = resp.DeletedCount
+		}
+	case op.IsPut() && ref.collection == model.CollectionDep:
+		dep := &pb.MicroServiceDependency{}
+		if err = json.Unmarshal(op.ValueBytes(), dep); err != nil {
+			return false, err
+		}
+		matched, err = s.update(ctx, ref.collection, filter, bson.M{"$set": bson.M{model.ColumnDep: dep}})
+	case op.IsPut():
+		// update the mod timestamp to make service center receive the update event
+		column := mutil.ConnectWithDot([]string{model.ColumnService, model.ColumnModTime})
+		if ref.collection == model.CollectionInstance {
+			column = mutil.ConnectWithDot([]string{model.ColumnInstance, model.ColumnModTime})
+		}
+		matched, err = s.update(ctx, ref.collection, filter,
+			bson.M{"$set": bson.M{column: strconv.FormatInt(time.Now().Unix(), 10)}})
+	default:
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return matched > 0, nil
+}
+
+func (s *mongoStore) update(ctx context.Context, table string, filter, update bson.M) (int64, error) {
+	resp, err := s.client.Update(ctx, table, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return resp.MatchedCount, nil
+}
+
+func (s *mongoStore) Close() {
+	s.client.Close()
+}
+
+func toKVs(now time.Time, services []*serviceDocument, instances []*instanceDocument,
+	schemas []*schemaDocument, rules []*ruleDocument) (etcdResponse, map[int64]bool, map[string]*documentRef) {
+	etcdResp := make(etcdResponse)
+	refs := make(map[string]*documentRef)
+	add := func(t, key string, v interface{}, ref *documentRef) *mvccpb.KeyValue {
+		var data []byte
+		switch value := v.(type) {
+		case string:
+			data = []byte(value)
+		case nil:
+		default:
+			data, _ = json.Marshal(value)
+		}
+		kv := &mvccpb.KeyValue{Key: []byte(key), Value: data}
+		etcdResp[t] = append(etcdResp[t], kv)
+		if ref != nil {
+			refs[key] = ref
+		}
+		return kv
+	}
+
+	for _, doc := range services {
+		if doc.Service.Service == nil {
+			continue
+		}
+		ms := doc.Service.Service
+		domainProject := doc.Domain + path.SPLIT + doc.Project
+		add(service, util.StringJoin([]string{datasource.ServiceKeyPrefix, doc.Domain, doc.Project, doc.ID.Hex()}, path.SPLIT),
+			ms, &documentRef{collection: model.CollectionService, id: doc.ID, loaded: bson.M{
+				mutil.ConnectWithDot([]string{model.ColumnService, model.ColumnModTime}): ms.ModTimestamp}})
+		// the indexes are stored in the service document
+		key := &pb.MicroServiceKey{Tenant: domainProject, Environment: ms.Environment,
+			AppId: ms.AppId, ServiceName: ms.ServiceName, Alias: ms.Alias, Version: ms.Version}
+		add(serviceIndex, path.GenerateServiceIndexKey(key), ms.ServiceId, nil)
+		if len(ms.Alias) > 0 {
+			add(serviceAlias, path.GenerateServiceAliasKey(key), ms.ServiceId, nil)
+		}
+	}
+
+	// the instances have no lease, the expired ones are attached to the fake expired leases
+	leases := make(map[int64]bool, len(instances))
+	for i, doc := range instances {
+		if doc.Instance.Instance == nil {
+			continue
+		}
+		inst := doc.Instance.Instance
+		kv := add(instance, util.StringJoin([]string{datasource.InstanceKeyPrefix, doc.Domain, doc.Project,
+			inst.ServiceId, doc.ID.Hex()}, path.SPLIT),
+			inst, &documentRef{collection: model.CollectionInstance, id: doc.ID,
+				loaded: bson.M{model.ColumnRefreshTime: doc.RefreshTime}})
+		kv.Lease = int64(i + 1)
+		leases[kv.Lease] = !isExpired(now, doc.RefreshTime, inst.HealthCheck)
+	}
+
+	for _, doc := range schemas {
+		domainProject := doc.Domain + path.SPLIT + doc.Project
+		if len(doc.Schema.Schema) > 0 {
+			add(schema, path.GenerateServiceSchemaKey(domainProject, doc.ServiceID, doc.SchemaID), nil, nil)
+		}
+		if len(doc.SchemaSummary) > 0 {
+			loaded := bson.M{model.ColumnSchemaSummary: doc.SchemaSummary}
+			if len(doc.Schema.Schema) == 0 {
+				loaded[model.ColumnSchema] = bson.M{"$in": bson.A{nil, ""}}
+			}
+			add(schemaSummary, path.GenerateServiceSchemaSummaryKey(domainProject, doc.ServiceID, doc.SchemaID),
+				doc.SchemaSummary, &documentRef{collection: model.CollectionSchema, id: doc.ID, loaded: loaded})
+		}
+	}
+
+	for _, doc := range rules {
+		if doc.ServiceKey == nil {
+			continue
+		}
+		domainProject := doc.Domain + path.SPLIT + doc.Project
+		dep := doc.Dep
+		if dep == nil {
+			dep = &pb.MicroServiceDependency{}
+		}
+		add(dependencyRule, path.GenerateServiceDependencyRuleKey(doc.Type, domainProject, doc.ServiceKey),
+			dep, &documentRef{collection: model.CollectionDep, id: doc.ID, loaded: bson.M{model.ColumnDep: doc.Dep}})
+	}
+	return etcdResp, leases, refs
+}
+
+func isExpired(now, refreshTime time.Time, healthCheck *pb.HealthCheck) bool {
+	var ttl int32
+	if healthCheck != nil {
+		ttl = healthCheck.Interval * (healthCheck.Times + 1)
+	}
+	if ttl <= 0 {
+		ttl = defaultInstanceTTL
+	}
+	return now.Sub(refreshTime) > time.Duration(ttl)*time.Second
+}
@@ -0,0 +1,84 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource/mongo/client/model"
+	pb "github.com/go-chassis/cari/discovery"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestToKVs(t *testing.T) {
+	now := time.Now()
+	services := []*serviceDocument{
+		{ID: primitive.NewObjectID(), Service: model.Service{Domain: "default", Project: "default",
+			Service: &pb.MicroService{ServiceId: "s1", AppId: "app", ServiceName: "c1", Version: "1.0.0", Environment: "dev"}}},
+	}
+	instances := []*instanceDocument{
+		{ID: primitive.NewObjectID(), Instance: model.Instance{Domain: "default", Project: "default", RefreshTime: now,
+			Instance: &pb.MicroServiceInstance{ServiceId: "s1", InstanceId: "i1"}}}, // pass
+		{ID: primitive.NewObjectID(), Instance: model.Instance{Domain: "default", Project: "default", RefreshTime: now.Add(-time.Minute),
+			Instance: &pb.MicroServiceInstance{ServiceId: "s1", InstanceId: "i2",
+				HealthCheck: &pb.HealthCheck{Interval: 10, Times: 2}}}}, // expired
+		{ID: primitive.NewObjectID(), Instance: model.Instance{Domain: "default", Project: "default", RefreshTime: now,
+			Instance: &pb.MicroServiceInstance{ServiceId: "s0", InstanceId: "i3"}}}, // orphan
+	}
+	schemas := []*schemaDocument{
+		{ID: primitive.NewObjectID(), Schema: model.Schema{Domain: "default", Project: "default",
+			ServiceID: "s1", SchemaID: "hello", Schema: "swagger", SchemaSummary: "x"}},
+		{ID: primitive.NewObjectID(), Schema: model.Schema{Domain: "default", Project: "default",
+			ServiceID: "s1", SchemaID: "world", SchemaSummary: "x"}}, // no content
+	}
+	rules := []*ruleDocument{
+		{ID: primitive.NewObjectID(), DependencyRule: model.DependencyRule{Type: "c", Domain: "default", Project: "default",
+			ServiceKey: &pb.MicroServiceKey{Environment: "dev", AppId: "app", ServiceName: "c1", Version: "1.0.0"}}},
+		{ID: primitive.NewObjectID(), DependencyRule: model.DependencyRule{Type: "c", Domain: "default", Project: "default",
+			ServiceKey: &pb.MicroServiceKey{Environment: "dev", AppId: "app", ServiceName: "c0", Version: "1.0.0"}}}, // deleted consumer
+	}
+
+	etcdResp, leases, refs := toKVs(now, services, instances, schemas, rules)
+	if len(etcdResp[service]) != 1 || len(etcdResp[serviceIndex]) != 1 || len(etcdResp[instance]) != 3 ||
+		len(etcdResp[schema]) != 1 || len(etcdResp[schemaSummary]) != 2 || len(etcdResp[dependencyRule]) != 2 {
+		t.Fatalf("TestToKVs failed, %v", etcdResp)
+	}
+	key := "/cse-sr/ms/files/default/default/" + services[0].ID.Hex()
+	if string(etcdResp[service][0].Key) != key || refs[key].id != services[0].ID {
+		t.Fatalf("TestToKVs failed, %s", etcdResp[service][0].Key)
+	}
+
+	issues := check(etcdResp, leases)
+	classes := make(map[string]int)
+	for _, issue := range issues {
+		classes[issue.Class]++
+	}
+	if len(issues) != 4 || classes[ClassOrphanInstance] != 1 || classes[ClassExpiredLease] != 1 ||
+		classes[ClassSchemaSummary] != 1 || classes[ClassDanglingDependency] != 1 {
+		t.Fatalf("TestToKVs failed, %v", classes)
+	}
+	for _, issue := range issues {
+		if issue.Class == ClassSchemaSummary && refs[issue.Key].id != schemas[1].ID {
+			t.Fatalf("TestToKVs failed, %v", issue)
+		}
+		// the expired instance is deleted only if it is not refreshed since loaded
+		if issue.Class == ClassExpiredLease &&
+			refs[issue.Key].loaded[model.ColumnRefreshTime] != instances[1].RefreshTime {
+			t.Fatalf("TestToKVs failed, %v", refs[issue.Key])
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/scctl/etcd"
+	"github.com/apache/servicecomb-service-center/scctl/mongo"
+	"github.com/coreos/etcd/clientv3"
+)
+
+// the kinds of the registry storage
+const (
+	KindEtcd  = "etcd"
+	KindMongo = "mongo"
+)
+
+// Store is the storage of service center to diagnose
+type Store interface {
+	// Load returns the data converted to the etcd KVs, and the alive leases of the instances
+	Load(ctx context.Context) (etcdResponse, map[int64]bool, error)
+	// Txn executes the etcd operations to repair the issue if the conditions hold,
+	// returns false if the data are changed since loaded
+	Txn(ctx context.Context, cmps []clientv3.Cmp, ops []clientv3.Op) (bool, error)
+	Close()
+}
+
+func NewStore(kind string) (Store, error) {
+	switch kind {
+	case KindEtcd:
+		etcdClient, err := etcd.NewEtcdClient(EtcdClientConfig)
+		if err != nil {
+			return nil, err
+		}
+		return &etcdStore{client: etcdClient}, nil
+	case KindMongo:
+		mongoClient, err := mongo.NewMongoClient(MongoClientConfig)
+		if err != nil {
+			return nil, err
+		}
+		return &mongoStore{client: mongoClient}, nil
+	default:
+		return nil, fmt.Errorf("unknown registry kind '%s', must be etcd or mongo", kind)
+	}
+}
+
+type etcdStore struct {
+	client *clientv3.Client
+}
+
+func (s *etcdStore) Load(ctx context.Context) (etcdResponse, map[int64]bool, error) {
+	etcdResp, err := getEtcdResponse(ctx, s.client)
+	if err != nil {
+		return nil, nil, err
+	}
+	leases, err := getLeases(ctx, s.client, etcdResp[instance])
+	if err != nil {
+		return nil, nil, err
+	}
+	return etcdResp, leases, nil
+}
+
+func (s *etcdStore) Txn(ctx context.Context, cmps []clientv3.Cmp, ops []clientv3.Op) (bool, error) {
+	resp, err := s.client.Txn(ctx).If(cmps...).Then(ops...).Commit()
+	if err != nil {
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
+
+func (s *etcdStore) Close() {
+	s.client.Close()
+}
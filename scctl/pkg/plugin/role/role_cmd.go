@@ -0,0 +1,258 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	"github.com/ghodss/yaml"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/spf13/cobra"
+)
+
+var (
+	File      string
+	Resources []string
+	Verbs     []string
+	Labels    map[string]string
+	Accounts  []string
+	Yes       bool
+)
+
+var (
+	roleTableHeader = []string{"NAME", "RESOURCES", "AGE"}
+	permTableHeader = []string{"RESOURCE", "LABELS", "VERBS"}
+)
+
+func init() {
+	NewRoleCommand(cmd.RootCmd())
+}
+
+func NewRoleCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "role <command> [options]",
+		Short: "Manage the roles of service center",
+	}
+
+	parent.AddCommand(cmd)
+
+	createCmd := &cobra.Command{
+		Use:   "create NAME [options]",
+		Short: "Create the role from the role file or the permission options",
+		Args:  cobra.ExactArgs(1),
+		Run:   CreateCommandFunc,
+		Example: cmd.CommandPath() + ` create viewer --resource service,governance --verb get;
+` + cmd.CommandPath() + ` create app-x-dev --resource service --verb '*' --label app=x;
+` + cmd.CommandPath() + ` create ops -f ops-role.yaml`,
+	}
+	createCmd.Flags().StringVarP(&File, "file", "f", "", "the yaml or json file of the role")
+	createCmd.Flags().StringSliceVar(&Resources, "resource", nil,
+		"the resources of the permission, e.g. service, governance, service/schema, account, role, ops")
+	createCmd.Flags().StringSliceVar(&Verbs, "verb", nil, "the verbs of the permission, e.g. get, create, update, delete, *")
+	createCmd.Flags().StringToStringVar(&Labels, "label", nil, "the labels of the resources, e.g. app=x,environment=production")
+	cmd.AddCommand(createCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the roles",
+		Args:    cobra.NoArgs,
+		Run:     ListCommandFunc,
+		Example: cmd.CommandPath() + ` list`,
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "describe NAME",
+		Short:   "Show the permissions of the role",
+		Args:    cobra.ExactArgs(1),
+		Run:     DescribeCommandFunc,
+		Example: cmd.CommandPath() + ` describe developer`,
+	})
+
+	bindCmd := &cobra.Command{
+		Use:     "bind NAME [options]",
+		Short:   "Bind the role to the accounts",
+		Args:    cobra.ExactArgs(1),
+		Run:     BindCommandFunc,
+		Example: cmd.CommandPath() + ` bind app-x-dev --account dev1,dev2`,
+	}
+	bindCmd.Flags().StringSliceVar(&Accounts, "account", nil, "the accounts to bind")
+	bindCmd.Flags().BoolVarP(&Yes, "yes", "y", false, "bind without the confirmation prompt")
+	cmd.AddCommand(bindCmd)
+	return cmd
+}
+
+func newClient() *client.Client {
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	return scClient
+}
+
+func CreateCommandFunc(_ *cobra.Command, args []string) {
+	role, err := NewRole(args[0], File, Resources, Verbs, Labels)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	if scErr := newClient().CreateRole(context.Background(), role); scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	fmt.Printf("role %s created\n", role.Name)
+}
+
+// NewRole builds the role from the file, or from the permission options if file is empty
+func NewRole(name, file string, resources, verbs []string, labels map[string]string) (*rbac.Role, error) {
+	role := &rbac.Role{}
+	if len(file) > 0 {
+		if len(resources) > 0 || len(verbs) > 0 || len(labels) > 0 {
+			return nil, errors.New("the permission options can not be used with the role file")
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, role); err != nil {
+			return nil, fmt.Errorf("parse %s failed: %v", file, err)
+		}
+		role.Name = name
+		return role, nil
+	}
+	if len(resources) == 0 || len(verbs) == 0 {
+		return nil, errors.New("--resource and --verb are required if no role file specified")
+	}
+	perm := &rbac.Permission{Verbs: verbs}
+	for _, r := range resources {
+		perm.Resources = append(perm.Resources, &rbac.Resource{Type: r, Labels: labels})
+	}
+	role.Name = name
+	role.Perms = []*rbac.Permission{perm}
+	return role, nil
+}
+
+func ListCommandFunc(_ *cobra.Command, _ []string) {
+	roles, scErr := newClient().ListRoles(context.Background())
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	body := make([][]string, 0, len(roles))
+	for _, r := range roles {
+		body = append(body, []string{r.Name, strings.Join(RoleResources(r), ","), writer.AgeFormat(r.CreateTime)})
+	}
+	writer.MakeTable(roleTableHeader, body)
+}
+
+// RoleResources returns the sorted resource types of the role
+func RoleResources(role *rbac.Role) []string {
+	set := make(map[string]struct{})
+	for _, perm := range role.Perms {
+		for _, r := range perm.Resources {
+			set[r.Type] = struct{}{}
+		}
+	}
+	types := make([]string, 0, len(set))
+	for t := range set {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func DescribeCommandFunc(_ *cobra.Command, args []string) {
+	role, scErr := newClient().GetRole(context.Background(), args[0])
+	if scErr != nil {
+		cmd.StopAndExit(cmd.ExitError, scErr)
+	}
+	fmt.Printf("Name:\t%s\nCreated:\t%s ago\n", role.Name, writer.AgeFormat(role.CreateTime))
+	writer.MakeTable(permTableHeader, PermTableBody(role.Perms))
+}
+
+func PermTableBody(perms []*rbac.Permission) [][]string {
+	var body [][]string
+	for _, perm := range perms {
+		verbs := strings.Join(perm.Verbs, ",")
+		for _, r := range perm.Resources {
+			body = append(body, []string{r.Type, LabelsString(r.Labels), verbs})
+		}
+	}
+	return body
+}
+
+// LabelsString formats the labels as sorted k=v pairs, returns "*" if no labels
+func LabelsString(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "*"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func BindCommandFunc(_ *cobra.Command, args []string) {
+	if len(Accounts) == 0 {
+		cmd.StopAndExit(cmd.ExitError, "--account is required")
+	}
+	name := args[0]
+	cmd.ConfirmOrExit(Yes, fmt.Sprintf("Bind the role %s to the accounts %s?", name, strings.Join(Accounts, ",")))
+
+	ctx := context.Background()
+	scClient := newClient()
+	failed := 0
+	for _, accountName := range Accounts {
+		account, scErr := scClient.GetAccount(ctx, accountName)
+		if scErr != nil {
+			fmt.Printf("get account %s failed: %s\n", accountName, scErr.Message)
+			failed++
+			continue
+		}
+		roles, ok := AppendRole(account.Roles, name)
+		if !ok {
+			fmt.Printf("account %s already has the role %s\n", accountName, name)
+			continue
+		}
+		scErr = scClient.UpdateAccount(ctx, &rbac.Account{Name: accountName, Roles: roles})
+		if scErr != nil {
+			fmt.Printf("bind the role to account %s failed: %s\n", accountName, scErr.Message)
+			failed++
+			continue
+		}
+		fmt.Printf("role %s bound to account %s\n", name, accountName)
+	}
+	if failed > 0 {
+		cmd.StopAndExit(cmd.ExitError)
+	}
+}
+
+// AppendRole appends the role to the roles, returns false if the role is already in the roles
+func AppendRole(roles []string, role string) ([]string, bool) {
+	for _, r := range roles {
+		if r == role {
+			return roles, false
+		}
+	}
+	return append(roles, role), true
+}
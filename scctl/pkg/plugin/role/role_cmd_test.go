@@ -0,0 +1,84 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package role
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewRole(t *testing.T) {
+	role, err := NewRole("dev", "", []string{"service", "governance"}, []string{"get"}, map[string]string{"app": "x"})
+	if err != nil || role.Name != "dev" || len(role.Perms) != 1 || len(role.Perms[0].Resources) != 2 {
+		t.Fatalf("TestNewRole failed, %v %v", role, err)
+	}
+	if role.Perms[0].Resources[1].Type != "governance" || role.Perms[0].Resources[1].Labels["app"] != "x" {
+		t.Fatalf("TestNewRole failed, %v", role.Perms[0].Resources[1])
+	}
+	if _, err := NewRole("dev", "", []string{"service"}, nil, nil); err == nil {
+		t.Fatalf("TestNewRole failed, no verb")
+	}
+
+	dir, err := ioutil.TempDir("", "role")
+	if err != nil {
+		t.Fatalf("TestNewRole failed, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "role.yaml")
+	err = ioutil.WriteFile(file, []byte(`name: ignored
+perms:
+- resources:
+  - type: service
+    labels:
+      app: x
+  verbs: ["get", "update"]
+`), 0600)
+	if err != nil {
+		t.Fatalf("TestNewRole failed, %v", err)
+	}
+	role, err = NewRole("ops", file, nil, nil, nil)
+	if err != nil || role.Name != "ops" || len(role.Perms) != 1 {
+		t.Fatalf("TestNewRole failed, %v %v", role, err)
+	}
+	if !reflect.DeepEqual(PermTableBody(role.Perms), [][]string{{"service", "app=x", "get,update"}}) {
+		t.Fatalf("TestNewRole failed, %v", PermTableBody(role.Perms))
+	}
+	if _, err := NewRole("ops", file, []string{"service"}, nil, nil); err == nil {
+		t.Fatalf("TestNewRole failed, file with options")
+	}
+}
+
+func TestLabelsString(t *testing.T) {
+	if s := LabelsString(nil); s != "*" {
+		t.Fatalf("TestLabelsString failed, %s", s)
+	}
+	if s := LabelsString(map[string]string{"b": "2", "a": "1"}); s != "a=1,b=2" {
+		t.Fatalf("TestLabelsString failed, %s", s)
+	}
+}
+
+func TestAppendRole(t *testing.T) {
+	roles, ok := AppendRole([]string{"developer"}, "viewer")
+	if !ok || !reflect.DeepEqual(roles, []string{"developer", "viewer"}) {
+		t.Fatalf("TestAppendRole failed, %v", roles)
+	}
+	if _, ok := AppendRole(roles, "viewer"); ok {
+		t.Fatalf("TestAppendRole failed, duplicated role")
+	}
+}
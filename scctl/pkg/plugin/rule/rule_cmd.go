@@ -0,0 +1,135 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rule
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/selector"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Selector    selector.Selector
+	Yes         bool
+	RuleType    string
+	Attribute   string
+	Pattern     string
+	Description string
+)
+
+func init() {
+	NewRuleCommand(cmd.RootCmd())
+}
+
+func NewRuleCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rule <command> [options]",
+		Short: "Manage the access rules of the microservices",
+	}
+	Selector.BindFlags(cmd.PersistentFlags())
+	cmd.PersistentFlags().BoolVarP(&Yes, "yes", "y", false, "update without the confirmation prompt")
+	cmd.PersistentFlags().StringVar(&Attribute, "attribute", "", "the attribute of the rule, e.g. ServiceName, tag_xxx, ip")
+	cmd.PersistentFlags().StringVar(&Pattern, "pattern", "", "the pattern of the rule")
+
+	parent.AddCommand(cmd)
+
+	addCmd := &cobra.Command{
+		Use:   "add [options]",
+		Short: "Add the rule to the microservices",
+		Run:   AddCommandFunc,
+		Example: cmd.CommandPath() + ` add --type WHITE --attribute ServiceName --pattern "^consumer$" --service-id 2a0b2f6a1c8e11ecb1a10242ac110002;
+` + cmd.CommandPath() + ` add --type DENY --attribute ip --pattern 10.0.0.0/8 --app springmvc --name provider -y`,
+	}
+	addCmd.Flags().StringVar(&RuleType, "type", "", "the type of the rule, WHITE, BLACK, ALLOW or DENY")
+	addCmd.Flags().StringVar(&Description, "description", "", "the description of the rule")
+	cmd.AddCommand(addCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:     "rm [RULE_ID...] [options]",
+		Aliases: []string{"remove"},
+		Short:   "Remove the rules by id, or by attribute and pattern from the microservices",
+		Run:     RemoveCommandFunc,
+		Example: cmd.CommandPath() + ` rm 5bdd6a8c1c8f11ecb1a10242ac110002 --service-id 2a0b2f6a1c8e11ecb1a10242ac110002;
+` + cmd.CommandPath() + ` rm --attribute ip --app springmvc --name provider -y`,
+	})
+	return cmd
+}
+
+func AddCommandFunc(_ *cobra.Command, args []string) {
+	if len(RuleType) == 0 || len(Attribute) == 0 || len(Pattern) == 0 {
+		cmd.StopAndExit(cmd.ExitError, "required --type, --attribute and --pattern")
+	}
+	rule := &pb.AddOrUpdateServiceRule{
+		RuleType:    RuleType,
+		Attribute:   Attribute,
+		Pattern:     Pattern,
+		Description: Description,
+	}
+	Selector.ForEachService(Yes, fmt.Sprintf("Add rule %s %s=%s to", RuleType, Attribute, Pattern), "add rule",
+		func(ctx context.Context, c *client.Client, service *pb.MicroService) *errsvc.Error {
+			_, scErr := c.AddRules(ctx, Selector.Domain, Selector.Project, service.ServiceId,
+				[]*pb.AddOrUpdateServiceRule{rule})
+			return scErr
+		})
+}
+
+func RemoveCommandFunc(_ *cobra.Command, args []string) {
+	if len(args) == 0 && len(Attribute) == 0 {
+		cmd.StopAndExit(cmd.ExitError, "required RULE_ID or --attribute")
+	}
+	Selector.ForEachService(Yes, "Remove the rules from", "remove rules",
+		func(ctx context.Context, c *client.Client, service *pb.MicroService) *errsvc.Error {
+			rules, scErr := c.GetRules(ctx, Selector.Domain, Selector.Project, service.ServiceId)
+			if scErr != nil {
+				return scErr
+			}
+			for _, rule := range MatchRules(rules, args, Attribute, Pattern) {
+				scErr = c.DeleteRule(ctx, Selector.Domain, Selector.Project, service.ServiceId, rule.RuleId)
+				if scErr != nil {
+					return scErr
+				}
+				fmt.Printf("rule %s %s %s=%s removed\n", rule.RuleId, rule.RuleType, rule.Attribute, rule.Pattern)
+			}
+			return nil
+		})
+}
+
+// MatchRules returns the rules with the ids,
+// or the rules with the attribute and the pattern if pattern is not empty
+func MatchRules(rules []*pb.ServiceRule, ids []string, attribute, pattern string) []*pb.ServiceRule {
+	var matched []*pb.ServiceRule
+	for _, rule := range rules {
+		if len(ids) > 0 {
+			for _, id := range ids {
+				if rule.RuleId == id {
+					matched = append(matched, rule)
+					break
+				}
+			}
+			continue
+		}
+		if rule.Attribute == attribute && (len(pattern) == 0 || rule.Pattern == pattern) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
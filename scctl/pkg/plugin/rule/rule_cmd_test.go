@@ -0,0 +1,43 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rule
+
+import (
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+func TestMatchRules(t *testing.T) {
+	rules := []*pb.ServiceRule{
+		{RuleId: "1", Attribute: "ServiceName", Pattern: "a"},
+		{RuleId: "2", Attribute: "ServiceName", Pattern: "b"},
+		{RuleId: "3", Attribute: "ip", Pattern: "10.0.0.0/8"},
+	}
+
+	matched := MatchRules(rules, []string{"3", "4"}, "ServiceName", "")
+	if len(matched) != 1 || matched[0].RuleId != "3" {
+		t.Fatalf("TestMatchRules failed, %v", matched)
+	}
+	matched = MatchRules(rules, nil, "ServiceName", "")
+	if len(matched) != 2 {
+		t.Fatalf("TestMatchRules failed, %v", matched)
+	}
+	matched = MatchRules(rules, nil, "ServiceName", "b")
+	if len(matched) != 1 || matched[0].RuleId != "2" {
+		t.Fatalf("TestMatchRules failed, %v", matched)
+	}
+}
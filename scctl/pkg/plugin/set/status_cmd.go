@@ -0,0 +1,93 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package set
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/selector"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/spf13/cobra"
+)
+
+var InstanceID string
+
+var instanceStatuses = []string{pb.MSI_UP, pb.MSI_DOWN, pb.MSI_STARTING, pb.MSI_TESTING, pb.MSI_OUTOFSERVICE}
+
+func NewStatusCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status <" + strings.Join(instanceStatuses, "|") + "> [options]",
+		Short: "Update the status of the microservice instances",
+		Args:  cobra.ExactArgs(1),
+		Run:   StatusCommandFunc,
+		Example: parent.CommandPath() + ` status OUTOFSERVICE --service-id 2a0b2f6a1c8e11ecb1a10242ac110002 --instance-id 7a6be9f861a811e9b3f6fa163eca30e0;
+` + parent.CommandPath() + ` status UP --app springmvc --name provider --version 0.0.1 -y`,
+	}
+	cmd.Flags().StringVar(&InstanceID, "instance-id", "", "select the instance by id, empty means all the instances")
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func StatusCommandFunc(_ *cobra.Command, args []string) {
+	status := strings.ToUpper(args[0])
+	if !isInstanceStatus(status) {
+		cmd.StopAndExit(cmd.ExitError, fmt.Sprintf("invalid status '%s', must be one of %s",
+			args[0], strings.Join(instanceStatuses, ", ")))
+	}
+
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	ctx := context.Background()
+	targets, err := Selector.Instances(ctx, scClient, InstanceID)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+
+	writer.MakeTable(selector.InstanceTableHeader, selector.InstanceTableBody(targets))
+	cmd.ConfirmOrExit(Yes, fmt.Sprintf("Set %d instances to %s?", len(targets), status))
+
+	failed := 0
+	for _, target := range targets {
+		scErr := scClient.UpdateInstanceStatus(ctx, Selector.Domain, Selector.Project,
+			target.Service.ServiceId, target.Instance.InstanceId, status)
+		if scErr != nil {
+			failed++
+			fmt.Printf("instance %s set status failed: %s\n", target.Instance.InstanceId, scErr.Error())
+			continue
+		}
+		fmt.Printf("instance %s status %s -> %s\n", target.Instance.InstanceId, target.Instance.Status, status)
+	}
+	if failed > 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Sprintf("error: %d of %d instances set status failed", failed, len(targets)))
+	}
+}
+
+func isInstanceStatus(status string) bool {
+	for _, s := range instanceStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
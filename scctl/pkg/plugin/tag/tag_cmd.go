@@ -0,0 +1,98 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/selector"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Selector selector.Selector
+	Yes      bool
+)
+
+func init() {
+	NewTagCommand(cmd.RootCmd())
+}
+
+func NewTagCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag <command> [options]",
+		Short: "Manage the tags of the microservices",
+	}
+	Selector.BindFlags(cmd.PersistentFlags())
+	cmd.PersistentFlags().BoolVarP(&Yes, "yes", "y", false, "update without the confirmation prompt")
+
+	parent.AddCommand(cmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "add KEY=VALUE... [options]",
+		Short: "Add the tags to the microservices, the value of the existing key will be overwritten",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   AddCommandFunc,
+		Example: cmd.CommandPath() + ` add team=a zone=z1 --service-id 2a0b2f6a1c8e11ecb1a10242ac110002;
+` + cmd.CommandPath() + ` add team=a --app springmvc --name provider -y`,
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:     "rm KEY... [options]",
+		Aliases: []string{"remove"},
+		Short:   "Remove the tags from the microservices",
+		Args:    cobra.MinimumNArgs(1),
+		Run:     RemoveCommandFunc,
+		Example: cmd.CommandPath() + ` rm team zone --app springmvc --name provider --version 0.0.1`,
+	})
+	return cmd
+}
+
+func AddCommandFunc(_ *cobra.Command, args []string) {
+	tags, err := ParseTags(args)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	Selector.ForEachService(Yes, fmt.Sprintf("Add tags %s to", strings.Join(args, ",")), "add tags",
+		func(ctx context.Context, c *client.Client, service *pb.MicroService) *errsvc.Error {
+			return c.AddTags(ctx, Selector.Domain, Selector.Project, service.ServiceId, tags)
+		})
+}
+
+func RemoveCommandFunc(_ *cobra.Command, args []string) {
+	Selector.ForEachService(Yes, fmt.Sprintf("Remove tags %s from", strings.Join(args, ",")), "remove tags",
+		func(ctx context.Context, c *client.Client, service *pb.MicroService) *errsvc.Error {
+			return c.DeleteTags(ctx, Selector.Domain, Selector.Project, service.ServiceId, args)
+		})
+}
+
+// ParseTags parses the KEY=VALUE arguments
+func ParseTags(args []string) (map[string]string, error) {
+	tags := make(map[string]string, len(args))
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, fmt.Errorf("invalid tag '%s', must be KEY=VALUE", arg)
+		}
+		tags[kv[0]] = kv[1]
+	}
+	return tags, nil
+}
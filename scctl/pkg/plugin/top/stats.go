@@ -0,0 +1,213 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package top
+
+import (
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/metrics"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// the metric families exported by service center
+const (
+	keyServiceTotal    = metrics.FamilyName + "_db_service_total"
+	keyInstanceTotal   = metrics.FamilyName + "_db_instance_total"
+	keySchemaTotal     = metrics.FamilyName + "_db_schema_total"
+	keyHeartbeatTotal  = metrics.FamilyName + "_db_heartbeat_total"
+	keySubscriberTotal = metrics.FamilyName + "_notify_subscriber_total"
+	keyRequestTotal    = metrics.FamilyName + "_http_request_total"
+	keySuccessTotal    = metrics.FamilyName + "_http_success_total"
+	keyRequestDuration = metrics.FamilyName + "_http_request_durations_microseconds"
+	keyQueryPerSeconds = metrics.FamilyName + "_http_query_per_seconds"
+
+	heartbeatFailure = "FAILURE"
+)
+
+// Tenant is the statistics of a domain/project
+type Tenant struct {
+	Domain    string
+	Project   string
+	Services  int64
+	Instances int64
+	Schemas   int64
+}
+
+// API is the request statistics of a rest api
+type API struct {
+	Method   string
+	Path     string
+	QPS      float64
+	Requests int64
+	Errors   int64
+	// the total latency in microseconds, used to calculate the average latency
+	latencySum   float64
+	latencyCount uint64
+}
+
+// Latency returns the average latency of the api
+func (a *API) Latency() time.Duration {
+	if a.latencyCount == 0 {
+		return 0
+	}
+	return time.Duration(a.latencySum / float64(a.latencyCount) * float64(time.Microsecond))
+}
+
+// Snapshot is the aggregated statistics of service center at a moment
+type Snapshot struct {
+	Time              time.Time
+	Tenants           map[string]*Tenant
+	APIs              map[string]*API
+	Watchers          map[string]int64
+	Heartbeats        int64
+	HeartbeatFailures int64
+}
+
+// NewSnapshot aggregates the metric families of all the service center instances,
+// the empty domain means all domains
+func NewSnapshot(now time.Time, families map[string]*dto.MetricFamily, domain string) *Snapshot {
+	s := &Snapshot{
+		Time:     now,
+		Tenants:  make(map[string]*Tenant),
+		APIs:     make(map[string]*API),
+		Watchers: make(map[string]int64),
+	}
+	match := func(labels map[string]string) bool {
+		return len(domain) == 0 || labels["domain"] == domain
+	}
+
+	eachMetric(families[keyServiceTotal], func(labels map[string]string, m *dto.Metric) {
+		if match(labels) {
+			s.tenant(labels).Services += int64(gaugeValue(m))
+		}
+	})
+	eachMetric(families[keyInstanceTotal], func(labels map[string]string, m *dto.Metric) {
+		if match(labels) {
+			s.tenant(labels).Instances += int64(gaugeValue(m))
+		}
+	})
+	eachMetric(families[keySchemaTotal], func(labels map[string]string, m *dto.Metric) {
+		if match(labels) {
+			s.tenant(labels).Schemas += int64(gaugeValue(m))
+		}
+	})
+	eachMetric(families[keySubscriberTotal], func(labels map[string]string, m *dto.Metric) {
+		if match(labels) {
+			s.Watchers[labels["domain"]] += int64(gaugeValue(m))
+		}
+	})
+	eachMetric(families[keyHeartbeatTotal], func(labels map[string]string, m *dto.Metric) {
+		n := int64(counterValue(m))
+		s.Heartbeats += n
+		if labels["status"] == heartbeatFailure {
+			s.HeartbeatFailures += n
+		}
+	})
+	eachMetric(families[keyRequestTotal], func(labels map[string]string, m *dto.Metric) {
+		if match(labels) {
+			a := s.api(labels)
+			a.Requests += int64(counterValue(m))
+			a.Errors += int64(counterValue(m))
+		}
+	})
+	eachMetric(families[keySuccessTotal], func(labels map[string]string, m *dto.Metric) {
+		if match(labels) {
+			s.api(labels).Errors -= int64(counterValue(m))
+		}
+	})
+	eachMetric(families[keyRequestDuration], func(labels map[string]string, m *dto.Metric) {
+		if match(labels) && m.GetSummary() != nil {
+			a := s.api(labels)
+			a.latencySum += m.GetSummary().GetSampleSum()
+			a.latencyCount += m.GetSummary().GetSampleCount()
+		}
+	})
+	eachMetric(families[keyQueryPerSeconds], func(labels map[string]string, m *dto.Metric) {
+		if match(labels) {
+			s.api(labels).QPS += gaugeValue(m)
+		}
+	})
+	return s
+}
+
+func (s *Snapshot) tenant(labels map[string]string) *Tenant {
+	key := labels["domain"] + "/" + labels["project"]
+	t, ok := s.Tenants[key]
+	if !ok {
+		t = &Tenant{Domain: labels["domain"], Project: labels["project"]}
+		s.Tenants[key] = t
+	}
+	return t
+}
+
+func (s *Snapshot) api(labels map[string]string) *API {
+	key := labels["method"] + " " + labels["api"]
+	a, ok := s.APIs[key]
+	if !ok {
+		a = &API{Method: labels["method"], Path: labels["api"]}
+		s.APIs[key] = a
+	}
+	return a
+}
+
+// QPS returns the total QPS of the apis
+func (s *Snapshot) QPS() (qps float64) {
+	for _, a := range s.APIs {
+		qps += a.QPS
+	}
+	return
+}
+
+// HeartbeatRate returns the heartbeats per second since the previous snapshot,
+// returns 0 if the previous snapshot is nil
+func (s *Snapshot) HeartbeatRate(prev *Snapshot) float64 {
+	if prev == nil {
+		return 0
+	}
+	elapsed := s.Time.Sub(prev.Time).Seconds()
+	if elapsed <= 0 || s.Heartbeats < prev.Heartbeats {
+		// the service center restarted and the counter was reset
+		return 0
+	}
+	return float64(s.Heartbeats-prev.Heartbeats) / elapsed
+}
+
+func eachMetric(family *dto.MetricFamily, f func(labels map[string]string, m *dto.Metric)) {
+	if family == nil {
+		return
+	}
+	for _, m := range family.GetMetric() {
+		labels := make(map[string]string, len(m.GetLabel()))
+		for _, pair := range m.GetLabel() {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		f(labels, m)
+	}
+}
+
+func gaugeValue(m *dto.Metric) float64 {
+	if m.GetGauge() != nil {
+		return m.GetGauge().GetValue()
+	}
+	return m.GetUntyped().GetValue()
+}
+
+func counterValue(m *dto.Metric) float64 {
+	if m.GetCounter() != nil {
+		return m.GetCounter().GetValue()
+	}
+	return m.GetUntyped().GetValue()
+}
@@ -0,0 +1,142 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package top
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/spf13/cobra"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+var (
+	Domain   string
+	Project  string
+	Interval time.Duration
+	Sort     string
+	Limit    int
+	Once     bool
+)
+
+func init() {
+	NewTopCommand(cmd.RootCmd())
+}
+
+func NewTopCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top [options]",
+		Short: "Display the live statistics of service center",
+		Long: `Display the live statistics of service center, including the services,
+instances and schemas of each domain/project, the heartbeat rate, the watchers
+and the QPS of each api.
+It requires the server enable the prometheus exporter by 'metrics.exporter'.`,
+		Run: TopCommandFunc,
+		Example: parent.CommandPath() + ` top --interval 5s --sort errors;
+` + parent.CommandPath() + ` top -d default --once`,
+	}
+
+	cmd.Flags().StringVarP(&Domain, "domain", "d", "", "only display the statistics of the domain, empty means all domains")
+	cmd.Flags().StringVar(&Project, "project", "default", "the project to query the service statistics of the domain")
+	cmd.Flags().DurationVar(&Interval, "interval", 2*time.Second, "the refresh interval")
+	cmd.Flags().StringVar(&Sort, "sort", ColumnInstances, "the column to sort by, e.g. services, watchers, qps, errors, latency")
+	cmd.Flags().IntVar(&Limit, "limit", 20, "the max number of apis to display, 0 means no limit")
+	cmd.Flags().BoolVar(&Once, "once", false, "print the statistics once and exit")
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func TopCommandFunc(_ *cobra.Command, args []string) {
+	if err := ValidateSort(Sort); err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	if Interval <= 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Errorf("invalid interval %s", Interval))
+	}
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	view := &View{Sort: Sort, Limit: Limit}
+	var prev *Snapshot
+	for {
+		cur, statistics, err := collect(ctx, scClient)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if Once || prev == nil {
+				cmd.StopAndExit(cmd.ExitError, err)
+			}
+			fmt.Fprintf(os.Stderr, "refresh failed: %s\n", err.Error())
+		} else {
+			// render to buffer first to avoid flicker
+			buf := bytes.NewBuffer(nil)
+			if !Once {
+				buf.WriteString(clearScreen)
+			}
+			view.Render(buf, cur, prev, statistics)
+			_, _ = os.Stdout.Write(buf.Bytes())
+			prev = cur
+		}
+		if Once {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(Interval):
+		}
+	}
+}
+
+func collect(ctx context.Context, scClient *client.Client) (*Snapshot, *pb.Statistics, error) {
+	families, scErr := scClient.GetMetrics(ctx)
+	if scErr != nil {
+		return nil, nil, scErr
+	}
+	cur := NewSnapshot(time.Now(), families, Domain)
+
+	domain := Domain
+	if len(domain) == 0 {
+		domain = "default"
+	}
+	statistics, scErr := scClient.GetStatistics(ctx, domain, Project)
+	if scErr != nil {
+		return nil, nil, scErr
+	}
+	return cur, statistics, nil
+}
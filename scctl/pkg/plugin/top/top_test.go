@@ -0,0 +1,144 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package top
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+const testMetrics = `# TYPE service_center_db_service_total gauge
+service_center_db_service_total{domain="default",framework="UNKNOWN",frameworkVersion="",instance="sc1",project="default"} 3
+service_center_db_service_total{domain="default",framework="go-chassis",frameworkVersion="2.0",instance="sc1",project="default"} 2
+service_center_db_service_total{domain="tenant1",framework="UNKNOWN",frameworkVersion="",instance="sc1",project="p1"} 1
+# TYPE service_center_db_instance_total gauge
+service_center_db_instance_total{domain="default",framework="UNKNOWN",frameworkVersion="",instance="sc1",project="default"} 4
+service_center_db_instance_total{domain="tenant1",framework="UNKNOWN",frameworkVersion="",instance="sc1",project="p1"} 10
+# TYPE service_center_db_schema_total gauge
+service_center_db_schema_total{domain="default",instance="sc1",project="default"} 6
+# TYPE service_center_db_heartbeat_total counter
+service_center_db_heartbeat_total{instance="sc1",status="SUCCESS"} 100
+service_center_db_heartbeat_total{instance="sc1",status="FAILURE"} 2
+# TYPE service_center_notify_subscriber_total gauge
+service_center_notify_subscriber_total{domain="default",instance="sc1",scheme="websocket"} 3
+service_center_notify_subscriber_total{domain="default",instance="sc1",scheme="list&watch"} 1
+# TYPE service_center_http_request_total counter
+service_center_http_request_total{api="/v4/:project/registry/microservices",code="200",domain="default",instance="sc1",method="GET"} 8
+service_center_http_request_total{api="/v4/:project/registry/microservices",code="500",domain="default",instance="sc1",method="GET"} 2
+service_center_http_request_total{api="/v4/:project/registry/heartbeats",code="200",domain="tenant1",instance="sc1",method="PUT"} 50
+# TYPE service_center_http_success_total counter
+service_center_http_success_total{api="/v4/:project/registry/microservices",code="200",domain="default",instance="sc1",method="GET"} 8
+service_center_http_success_total{api="/v4/:project/registry/heartbeats",code="200",domain="tenant1",instance="sc1",method="PUT"} 50
+# TYPE service_center_http_request_durations_microseconds summary
+service_center_http_request_durations_microseconds_sum{api="/v4/:project/registry/microservices",domain="default",instance="sc1",method="GET"} 5000
+service_center_http_request_durations_microseconds_count{api="/v4/:project/registry/microservices",domain="default",instance="sc1",method="GET"} 10
+# TYPE service_center_http_query_per_seconds gauge
+service_center_http_query_per_seconds{api="/v4/:project/registry/microservices",domain="default",instance="sc1",method="GET"} 1.5
+service_center_http_query_per_seconds{api="/v4/:project/registry/heartbeats",domain="tenant1",instance="sc1",method="PUT"} 5
+`
+
+func parseSnapshot(t *testing.T, now time.Time, domain string) *Snapshot {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(strings.NewReader(testMetrics))
+	if err != nil {
+		t.Fatalf("parse metrics failed, %s", err.Error())
+	}
+	return NewSnapshot(now, families, domain)
+}
+
+func TestNewSnapshot(t *testing.T) {
+	s := parseSnapshot(t, time.Now(), "")
+	if len(s.Tenants) != 2 {
+		t.Fatalf("TestNewSnapshot failed, %v", s.Tenants)
+	}
+	d := s.Tenants["default/default"]
+	if d.Services != 5 || d.Instances != 4 || d.Schemas != 6 {
+		t.Fatalf("TestNewSnapshot failed, %v", d)
+	}
+	if s.Watchers["default"] != 4 || s.Heartbeats != 102 || s.HeartbeatFailures != 2 {
+		t.Fatalf("TestNewSnapshot failed, %v %d %d", s.Watchers, s.Heartbeats, s.HeartbeatFailures)
+	}
+	a := s.APIs["GET /v4/:project/registry/microservices"]
+	if a.Requests != 10 || a.Errors != 2 || a.QPS != 1.5 || a.Latency() != 500*time.Microsecond {
+		t.Fatalf("TestNewSnapshot failed, %v %s", a, a.Latency())
+	}
+	if s.QPS() != 6.5 {
+		t.Fatalf("TestNewSnapshot failed, %f", s.QPS())
+	}
+
+	s = parseSnapshot(t, time.Now(), "tenant1")
+	if len(s.Tenants) != 1 || len(s.APIs) != 1 || s.Tenants["tenant1/p1"].Instances != 10 {
+		t.Fatalf("TestNewSnapshot failed, %v %v", s.Tenants, s.APIs)
+	}
+}
+
+func TestSnapshot_HeartbeatRate(t *testing.T) {
+	now := time.Now()
+	prev := &Snapshot{Time: now.Add(-2 * time.Second), Heartbeats: 80}
+	cur := &Snapshot{Time: now, Heartbeats: 102}
+	if r := cur.HeartbeatRate(nil); r != 0 {
+		t.Fatalf("TestSnapshot_HeartbeatRate failed, %f", r)
+	}
+	if r := cur.HeartbeatRate(prev); r != 11 {
+		t.Fatalf("TestSnapshot_HeartbeatRate failed, %f", r)
+	}
+	prev.Heartbeats = 200
+	if r := cur.HeartbeatRate(prev); r != 0 {
+		t.Fatalf("TestSnapshot_HeartbeatRate failed, %f", r)
+	}
+}
+
+func TestSort(t *testing.T) {
+	s := parseSnapshot(t, time.Now(), "")
+	tenants := []*Tenant{s.Tenants["default/default"], s.Tenants["tenant1/p1"]}
+	SortTenants(tenants, s.Watchers, ColumnInstances)
+	if tenants[0].Domain != "tenant1" {
+		t.Fatalf("TestSort failed, %v", tenants[0])
+	}
+	SortTenants(tenants, s.Watchers, ColumnWatchers)
+	if tenants[0].Domain != "default" {
+		t.Fatalf("TestSort failed, %v", tenants[0])
+	}
+
+	apis := []*API{s.APIs["GET /v4/:project/registry/microservices"], s.APIs["PUT /v4/:project/registry/heartbeats"]}
+	SortAPIs(apis, ColumnQPS)
+	if apis[0].Method != "PUT" {
+		t.Fatalf("TestSort failed, %v", apis[0])
+	}
+	SortAPIs(apis, ColumnErrors)
+	if apis[0].Method != "GET" {
+		t.Fatalf("TestSort failed, %v", apis[0])
+	}
+
+	if ValidateSort("latency") != nil || ValidateSort("unknown") == nil {
+		t.Fatalf("TestSort failed")
+	}
+}
+
+func TestView_Render(t *testing.T) {
+	s := parseSnapshot(t, time.Now(), "")
+	buf := bytes.NewBuffer(nil)
+	(&View{Sort: ColumnQPS, Limit: 1}).Render(buf, s, nil, nil)
+	out := buf.String()
+	if !strings.Contains(out, "tenant1") || !strings.Contains(out, "heartbeats") ||
+		strings.Contains(out, "registry/microservices") {
+		t.Fatalf("TestView_Render failed, %s", out)
+	}
+}
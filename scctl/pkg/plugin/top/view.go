@@ -0,0 +1,183 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package top
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/olekukonko/tablewriter"
+)
+
+// the sortable columns
+const (
+	ColumnDomain    = "domain"
+	ColumnProject   = "project"
+	ColumnServices  = "services"
+	ColumnInstances = "instances"
+	ColumnSchemas   = "schemas"
+	ColumnWatchers  = "watchers"
+	ColumnMethod    = "method"
+	ColumnAPI       = "api"
+	ColumnQPS       = "qps"
+	ColumnRequests  = "requests"
+	ColumnErrors    = "errors"
+	ColumnLatency   = "latency"
+)
+
+var (
+	tenantColumns = []string{ColumnDomain, ColumnProject, ColumnServices, ColumnInstances, ColumnSchemas, ColumnWatchers}
+	apiColumns    = []string{ColumnMethod, ColumnAPI, ColumnQPS, ColumnRequests, ColumnErrors, ColumnLatency}
+)
+
+// ValidateSort checks the column can be sorted by
+func ValidateSort(column string) error {
+	for _, c := range append(tenantColumns, apiColumns...) {
+		if c == column {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown sort column '%s', must be one of %s", column,
+		strings.Join(append(tenantColumns, apiColumns...), ", "))
+}
+
+// SortTenants sorts the tenants by the column, the numeric columns are in descending order,
+// the tenants are sorted by instances if the column is not a tenant column
+func SortTenants(tenants []*Tenant, watchers map[string]int64, column string) {
+	less := func(a, b *Tenant) (bool, bool) {
+		switch column {
+		case ColumnDomain:
+			return a.Domain < b.Domain, a.Domain == b.Domain
+		case ColumnProject:
+			return a.Project < b.Project, a.Project == b.Project
+		case ColumnServices:
+			return a.Services > b.Services, a.Services == b.Services
+		case ColumnSchemas:
+			return a.Schemas > b.Schemas, a.Schemas == b.Schemas
+		case ColumnWatchers:
+			return watchers[a.Domain] > watchers[b.Domain], watchers[a.Domain] == watchers[b.Domain]
+		default:
+			return a.Instances > b.Instances, a.Instances == b.Instances
+		}
+	}
+	sort.SliceStable(tenants, func(i, j int) bool {
+		if l, eq := less(tenants[i], tenants[j]); !eq {
+			return l
+		}
+		return tenants[i].Domain+"/"+tenants[i].Project < tenants[j].Domain+"/"+tenants[j].Project
+	})
+}
+
+// SortAPIs sorts the apis by the column, the numeric columns are in descending order,
+// the apis are sorted by qps if the column is not an api column
+func SortAPIs(apis []*API, column string) {
+	less := func(a, b *API) (bool, bool) {
+		switch column {
+		case ColumnMethod:
+			return a.Method < b.Method, a.Method == b.Method
+		case ColumnAPI:
+			return a.Path < b.Path, a.Path == b.Path
+		case ColumnRequests:
+			return a.Requests > b.Requests, a.Requests == b.Requests
+		case ColumnErrors:
+			return a.Errors > b.Errors, a.Errors == b.Errors
+		case ColumnLatency:
+			return a.Latency() > b.Latency(), a.Latency() == b.Latency()
+		default:
+			return a.QPS > b.QPS, a.QPS == b.QPS
+		}
+	}
+	sort.SliceStable(apis, func(i, j int) bool {
+		if l, eq := less(apis[i], apis[j]); !eq {
+			return l
+		}
+		return apis[i].Path+apis[i].Method < apis[j].Path+apis[j].Method
+	})
+}
+
+// View renders the snapshot like the 'top' command
+type View struct {
+	Sort  string
+	Limit int
+}
+
+func (v *View) Render(w io.Writer, cur, prev *Snapshot, statistics *pb.Statistics) {
+	fmt.Fprintf(w, "scctl top - %s\n", cur.Time.Format("15:04:05"))
+	if statistics != nil {
+		services, instances, apps := statistics.Services, statistics.Instances, statistics.Apps
+		if services == nil {
+			services = &pb.StService{}
+		}
+		if instances == nil {
+			instances = &pb.StInstance{}
+		}
+		if apps == nil {
+			apps = &pb.StApp{}
+		}
+		fmt.Fprintf(w, "Apps: %d, Services: %d total, %d online, Instances: %d in domain, %d total\n",
+			apps.Count, services.Count, services.OnlineCount, instances.CountByDomain, instances.Count)
+	}
+	var watchers int64
+	for _, n := range cur.Watchers {
+		watchers += n
+	}
+	fmt.Fprintf(w, "Heartbeats: %.1f/s, %d total, %d failed, Watchers: %d, QPS: %.1f\n\n",
+		cur.HeartbeatRate(prev), cur.Heartbeats, cur.HeartbeatFailures, watchers, cur.QPS())
+
+	tenants := make([]*Tenant, 0, len(cur.Tenants))
+	for _, t := range cur.Tenants {
+		tenants = append(tenants, t)
+	}
+	SortTenants(tenants, cur.Watchers, v.Sort)
+	var body [][]string
+	for _, t := range tenants {
+		body = append(body, []string{t.Domain, t.Project, strconv.FormatInt(t.Services, 10),
+			strconv.FormatInt(t.Instances, 10), strconv.FormatInt(t.Schemas, 10),
+			strconv.FormatInt(cur.Watchers[t.Domain], 10)})
+	}
+	// watchers are counted by domain
+	makeTable(w, []string{"DOMAIN", "PROJECT", "SERVICES", "INSTANCES", "SCHEMAS", "DOMAIN WATCHERS"}, body)
+	fmt.Fprintln(w)
+
+	apis := make([]*API, 0, len(cur.APIs))
+	for _, a := range cur.APIs {
+		apis = append(apis, a)
+	}
+	SortAPIs(apis, v.Sort)
+	if v.Limit > 0 && len(apis) > v.Limit {
+		apis = apis[:v.Limit]
+	}
+	body = nil
+	for _, a := range apis {
+		body = append(body, []string{a.Method, a.Path, strconv.FormatFloat(a.QPS, 'f', 1, 64),
+			strconv.FormatInt(a.Requests, 10), strconv.FormatInt(a.Errors, 10),
+			a.Latency().Round(time.Microsecond).String()})
+	}
+	makeTable(w, []string{"METHOD", "API", "QPS", "REQUESTS", "ERRORS", "AVG LATENCY"}, body)
+}
+
+func makeTable(w io.Writer, header []string, body [][]string) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetBorder(false)
+	table.AppendBulk(body)
+	table.Render()
+}
@@ -0,0 +1,87 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"strings"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/model"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+// Event is the instance event received from service center
+type Event struct {
+	Time          time.Time                `json:"time"`
+	Action        string                   `json:"action"`
+	DomainProject string                   `json:"domainProject,omitempty"`
+	AppID         string                   `json:"appId"`
+	ServiceName   string                   `json:"serviceName"`
+	Version       string                   `json:"version"`
+	Instance      *pb.MicroServiceInstance `json:"instance"`
+}
+
+// Filter selects the events by domain, app and service name, the empty field matches all
+type Filter struct {
+	Domain      string
+	AppID       string
+	ServiceName string
+}
+
+func (f *Filter) Match(evt *Event) bool {
+	if len(f.Domain) > 0 && len(evt.DomainProject) > 0 &&
+		!strings.HasPrefix(evt.DomainProject+"/", f.Domain+"/") {
+		return false
+	}
+	if len(f.AppID) > 0 && evt.AppID != f.AppID {
+		return false
+	}
+	return len(f.ServiceName) == 0 || evt.ServiceName == f.ServiceName
+}
+
+// FromWatchResponse converts the event of the service watcher
+func FromWatchResponse(resp *pb.WatchInstanceResponse) *Event {
+	evt := &Event{Time: time.Now(), Action: resp.Action, Instance: resp.Instance}
+	if resp.Key != nil {
+		evt.DomainProject = resp.Key.Tenant
+		evt.AppID = resp.Key.AppId
+		evt.ServiceName = resp.Key.ServiceName
+		evt.Version = resp.Key.Version
+	}
+	return evt
+}
+
+// FromChangedEvent converts the event of the syncer watcher
+func FromChangedEvent(e *dump.WatchInstanceChangedEvent) *Event {
+	evt := &Event{Time: time.Now(), Action: e.Action}
+	if e.Service != nil && e.Service.Value != nil {
+		if e.Service.KV != nil {
+			evt.DomainProject = model.GetDomainProject(e.Service)
+		}
+		evt.AppID = e.Service.Value.AppId
+		evt.ServiceName = e.Service.Value.ServiceName
+		evt.Version = e.Service.Value.Version
+	}
+	if e.Instance != nil {
+		evt.Instance = e.Instance.Value
+	}
+	return evt
+}
+
+func (evt *Event) ServiceKey() string {
+	return evt.AppID + "/" + evt.ServiceName + "/" + evt.Version
+}
@@ -0,0 +1,156 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+// the output formats of the watch command
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatDiff  = "diff"
+)
+
+const (
+	timeLayout  = "15:04:05"
+	tableFormat = "%-8s  %-6s  %-40s  %-32s  %-20s  %-12s  %s\n"
+)
+
+// Printer prints the events in stream
+type Printer interface {
+	Print(evt *Event)
+}
+
+func NewPrinter(format string, w io.Writer) (Printer, error) {
+	switch format {
+	case FormatTable:
+		return &tablePrinter{w: w}, nil
+	case FormatJSON:
+		return &jsonPrinter{encoder: json.NewEncoder(w)}, nil
+	case FormatDiff:
+		return &diffPrinter{w: w, instances: make(map[string]*pb.MicroServiceInstance)}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format '%s', must be one of table, json and diff", format)
+	}
+}
+
+type tablePrinter struct {
+	w      io.Writer
+	header bool
+}
+
+func (p *tablePrinter) Print(evt *Event) {
+	if !p.header {
+		fmt.Fprintf(p.w, tableFormat, "TIME", "ACTION", "SERVICE", "INSTANCE", "HOST", "STATUS", "ENDPOINTS")
+		p.header = true
+	}
+	instance := evt.Instance
+	if instance == nil {
+		instance = &pb.MicroServiceInstance{}
+	}
+	fmt.Fprintf(p.w, tableFormat, evt.Time.Format(timeLayout), evt.Action, evt.ServiceKey(),
+		instance.InstanceId, instance.HostName, instance.Status, strings.Join(instance.Endpoints, ","))
+}
+
+type jsonPrinter struct {
+	encoder *json.Encoder
+}
+
+func (p *jsonPrinter) Print(evt *Event) {
+	_ = p.encoder.Encode(evt)
+}
+
+// diffPrinter prints '+' for the created instance, '-' for the deleted instance,
+// and '~' with the changed fields for the updated instance
+type diffPrinter struct {
+	w         io.Writer
+	instances map[string]*pb.MicroServiceInstance
+}
+
+func (p *diffPrinter) Print(evt *Event) {
+	instance := evt.Instance
+	if instance == nil {
+		return
+	}
+	prefix := fmt.Sprintf("%s %s %s", evt.Time.Format(timeLayout), evt.ServiceKey(), instance.InstanceId)
+	summary := fmt.Sprintf("%s %s [%s]", instance.HostName, instance.Status, strings.Join(instance.Endpoints, ","))
+	old, ok := p.instances[instance.InstanceId]
+	switch evt.Action {
+	case string(pb.EVT_CREATE):
+		p.instances[instance.InstanceId] = instance
+		fmt.Fprintf(p.w, "+ %s %s\n", prefix, summary)
+	case string(pb.EVT_DELETE):
+		delete(p.instances, instance.InstanceId)
+		fmt.Fprintf(p.w, "- %s %s\n", prefix, summary)
+	default:
+		p.instances[instance.InstanceId] = instance
+		if !ok {
+			fmt.Fprintf(p.w, "~ %s %s\n", prefix, summary)
+			return
+		}
+		changes := DiffInstance(old, instance)
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(p.w, "~ %s %s\n", prefix, strings.Join(changes, ", "))
+	}
+}
+
+// DiffInstance returns the changed fields of the instance
+func DiffInstance(old, cur *pb.MicroServiceInstance) []string {
+	var changes []string
+	diff := func(field, a, b string) {
+		if a != b {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", field, a, b))
+		}
+	}
+	diff("status", old.Status, cur.Status)
+	diff("host", old.HostName, cur.HostName)
+	diff("version", old.Version, cur.Version)
+	diff("endpoints", "["+strings.Join(old.Endpoints, ",")+"]", "["+strings.Join(cur.Endpoints, ",")+"]")
+
+	keys := make([]string, 0, len(old.Properties)+len(cur.Properties))
+	for k := range old.Properties {
+		keys = append(keys, k)
+	}
+	for k := range cur.Properties {
+		if _, ok := old.Properties[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		a, aok := old.Properties[k]
+		b, bok := cur.Properties[k]
+		switch {
+		case !aok:
+			changes = append(changes, fmt.Sprintf("properties.%s: +%s", k, b))
+		case !bok:
+			changes = append(changes, fmt.Sprintf("properties.%s: -%s", k, a))
+		default:
+			diff("properties."+k, a, b)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,132 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+func newEvent(action, status string) *Event {
+	return &Event{
+		Time:          time.Date(2021, 1, 1, 8, 0, 0, 0, time.UTC),
+		Action:        action,
+		DomainProject: "default/default",
+		AppID:         "a",
+		ServiceName:   "s",
+		Version:       "1.0.0",
+		Instance: &pb.MicroServiceInstance{
+			InstanceId: "i1",
+			HostName:   "h1",
+			Status:     status,
+			Endpoints:  []string{"rest://127.0.0.1:8080"},
+		},
+	}
+}
+
+func TestDiffPrinter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	p, err := NewPrinter(FormatDiff, buf)
+	if err != nil {
+		t.Fatalf("TestDiffPrinter failed, %v", err)
+	}
+	p.Print(newEvent("CREATE", "UP"))
+	p.Print(newEvent("UPDATE", "UP"))
+	p.Print(newEvent("UPDATE", "OUTOFSERVICE"))
+	p.Print(newEvent("DELETE", "OUTOFSERVICE"))
+
+	expected := `+ 08:00:00 a/s/1.0.0 i1 h1 UP [rest://127.0.0.1:8080]
+~ 08:00:00 a/s/1.0.0 i1 status: UP -> OUTOFSERVICE
+- 08:00:00 a/s/1.0.0 i1 h1 OUTOFSERVICE [rest://127.0.0.1:8080]
+`
+	if buf.String() != expected {
+		t.Fatalf("TestDiffPrinter failed, %s", buf.String())
+	}
+}
+
+func TestNewPrinter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	p, _ := NewPrinter(FormatTable, buf)
+	p.Print(newEvent("CREATE", "UP"))
+	p.Print(newEvent("DELETE", "UP"))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "TIME") || !strings.Contains(lines[2], "DELETE") {
+		t.Fatalf("TestNewPrinter failed, %s", buf.String())
+	}
+
+	buf.Reset()
+	p, _ = NewPrinter(FormatJSON, buf)
+	p.Print(newEvent("CREATE", "UP"))
+	if !strings.Contains(buf.String(), `"action":"CREATE"`) || strings.Count(buf.String(), "\n") != 1 {
+		t.Fatalf("TestNewPrinter failed, %s", buf.String())
+	}
+
+	if _, err := NewPrinter("yaml", buf); err == nil {
+		t.Fatalf("TestNewPrinter failed, unknown format")
+	}
+}
+
+func TestDiffInstance(t *testing.T) {
+	old := &pb.MicroServiceInstance{Status: "UP", Properties: map[string]string{"a": "1", "b": "2"}}
+	cur := &pb.MicroServiceInstance{Status: "UP", Properties: map[string]string{"b": "3", "c": "4"}}
+	changes := DiffInstance(old, cur)
+	if strings.Join(changes, ", ") != "properties.a: -1, properties.b: 2 -> 3, properties.c: +4" {
+		t.Fatalf("TestDiffInstance failed, %v", changes)
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	evt := newEvent("CREATE", "UP")
+	cases := []struct {
+		filter   Filter
+		expected bool
+	}{
+		{Filter{}, true},
+		{Filter{Domain: "default", AppID: "a", ServiceName: "s"}, true},
+		{Filter{Domain: "def"}, false},
+		{Filter{AppID: "b"}, false},
+		{Filter{ServiceName: "x"}, false},
+	}
+	for _, c := range cases {
+		if c.filter.Match(evt) != c.expected {
+			t.Fatalf("TestFilter_Match failed, %v", c.filter)
+		}
+	}
+}
+
+func TestLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls, errs := 0, 0
+	Loop(ctx, time.Millisecond, func(ctx context.Context) error {
+		calls++
+		if calls == 3 {
+			cancel()
+			return nil
+		}
+		return errors.New("broken")
+	}, func(err error) {
+		errs++
+	})
+	if calls != 3 || errs != 2 {
+		t.Fatalf("TestLoop failed, calls %d, errors %d", calls, errs)
+	}
+}
@@ -0,0 +1,137 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Domain        string
+	Project       string
+	ServiceID     string
+	AppID         string
+	ServiceName   string
+	Output        string
+	RetryInterval time.Duration
+)
+
+func init() {
+	NewWatchCommand(cmd.RootCmd())
+}
+
+func NewWatchCommand(parent *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch [options]",
+		Short: "Stream the instance events of service center",
+		Long: `Stream the instance CREATE, UPDATE and DELETE events of service center.
+Without --service-id, it watches all the services by the syncer watch api,
+which requires the syncer enabled in service center.
+With --service-id, it watches the providers which the service depends on.`,
+		Run: WatchCommandFunc,
+		Example: parent.CommandPath() + ` watch --app springmvc -o diff;
+` + parent.CommandPath() + ` watch --service-id 2a0b2f6a1c8e11ecb1a10242ac110002 -o json`,
+	}
+
+	cmd.Flags().StringVarP(&Domain, "domain", "d", "default", "the domain of the services, empty means all domains")
+	cmd.Flags().StringVar(&Project, "project", "default", "the project of the service with --service-id")
+	cmd.Flags().StringVar(&ServiceID, "service-id", "", "watch the providers which the service depends on")
+	cmd.Flags().StringVar(&AppID, "app", "", "only print the events of the app")
+	cmd.Flags().StringVar(&ServiceName, "name", "", "only print the events of the service name")
+	cmd.Flags().StringVarP(&Output, "output", "o", FormatTable, "the output format, table, json or diff")
+	cmd.Flags().DurationVar(&RetryInterval, "retry-interval", 3*time.Second, "the interval to reconnect after disconnected")
+
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func WatchCommandFunc(_ *cobra.Command, args []string) {
+	printer, err := NewPrinter(Output, os.Stdout)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	filter := &Filter{Domain: Domain, AppID: AppID, ServiceName: ServiceName}
+	handle := func(evt *Event) {
+		if filter.Match(evt) {
+			printer.Print(evt)
+		}
+	}
+	watch := func(ctx context.Context) error {
+		var scErr *errsvc.Error
+		if len(ServiceID) > 0 {
+			scErr = scClient.Watch(ctx, Domain, Project, ServiceID, func(resp *pb.WatchInstanceResponse) {
+				handle(FromWatchResponse(resp))
+			})
+		} else {
+			scErr = scClient.WatchAll(ctx, func(e *dump.WatchInstanceChangedEvent) {
+				handle(FromChangedEvent(e))
+			})
+		}
+		if scErr != nil {
+			return scErr
+		}
+		return nil
+	}
+	Loop(ctx, RetryInterval, watch, func(err error) {
+		fmt.Fprintf(os.Stderr, "watch disconnected: %s, reconnect in %s\n", err.Error(), RetryInterval)
+	})
+}
+
+// Loop calls watch until ctx is done, and waits interval before reconnecting
+func Loop(ctx context.Context, interval time.Duration, watch func(context.Context) error, onError func(error)) {
+	for {
+		err := watch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = errors.New("connection closed")
+		}
+		onError(err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
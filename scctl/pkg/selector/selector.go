@@ -0,0 +1,176 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/apache/servicecomb-service-center/client"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/cmd"
+	"github.com/apache/servicecomb-service-center/scctl/pkg/writer"
+	pb "github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/spf13/pflag"
+)
+
+// Selector selects the services by id, or in batch by appId, name and version
+type Selector struct {
+	Domain      string
+	Project     string
+	ServiceID   string
+	AppID       string
+	ServiceName string
+	// Version is empty means all the versions
+	Version     string
+	Environment string
+}
+
+var (
+	ServiceTableHeader  = []string{"SERVICEID", "APPID", "NAME", "VERSION", "ENV"}
+	InstanceTableHeader = []string{"INSTANCEID", "HOST", "STATUS", "SERVICE"}
+)
+
+// Target is a selected instance and the service it belongs to
+type Target struct {
+	Service  *pb.MicroService
+	Instance *pb.MicroServiceInstance
+}
+
+func (s *Selector) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&s.Domain, "domain", "d", "default", "the domain of the services")
+	flags.StringVar(&s.Project, "project", "default", "the project of the services")
+	flags.StringVar(&s.ServiceID, "service-id", "", "select the service by id")
+	flags.StringVar(&s.AppID, "app", "default", "select the services by appId")
+	flags.StringVar(&s.ServiceName, "name", "", "select the services by name")
+	flags.StringVar(&s.Version, "version", "", "select the services by version, empty means all versions")
+	flags.StringVar(&s.Environment, "env", "", "select the services by environment")
+}
+
+func (s *Selector) Validate() error {
+	if len(s.ServiceID) == 0 && len(s.ServiceName) == 0 {
+		return errors.New("required --service-id or --name")
+	}
+	return nil
+}
+
+// Match returns true if the service is selected
+func (s *Selector) Match(service *pb.MicroService) bool {
+	if len(s.ServiceID) > 0 {
+		return service.ServiceId == s.ServiceID
+	}
+	return service.AppId == s.AppID && service.ServiceName == s.ServiceName &&
+		service.Environment == s.Environment && (len(s.Version) == 0 || service.Version == s.Version)
+}
+
+// Services returns the selected services
+func (s *Selector) Services(ctx context.Context, c *client.Client) ([]*pb.MicroService, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	services, scErr := c.GetServices(ctx, s.Domain, s.Project)
+	if scErr != nil {
+		return nil, fmt.Errorf("list services failed, %s", scErr.Error())
+	}
+	var selected []*pb.MicroService
+	for _, service := range services {
+		if s.Match(service) {
+			selected = append(selected, service)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("no service matched")
+	}
+	return selected, nil
+}
+
+// Instances returns the instances of the selected services,
+// only the instance with id is returned if instanceID is not empty
+func (s *Selector) Instances(ctx context.Context, c *client.Client, instanceID string) ([]*Target, error) {
+	services, err := s.Services(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	var targets []*Target
+	for _, service := range services {
+		instances, scErr := c.GetInstancesByServiceID(ctx, s.Domain, s.Project, service.ServiceId, "")
+		if scErr != nil {
+			return nil, fmt.Errorf("get service %s instances failed, %s", service.ServiceId, scErr.Error())
+		}
+		for _, instance := range instances {
+			if len(instanceID) == 0 || instance.InstanceId == instanceID {
+				targets = append(targets, &Target{Service: service, Instance: instance})
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("no instance matched")
+	}
+	return targets, nil
+}
+
+func ServiceName(service *pb.MicroService) string {
+	return service.AppId + "/" + service.ServiceName + "/" + service.Version
+}
+
+func InstanceTableBody(targets []*Target) [][]string {
+	body := make([][]string, 0, len(targets))
+	for _, target := range targets {
+		body = append(body, []string{target.Instance.InstanceId, target.Instance.HostName,
+			target.Instance.Status, ServiceName(target.Service)})
+	}
+	return body
+}
+
+func ServiceTableBody(services []*pb.MicroService) [][]string {
+	body := make([][]string, 0, len(services))
+	for _, service := range services {
+		body = append(body, []string{service.ServiceId, service.AppId, service.ServiceName, service.Version, service.Environment})
+	}
+	return body
+}
+
+// ForEachService prints the selected services and asks for confirmation unless yes is true,
+// then calls f with each service and exits with error if any call failed
+func (s *Selector) ForEachService(yes bool, question, operation string,
+	f func(context.Context, *client.Client, *pb.MicroService) *errsvc.Error) {
+	scClient, err := client.NewSCClient(cmd.ScClientConfig)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+	ctx := context.Background()
+	services, err := s.Services(ctx, scClient)
+	if err != nil {
+		cmd.StopAndExit(cmd.ExitError, err)
+	}
+
+	writer.MakeTable(ServiceTableHeader, ServiceTableBody(services))
+	cmd.ConfirmOrExit(yes, fmt.Sprintf("%s %d services?", question, len(services)))
+
+	failed := 0
+	for _, service := range services {
+		if scErr := f(ctx, scClient, service); scErr != nil {
+			failed++
+			fmt.Printf("service %s %s failed: %s\n", ServiceName(service), operation, scErr.Error())
+			continue
+		}
+		fmt.Printf("service %s %s done\n", ServiceName(service), operation)
+	}
+	if failed > 0 {
+		cmd.StopAndExit(cmd.ExitError, fmt.Sprintf("error: %d of %d services %s failed", failed, len(services), operation))
+	}
+}
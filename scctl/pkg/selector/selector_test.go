@@ -0,0 +1,54 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"testing"
+
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+func TestSelector_Match(t *testing.T) {
+	service := &pb.MicroService{ServiceId: "1", AppId: "a", ServiceName: "s", Version: "1.0.0"}
+
+	s := &Selector{}
+	if err := s.Validate(); err == nil {
+		t.Fatalf("TestSelector_Match failed, empty selector should be invalid")
+	}
+
+	s = &Selector{ServiceID: "1", AppID: "b"}
+	if !s.Match(service) {
+		t.Fatalf("TestSelector_Match failed, select by id")
+	}
+	s.ServiceID = "2"
+	if s.Match(service) {
+		t.Fatalf("TestSelector_Match failed, select by id")
+	}
+
+	s = &Selector{AppID: "a", ServiceName: "s"}
+	if !s.Match(service) {
+		t.Fatalf("TestSelector_Match failed, select all versions")
+	}
+	s.Version = "1.0.1"
+	if s.Match(service) {
+		t.Fatalf("TestSelector_Match failed, select by version")
+	}
+	s.Version = "1.0.0"
+	s.Environment = "production"
+	if s.Match(service) {
+		t.Fatalf("TestSelector_Match failed, select by environment")
+	}
+}
@@ -46,6 +46,15 @@ func TimeFormat(delta time.Duration) string {
 	}
 }
 
+// AgeFormat formats the age of the unix seconds string t, returns "-" if t is invalid
+func AgeFormat(t string) string {
+	sec, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || sec <= 0 {
+		return "-"
+	}
+	return TimeFormat(time.Since(time.Unix(sec, 0)))
+}
+
 func Reshape(maxWidth int, line []string) []string {
 	for i, col := range line {
 		if len(col)-maxWidth > 3 {
@@ -16,6 +16,7 @@
 package alarm
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/apache/servicecomb-service-center/pkg/event"
@@ -23,8 +24,9 @@ import (
 )
 
 const (
-	Activated model.Status = "ACTIVATED"
-	Cleared   model.Status = "CLEARED"
+	Activated    model.Status = "ACTIVATED"
+	Cleared      model.Status = "CLEARED"
+	Acknowledged model.Status = "ACKNOWLEDGED"
 )
 
 const (
@@ -84,3 +86,23 @@ func Clear(id model.ID) error {
 func ClearAll() {
 	Center().ClearAll()
 }
+
+func SetInstance(instance string) {
+	Center().SetInstance(instance)
+}
+
+func EnablePersistence(opts PersistOptions) {
+	Center().EnablePersistence(opts)
+}
+
+func List(ctx context.Context, f *model.HistoryFilter) ([]*model.AlarmEvent, error) {
+	return Center().List(ctx, f)
+}
+
+func History(ctx context.Context, f *model.HistoryFilter) ([]*model.History, error) {
+	return Center().History(ctx, f)
+}
+
+func Ack(ctx context.Context, id model.ID, instance, actor, comment string) error {
+	return Center().Ack(ctx, id, instance, actor, comment)
+}
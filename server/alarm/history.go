@@ -0,0 +1,251 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alarm
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+)
+
+const clearInterval = time.Hour
+
+var (
+	ErrPersistenceDisabled = errors.New("alarm persistence is disabled")
+	ErrAlarmNotActivated   = errors.New("alarm is not activated")
+)
+
+// PersistOptions is the options of the alarm persistence, the history
+// and the cleared alarms older than Retention are removed
+type PersistOptions struct {
+	Retention time.Duration
+}
+
+// EnablePersistence records the alarm status changes to the datasource, so the alarms
+// survive the restart and are shared by all instances, it must be called after
+// the datasource initialized
+func (ac *Service) EnablePersistence(opts PersistOptions) {
+	ac.persist = true
+	ac.autoClear(opts.Retention)
+	log.Infof("alarm persistence enabled, instance: %s, retention: %s", ac.instance, opts.Retention)
+}
+
+// List returns the current alarms matched the filter, they are of all instances
+// and loaded from the saved alarm states if the persistence is enabled
+func (ac *Service) List(ctx context.Context, f *model.HistoryFilter) ([]*model.AlarmEvent, error) {
+	var alarms []*model.AlarmEvent
+	if ac.persist {
+		states, err := datasource.GetAlarmManager().ListAlarmStates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		alarms = CurrentAlarms(states)
+	} else {
+		alarms = ac.ListAll()
+	}
+	if f == nil {
+		return alarms, nil
+	}
+	matched := make([]*model.AlarmEvent, 0, len(alarms))
+	for _, a := range alarms {
+		if f.Match(&model.History{AlarmID: a.ID, Status: a.Status, Instance: a.Instance, Timestamp: a.Timestamp}) {
+			matched = append(matched, a)
+		}
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+	}
+	return matched, nil
+}
+
+// History returns the alarm history matched the filter, the latest first
+func (ac *Service) History(ctx context.Context, f *model.HistoryFilter) ([]*model.History, error) {
+	if !ac.persist {
+		return nil, ErrPersistenceDisabled
+	}
+	return datasource.GetAlarmManager().ListAlarmHistory(ctx, f)
+}
+
+// Ack acknowledges the activated alarm with a comment,
+// the alarm raised by all instances is acknowledged if instance is empty
+func (ac *Service) Ack(ctx context.Context, id model.ID, instance, actor, comment string) error {
+	ack := &model.Ack{Actor: actor, Comment: comment, Timestamp: nowMillis()}
+	if !ac.persist {
+		itf, ok := ac.alarms.Get(id)
+		if !ok || itf.(*model.AlarmEvent).Status != Activated {
+			return ErrAlarmNotActivated
+		}
+		itf.(*model.AlarmEvent).Ack = ack
+		return nil
+	}
+
+	states, err := datasource.GetAlarmManager().ListAlarmStates(ctx)
+	if err != nil {
+		return err
+	}
+	acked := 0
+	for _, s := range states {
+		if s.AlarmID != id || s.Status != Activated || (len(instance) > 0 && s.Instance != instance) {
+			continue
+		}
+		// the alarm cleared or raised again concurrently is not acknowledged
+		ok, err := datasource.GetAlarmManager().AckAlarmState(ctx, s, ack)
+		if err != nil {
+			return err
+		}
+		if ok {
+			acked++
+		}
+	}
+	if acked == 0 {
+		return ErrAlarmNotActivated
+	}
+	err = datasource.GetAlarmManager().AddAlarmHistory(ctx, &model.History{
+		ID:        util.GenerateUUID(),
+		AlarmID:   id,
+		Status:    Acknowledged,
+		Instance:  instance,
+		Actor:     actor,
+		Comment:   comment,
+		Timestamp: ack.Timestamp,
+	})
+	if err != nil {
+		return err
+	}
+	if itf, ok := ac.alarms.Get(id); ok && (len(instance) == 0 || instance == ac.instance) {
+		itf.(*model.AlarmEvent).Ack = ack
+	}
+	log.Infof("alarm[%s] of instance[%s] is acknowledged by %s", id, instance, actor)
+	return nil
+}
+
+// record saves the history and the alarm state asynchronously, the alarm may be
+// raised when the datasource is unavailable, so the failure is only logged
+func (ac *Service) record(h *model.History) {
+	h.ID = util.GenerateUUID()
+	h.Timestamp = nowMillis()
+	gopool.Go(func(ctx context.Context) {
+		saveHistory(ctx, h)
+	})
+}
+
+// clearActivated records the activated alarms of all instances cleared,
+// except the local ones in skip which are recorded by the cleared events
+func (ac *Service) clearActivated(skip map[model.ID]bool) {
+	gopool.Go(func(ctx context.Context) {
+		actives, err := ac.List(ctx, &model.HistoryFilter{Status: Activated})
+		if err != nil {
+			log.Errorf(err, "list activated alarms failed")
+			return
+		}
+		for _, a := range actives {
+			if a.Instance == ac.instance && skip[a.ID] {
+				continue
+			}
+			saveHistory(ctx, &model.History{
+				ID:        util.GenerateUUID(),
+				AlarmID:   a.ID,
+				Status:    Cleared,
+				Instance:  a.Instance,
+				Timestamp: nowMillis(),
+			})
+		}
+	})
+}
+
+func saveHistory(ctx context.Context, h *model.History) {
+	if err := datasource.GetAlarmManager().AddAlarmHistory(ctx, h); err != nil {
+		log.Errorf(err, "record alarm[%s] of instance[%s] %s failed", h.AlarmID, h.Instance, h.Status)
+	}
+	err := datasource.GetAlarmManager().PutAlarmState(ctx, &model.State{
+		AlarmID:   h.AlarmID,
+		Status:    h.Status,
+		Instance:  h.Instance,
+		Fields:    h.Fields,
+		Timestamp: h.Timestamp,
+	})
+	if err != nil {
+		log.Errorf(err, "save alarm[%s] state of instance[%s] failed", h.AlarmID, h.Instance)
+	}
+}
+
+// autoClear removes the alarm history and the cleared alarms older than the retention
+// from the datasource, the activated alarms are kept however long they are raised
+func (ac *Service) autoClear(retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	gopool.Go(func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(clearInterval):
+				before := time.Now().Add(-retention).UnixNano() / int64(time.Millisecond)
+				if err := datasource.GetAlarmManager().DeleteAlarmHistory(ctx, before); err != nil {
+					log.Errorf(err, "clear alarm history before %d failed", before)
+				}
+				clearStates(ctx, before)
+			}
+		}
+	})
+}
+
+// clearStates removes the cleared alarm states before the timestamp in milliseconds
+func clearStates(ctx context.Context, before int64) {
+	states, err := datasource.GetAlarmManager().ListAlarmStates(ctx)
+	if err != nil {
+		log.Errorf(err, "list alarm states failed")
+		return
+	}
+	for _, s := range states {
+		if s.Status != Cleared || s.Timestamp >= before {
+			continue
+		}
+		if err := datasource.GetAlarmManager().DeleteAlarmState(ctx, s); err != nil {
+			log.Errorf(err, "clear alarm[%s] state of instance[%s] failed", s.AlarmID, s.Instance)
+		}
+	}
+}
+
+// CurrentAlarms converts the alarm states to the alarms, the latest first
+func CurrentAlarms(states []*model.State) []*model.AlarmEvent {
+	alarms := make([]*model.AlarmEvent, 0, len(states))
+	for _, s := range states {
+		ae := &model.AlarmEvent{
+			Status:    s.Status,
+			ID:        s.AlarmID,
+			Fields:    s.Fields,
+			Instance:  s.Instance,
+			Timestamp: s.Timestamp,
+		}
+		if s.Status == Activated {
+			ae.Ack = s.Ack
+		}
+		alarms = append(alarms, ae)
+	}
+	sort.SliceStable(alarms, func(i, j int) bool {
+		return alarms[i].Timestamp > alarms[j].Timestamp
+	})
+	return alarms
+}
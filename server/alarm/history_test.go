@@ -0,0 +1,48 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alarm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+)
+
+func TestCurrentAlarms(t *testing.T) {
+	ack := &model.Ack{Actor: "root", Comment: "known issue", Timestamp: 5}
+	states := []*model.State{
+		{AlarmID: alarm.IDBackendConnectionRefuse, Status: alarm.Cleared, Instance: "a", Timestamp: 3, Ack: ack},
+		{AlarmID: alarm.IDInternalError, Status: alarm.Activated, Instance: "b", Timestamp: 6},
+		{AlarmID: alarm.IDInternalError, Status: alarm.Activated, Instance: "a", Timestamp: 4, Ack: ack},
+	}
+	alarms := alarm.CurrentAlarms(states)
+	assert.Equal(t, 3, len(alarms))
+
+	assert.Equal(t, "b", alarms[0].Instance)
+	assert.Equal(t, alarm.Activated, alarms[0].Status)
+	assert.Nil(t, alarms[0].Ack)
+
+	assert.Equal(t, "a", alarms[1].Instance)
+	assert.Equal(t, alarm.Activated, alarms[1].Status)
+	assert.Equal(t, ack, alarms[1].Ack)
+
+	assert.Equal(t, alarm.IDBackendConnectionRefuse, alarms[2].ID)
+	assert.Equal(t, alarm.Cleared, alarms[2].Status)
+	assert.Nil(t, alarms[2].Ack)
+}
@@ -30,10 +30,76 @@ type Field struct {
 }
 
 type AlarmEvent struct {
-	nf.Event `json:"-"`
-	Status   Status          `json:"status"`
-	ID       ID              `json:"id"`
-	Fields   util.JSONObject `json:"fields,omitempty"`
+	nf.Event  `json:"-"`
+	Status    Status          `json:"status"`
+	ID        ID              `json:"id"`
+	Fields    util.JSONObject `json:"fields,omitempty"`
+	Instance  string          `json:"instance,omitempty"`
+	Timestamp int64           `json:"timestamp,omitempty"`
+	Ack       *Ack            `json:"ack,omitempty"`
+}
+
+// Ack is the acknowledgement of an alarm, Timestamp is in milliseconds
+type Ack struct {
+	Actor     string `json:"actor,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+}
+
+// History is the alarm event persisted in the datasource, it is raised, cleared
+// or acknowledged by the Status. An acknowledgement without Instance applies
+// to the alarm raised by all instances, Timestamp is in milliseconds
+type History struct {
+	ID        string          `json:"id,omitempty"`
+	AlarmID   ID              `json:"alarmId,omitempty" bson:"alarm_id"`
+	Status    Status          `json:"status,omitempty"`
+	Instance  string          `json:"instance,omitempty"`
+	Fields    util.JSONObject `json:"fields,omitempty"`
+	Actor     string          `json:"actor,omitempty"`
+	Comment   string          `json:"comment,omitempty"`
+	Timestamp int64           `json:"timestamp,omitempty"`
+}
+
+// State is the current state of the alarm ID raised by the instance,
+// it is saved along with the history, so the current alarms are listed
+// without scanning the history, Timestamp is in milliseconds
+type State struct {
+	AlarmID   ID              `json:"alarmId" bson:"alarm_id"`
+	Status    Status          `json:"status"`
+	Instance  string          `json:"instance,omitempty"`
+	Fields    util.JSONObject `json:"fields,omitempty"`
+	Timestamp int64           `json:"timestamp"`
+	Ack       *Ack            `json:"ack,omitempty"`
+}
+
+// HistoryFilter is the alarm history query conditions, the zero values match all,
+// Start and End are in milliseconds
+type HistoryFilter struct {
+	AlarmID  ID
+	Status   Status
+	Instance string
+	Start    int64
+	End      int64
+	Limit    int
+}
+
+func (f *HistoryFilter) Match(h *History) bool {
+	if len(f.AlarmID) > 0 && f.AlarmID != h.AlarmID {
+		return false
+	}
+	if len(f.Status) > 0 && f.Status != h.Status {
+		return false
+	}
+	if len(f.Instance) > 0 && f.Instance != h.Instance {
+		return false
+	}
+	if f.Start > 0 && h.Timestamp < f.Start {
+		return false
+	}
+	if f.End > 0 && h.Timestamp > f.End {
+		return false
+	}
+	return true
 }
 
 func (ae *AlarmEvent) FieldBool(key string) bool {
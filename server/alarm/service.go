@@ -17,6 +17,7 @@ package alarm
 
 import (
 	"sync"
+	"time"
 
 	nf "github.com/apache/servicecomb-service-center/pkg/event"
 	"github.com/apache/servicecomb-service-center/pkg/log"
@@ -33,14 +34,24 @@ var (
 type Service struct {
 	nf.Subscriber
 	alarms util.ConcurrentMap
+	// persist the alarm history to the datasource if enabled
+	persist  bool
+	instance string
+}
+
+// SetInstance sets the name of the current instance raising the alarms
+func (ac *Service) SetInstance(instance string) {
+	ac.instance = instance
 }
 
 func (ac *Service) Raise(id model.ID, fields ...model.Field) error {
 	ae := &model.AlarmEvent{
-		Event:  nf.NewEvent(ALARM, Subject, ""),
-		Status: Activated,
-		ID:     id,
-		Fields: util.NewJSONObject(),
+		Event:     nf.NewEvent(ALARM, Subject, ""),
+		Status:    Activated,
+		ID:        id,
+		Fields:    util.NewJSONObject(),
+		Instance:  ac.instance,
+		Timestamp: nowMillis(),
 	}
 	for _, f := range fields {
 		ae.Fields[f.Key] = f.Value
@@ -50,9 +61,11 @@ func (ac *Service) Raise(id model.ID, fields ...model.Field) error {
 
 func (ac *Service) Clear(id model.ID) error {
 	ae := &model.AlarmEvent{
-		Event:  nf.NewEvent(ALARM, Subject, ""),
-		Status: Cleared,
-		ID:     id,
+		Event:     nf.NewEvent(ALARM, Subject, ""),
+		Status:    Cleared,
+		ID:        id,
+		Instance:  ac.instance,
+		Timestamp: nowMillis(),
 	}
 	return event.Center().Fire(ae)
 }
@@ -65,24 +78,59 @@ func (ac *Service) ListAll() (ls []*model.AlarmEvent) {
 	return
 }
 
+// ClearAll clears the activated alarms, the local ones are cleared by
+// events so that the sinks are notified
 func (ac *Service) ClearAll() {
-	ac.alarms = util.ConcurrentMap{}
+	cleared := make(map[model.ID]bool)
+	for _, a := range ac.ListAll() {
+		if a.Status == Cleared {
+			continue
+		}
+		if err := ac.Clear(a.ID); err != nil {
+			log.Errorf(err, "clear alarm[%s] failed", a.ID)
+			continue
+		}
+		cleared[a.ID] = true
+	}
+	if ac.persist {
+		ac.clearActivated(cleared)
+	}
 }
 
 func (ac *Service) OnMessage(evt nf.Event) {
 	alarm := evt.(*model.AlarmEvent)
+	changed := false
 	switch alarm.Status {
 	case Cleared:
 		if itf, ok := ac.alarms.Get(alarm.ID); ok {
 			if exist := itf.(*model.AlarmEvent); exist.Status != Cleared {
 				exist.Status = Cleared
 				alarm = exist
+				changed = true
 			}
 		}
 	default:
+		itf, ok := ac.alarms.Get(alarm.ID)
+		changed = !ok || itf.(*model.AlarmEvent).Status != Activated
 		ac.alarms.Put(alarm.ID, alarm)
 	}
 	log.Debugf("alarm[%s] %s, %v", alarm.ID, alarm.Status, alarm.Fields)
+	// only the status changes are recorded, the same alarm raised repeatedly is recorded once
+	if changed && ac.persist {
+		h := &model.History{
+			AlarmID:  alarm.ID,
+			Status:   alarm.Status,
+			Instance: ac.instance,
+		}
+		if alarm.Status == Activated {
+			h.Fields = alarm.Fields
+		}
+		ac.record(h)
+	}
+}
+
+func nowMillis() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
 }
 
 func NewAlarmService() *Service {
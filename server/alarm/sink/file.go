@@ -0,0 +1,58 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+// FileSink writes the alarm events to a JSON-lines file,
+// it inherits log's rotate and backup configuration
+type FileSink struct {
+	logger *log.Logger
+}
+
+func (s *FileSink) Name() string {
+	return "file"
+}
+
+func (s *FileSink) Notify(_ context.Context, ae *model.AlarmEvent) error {
+	b, err := json.Marshal(ae)
+	if err != nil {
+		return err
+	}
+	s.logger.Info(string(b))
+	return nil
+}
+
+func NewFileSink(file string) *FileSink {
+	return &FileSink{
+		logger: log.NewLogger(log.Config{
+			LoggerFile:     file,
+			LogFormatText:  true,
+			LogRotateSize:  int(config.GetLog().LogRotateSize),
+			LogBackupCount: int(config.GetLog().LogBackupCount),
+			NoCaller:       true,
+			NoTime:         true,
+			NoLevel:        true,
+		}),
+	}
+}
@@ -0,0 +1,163 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sink notifies the alarm status changes to the outside,
+// e.g. webhook, file, syslog and prometheus
+package sink
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	nf "github.com/apache/servicecomb-service-center/pkg/event"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/queue"
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/event"
+)
+
+const (
+	defaultFile           = "./alarm.log"
+	defaultWebhookTimeout = 5 * time.Second
+	defaultWebhookRetries = 3
+	defaultSyslogTag      = "service-center"
+	sinkQueueSize         = 1000
+)
+
+// Sink notifies the alarm event, it is called by the worker of the sink
+// in the order of the events, so it should not block for long
+type Sink interface {
+	Name() string
+	Notify(ctx context.Context, ae *model.AlarmEvent) error
+}
+
+// Subscriber dispatches the alarm status changes to the sinks,
+// the same alarm raised repeatedly is notified once
+type Subscriber struct {
+	nf.Subscriber
+	queues []*queue.TaskQueue
+	lock   sync.Mutex
+	status map[model.ID]model.Status
+}
+
+func (s *Subscriber) OnMessage(evt nf.Event) {
+	ae, ok := evt.(*model.AlarmEvent)
+	if !ok || !s.changed(ae) {
+		return
+	}
+	// the alarm service updates the event, notify a snapshot
+	snapshot := *ae
+	for _, q := range s.queues {
+		q.Add(queue.Task{Payload: &snapshot})
+	}
+}
+
+func (s *Subscriber) changed(ae *model.AlarmEvent) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	prev := s.status[ae.ID]
+	switch ae.Status {
+	case alarm.Activated:
+		if prev == alarm.Activated {
+			return false
+		}
+	case alarm.Cleared:
+		if prev != alarm.Activated {
+			return false
+		}
+	default:
+		return false
+	}
+	s.status[ae.ID] = ae.Status
+	return true
+}
+
+// sinkWorker notifies the events of the queue to the sink one by one
+type sinkWorker struct {
+	sink Sink
+}
+
+func (w *sinkWorker) Handle(ctx context.Context, obj interface{}) {
+	ae := obj.(*model.AlarmEvent)
+	if err := w.sink.Notify(ctx, ae); err != nil {
+		log.Errorf(err, "notify alarm[%s] %s to %s sink failed", ae.ID, ae.Status, w.sink.Name())
+	}
+}
+
+// NewSubscriber returns the subscriber with one ordered worker queue for each sink,
+// a slow sink does not delay the others
+func NewSubscriber(sinks ...Sink) *Subscriber {
+	queues := make([]*queue.TaskQueue, 0, len(sinks))
+	for _, sk := range sinks {
+		q := queue.NewTaskQueue(sinkQueueSize)
+		q.AddWorker(&sinkWorker{sink: sk})
+		q.Run()
+		queues = append(queues, q)
+	}
+	return &Subscriber{
+		Subscriber: nf.NewSubscriber(alarm.ALARM, alarm.Subject, alarm.Group),
+		queues:     queues,
+		status:     make(map[model.ID]model.Status),
+	}
+}
+
+// Init subscribes the ALARM event with the sinks enabled in config
+func Init() {
+	var sinks []Sink
+	if config.GetBool("alarm.sinks.webhook.enable", false) {
+		s, err := NewWebhookSink(WebhookOptions{
+			URL:      config.GetString("alarm.sinks.webhook.url", ""),
+			Template: config.GetString("alarm.sinks.webhook.template", ""),
+			Timeout:  config.GetDuration("alarm.sinks.webhook.timeout", defaultWebhookTimeout),
+			Retries:  config.GetInt("alarm.sinks.webhook.retries", defaultWebhookRetries),
+		})
+		if err != nil {
+			log.Errorf(err, "init alarm webhook sink failed")
+		} else {
+			sinks = append(sinks, s)
+		}
+	}
+	if config.GetBool("alarm.sinks.file.enable", false) {
+		sinks = append(sinks, NewFileSink(config.GetString("alarm.sinks.file.path", defaultFile)))
+	}
+	if config.GetBool("alarm.sinks.syslog.enable", false) {
+		s, err := NewSyslogSink(config.GetString("alarm.sinks.syslog.network", ""),
+			config.GetString("alarm.sinks.syslog.address", ""),
+			config.GetString("alarm.sinks.syslog.tag", defaultSyslogTag))
+		if err != nil {
+			log.Errorf(err, "init alarm syslog sink failed")
+		} else {
+			sinks = append(sinks, s)
+		}
+	}
+	if config.GetBool("alarm.sinks.prometheus.enable", true) {
+		sinks = append(sinks, &PrometheusSink{})
+	}
+	if len(sinks) == 0 {
+		return
+	}
+	if err := event.Center().AddSubscriber(NewSubscriber(sinks...)); err != nil {
+		log.Errorf(err, "subscribe alarm event failed")
+		return
+	}
+	names := make([]string, 0, len(sinks))
+	for _, s := range sinks {
+		names = append(names, s.Name())
+	}
+	log.Infof("alarm sinks init: %v", names)
+}
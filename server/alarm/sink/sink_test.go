@@ -0,0 +1,188 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/backoff"
+	nf "github.com/apache/servicecomb-service-center/pkg/event"
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+	"github.com/apache/servicecomb-service-center/server/alarm/sink"
+)
+
+var testBackoff = &backoff.PowerBackoff{InitDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, Factor: 2}
+
+func TestWebhookSink_Notify(t *testing.T) {
+	ae := &model.AlarmEvent{ID: alarm.IDInternalError, Status: alarm.Activated, Instance: "a", Timestamp: 1,
+		Fields: map[string]interface{}{alarm.FieldAdditionalContext: "err"}}
+
+	t.Run("failed at first, should retry", func(t *testing.T) {
+		var (
+			lock  sync.Mutex
+			calls int
+			body  map[string]interface{}
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			b, _ := ioutil.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &body)
+		}))
+		defer server.Close()
+
+		s, err := sink.NewWebhookSink(sink.WebhookOptions{URL: server.URL, Retries: 2, Backoff: testBackoff})
+		assert.NoError(t, err)
+		assert.NoError(t, s.Notify(context.Background(), ae))
+		assert.Eventually(t, func() bool {
+			lock.Lock()
+			defer lock.Unlock()
+			return calls == 2
+		}, time.Second, 5*time.Millisecond)
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(t, "InternalError", body["id"])
+		assert.Equal(t, "ACTIVATED", body["status"])
+		assert.Equal(t, "err", body["fields"].(map[string]interface{})["detail"])
+	})
+	t.Run("always failed, should return error without retries", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		s, err := sink.NewWebhookSink(sink.WebhookOptions{URL: server.URL, Backoff: testBackoff})
+		assert.NoError(t, err)
+		assert.Error(t, s.Notify(context.Background(), ae))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		s, err = sink.NewWebhookSink(sink.WebhookOptions{URL: server.URL, Retries: 1, Backoff: testBackoff})
+		assert.NoError(t, err)
+		assert.NoError(t, s.Notify(context.Background(), ae))
+		assert.Eventually(t, func() bool {
+			return atomic.LoadInt32(&calls) == 3
+		}, time.Second, 5*time.Millisecond)
+	})
+	t.Run("newer event posted, should drop the retry of the older", func(t *testing.T) {
+		var (
+			lock     sync.Mutex
+			statuses []string
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			b, _ := ioutil.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &body)
+			lock.Lock()
+			defer lock.Unlock()
+			statuses = append(statuses, body["status"].(string))
+			if len(statuses) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		defer server.Close()
+
+		s, err := sink.NewWebhookSink(sink.WebhookOptions{URL: server.URL, Retries: 1,
+			Backoff: &backoff.PowerBackoff{InitDelay: 50 * time.Millisecond, MaxDelay: 50 * time.Millisecond, Factor: 1}})
+		assert.NoError(t, err)
+		assert.NoError(t, s.Notify(context.Background(), ae))
+		cleared := *ae
+		cleared.Status = alarm.Cleared
+		assert.NoError(t, s.Notify(context.Background(), &cleared))
+		time.Sleep(100 * time.Millisecond)
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(t, []string{"ACTIVATED", "CLEARED"}, statuses)
+	})
+	t.Run("custom template, should render the event", func(t *testing.T) {
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = ioutil.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		s, err := sink.NewWebhookSink(sink.WebhookOptions{URL: server.URL,
+			Template: `{"text":{{json (printf "%s is %s: %s" .ID .Status (index .Fields "detail"))}}}`})
+		assert.NoError(t, err)
+		assert.NoError(t, s.Notify(context.Background(), ae))
+		assert.Equal(t, `{"text":"InternalError is ACTIVATED: err"}`, string(body))
+	})
+	t.Run("invalid options, should return error", func(t *testing.T) {
+		_, err := sink.NewWebhookSink(sink.WebhookOptions{})
+		assert.Error(t, err)
+		_, err = sink.NewWebhookSink(sink.WebhookOptions{URL: "http://127.0.0.1", Template: "{{"})
+		assert.Error(t, err)
+	})
+}
+
+type recordSink struct {
+	lock   sync.Mutex
+	events []model.Status
+}
+
+func (s *recordSink) Name() string {
+	return "record"
+}
+
+func (s *recordSink) Notify(_ context.Context, ae *model.AlarmEvent) error {
+	s.lock.Lock()
+	s.events = append(s.events, ae.Status)
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *recordSink) Events() []model.Status {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]model.Status(nil), s.events...)
+}
+
+func TestSubscriber_OnMessage(t *testing.T) {
+	rs := &recordSink{}
+	s := sink.NewSubscriber(rs)
+	fire := func(status model.Status) {
+		s.OnMessage(&model.AlarmEvent{Event: nf.NewEvent(alarm.ALARM, alarm.Subject, ""),
+			ID: alarm.IDInternalError, Status: status})
+	}
+	fire(alarm.Cleared)
+	fire(alarm.Activated)
+	fire(alarm.Activated)
+	fire(alarm.Cleared)
+	fire(alarm.Cleared)
+	fire(alarm.Activated)
+	// the sink worker keeps the notifications in order
+	assert.Eventually(t, func() bool {
+		return len(rs.Events()) == 3
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []model.Status{alarm.Activated, alarm.Cleared, alarm.Activated}, rs.Events())
+}
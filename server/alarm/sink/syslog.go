@@ -0,0 +1,57 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows,!plan9
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"log/syslog"
+
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+)
+
+// SyslogSink writes the alarm events in JSON to syslog,
+// the activated alarms are in warning level and the cleared are in info level
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+func (s *SyslogSink) Name() string {
+	return "syslog"
+}
+
+func (s *SyslogSink) Notify(_ context.Context, ae *model.AlarmEvent) error {
+	b, err := json.Marshal(ae)
+	if err != nil {
+		return err
+	}
+	if ae.Status == alarm.Activated {
+		return s.writer.Warning(string(b))
+	}
+	return s.writer.Info(string(b))
+}
+
+// NewSyslogSink connects to the syslog daemon, the local one if network and address are empty
+func NewSyslogSink(network, address, tag string) (*SyslogSink, error) {
+	w, err := syslog.Dial(network, address, syslog.LOG_WARNING|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{writer: w}, nil
+}
@@ -0,0 +1,169 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/backoff"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+)
+
+// DefaultWebhookTemplate is the request body template if not set in config
+const DefaultWebhookTemplate = `{"id":{{json .ID}},"status":{{json .Status}},"instance":{{json .Instance}},` +
+	`"timestamp":{{.Timestamp}},"fields":{{json .Fields}}}`
+
+// WebhookOptions is the webhook config, Template is the go template of the request body
+// rendered with the alarm event, the 'json' function encodes a value to JSON
+type WebhookOptions struct {
+	URL      string
+	Template string
+	Timeout  time.Duration
+	Retries  int
+	Backoff  backoff.Backoff
+}
+
+// WebhookSink posts the alarm event to the URL, retries with backoff in background if failed,
+// the retry is dropped once a newer event of the same alarm is posted
+type WebhookSink struct {
+	url      string
+	template *template.Template
+	client   *http.Client
+	retries  int
+	backoff  backoff.Backoff
+
+	lock   sync.Mutex
+	latest map[model.ID]uint64
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *WebhookSink) Notify(ctx context.Context, ae *model.AlarmEvent) error {
+	var body bytes.Buffer
+	if err := s.template.Execute(&body, ae); err != nil {
+		return err
+	}
+	seq := s.next(ae.ID)
+	err := s.post(ctx, body.Bytes())
+	if err == nil || s.retries <= 0 {
+		return err
+	}
+	// retry off the worker of the sink, so it does not delay the following events
+	gopool.Go(func(ctx context.Context) {
+		if err := s.retry(ctx, ae.ID, seq, body.Bytes()); err != nil {
+			log.Errorf(err, "notify alarm[%s] %s to %s sink failed", ae.ID, ae.Status, s.Name())
+		}
+	})
+	return nil
+}
+
+func (s *WebhookSink) retry(ctx context.Context, id model.ID, seq uint64, body []byte) (err error) {
+	for retries := 0; retries < s.retries; retries++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.backoff.Delay(retries)):
+		}
+		if s.outdated(id, seq) {
+			return nil
+		}
+		if err = s.post(ctx, body); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// next returns the sequence of the event of the alarm
+func (s *WebhookSink) next(id model.ID) uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.latest[id]++
+	return s.latest[id]
+}
+
+// outdated returns true if there is a newer event of the alarm
+func (s *WebhookSink) outdated(id model.ID, seq uint64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.latest[id] != seq
+}
+
+func (s *WebhookSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(rest.HeaderContentType, rest.ContentTypeJSON)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responds %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func NewWebhookSink(opts WebhookOptions) (*WebhookSink, error) {
+	if len(opts.URL) == 0 {
+		return nil, errors.New("required webhook url")
+	}
+	text := opts.Template
+	if len(text) == 0 {
+		text = DefaultWebhookTemplate
+	}
+	tmpl, err := template.New("webhook").Funcs(template.FuncMap{"json": toJSON}).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = backoff.GetBackoff()
+	}
+	return &WebhookSink{
+		url:      opts.URL,
+		template: tmpl,
+		client:   &http.Client{Timeout: opts.Timeout},
+		retries:  opts.Retries,
+		backoff:  opts.Backoff,
+		latest:   make(map[model.ID]uint64),
+	}, nil
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -46,6 +46,9 @@ import (
 	//tracing
 	_ "github.com/apache/servicecomb-service-center/server/plugin/tracing/pzipkin"
 
+	//auditlog
+	_ "github.com/apache/servicecomb-service-center/server/plugin/auditlog/buildin"
+
 	//tlsconf
 	_ "github.com/apache/servicecomb-service-center/server/plugin/security/tlsconf/buildin"
 
@@ -67,11 +70,13 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/server/broker"
 	"github.com/apache/servicecomb-service-center/server/handler/accesslog"
+	"github.com/apache/servicecomb-service-center/server/handler/auditlog"
 	"github.com/apache/servicecomb-service-center/server/handler/auth"
 	"github.com/apache/servicecomb-service-center/server/handler/context"
 	"github.com/apache/servicecomb-service-center/server/handler/exception"
 	"github.com/apache/servicecomb-service-center/server/handler/maxbody"
 	"github.com/apache/servicecomb-service-center/server/handler/metrics"
+	"github.com/apache/servicecomb-service-center/server/handler/ratelimit"
 	"github.com/apache/servicecomb-service-center/server/handler/route"
 	"github.com/apache/servicecomb-service-center/server/handler/tracing"
 	"github.com/apache/servicecomb-service-center/server/interceptor"
@@ -90,7 +95,9 @@ func init() {
 	exception.RegisterHandlers()
 	context.RegisterHandlers()
 	accesslog.RegisterHandlers()
+	auditlog.RegisterHandlers()
 	maxbody.RegisterHandlers()
+	ratelimit.RegisterHandlers()
 	auth.RegisterHandlers()
 	metrics.RegisterHandlers()
 	tracing.RegisterHandlers()
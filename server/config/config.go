@@ -83,6 +83,30 @@ func GetRBAC() ServerConfigDetail {
 	return App.Server.Config
 }
 
+//GetOIDC return the external identity provider configs, nil if not configured
+func GetOIDC() *OIDC {
+	if App.RBAC == nil {
+		return nil
+	}
+	return App.RBAC.OIDC
+}
+
+//GetTOTP return the second factor configs, nil if not configured
+func GetTOTP() *TOTP {
+	if App.RBAC == nil {
+		return nil
+	}
+	return App.RBAC.TOTP
+}
+
+//GetLDAP return the corporate directory configs, nil if not configured
+func GetLDAP() *LDAP {
+	if App.RBAC == nil {
+		return nil
+	}
+	return App.RBAC.LDAP
+}
+
 func Init() {
 	setCPUs()
 
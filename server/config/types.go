@@ -30,6 +30,7 @@ const (
 type AppConfig struct {
 	Gov    *Gov          `yaml:"gov"`
 	Server *ServerConfig `yaml:"server"`
+	RBAC   *RBAC         `yaml:"rbac"`
 }
 type Gov struct {
 	DistOptions []DistributorOptions `yaml:"plugins"`
@@ -40,6 +41,66 @@ type DistributorOptions struct {
 	Endpoint string `yaml:"endpoint"`
 }
 
+type RBAC struct {
+	OIDC *OIDC `yaml:"oidc"`
+	LDAP *LDAP `yaml:"ldap"`
+	TOTP *TOTP `yaml:"totp"`
+}
+
+// OIDC is the external identity provider configs,
+// the JWTs issued by Issuer are verified by the keys in JWKS
+type OIDC struct {
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// JWKS is the local file path or the http(s) url of the JSON Web Key Set
+	JWKS                string `yaml:"jwks"`
+	JWKSRefreshInterval string `yaml:"jwksRefreshInterval"`
+	// UserClaim is the claim used as the account name, default is sub
+	UserClaim    string        `yaml:"userClaim"`
+	RoleMappings []RoleMapping `yaml:"roleMappings"`
+}
+
+// LDAP is the corporate directory configs, the user is searched by UserFilter
+// with BindDN, then bound with the password to login
+type LDAP struct {
+	// URL is ldap://host:389 or ldaps://host:636
+	URL                string `yaml:"url"`
+	StartTLS           bool   `yaml:"startTLS"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
+	BindDN             string `yaml:"bindDN"`
+	// BindPassword can be encrypted by the cipher plugin
+	BindPassword string `yaml:"bindPassword"`
+	BaseDN       string `yaml:"baseDN"`
+	// UserFilter is the filter to search the user, %s is the escaped user name
+	UserFilter string `yaml:"userFilter"`
+	// GroupBaseDN is the base DN to search groups, default is BaseDN
+	GroupBaseDN string `yaml:"groupBaseDN"`
+	// GroupFilter is the filter to search the groups of the user, %s is the escaped user DN
+	GroupFilter    string `yaml:"groupFilter"`
+	GroupAttribute string `yaml:"groupAttribute"`
+	// RoleMappings maps the groups to the roles, the claim of the rules is 'groups'
+	RoleMappings []RoleMapping `yaml:"roleMappings"`
+	RoleCacheTTL string        `yaml:"roleCacheTTL"`
+	// LocalAccounts are still authenticated by the local accounts, default is root
+	LocalAccounts []string `yaml:"localAccounts"`
+}
+
+// TOTP is the second factor configs
+type TOTP struct {
+	// Issuer is shown in the authenticator apps
+	Issuer string `yaml:"issuer"`
+	// EnforceRoles are the roles whose accounts must enroll TOTP
+	EnforceRoles []string `yaml:"enforceRoles"`
+}
+
+// RoleMapping grants the Roles if the Claim equals to or contains the Value,
+// empty Value matches any value of the Claim
+type RoleMapping struct {
+	Claim string   `yaml:"claim"`
+	Value string   `yaml:"value"`
+	Roles []string `yaml:"roles"`
+}
+
 // GetImplName return the impl name
 func (c *AppConfig) GetImplName(kind plugin.Kind) string {
 	return GetString(kind.String()+".kind", plugin.Buildin, WithStandby(kind.String()+"_plugin"))
@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditlog
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	authHandler "github.com/apache/servicecomb-service-center/server/handler/auth"
+	"github.com/apache/servicecomb-service-center/server/handler/ratelimit"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+// defaultExcludeGroups are the API groups not audited by default,
+// the heartbeats are too frequent to be audited
+var defaultExcludeGroups = []string{ratelimit.GroupHeartbeat, ratelimit.GroupWatch}
+
+// Handler records the audit log of every mutating API call,
+// except the APIs in the excluded groups, see ratelimit.APIGroup
+type Handler struct {
+	excludeGroups map[string]bool
+}
+
+func NewHandler(excludeGroups []string) *Handler {
+	h := &Handler{excludeGroups: make(map[string]bool, len(excludeGroups))}
+	for _, group := range excludeGroups {
+		if group = strings.TrimSpace(group); len(group) > 0 {
+			h.excludeGroups[group] = true
+		}
+	}
+	return h
+}
+
+func (h *Handler) Handle(i *chain.Invocation) {
+	r := i.Context().Value(rest.CtxRequest).(*http.Request)
+	pattern, _ := i.Context().Value(rest.CtxMatchPattern).(string)
+	if !h.audited(r.Method, pattern) {
+		i.Next()
+		return
+	}
+	start, ok := i.Context().Value(rest.CtxStartTimestamp).(time.Time)
+	if !ok {
+		start = time.Now()
+	}
+	i.Next(chain.WithAsyncFunc(func(_ chain.Result) {
+		ctx := i.Context()
+		statusCode, _ := ctx.Value(rest.CtxResponseStatus).(int)
+		api, _ := ctx.Value(rest.CtxMatchPattern).(string)
+		l := &dump.AuditLog{
+			ID:        util.GenerateUUID(),
+			Timestamp: start.UnixNano() / int64(time.Millisecond),
+			Actor:     rbacsvc.UserFromContext(ctx),
+			Domain:    util.ParseDomain(ctx),
+			Project:   util.ParseProject(ctx),
+			Method:    r.Method,
+			API:       api,
+			TargetIDs: targetIDs(r),
+			Code:      statusCode,
+			Latency:   int64(time.Since(start) / time.Millisecond),
+			SourceIP:  util.GetIPFromContext(ctx),
+		}
+		if scope, ok := ctx.Value(authHandler.CtxResourceScopes).(*auth.ResourceScope); ok && scope != nil {
+			l.Resource, l.Verb = scope.Type, scope.Verb
+		}
+		auditlog.Record(ctx, l)
+	}))
+}
+
+func (h *Handler) audited(method, pattern string) bool {
+	if !isMutating(method) {
+		return false
+	}
+	group := ratelimit.APIGroup(method, pattern)
+	return len(group) == 0 || !h.excludeGroups[group]
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// targetIDs returns the path parameters except project, e.g. serviceId=xxx
+func targetIDs(r *http.Request) []string {
+	var ids []string
+	for k, v := range r.URL.Query() {
+		if !strings.HasPrefix(k, ":") || k == ":project" || len(v) == 0 {
+			continue
+		}
+		ids = append(ids, k[1:]+"="+v[0])
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// RegisterHandlers registers an audit log handler to the handler chain
+func RegisterHandlers() {
+	if !config.GetBool("auditlog.enable", false) {
+		return
+	}
+	groups := config.GetString("auditlog.excludeGroups", strings.Join(defaultExcludeGroups, ","))
+	chain.RegisterHandler(rest.ServerChainName, NewHandler(strings.Split(groups, ",")))
+}
@@ -0,0 +1,37 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auditlog
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_audited(t *testing.T) {
+	h := NewHandler(defaultExcludeGroups)
+	assert.True(t, h.audited(http.MethodPost, "/v4/:project/registry/microservices"))
+	assert.True(t, h.audited(http.MethodDelete, "/v4/:project/admin/alarms"))
+	assert.False(t, h.audited(http.MethodGet, "/v4/:project/registry/microservices"))
+	assert.False(t, h.audited(http.MethodPut, "/v4/:project/registry/microservices/:serviceId/instances/:instanceId/heartbeat"))
+	assert.False(t, h.audited(http.MethodPut, "/v4/:project/registry/heartbeats"))
+
+	h = NewHandler([]string{""})
+	assert.True(t, h.audited(http.MethodPut, "/v4/:project/registry/heartbeats"))
+}
@@ -18,18 +18,22 @@
 package exception
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
+	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/pkg/errsvc"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/chain"
 	"github.com/apache/servicecomb-service-center/pkg/errors"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
 )
 
 var whitelists = make(map[string]struct{})
@@ -54,7 +58,7 @@ func (h *Handler) Handle(i *chain.Invocation) {
 	i.WithContext(rest.CtxResponse, asyncWriter)
 	i.Next(chain.WithFunc(func(ret chain.Result) {
 		if !ret.OK {
-			i.WithContext(rest.CtxResponseStatus, h.responseError(w, ret.Err))
+			i.WithContext(rest.CtxResponseStatus, h.responseError(w, r, ret.Err))
 			return
 		}
 
@@ -62,11 +66,11 @@ func (h *Handler) Handle(i *chain.Invocation) {
 		if err := asyncWriter.Flush(); err != nil {
 			log.Error("response writer flush failed", err)
 		}
-		h.alarmIfInternalError(asyncWriter.StatusCode, util.BytesToStringWithNoCopy(asyncWriter.Body))
+		h.alarmIfInternalError(r, asyncWriter.StatusCode, util.BytesToStringWithNoCopy(asyncWriter.Body))
 	}))
 }
 
-func (h *Handler) responseError(w http.ResponseWriter, e error) (statusCode int) {
+func (h *Handler) responseError(w http.ResponseWriter, r *http.Request, e error) (statusCode int) {
 	statusCode = http.StatusBadRequest
 	contentType := rest.ContentTypeText
 	body := []byte("Unknown error")
@@ -76,7 +80,7 @@ func (h *Handler) responseError(w http.ResponseWriter, e error) (statusCode int)
 		if _, writeErr := w.Write(body); writeErr != nil {
 			log.Error("write response failed", writeErr)
 		}
-		h.alarmIfInternalError(statusCode, util.BytesToStringWithNoCopy(body))
+		h.alarmIfInternalError(r, statusCode, util.BytesToStringWithNoCopy(body))
 	}()
 
 	if e == nil {
@@ -96,16 +100,40 @@ func (h *Handler) responseError(w http.ResponseWriter, e error) (statusCode int)
 	return
 }
 
-func (h *Handler) alarmIfInternalError(statusCode int, errMsg string) {
+func (h *Handler) alarmIfInternalError(r *http.Request, statusCode int, errMsg string) {
 	if statusCode < http.StatusInternalServerError {
 		return
 	}
-	err := alarm.Raise(alarm.IDInternalError, alarm.AdditionalContext(errMsg))
+	fields := append([]model.Field{alarm.AdditionalContext(errMsg)}, serviceAlarmFields(r)...)
+	err := alarm.Raise(alarm.IDInternalError, fields...)
 	if err != nil {
 		log.Error("raise alarm failed", err)
 	}
 }
 
+// serviceAlarmFields returns the fields of the requested service and its owner,
+// then the alarm can be routed to the owner of the service
+func serviceAlarmFields(r *http.Request) []model.Field {
+	serviceID := r.URL.Query().Get(":serviceId")
+	if len(serviceID) == 0 {
+		return nil
+	}
+	service, err := getService(r.Context(), serviceID)
+	if err != nil || service == nil {
+		log.Warn(fmt.Sprintf("get the owner of service[%s] failed", serviceID))
+		return []model.Field{{Key: datasource.AlarmFieldServiceID, Value: serviceID}}
+	}
+	return datasource.OwnerAlarmFields(service)
+}
+
+var getService = func(ctx context.Context, serviceID string) (*discovery.MicroService, error) {
+	resp, err := datasource.GetMetadataManager().GetService(ctx, &discovery.GetServiceRequest{ServiceId: serviceID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Service, nil
+}
+
 func RegisterHandlers() {
 	chain.RegisterHandler(rest.ServerChainName, &Handler{})
 }
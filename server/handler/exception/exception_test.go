@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package exception
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/event"
+)
+
+func TestHandler_AlarmIfInternalError(t *testing.T) {
+	event.Center().Start()
+	getService = func(ctx context.Context, serviceID string) (*discovery.MicroService, error) {
+		return &discovery.MicroService{ServiceId: serviceID, Properties: map[string]string{
+			datasource.PropOwnerTeam:   "team-a",
+			datasource.PropOwnerOnCall: "https://oncall.example.com/team-a",
+		}}, nil
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/v4/default/registry/microservices/svc-1", nil)
+	v := r.URL.Query()
+	v.Set(":serviceId", "svc-1")
+	r.URL.RawQuery = v.Encode()
+	(&Handler{}).alarmIfInternalError(r, http.StatusInternalServerError, "internal error")
+	time.Sleep(time.Second)
+
+	var found bool
+	for _, a := range alarm.ListAll() {
+		if a.ID != alarm.IDInternalError {
+			continue
+		}
+		found = true
+		assert.Equal(t, "internal error", a.FieldString(alarm.FieldAdditionalContext))
+		assert.Equal(t, "svc-1", a.FieldString(datasource.AlarmFieldServiceID))
+		assert.Equal(t, "team-a", a.FieldString(datasource.AlarmFieldOwnerTeam))
+		assert.Equal(t, "https://oncall.example.com/team-a", a.FieldString(datasource.AlarmFieldOwnerOnCall))
+	}
+	assert.True(t, found)
+}
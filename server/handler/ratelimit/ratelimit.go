@@ -0,0 +1,170 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/patrickmn/go-cache"
+	"golang.org/x/time/rate"
+
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/metrics"
+)
+
+const ErrTooManyRequests int32 = 429001
+
+const headerRetryAfter = "Retry-After"
+
+// limiterIdleTTL is how long the limiter is kept without any request,
+// it is extended to the time of refilling the whole bucket if longer
+const limiterIdleTTL = 10 * time.Minute
+
+func init() {
+	discovery.MustRegisterErr(ErrTooManyRequests, "Too many requests")
+}
+
+// Handler limits the request rate of each domain/project by token buckets
+type Handler struct {
+	rules *Rules
+	// limiters is keyed by domain/project[/group], the idle ones are expired
+	limiters *cache.Cache
+}
+
+func NewHandler(rules *Rules) *Handler {
+	return &Handler{
+		rules:    rules,
+		limiters: cache.New(limiterIdleTTL, limiterIdleTTL),
+	}
+}
+
+func (h *Handler) Handle(i *chain.Invocation) {
+	ctx := i.Context()
+	r := ctx.Value(rest.CtxRequest).(*http.Request)
+	pattern, _ := ctx.Value(rest.CtxMatchPattern).(string)
+	domain, project := util.ParseDomain(ctx), util.ParseProject(ctx)
+	group := APIGroup(r.Method, pattern)
+
+	delay, ok := h.reserve(domain, project, group)
+	if ok {
+		i.Next()
+		return
+	}
+
+	metrics.ReportThrottled(domain, project, group)
+	w := ctx.Value(rest.CtxResponse).(http.ResponseWriter)
+	w.Header().Set(headerRetryAfter, strconv.Itoa(retryAfterSeconds(delay)))
+	i.Fail(discovery.NewError(ErrTooManyRequests,
+		fmt.Sprintf("the request rate of %s/%s exceeds the limit, group: %s", domain, project, group)))
+}
+
+// reserve takes a token from the bucket, returns false and the delay to wait if no token available
+func (h *Handler) reserve(domain, project, group string) (time.Duration, bool) {
+	limit, grouped := h.rules.Resolve(domain, project, group)
+	if limit == nil || limit.QPS <= 0 {
+		return 0, true
+	}
+	key := util.StringJoin([]string{domain, project}, "/")
+	if grouped {
+		key = util.StringJoin([]string{key, group}, "/")
+	}
+	limiter := h.limiter(key, limit)
+
+	now := time.Now()
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return time.Second, false
+	}
+	delay := reservation.DelayFrom(now)
+	if delay > 0 {
+		reservation.CancelAt(now)
+		return delay, false
+	}
+	return 0, true
+}
+
+func (h *Handler) limiter(key string, limit *Limit) *rate.Limiter {
+	ttl := idleTTL(limit)
+	if v, ok := h.limiters.Get(key); ok {
+		// refresh the expiration
+		h.limiters.Set(key, v, ttl)
+		return v.(*rate.Limiter)
+	}
+	l := rate.NewLimiter(rate.Limit(limit.QPS), limit.BurstSize())
+	if err := h.limiters.Add(key, l, ttl); err != nil {
+		// added by the concurrent request
+		if v, ok := h.limiters.Get(key); ok {
+			return v.(*rate.Limiter)
+		}
+	}
+	return l
+}
+
+// idleTTL returns the expiration of the idle limiter, the bucket must be
+// full when it expires, otherwise the new limiter allows an extra burst
+func idleTTL(limit *Limit) time.Duration {
+	refill := time.Duration(float64(limit.BurstSize()) / limit.QPS * float64(time.Second))
+	if refill > limiterIdleTTL {
+		return refill
+	}
+	return limiterIdleTTL
+}
+
+func retryAfterSeconds(delay time.Duration) int {
+	s := int(math.Ceil(delay.Seconds()))
+	if s < 1 {
+		return 1
+	}
+	return s
+}
+
+// APIGroup returns the group of the API, empty if the API is not in any group
+func APIGroup(method, pattern string) string {
+	switch {
+	case strings.HasSuffix(pattern, "/watcher") || strings.HasSuffix(pattern, "/listwatcher"):
+		return GroupWatch
+	case strings.HasSuffix(pattern, "/heartbeat") || strings.HasSuffix(pattern, "/heartbeats"):
+		return GroupHeartbeat
+	case !strings.HasPrefix(pattern, "/v4/:project/registry/") && !strings.HasPrefix(pattern, "/registry/v3/"):
+		return ""
+	case method == http.MethodGet || strings.HasSuffix(pattern, "/instances/action"):
+		// instances/action is the batch find
+		return GroupRegistryRead
+	default:
+		return GroupRegistryWrite
+	}
+}
+
+func RegisterHandlers() {
+	if !config.GetBool("ratelimit.enable", false) {
+		return
+	}
+	rules := LoadRules()
+	log.Info(fmt.Sprintf("rate limit enabled, %d tenant rules", len(rules.Tenants)))
+	chain.RegisterHandler(rest.ServerChainName, NewHandler(rules))
+}
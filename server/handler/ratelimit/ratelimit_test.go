@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/go-archaius"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/chain"
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/handler/ratelimit"
+)
+
+func init() {
+	err := archaius.Init(archaius.WithMemorySource())
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	_ = archaius.Set("ratelimit.qps", 100)
+	_ = archaius.Set("ratelimit.groups.heartbeat.qps", 1000)
+	_ = archaius.Set("ratelimit.tenants.d1.qps", 10)
+	_ = archaius.Set("ratelimit.tenants.d1.burst", 20)
+	_ = archaius.Set("ratelimit.tenants.d1.projects.p1.groups.watch.qps", 1)
+	_ = archaius.Set("ratelimit.tenants.d2.projects.p2.qps", 0)
+	defer func() {
+		_ = archaius.Delete("ratelimit.qps")
+		_ = archaius.Delete("ratelimit.groups.heartbeat.qps")
+		_ = archaius.Delete("ratelimit.tenants.d1.qps")
+		_ = archaius.Delete("ratelimit.tenants.d1.burst")
+		_ = archaius.Delete("ratelimit.tenants.d1.projects.p1.groups.watch.qps")
+		_ = archaius.Delete("ratelimit.tenants.d2.projects.p2.qps")
+	}()
+	rules := ratelimit.LoadRules()
+
+	t.Run("no tenant rule, should use global rule", func(t *testing.T) {
+		limit, grouped := rules.Resolve("default", "default", ratelimit.GroupRegistryRead)
+		assert.False(t, grouped)
+		assert.Equal(t, &ratelimit.Limit{QPS: 100}, limit)
+		assert.Equal(t, 100, limit.BurstSize())
+
+		limit, grouped = rules.Resolve("default", "default", ratelimit.GroupHeartbeat)
+		assert.True(t, grouped)
+		assert.Equal(t, float64(1000), limit.QPS)
+	})
+	t.Run("tenant rule, should override global rule", func(t *testing.T) {
+		limit, grouped := rules.Resolve("d1", "default", ratelimit.GroupHeartbeat)
+		assert.False(t, grouped)
+		assert.Equal(t, &ratelimit.Limit{QPS: 10, Burst: 20}, limit)
+
+		limit, grouped = rules.Resolve("d1", "p1", ratelimit.GroupWatch)
+		assert.True(t, grouped)
+		assert.Equal(t, &ratelimit.Limit{QPS: 1}, limit)
+
+		limit, _ = rules.Resolve("d1", "p1", ratelimit.GroupRegistryWrite)
+		assert.Equal(t, float64(10), limit.QPS)
+
+		limit, _ = rules.Resolve("d2", "p2", ratelimit.GroupRegistryRead)
+		assert.Equal(t, float64(0), limit.QPS)
+	})
+}
+
+func TestAPIGroup(t *testing.T) {
+	assert.Equal(t, ratelimit.GroupWatch,
+		ratelimit.APIGroup(http.MethodGet, "/v4/:project/registry/microservices/:serviceId/listwatcher"))
+	assert.Equal(t, ratelimit.GroupHeartbeat,
+		ratelimit.APIGroup(http.MethodPut, "/v4/:project/registry/microservices/:serviceId/instances/:instanceId/heartbeat"))
+	assert.Equal(t, ratelimit.GroupHeartbeat,
+		ratelimit.APIGroup(http.MethodPut, "/v4/:project/registry/heartbeats"))
+	assert.Equal(t, ratelimit.GroupRegistryRead,
+		ratelimit.APIGroup(http.MethodGet, "/v4/:project/registry/microservices"))
+	assert.Equal(t, ratelimit.GroupRegistryRead,
+		ratelimit.APIGroup(http.MethodPost, "/v4/:project/registry/instances/action"))
+	assert.Equal(t, ratelimit.GroupRegistryWrite,
+		ratelimit.APIGroup(http.MethodPost, "/registry/v3/microservices"))
+	assert.Equal(t, "", ratelimit.APIGroup(http.MethodGet, "/v4/:project/admin/dump"))
+}
+
+func TestHandler_Handle(t *testing.T) {
+	h := ratelimit.NewHandler(&ratelimit.Rules{
+		Global: &ratelimit.Rule{Limit: &ratelimit.Limit{QPS: 1}},
+		Tenants: map[string]*ratelimit.Rule{
+			"unlimited": {Limit: &ratelimit.Limit{QPS: 0}},
+		},
+	})
+	handle := func(domain string) (*httptest.ResponseRecorder, chain.Result) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/v4/default/registry/microservices", nil)
+		inv := chain.NewInvocation(util.SetDomainProject(context.Background(), domain, "default"),
+			chain.NewChain("test", []chain.Handler{h}))
+		inv.WithContext(rest.CtxRequest, r).
+			WithContext(rest.CtxResponse, w).
+			WithContext(rest.CtxMatchPattern, "/v4/:project/registry/microservices")
+		var result chain.Result
+		inv.Invoke(func(r chain.Result) {
+			result = r
+		})
+		return w, result
+	}
+
+	t.Run("exceed the limit, should return 429 with Retry-After", func(t *testing.T) {
+		_, result := handle("default")
+		assert.True(t, result.OK)
+
+		w, result := handle("default")
+		assert.False(t, result.OK)
+		err, ok := result.Err.(*errsvc.Error)
+		assert.True(t, ok)
+		assert.Equal(t, ratelimit.ErrTooManyRequests, err.Code)
+		assert.Equal(t, http.StatusTooManyRequests, err.StatusCode())
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	})
+	t.Run("tenant without limit, should not be limited", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			w, result := handle("unlimited")
+			assert.True(t, result.OK)
+			assert.Empty(t, w.Header().Get("Retry-After"))
+		}
+	})
+}
@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"math"
+	"strings"
+
+	"github.com/go-chassis/go-archaius"
+
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+const (
+	GroupRegistryRead  = "registryRead"
+	GroupRegistryWrite = "registryWrite"
+	GroupHeartbeat     = "heartbeat"
+	GroupWatch         = "watch"
+)
+
+const (
+	configPrefix = "ratelimit."
+	keyTenants   = "tenants"
+	keyProjects  = "projects"
+	keyGroups    = "groups"
+	keyQPS       = "qps"
+	keyBurst     = "burst"
+)
+
+// Limit is the token bucket config, QPS is the refill rate and Burst is the bucket size
+type Limit struct {
+	QPS   float64
+	Burst int
+}
+
+// BurstSize returns Burst, or the QPS rounded up if Burst is not set
+func (l *Limit) BurstSize() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return int(math.Max(1, math.Ceil(l.QPS)))
+}
+
+// Rule is the limit of all APIs and the limits of the API groups
+type Rule struct {
+	Limit  *Limit
+	Groups map[string]*Limit
+}
+
+// Rules is the global rule and the tenant rules overriding it,
+// the key of Tenants is domain or domain/project
+type Rules struct {
+	Global  *Rule
+	Tenants map[string]*Rule
+}
+
+// Resolve returns the limit of the domain/project and API group, nil means unlimited.
+// The project rule goes first, then the domain rule, at last the global rule,
+// in each rule the group limit goes before the limit of all APIs.
+// grouped is true if the limit is of the API group
+func (rs *Rules) Resolve(domain, project, group string) (limit *Limit, grouped bool) {
+	candidates := []*Rule{
+		rs.Tenants[util.StringJoin([]string{domain, project}, "/")],
+		rs.Tenants[domain],
+		rs.Global,
+	}
+	for _, rule := range candidates {
+		if rule == nil {
+			continue
+		}
+		if l, ok := rule.Groups[group]; ok && len(group) > 0 {
+			return l, true
+		}
+		if rule.Limit != nil {
+			return rule.Limit, false
+		}
+	}
+	return nil, false
+}
+
+// LoadRules loads the rules from config, the keys are like
+// ratelimit[.tenants.{domain}[.projects.{project}]][.groups.{group}].(qps|burst)
+func LoadRules() *Rules {
+	rules := &Rules{Global: &Rule{}, Tenants: make(map[string]*Rule)}
+	for key := range archaius.GetConfigs() {
+		if !strings.HasPrefix(key, configPrefix) {
+			continue
+		}
+		rules.set(strings.Split(strings.TrimPrefix(key, configPrefix), "."), key)
+	}
+	return rules
+}
+
+func (rs *Rules) set(parts []string, key string) {
+	rule := rs.Global
+	if parts[0] == keyTenants {
+		if len(parts) < 3 {
+			return
+		}
+		tenant := parts[1]
+		parts = parts[2:]
+		if parts[0] == keyProjects && len(parts) >= 3 {
+			tenant = util.StringJoin([]string{tenant, parts[1]}, "/")
+			parts = parts[2:]
+		}
+		rule = rs.Tenants[tenant]
+		if rule == nil {
+			rule = &Rule{}
+			rs.Tenants[tenant] = rule
+		}
+	}
+
+	field := parts[len(parts)-1]
+	if field != keyQPS && field != keyBurst {
+		return
+	}
+	var limit *Limit
+	switch {
+	case len(parts) == 1:
+		if rule.Limit == nil {
+			rule.Limit = &Limit{}
+		}
+		limit = rule.Limit
+	case len(parts) == 3 && parts[0] == keyGroups:
+		if rule.Groups == nil {
+			rule.Groups = make(map[string]*Limit)
+		}
+		if rule.Groups[parts[1]] == nil {
+			rule.Groups[parts[1]] = &Limit{}
+		}
+		limit = rule.Groups[parts[1]]
+	default:
+		return
+	}
+	if field == keyQPS {
+		limit.QPS = archaius.GetFloat64(key, 0)
+		return
+	}
+	limit.Burst = archaius.GetInt(key, 0)
+}
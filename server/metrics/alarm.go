@@ -0,0 +1,39 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/apache/servicecomb-service-center/pkg/metrics"
+	helper "github.com/apache/servicecomb-service-center/pkg/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var alarmStatus = helper.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: metrics.FamilyName,
+		Subsystem: "alarm",
+		Name:      "status",
+		Help:      "Gauge of the alarm status, 1 if the alarm is in the status, otherwise 0",
+	}, []string{"instance", "id", "status"})
+
+// ReportAlarmStatus sets whether the alarm is in the status
+func ReportAlarmStatus(id, status string, in bool) {
+	v := float64(0)
+	if in {
+		v = 1
+	}
+	alarmStatus.WithLabelValues(metrics.InstanceName(), id, status).Set(v)
+}
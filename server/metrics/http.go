@@ -65,6 +65,14 @@ var (
 			Name:      "query_per_seconds",
 			Help:      "HTTP requests per seconds of ROA handler",
 		}, []string{"method", "instance", "api", "domain"})
+
+	throttledRequests = helper.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.FamilyName,
+			Subsystem: "http",
+			Name:      "throttled_total",
+			Help:      "Counter of requests rejected by the tenant rate limit",
+		}, []string{"instance", "domain", "project", "group"})
 )
 
 func ReportRequestCompleted(w http.ResponseWriter, r *http.Request, start time.Time) {
@@ -93,3 +101,8 @@ func parseStatus(code int) (bool, string) {
 	}
 	return false, sz
 }
+
+// ReportThrottled counts the request rejected by the rate limit of the domain/project and API group
+func ReportThrottled(domain, project, group string) {
+	throttledRequests.WithLabelValues(metrics.InstanceName(), domain, project, group).Inc()
+}
@@ -18,17 +18,25 @@
 package auditlog
 
 import (
-	"net/http"
+	"context"
 
+	"github.com/apache/servicecomb-service-center/pkg/dump"
 	"github.com/apache/servicecomb-service-center/pkg/plugin"
 )
 
 const AUDITLOG plugin.Kind = "auditlog"
 
+// AuditLogger records the mutating API calls
 type AuditLogger interface {
-	Record(r *http.Request, responseHeaders http.Header)
+	Record(ctx context.Context, l *dump.AuditLog)
+	// Query returns the audit logs matched the filter, the latest first
+	Query(ctx context.Context, f *dump.AuditLogFilter) ([]*dump.AuditLog, error)
 }
 
-func Record(r *http.Request, responseHeaders http.Header) {
-	plugin.Plugins().Instance(AUDITLOG).(AuditLogger).Record(r, responseHeaders)
+func Record(ctx context.Context, l *dump.AuditLog) {
+	plugin.Plugins().Instance(AUDITLOG).(AuditLogger).Record(ctx, l)
+}
+
+func Query(ctx context.Context, f *dump.AuditLogFilter) ([]*dump.AuditLog, error) {
+	return plugin.Plugins().Instance(AUDITLOG).(AuditLogger).Query(ctx, f)
 }
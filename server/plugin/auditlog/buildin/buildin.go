@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/plugin"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog"
+)
+
+const (
+	defaultFile      = "./audit.log"
+	defaultRetention = 30 * 24 * time.Hour
+	clearInterval    = time.Hour
+)
+
+func init() {
+	plugin.RegisterPlugin(plugin.Plugin{Kind: auditlog.AUDITLOG, Name: "buildin", New: New})
+}
+
+func New() plugin.Instance {
+	file := os.ExpandEnv(config.GetString("auditlog.file", defaultFile))
+	l := NewAuditLogger(file, config.GetBool("auditlog.datasource.enable", false))
+	if l.datasource {
+		l.autoClear(config.GetDuration("auditlog.datasource.retention", defaultRetention))
+	}
+	log.Infof("audit log init, file: %s, datasource: %t", file, l.datasource)
+	return l
+}
+
+// AuditLogger writes the audit logs to a rotating JSON-lines file,
+// and to the datasource if enabled, the audit log file inherits log's
+// rotate and backup configuration
+type AuditLogger struct {
+	logger     *log.Logger
+	file       string
+	datasource bool
+}
+
+func NewAuditLogger(file string, ds bool) *AuditLogger {
+	return &AuditLogger{
+		logger: log.NewLogger(log.Config{
+			LoggerFile:     file,
+			LogFormatText:  true,
+			LogRotateSize:  int(config.GetLog().LogRotateSize),
+			LogBackupCount: int(config.GetLog().LogBackupCount),
+			NoCaller:       true,
+			NoTime:         true,
+			NoLevel:        true,
+		}),
+		file:       file,
+		datasource: ds,
+	}
+}
+
+func (a *AuditLogger) Record(ctx context.Context, l *dump.AuditLog) {
+	b, err := json.Marshal(l)
+	if err != nil {
+		log.Errorf(err, "audit log is invalid")
+		return
+	}
+	a.logger.Info(string(b))
+	if !a.datasource {
+		return
+	}
+	if err := datasource.GetAuditLogManager().AddAuditLog(ctx, l); err != nil {
+		log.Errorf(err, "save audit log [%s] failed", l.ID)
+	}
+}
+
+// Query returns the audit logs from the datasource if enabled,
+// otherwise from the current audit log file, the rotated files are excluded
+func (a *AuditLogger) Query(ctx context.Context, f *dump.AuditLogFilter) ([]*dump.AuditLog, error) {
+	if a.datasource {
+		return datasource.GetAuditLogManager().ListAuditLog(ctx, f)
+	}
+	return a.queryFile(f)
+}
+
+func (a *AuditLogger) queryFile(f *dump.AuditLogFilter) ([]*dump.AuditLog, error) {
+	fd, err := os.Open(a.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		log.Errorf(err, "open audit log file %s failed", a.file)
+		return nil, err
+	}
+	defer fd.Close()
+
+	var logs []*dump.AuditLog
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		l := &dump.AuditLog{}
+		if err := json.Unmarshal(scanner.Bytes(), l); err != nil {
+			continue
+		}
+		if f.Match(l) {
+			logs = append(logs, l)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Errorf(err, "read audit log file %s failed", a.file)
+		return nil, err
+	}
+	// the file is in time order, return the latest first
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+	if f.Limit > 0 && len(logs) > f.Limit {
+		logs = logs[:f.Limit]
+	}
+	return logs, nil
+}
+
+// autoClear removes the audit logs older than the retention from the datasource
+func (a *AuditLogger) autoClear(retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	gopool.Go(func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(clearInterval):
+				before := time.Now().Add(-retention).UnixNano() / int64(time.Millisecond)
+				if err := datasource.GetAuditLogManager().DeleteAuditLog(ctx, before); err != nil {
+					log.Errorf(err, "clear audit logs before %d failed", before)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildin_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog/buildin"
+)
+
+func TestAuditLogger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditlog")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	l := buildin.NewAuditLogger(filepath.Join(dir, "audit.log"), false)
+	t.Run("query empty file, should return empty", func(t *testing.T) {
+		logs, err := l.Query(ctx, &dump.AuditLogFilter{})
+		assert.NoError(t, err)
+		assert.Empty(t, logs)
+	})
+
+	l1 := &dump.AuditLog{ID: "1", Timestamp: 1000, Actor: "root", Verb: "create", Code: 200}
+	l2 := &dump.AuditLog{ID: "2", Timestamp: 2000, Actor: "dev", Verb: "delete", Code: 403}
+	l3 := &dump.AuditLog{ID: "3", Timestamp: 3000, Actor: "root", Verb: "update", Code: 200}
+	for _, item := range []*dump.AuditLog{l1, l2, l3} {
+		l.Record(ctx, item)
+	}
+	t.Run("query all, should return the latest first", func(t *testing.T) {
+		logs, err := l.Query(ctx, &dump.AuditLogFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*dump.AuditLog{l3, l2, l1}, logs)
+	})
+	t.Run("query by time range and actor, should return matched", func(t *testing.T) {
+		logs, err := l.Query(ctx, &dump.AuditLogFilter{Start: 1500, Actor: "root"})
+		assert.NoError(t, err)
+		assert.Equal(t, []*dump.AuditLog{l3}, logs)
+
+		logs, err = l.Query(ctx, &dump.AuditLogFilter{End: 2000, Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []*dump.AuditLog{l2}, logs)
+	})
+}
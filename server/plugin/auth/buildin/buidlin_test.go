@@ -20,10 +20,18 @@ package buildin_test
 // initialize
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
@@ -35,6 +43,7 @@ import (
 	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
 	_ "github.com/apache/servicecomb-service-center/test"
 	"github.com/astaxie/beego"
+	"github.com/dgrijalva/jwt-go"
 	carirbac "github.com/go-chassis/cari/rbac"
 	"github.com/go-chassis/go-archaius"
 	"github.com/go-chassis/go-chassis/v2/security/authr"
@@ -145,6 +154,60 @@ func TestTokenAuthenticator_Identify(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("oidc token with forged local claims, should not be able to create root api key", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		dir, err := ioutil.TempDir("", "oidc")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		jwks := filepath.Join(dir, "jwks.json")
+		b, err := json.Marshal(rbacsvc.JSONWebKeySet{Keys: []rbacsvc.JSONWebKey{{
+			Kty: "RSA", Kid: "rsa1", Use: "sig",
+			N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+		assert.NoError(t, err)
+		assert.NoError(t, ioutil.WriteFile(jwks, b, 0600))
+
+		origin := config.App.RBAC
+		config.App.RBAC = &config.RBAC{OIDC: &config.OIDC{
+			Issuer: "https://idp.example.com",
+			JWKS:   jwks,
+			RoleMappings: []config.RoleMapping{
+				{Claim: "groups", Value: "sc-developers", Roles: []string{rbac.RoleDeveloper}},
+			},
+		}}
+		assert.NoError(t, authr.Init(authr.WithPlugin(rbacsvc.AuthenticatorOIDC)))
+		defer func() {
+			config.App.RBAC = origin
+			assert.NoError(t, authr.Init(authr.WithPlugin("default")))
+		}()
+
+		to := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":                         "https://idp.example.com",
+			"sub":                         "root",
+			"groups":                      "sc-developers",
+			"exp":                         time.Now().Add(time.Hour).Unix(),
+			rbacsvc.ClaimsSource:          rbacsvc.SourceLocal,
+			rbacsvc.ClaimsTokenID:         "forged",
+			rbacsvc.ClaimsTOTPRequired:    false,
+			rbacsvc.ClaimsIssuedAt:        time.Now().UnixNano() / int64(time.Millisecond),
+			rbacsvc.ClaimsPasswordExpired: false,
+		})
+		to.Header["kid"] = "rsa1"
+		s, err := to.SignedString(key)
+		assert.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/v4/accounts/root/apikeys", nil)
+		v := r.URL.Query()
+		v.Set(":name", "root")
+		r.URL.RawQuery = v.Encode()
+		util.SetRequestContext(r, rest.CtxMatchPattern, rbacsvc.APIAccountAPIKeys)
+		r.Header.Set(restful.HeaderAuth, "Bear "+s)
+		err = ta.Identify(r)
+		assert.Error(t, err)
+	})
+
 	t.Run("TestTokenAuthenticator_ResourceScopes", func(t *testing.T) {
 		url := "/v4/accounts/:name"
 		a := buildin.New()
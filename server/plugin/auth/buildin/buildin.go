@@ -24,6 +24,7 @@ import (
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/plugin"
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	authHandler "github.com/apache/servicecomb-service-center/server/handler/auth"
@@ -59,9 +60,9 @@ func (ba *TokenAuthenticator) Identify(req *http.Request) error {
 		return nil
 	}
 
-	claims, err := ba.VerifyToken(req)
+	claims, err := ba.verifyCredential(req)
 	if err != nil {
-		log.Errorf(err, "verify request token failed, %s %s", req.Method, req.RequestURI)
+		log.Errorf(err, "verify request credential failed, %s %s", req.Method, req.RequestURI)
 		return err
 	}
 
@@ -80,6 +81,25 @@ func (ba *TokenAuthenticator) Identify(req *http.Request) error {
 	if isChangeSelfPassword(pattern, account, req) {
 		return nil
 	}
+	// the expired password must be changed before any other operation
+	if passwordExpired, _ := m[rbacsvc.ClaimsPasswordExpired].(bool); passwordExpired {
+		return rbac.NewError(rbacsvc.ErrPasswordExpired, "")
+	}
+	// the account of the enforced roles must enroll totp before any other operation
+	if totpRequired, _ := m[rbacsvc.ClaimsTOTPRequired].(bool); totpRequired {
+		if strings.HasPrefix(pattern, rbacsvc.APIAccountTOTP) && isManageSelfCredential(pattern, account, m, req) {
+			return nil
+		}
+		return rbac.NewError(rbacsvc.ErrTOTPEnrollRequired, "")
+	}
+	// user can manage self api keys, sessions and totp, except by an api key
+	if isManageSelfCredential(pattern, account, m, req) {
+		return nil
+	}
+	// user can check self permissions
+	if pattern == rbacsvc.APIPermissionCheck {
+		return nil
+	}
 
 	if len(account.Roles) == 0 {
 		log.Error("no role found in token", nil)
@@ -108,6 +128,20 @@ func isChangeSelfPassword(pattern string, a *rbac.Account, req *http.Request) bo
 	return changerName == targetName
 }
 
+func isManageSelfCredential(pattern string, a *rbac.Account, claims map[string]interface{}, req *http.Request) bool {
+	if !strings.HasPrefix(pattern, rbacsvc.APIAccountAPIKeys) &&
+		!strings.HasPrefix(pattern, rbacsvc.APIAccountSessions) &&
+		!strings.HasPrefix(pattern, rbacsvc.APIAccountTOTP) {
+		return false
+	}
+	if a.Name != req.URL.Query().Get(":name") {
+		return false
+	}
+	// only the session token signed for the local account can manage its credentials,
+	// the api key, oidc and ldap identities may share the name with a local account
+	return rbacsvc.IsLocalSession(req.Context(), claims)
+}
+
 func filterRoles(roleList []string) (hasAdmin bool, normalRoles []string) {
 	for _, r := range roleList {
 		if r == rbac.RoleAdmin {
@@ -119,6 +153,14 @@ func filterRoles(roleList []string) (hasAdmin bool, normalRoles []string) {
 	return
 }
 
+// verifyCredential verifies the api key header if present, otherwise the token
+func (ba *TokenAuthenticator) verifyCredential(req *http.Request) (interface{}, error) {
+	if key := req.Header.Get(rbacpkg.HeaderAPIKey); len(key) > 0 {
+		return rbacsvc.AuthenticateAPIKey(req.Context(), key)
+	}
+	return ba.VerifyToken(req)
+}
+
 func (ba *TokenAuthenticator) VerifyToken(req *http.Request) (interface{}, error) {
 	v := req.Header.Get(restful.HeaderAuth)
 	if v == "" {
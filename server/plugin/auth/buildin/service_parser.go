@@ -54,6 +54,8 @@ var (
 	APIDiscovery = "/v4/:project/registry/instances"
 	// APIBatchDiscovery Apply by request body
 	APIBatchDiscovery = "/v4/:project/registry/instances/action"
+	// APIServiceApply Apply by the service in request body
+	APIServiceApply = "/v4/:project/registry/apply"
 	// APIHeartbeats Apply by request body
 	APIHeartbeats = "/v4/:project/registry/heartbeats"
 	// APIGovServicesList Apply by optional service key
@@ -78,6 +80,7 @@ func init() {
 	RegisterParseFunc(APIServiceExistence, ByServiceKey)
 	RegisterParseFunc(APIGovServicesList, ApplyAll)
 	RegisterParseFunc(APIServicesList, ByRequestBody)
+	RegisterParseFunc(APIServiceApply, ByRequestBody)
 	RegisterParseFunc(APIBatchDiscovery, ByDiscoveryRequestBody)
 	RegisterParseFunc(APIHeartbeats, ByHeartbeatRequestBody)
 }
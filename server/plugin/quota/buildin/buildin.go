@@ -19,9 +19,12 @@ package buildin
 
 import (
 	"context"
+	"errors"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/plugin"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/plugin/quota"
 )
 
@@ -42,7 +45,12 @@ func New() plugin.Instance {
 type Quota struct {
 }
 
+// GetQuota returns the limit overridden by the project of ctx first,
+// then by the domain, at last the global default
 func (q *Quota) GetQuota(ctx context.Context, t quota.ResourceType) int64 {
+	if limit, ok := getOverrideQuota(ctx, t); ok {
+		return limit
+	}
 	switch t {
 	case quota.TypeInstance:
 		return int64(quota.DefaultInstanceQuota)
@@ -63,6 +71,30 @@ func (q *Quota) GetQuota(ctx context.Context, t quota.ResourceType) int64 {
 	}
 }
 
+func getOverrideQuota(ctx context.Context, t quota.ResourceType) (int64, bool) {
+	domain, project := util.ParseDomain(ctx), util.ParseProject(ctx)
+	if len(domain) == 0 {
+		return 0, false
+	}
+	projects := []string{""}
+	if len(project) > 0 {
+		projects = []string{project, ""}
+	}
+	for _, p := range projects {
+		override, err := datasource.GetCachedQuotaOverride(ctx, domain, p)
+		if err != nil {
+			if !errors.Is(err, datasource.ErrQuotaOverrideNotExist) {
+				log.Errorf(err, "get quota override of %s/%s failed, fallback", domain, p)
+			}
+			continue
+		}
+		if limit, ok := override.Limits[t.Name()]; ok {
+			return limit, true
+		}
+	}
+	return 0, false
+}
+
 //向配额中心上报配额使用量
 func (q *Quota) RemandQuotas(ctx context.Context, quotaType quota.ResourceType) {
 	df, ok := plugin.DynamicPluginFunc(quota.QUOTA, "RemandQuotas").(func(context.Context, quota.ResourceType))
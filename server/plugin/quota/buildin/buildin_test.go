@@ -29,6 +29,7 @@ import (
 
 	_ "github.com/apache/servicecomb-service-center/server/bootstrap"
 	"github.com/apache/servicecomb-service-center/server/plugin/quota"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota/buildin"
 	pb "github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/go-archaius"
 	"github.com/stretchr/testify/assert"
@@ -84,3 +85,41 @@ func TestGetResourceLimit(t *testing.T) {
 	})
 
 }
+
+func TestGetQuotaOverride(t *testing.T) {
+	ctx := util.SetDomainProject(context.TODO(), "quota-override", "quota-override")
+	q := &buildin.Quota{}
+	defer func() {
+		_ = datasource.GetQuotaOverrideManager().DeleteQuotaOverride(ctx, "quota-override", "")
+		_ = datasource.GetQuotaOverrideManager().DeleteQuotaOverride(ctx, "quota-override", "quota-override")
+	}()
+
+	t.Run("no override, should return global default", func(t *testing.T) {
+		assert.Equal(t, int64(quota.DefaultServiceQuota), q.GetQuota(ctx, quota.TypeService))
+	})
+	t.Run("domain override, should return domain limit", func(t *testing.T) {
+		err := datasource.GetQuotaOverrideManager().UpsertQuotaOverride(ctx, &datasource.QuotaOverride{
+			Domain: "quota-override",
+			Limits: map[string]int64{"service": 10, "instance": 100},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(quota.DefaultServiceQuota), q.GetQuota(ctx, quota.TypeService), "cached")
+		datasource.InvalidateQuotaOverride("quota-override", "")
+		assert.Equal(t, int64(10), q.GetQuota(ctx, quota.TypeService))
+		assert.Equal(t, int64(quota.DefaultSchemaQuota), q.GetQuota(ctx, quota.TypeSchema))
+	})
+	t.Run("project override, should return project limit first", func(t *testing.T) {
+		err := datasource.GetQuotaOverrideManager().UpsertQuotaOverride(ctx, &datasource.QuotaOverride{
+			Domain:  "quota-override",
+			Project: "quota-override",
+			Limits:  map[string]int64{"service": 5},
+		})
+		assert.NoError(t, err)
+		datasource.InvalidateQuotaOverride("quota-override", "quota-override")
+		assert.Equal(t, int64(5), q.GetQuota(ctx, quota.TypeService))
+		assert.Equal(t, int64(100), q.GetQuota(ctx, quota.TypeInstance))
+
+		err = quota.Apply(ctx, quota.NewApplyQuotaResource(quota.TypeService, "quota-override/quota-override", "", 6))
+		assert.NotNil(t, err)
+	})
+}
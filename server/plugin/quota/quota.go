@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/plugin"
@@ -54,6 +55,9 @@ const (
 	TypeRole
 )
 
+// Types is all the resource types limited by quota
+var Types = []ResourceType{TypeService, TypeInstance, TypeSchema, TypeTag, TypeRule, TypeAccount, TypeRole}
+
 var (
 	DefaultServiceQuota  = defaultServiceLimit
 	DefaultInstanceQuota = defaultInstanceLimit
@@ -118,6 +122,66 @@ func (r ResourceType) String() string {
 	}
 }
 
+// Name returns the lower case name of the resource type,
+// which is the same as the name in config 'quota.cap.<name>.limit'
+func (r ResourceType) Name() string {
+	return strings.ToLower(r.String())
+}
+
+// ParseResourceType returns the resource type of the name
+func ParseResourceType(name string) (ResourceType, bool) {
+	for _, t := range Types {
+		if t.Name() == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// PerService returns true if the resource is limited per service
+func (r ResourceType) PerService() bool {
+	switch r {
+	case TypeSchema, TypeTag, TypeRule:
+		return true
+	default:
+		return false
+	}
+}
+
+// ResourceUsage is the current usage of the resource versus the limit
+type ResourceUsage struct {
+	Resource string `json:"resource"`
+	Limit    int64  `json:"limit"`
+	Used     int64  `json:"used"`
+}
+
+// GetUsage returns the usage of all resource types in the domain project of ctx,
+// the per service resources are returned only if serviceID is not empty
+func GetUsage(ctx context.Context, serviceID string) ([]*ResourceUsage, error) {
+	manager := plugin.Plugins().Instance(QUOTA).(Manager)
+	usages := make([]*ResourceUsage, 0, len(Types))
+	for _, t := range Types {
+		if t.PerService() && len(serviceID) == 0 {
+			continue
+		}
+		used, err := GetResourceUsage(ctx, &ApplyQuotaResource{
+			QuotaType:     t,
+			DomainProject: util.ParseDomainProject(ctx),
+			ServiceID:     serviceID,
+		})
+		if err != nil {
+			log.Errorf(err, "get %s usage failed", t)
+			return nil, err
+		}
+		usages = append(usages, &ResourceUsage{
+			Resource: t.Name(),
+			Limit:    manager.GetQuota(ctx, t),
+			Used:     used,
+		})
+	}
+	return usages, nil
+}
+
 //申请配额sourceType serviceinstance servicetype
 func Apply(ctx context.Context, res *ApplyQuotaResource) *errsvc.Error {
 	if res == nil {
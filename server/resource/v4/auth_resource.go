@@ -21,15 +21,19 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/pkg/errsvc"
 	"github.com/go-chassis/cari/rbac"
 	"github.com/go-chassis/go-chassis/v2/security/authr"
+	"github.com/go-chassis/go-chassis/v2/server/restful"
 
 	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
 	"github.com/apache/servicecomb-service-center/server/service/validator"
 )
@@ -43,12 +47,25 @@ type AuthResource struct {
 func (ar *AuthResource) URLPatterns() []rest.Route {
 	return []rest.Route{
 		{Method: http.MethodPost, Path: "/v4/token", Func: ar.Login},
+		{Method: http.MethodDelete, Path: "/v4/token", Func: ar.Logout},
 		{Method: http.MethodPost, Path: "/v4/accounts", Func: ar.CreateAccount},
 		{Method: http.MethodGet, Path: "/v4/accounts", Func: ar.ListAccount},
 		{Method: http.MethodGet, Path: "/v4/accounts/:name", Func: ar.GetAccount},
 		{Method: http.MethodDelete, Path: "/v4/accounts/:name", Func: ar.DeleteAccount},
 		{Method: http.MethodPut, Path: "/v4/accounts/:name", Func: ar.UpdateAccount},
 		{Method: http.MethodPost, Path: "/v4/accounts/:name/password", Func: ar.ChangePassword},
+		{Method: http.MethodPost, Path: "/v4/accounts/:name/apikeys", Func: ar.CreateAPIKey},
+		{Method: http.MethodGet, Path: "/v4/accounts/:name/apikeys", Func: ar.ListAPIKey},
+		{Method: http.MethodPost, Path: "/v4/accounts/:name/apikeys/:id/rotate", Func: ar.RotateAPIKey},
+		{Method: http.MethodDelete, Path: "/v4/accounts/:name/apikeys/:id", Func: ar.RevokeAPIKey},
+		{Method: http.MethodGet, Path: "/v4/accounts/:name/sessions", Func: ar.ListSession},
+		{Method: http.MethodDelete, Path: "/v4/accounts/:name/sessions", Func: ar.RevokeAccountSessions},
+		{Method: http.MethodDelete, Path: "/v4/accounts/:name/sessions/:id", Func: ar.RevokeSession},
+		{Method: http.MethodPost, Path: "/v4/accounts/:name/totp", Func: ar.EnrollTOTP},
+		{Method: http.MethodGet, Path: "/v4/accounts/:name/totp", Func: ar.GetTOTPStatus},
+		{Method: http.MethodPost, Path: "/v4/accounts/:name/totp/verify", Func: ar.ConfirmTOTP},
+		{Method: http.MethodDelete, Path: "/v4/accounts/:name/totp", Func: ar.DisableTOTP},
+		{Method: http.MethodPost, Path: "/v4/auth/permissions/check", Func: ar.CheckPermission},
 	}
 }
 
@@ -178,14 +195,168 @@ func (ar *AuthResource) Login(w http.ResponseWriter, r *http.Request) {
 		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
 		return
 	}
-	t, err := authr.Login(r.Context(), a.Name, a.Password,
+	second := &rbacpkg.TOTPLogin{}
+	if err = json.Unmarshal(body, second); err != nil {
+		log.Error("json err", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	ctx := util.SetContext(r.Context(), rbacsvc.CtxTOTPCode, second.Code)
+	t, err := authr.Login(ctx, a.Name, a.Password,
 		authr.ExpireAfter(a.TokenExpirationTime))
 	if err != nil {
 		log.Error("not authorized", err)
 		writeErrsvcOrInternalErr(w, err)
 		return
 	}
-	rest.WriteResponse(w, r, nil, &rbac.Token{TokenStr: t})
+	resp := &rbacpkg.Token{TokenStr: t}
+	if days, ok := rbacsvc.PasswordRemainingDays(r.Context(), a.Name); ok {
+		resp.PasswordExpireDays = &days
+	}
+	rest.WriteResponse(w, r, nil, resp)
+}
+
+//Logout revokes the token of the request
+func (ar *AuthResource) Logout(w http.ResponseWriter, r *http.Request) {
+	s := strings.Split(r.Header.Get(restful.HeaderAuth), " ")
+	if len(s) != 2 {
+		rest.WriteErrsvcError(w, rbac.NewError(rbac.ErrNoAuthHeader, ""))
+		return
+	}
+	claims, err := authr.Authenticate(r.Context(), s[1])
+	if err != nil {
+		log.Error("logout failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	m, ok := claims.(map[string]interface{})
+	if !ok {
+		rest.WriteError(w, discovery.ErrInternal, rbac.MsgConvertErr)
+		return
+	}
+	if err = rbacsvc.RevokeToken(r.Context(), m); err != nil {
+		log.Error("logout failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteSuccess(w, r)
+}
+
+func (ar *AuthResource) ListSession(w http.ResponseWriter, r *http.Request) {
+	sessions, err := rbacsvc.ListSession(r.Context(), r.URL.Query().Get(":name"))
+	if err != nil {
+		log.Error("list sessions failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, &rbacpkg.SessionResponse{
+		Total:    int64(len(sessions)),
+		Sessions: sessions,
+	})
+}
+
+func (ar *AuthResource) RevokeAccountSessions(w http.ResponseWriter, r *http.Request) {
+	err := rbacsvc.RevokeAccountSessions(r.Context(), r.URL.Query().Get(":name"))
+	if err != nil {
+		log.Error("revoke sessions failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteSuccess(w, r)
+}
+
+func (ar *AuthResource) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	err := rbacsvc.RevokeSession(r.Context(), query.Get(":name"), query.Get(":id"))
+	if err != nil {
+		log.Error("revoke session failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteSuccess(w, r)
+}
+
+func (ar *AuthResource) CheckPermission(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	in := &rbacpkg.PermissionCheckRequest{}
+	if err = json.Unmarshal(body, in); err != nil {
+		log.Error("json err", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
+		return
+	}
+	if !rbacsvc.Enabled() {
+		rest.WriteResponse(w, r, nil, &rbacpkg.PermissionCheckResponse{Allowed: true})
+		return
+	}
+	resp, err := rbacsvc.CheckPermission(r.Context(), in)
+	if err != nil {
+		log.Error("check permission failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, r, nil, resp)
+}
+
+func (ar *AuthResource) CreateAPIKey(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	in := &rbacpkg.APIKeyRequest{}
+	if err = json.Unmarshal(body, in); err != nil {
+		log.Error("json err", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
+		return
+	}
+	k, err := rbacsvc.CreateAPIKey(req.Context(), req.URL.Query().Get(":name"), in)
+	if err != nil {
+		log.Error("create api key failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, req, nil, k)
+}
+
+func (ar *AuthResource) ListAPIKey(w http.ResponseWriter, req *http.Request) {
+	keys, err := rbacsvc.ListAPIKey(req.Context(), req.URL.Query().Get(":name"))
+	if err != nil {
+		log.Error("list api keys failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, req, nil, &rbacpkg.APIKeyResponse{
+		Total:   int64(len(keys)),
+		APIKeys: keys,
+	})
+}
+
+func (ar *AuthResource) RotateAPIKey(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	k, err := rbacsvc.RotateAPIKey(req.Context(), query.Get(":name"), query.Get(":id"))
+	if err != nil {
+		log.Error("rotate api key failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, req, nil, k)
+}
+
+func (ar *AuthResource) RevokeAPIKey(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	err := rbacsvc.RevokeAPIKey(req.Context(), query.Get(":name"), query.Get(":id"))
+	if err != nil {
+		log.Error("revoke api key failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteSuccess(w, req)
 }
 
 func MakeBanKey(name, ip string) string {
@@ -200,3 +371,55 @@ func writeErrsvcOrInternalErr(w http.ResponseWriter, err error) {
 	}
 	rest.WriteError(w, discovery.ErrInternal, err.Error())
 }
+
+func (ar *AuthResource) EnrollTOTP(w http.ResponseWriter, req *http.Request) {
+	e, err := rbacsvc.EnrollTOTP(req.Context(), req.URL.Query().Get(":name"))
+	if err != nil {
+		log.Error("enroll totp failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, req, nil, e)
+}
+
+func (ar *AuthResource) GetTOTPStatus(w http.ResponseWriter, req *http.Request) {
+	status, err := rbacsvc.GetTOTPStatus(req.Context(), req.URL.Query().Get(":name"))
+	if err != nil {
+		log.Error("get totp failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteResponse(w, req, nil, status)
+}
+
+func (ar *AuthResource) ConfirmTOTP(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		log.Error("read body err", err)
+		rest.WriteError(w, discovery.ErrInternal, err.Error())
+		return
+	}
+	in := &rbacpkg.TOTPRequest{}
+	if err = json.Unmarshal(body, in); err != nil {
+		log.Error("json err", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
+		return
+	}
+	err = rbacsvc.ConfirmTOTP(req.Context(), req.URL.Query().Get(":name"), in.Code)
+	if err != nil {
+		log.Error("verify totp failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteSuccess(w, req)
+}
+
+func (ar *AuthResource) DisableTOTP(w http.ResponseWriter, req *http.Request) {
+	err := rbacsvc.DisableTOTP(req.Context(), req.URL.Query().Get(":name"))
+	if err != nil {
+		log.Error("disable totp failed", err)
+		writeErrsvcOrInternalErr(w, err)
+		return
+	}
+	rest.WriteSuccess(w, req)
+}
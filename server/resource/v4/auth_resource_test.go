@@ -29,6 +29,7 @@ import (
 
 	rbacmodel "github.com/go-chassis/cari/rbac"
 
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/server/config"
 	v4 "github.com/apache/servicecomb-service-center/server/resource/v4"
@@ -397,3 +398,81 @@ func BenchmarkAuthResource_Login(b *testing.B) {
 	}
 	b.ReportAllocs()
 }
+
+func TestAuthResource_CheckPermission(t *testing.T) {
+	rbacsvc.DeleteAccount(context.TODO(), "check_account")
+	rootToken := login(t, "root", pwd)
+
+	b, _ := json.Marshal(&rbacmodel.Account{Name: "check_account", Password: pwd, Roles: []string{"developer"}})
+	r, _ := http.NewRequest(http.MethodPost, "/v4/accounts", bytes.NewBuffer(b))
+	r.Header.Set(restful.HeaderAuth, "Bearer "+rootToken)
+	w := httptest.NewRecorder()
+	rest.GetRouter().ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	devToken := login(t, "check_account", pwd)
+
+	check := func(token string, in *rbacpkg.PermissionCheckRequest) (int, *rbacpkg.PermissionCheckResponse) {
+		b, _ := json.Marshal(in)
+		r, _ := http.NewRequest(http.MethodPost, "/v4/auth/permissions/check", bytes.NewBuffer(b))
+		if len(token) > 0 {
+			r.Header.Set(restful.HeaderAuth, "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		rest.GetRouter().ServeHTTP(w, r)
+		resp := &rbacpkg.PermissionCheckResponse{}
+		json.Unmarshal(w.Body.Bytes(), resp)
+		return w.Code, resp
+	}
+
+	t.Run("admin check self permission, should be allowed", func(t *testing.T) {
+		code, resp := check(rootToken, &rbacpkg.PermissionCheckRequest{Resource: rbacsvc.ResourceAccount, Verb: "create"})
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, resp.Allowed)
+		assert.Equal(t, rbacmodel.RoleAdmin, resp.Role)
+	})
+	t.Run("admin check the other account permission, should return the granted role", func(t *testing.T) {
+		code, resp := check(rootToken, &rbacpkg.PermissionCheckRequest{Account: "check_account",
+			Resource: rbacsvc.ResourceService, Verb: "delete", Labels: map[string]string{"app": "x"}})
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, resp.Allowed)
+		assert.Equal(t, "check_account", resp.Account)
+		assert.Equal(t, "developer", resp.Role)
+		assert.NotNil(t, resp.Permission)
+	})
+	t.Run("developer check self account permission, should be denied", func(t *testing.T) {
+		code, resp := check(devToken, &rbacpkg.PermissionCheckRequest{Resource: rbacsvc.ResourceAccount, Verb: "create"})
+		assert.Equal(t, http.StatusOK, code)
+		assert.False(t, resp.Allowed)
+		assert.Empty(t, resp.Role)
+		assert.NotEmpty(t, resp.Reason)
+	})
+	t.Run("developer check the other account permission, should be forbidden", func(t *testing.T) {
+		code, _ := check(devToken, &rbacpkg.PermissionCheckRequest{Account: "root",
+			Resource: rbacsvc.ResourceAccount, Verb: "get"})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+	t.Run("check not exist account permission, should fail", func(t *testing.T) {
+		code, _ := check(rootToken, &rbacpkg.PermissionCheckRequest{Account: "not_exist_account",
+			Resource: rbacsvc.ResourceAccount, Verb: "get"})
+		assert.NotEqual(t, http.StatusOK, code)
+	})
+	t.Run("check permission without verb, should fail", func(t *testing.T) {
+		code, _ := check(rootToken, &rbacpkg.PermissionCheckRequest{Resource: rbacsvc.ResourceAccount})
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+	t.Run("check permission without token, should fail", func(t *testing.T) {
+		code, _ := check("", &rbacpkg.PermissionCheckRequest{Resource: rbacsvc.ResourceAccount, Verb: "get"})
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
+
+func login(t *testing.T, name, password string) string {
+	b, _ := json.Marshal(&rbacmodel.Account{Name: name, Password: password})
+	r, _ := http.NewRequest(http.MethodPost, "/v4/token", bytes.NewBuffer(b))
+	w := httptest.NewRecorder()
+	rest.GetRouter().ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	to := &rbacmodel.Token{}
+	json.Unmarshal(w.Body.Bytes(), to)
+	return to.TokenStr
+}
@@ -23,10 +23,10 @@ import (
 	"net/http"
 
 	"github.com/go-chassis/cari/discovery"
-	"github.com/go-chassis/cari/rbac"
 
 	errorsEx "github.com/apache/servicecomb-service-center/pkg/errors"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
 )
@@ -49,13 +49,13 @@ func (rr *RoleResource) URLPatterns() []rest.Route {
 
 //ListRoles list all roles and there's permissions
 func (rr *RoleResource) ListRoles(w http.ResponseWriter, req *http.Request) {
-	rs, num, err := rbacsvc.ListRole(req.Context())
+	rs, num, err := rbacsvc.ListRoleWithParents(req.Context())
 	if err != nil {
 		log.Error(errorsEx.MsgGetRoleFailed, err)
 		rest.WriteError(w, discovery.ErrInternal, errorsEx.MsgGetRoleFailed)
 		return
 	}
-	resp := &rbac.RoleResponse{
+	resp := &rbacpkg.RoleResponse{
 		Total: num,
 		Roles: rs,
 	}
@@ -63,8 +63,8 @@ func (rr *RoleResource) ListRoles(w http.ResponseWriter, req *http.Request) {
 }
 
 //roleParse parse the role info from the request body
-func (rr *RoleResource) roleParse(body []byte) (*rbac.Role, error) {
-	role := &rbac.Role{}
+func (rr *RoleResource) roleParse(body []byte) (*rbacpkg.Role, error) {
+	role := &rbacpkg.Role{}
 	err := json.Unmarshal(body, role)
 	if err != nil {
 		log.Error("json err", err)
@@ -88,7 +88,7 @@ func (rr *RoleResource) CreateRole(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err = rbacsvc.CreateRole(req.Context(), role)
+	err = rbacsvc.CreateRole(req.Context(), &role.Role, role.Parents...)
 	if err != nil {
 		log.Error(errorsEx.MsgOperateRoleFailed, err)
 		writeErrsvcOrInternalErr(w, err)
@@ -111,7 +111,7 @@ func (rr *RoleResource) UpdateRole(w http.ResponseWriter, req *http.Request) {
 		rest.WriteError(w, discovery.ErrInvalidParams, errorsEx.MsgJSON)
 		return
 	}
-	err = rbacsvc.EditRole(req.Context(), name, role)
+	err = rbacsvc.EditRole(req.Context(), name, &role.Role, role.Parents...)
 	if err != nil {
 		log.Error(errorsEx.MsgOperateRoleFailed, err)
 		writeErrsvcOrInternalErr(w, err)
@@ -123,7 +123,7 @@ func (rr *RoleResource) UpdateRole(w http.ResponseWriter, req *http.Request) {
 
 //GetRole get the role info according to role name
 func (rr *RoleResource) GetRole(w http.ResponseWriter, r *http.Request) {
-	resp, err := rbacsvc.GetRole(r.Context(), r.URL.Query().Get(":roleName"))
+	resp, err := rbacsvc.GetRoleWithParents(r.Context(), r.URL.Query().Get(":roleName"))
 	if err != nil {
 		log.Error(errorsEx.MsgGetRoleFailed, err)
 		writeErrsvcOrInternalErr(w, err)
@@ -42,10 +42,10 @@ func MicroserviceListFilter(obj interface{}, labels []map[string]string) interfa
 }
 
 func matchOne(service *discovery.MicroService, labels map[string]string) bool {
-	if env, ok := labels["environment"]; ok && service.Environment != env {
+	if env, ok := labels["environment"]; ok && !util.WildcardMatch(env, service.Environment) {
 		return false
 	}
-	if app, ok := labels["appId"]; ok && service.AppId != app {
+	if app, ok := labels["appId"]; ok && !util.WildcardMatch(app, service.AppId) {
 		return false
 	}
 	if name, ok := labels["serviceName"]; ok && !util.WildcardMatch(name, service.ServiceName) {
@@ -113,7 +113,7 @@ func AppIDListFilter(obj interface{}, labelsList []map[string]string) interface{
 	var apps []string
 	for _, appID := range appsResponse.AppIds {
 		for _, labels := range labelsList {
-			if app, ok := labels["appId"]; ok && appID != app {
+			if app, ok := labels["appId"]; ok && !util.WildcardMatch(app, appID) {
 				continue
 			}
 			apps = append(apps, appID)
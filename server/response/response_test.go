@@ -147,4 +147,26 @@ func TestMicroserviceListFilter(t *testing.T) {
 		assert.Equal(t, 1, len(mss))
 		assert.Equal(t, "TestA", mss[0].ServiceName)
 	})
+
+	t.Run("wildcard match appId & environment, should return matched resources", func(t *testing.T) {
+		rs := response.MicroserviceListFilter(&discovery.GetServicesResponse{
+			Services: []*discovery.MicroService{
+				{AppId: "order-app", Environment: "production", ServiceName: "A"},
+				{AppId: "order-app", Environment: "testing", ServiceName: "B"},
+				{AppId: "user-app", Environment: "production", ServiceName: "C"},
+			},
+		}, []map[string]string{{"appId": "order-*", "environment": "prod*"}})
+		mss := rs.(*discovery.GetServicesResponse).Services
+		assert.Equal(t, 1, len(mss))
+		assert.Equal(t, "A", mss[0].ServiceName)
+	})
+}
+
+func TestAppIDListFilter(t *testing.T) {
+	t.Run("wildcard match appId, should return matched appIds", func(t *testing.T) {
+		rs := response.AppIDListFilter(&discovery.GetAppsResponse{
+			AppIds: []string{"order-app", "order-admin", "user-app"},
+		}, []map[string]string{{"appId": "order-*"}})
+		assert.Equal(t, []string{"order-app", "order-admin"}, rs.(*discovery.GetAppsResponse).AppIds)
+	})
 }
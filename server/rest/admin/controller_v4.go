@@ -18,15 +18,25 @@
 package admin
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
-
-	"github.com/apache/servicecomb-service-center/pkg/dump"
-
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/apache/servicecomb-service-center/pkg/dump"
+	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/alarm/model"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/go-chassis/cari/discovery"
 )
 
+const defaultAuditLogLimit = 100
+
 // Service 治理相关接口服务
 type ControllerV4 struct {
 }
@@ -36,8 +46,17 @@ func (ctrl *ControllerV4) URLPatterns() []rest.Route {
 	return []rest.Route{
 		{Method: http.MethodGet, Path: "/v4/:project/admin/alarms", Func: ctrl.AlarmList},
 		{Method: http.MethodDelete, Path: "/v4/:project/admin/alarms", Func: ctrl.ClearAlarm},
+		{Method: http.MethodGet, Path: "/v4/:project/admin/alarms/history", Func: ctrl.AlarmHistory},
+		{Method: http.MethodPut, Path: "/v4/:project/admin/alarms/:id/ack", Func: ctrl.AckAlarm},
 		{Method: http.MethodGet, Path: "/v4/:project/admin/dump", Func: ctrl.Dump},
+		{Method: http.MethodGet, Path: "/v4/:project/admin/audit", Func: ctrl.AuditLog},
 		{Method: http.MethodGet, Path: "/v4/:project/admin/clusters", Func: ctrl.Clusters},
+		{Method: http.MethodGet, Path: "/v4/:project/admin/quotas", Func: ctrl.ListQuotaOverrides},
+		{Method: http.MethodGet, Path: "/v4/:project/admin/quotas/:domain", Func: ctrl.GetQuotaOverride},
+		{Method: http.MethodPut, Path: "/v4/:project/admin/quotas/:domain", Func: ctrl.UpsertQuotaOverride},
+		{Method: http.MethodDelete, Path: "/v4/:project/admin/quotas/:domain", Func: ctrl.DeleteQuotaOverride},
+		{Method: http.MethodGet, Path: "/v4/:project/admin/dependencies/stale", Func: ctrl.ListStaleDependencies},
+		{Method: http.MethodDelete, Path: "/v4/:project/admin/dependencies/stale", Func: ctrl.ClearStaleDependencies},
 	}
 }
 
@@ -62,16 +81,191 @@ func (ctrl *ControllerV4) Clusters(w http.ResponseWriter, r *http.Request) {
 	rest.WriteResponse(w, r, resp.Response, resp)
 }
 
+// AlarmList queries the current alarms filtered by the query params id, status and instance
 func (ctrl *ControllerV4) AlarmList(w http.ResponseWriter, r *http.Request) {
-	request := &dump.AlarmListRequest{}
+	filter, err := alarmFilter(r.URL.Query())
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	request := &dump.AlarmListRequest{Filter: filter}
 	ctx := r.Context()
 	resp, _ := AdminServiceAPI.AlarmList(ctx, request)
 	rest.WriteResponse(w, r, resp.Response, resp)
 }
 
+// AlarmHistory queries the alarm history, the query params are the same as AlarmList's
+// and start, end in RFC3339 format and limit
+func (ctrl *ControllerV4) AlarmHistory(w http.ResponseWriter, r *http.Request) {
+	filter, err := alarmFilter(r.URL.Query())
+	if err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, _ := AdminServiceAPI.AlarmHistory(r.Context(), &dump.AlarmHistoryRequest{Filter: filter})
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+// AckAlarm acknowledges the alarm with a comment in body, the alarm raised by all
+// instances is acknowledged unless the query param instance is set
+func (ctrl *ControllerV4) AckAlarm(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &dump.AckAlarmRequest{
+		ID:       model.ID(query.Get(":id")),
+		Instance: strings.TrimSpace(query.Get("instance")),
+	}
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	if len(message) > 0 {
+		if err = json.Unmarshal(message, request); err != nil {
+			log.Errorf(err, "invalid json: %s", string(message))
+			rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+	}
+	resp, _ := AdminServiceAPI.AckAlarm(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, nil)
+}
+
+func alarmFilter(query url.Values) (*model.HistoryFilter, error) {
+	filter := &model.HistoryFilter{
+		AlarmID:  model.ID(strings.TrimSpace(query.Get("id"))),
+		Status:   model.Status(strings.ToUpper(strings.TrimSpace(query.Get("status")))),
+		Instance: strings.TrimSpace(query.Get("instance")),
+	}
+	var err error
+	if filter.Start, err = parseTimeParam(query, "start"); err != nil {
+		return nil, err
+	}
+	if filter.End, err = parseTimeParam(query, "end"); err != nil {
+		return nil, err
+	}
+	if s := strings.TrimSpace(query.Get("limit")); len(s) > 0 {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("required a positive limit")
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
 func (ctrl *ControllerV4) ClearAlarm(w http.ResponseWriter, r *http.Request) {
 	request := &dump.ClearAlarmRequest{}
 	ctx := r.Context()
 	resp, _ := AdminServiceAPI.ClearAlarm(ctx, request)
 	rest.WriteResponse(w, r, resp.Response, nil)
 }
+
+// AuditLog queries the audit logs, start and end are in RFC3339 format
+func (ctrl *ControllerV4) AuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := &dump.AuditLogFilter{
+		Actor: strings.TrimSpace(query.Get("actor")),
+		Limit: defaultAuditLogLimit,
+	}
+	var err error
+	if filter.Start, err = parseTimeParam(query, "start"); err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	if filter.End, err = parseTimeParam(query, "end"); err != nil {
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	if s := strings.TrimSpace(query.Get("limit")); len(s) > 0 {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			rest.WriteError(w, discovery.ErrInvalidParams, "Required a positive limit")
+			return
+		}
+		filter.Limit = limit
+	}
+	resp, _ := AdminServiceAPI.AuditLog(r.Context(), &dump.AuditLogRequest{Filter: filter})
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+// parseTimeParam returns the time param in milliseconds, zero if not set
+func parseTimeParam(query url.Values, key string) (int64, error) {
+	s := strings.TrimSpace(query.Get(key))
+	if len(s) == 0 {
+		return 0, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", key, err.Error())
+	}
+	return t.UnixNano() / int64(time.Millisecond), nil
+}
+
+func (ctrl *ControllerV4) ListStaleDependencies(w http.ResponseWriter, r *http.Request) {
+	ctrl.staleDependencies(w, r, true)
+}
+
+func (ctrl *ControllerV4) ClearStaleDependencies(w http.ResponseWriter, r *http.Request) {
+	ctrl.staleDependencies(w, r, false)
+}
+
+func (ctrl *ControllerV4) staleDependencies(w http.ResponseWriter, r *http.Request, dryRun bool) {
+	ttl := config.GetDuration("registry.dependency.staleTTL", 0)
+	if s := strings.TrimSpace(r.URL.Query().Get("ttl")); len(s) > 0 {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+			return
+		}
+		ttl = d
+	}
+	request := &StaleDependenciesRequest{
+		TTL:    ttl,
+		DryRun: dryRun,
+	}
+	resp, _ := AdminServiceAPI.StaleDependencies(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+func (ctrl *ControllerV4) ListQuotaOverrides(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.ListQuotaOverrides(r.Context())
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+func (ctrl *ControllerV4) GetQuotaOverride(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.GetQuotaOverride(r.Context(), quotaOverrideRequest(r))
+	rest.WriteResponse(w, r, resp.Response, resp.Override)
+}
+
+// UpsertQuotaOverride saves the limits of the domain, or of the project if query param 'project' is set
+func (ctrl *ControllerV4) UpsertQuotaOverride(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	request := quotaOverrideRequest(r)
+	err = json.Unmarshal(message, request)
+	if err != nil {
+		log.Errorf(err, "invalid json: %s", string(message))
+		rest.WriteError(w, discovery.ErrInvalidParams, err.Error())
+		return
+	}
+	resp, _ := AdminServiceAPI.UpsertQuotaOverride(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, resp.Override)
+}
+
+func (ctrl *ControllerV4) DeleteQuotaOverride(w http.ResponseWriter, r *http.Request) {
+	resp, _ := AdminServiceAPI.DeleteQuotaOverride(r.Context(), quotaOverrideRequest(r))
+	rest.WriteResponse(w, r, resp.Response, nil)
+}
+
+func quotaOverrideRequest(r *http.Request) *QuotaOverrideRequest {
+	query := r.URL.Query()
+	return &QuotaOverrideRequest{
+		Domain:  query.Get(":domain"),
+		Project: strings.TrimSpace(query.Get("project")),
+	}
+}
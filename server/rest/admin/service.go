@@ -19,12 +19,18 @@ package admin
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/dump"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/plugin/auditlog"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
 	"github.com/apache/servicecomb-service-center/version"
 	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/go-archaius"
@@ -34,9 +40,39 @@ var (
 	AdminServiceAPI = &Service{}
 )
 
+// ErrQuotaOverrideNotExist is the code of getting the quota override not exist
+const ErrQuotaOverrideNotExist int32 = 404001
+
+func init() {
+	discovery.MustRegisterErr(ErrQuotaOverrideNotExist, "Quota override does not exist")
+}
+
 type Service struct {
 }
 
+type StaleDependenciesRequest struct {
+	TTL    time.Duration
+	DryRun bool
+}
+
+type StaleDependenciesResponse struct {
+	Response     *discovery.Response           `json:"-"`
+	Dependencies []*datasource.StaleDependency `json:"dependencies,omitempty"`
+}
+
+// QuotaOverrideRequest is the limits of the domain if Project is empty
+type QuotaOverrideRequest struct {
+	Domain  string           `json:"-"`
+	Project string           `json:"-"`
+	Limits  map[string]int64 `json:"limits"`
+}
+
+type QuotaOverrideResponse struct {
+	Response  *discovery.Response         `json:"-"`
+	Override  *datasource.QuotaOverride   `json:"-"`
+	Overrides []*datasource.QuotaOverride `json:"overrides,omitempty"`
+}
+
 func (service *Service) Dump(ctx context.Context, in *dump.Request) (*dump.Response, error) {
 	domainProject := util.ParseDomainProject(ctx)
 
@@ -95,8 +131,52 @@ func (service *Service) Clusters(ctx context.Context, in *dump.ClustersRequest)
 }
 
 func (service *Service) AlarmList(ctx context.Context, in *dump.AlarmListRequest) (*dump.AlarmListResponse, error) {
+	alarms, err := alarm.List(ctx, in.Filter)
+	if err != nil {
+		log.Errorf(err, "list alarms failed")
+		return &dump.AlarmListResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, err
+	}
 	return &dump.AlarmListResponse{
-		Alarms: alarm.ListAll(),
+		Alarms: alarms,
+	}, nil
+}
+
+func (service *Service) AlarmHistory(ctx context.Context, in *dump.AlarmHistoryRequest) (*dump.AlarmHistoryResponse, error) {
+	history, err := alarm.History(ctx, in.Filter)
+	if err != nil {
+		if errors.Is(err, alarm.ErrPersistenceDisabled) {
+			return &dump.AlarmHistoryResponse{
+				Response: discovery.CreateResponse(discovery.ErrInvalidParams, err.Error()),
+			}, nil
+		}
+		log.Errorf(err, "list alarm history failed")
+		return &dump.AlarmHistoryResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, err
+	}
+	return &dump.AlarmHistoryResponse{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "List alarm history successfully"),
+		History:  history,
+	}, nil
+}
+
+func (service *Service) AckAlarm(ctx context.Context, in *dump.AckAlarmRequest) (*dump.AckAlarmResponse, error) {
+	err := alarm.Ack(ctx, in.ID, in.Instance, rbacsvc.UserFromContext(ctx), in.Comment)
+	if err != nil {
+		if errors.Is(err, alarm.ErrAlarmNotActivated) {
+			return &dump.AckAlarmResponse{
+				Response: discovery.CreateResponse(discovery.ErrInvalidParams, err.Error()),
+			}, nil
+		}
+		log.Errorf(err, "acknowledge alarm[%s] failed", in.ID)
+		return &dump.AckAlarmResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, err
+	}
+	return &dump.AckAlarmResponse{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Acknowledge alarm successfully"),
 	}, nil
 }
 
@@ -105,3 +185,166 @@ func (service *Service) ClearAlarm(ctx context.Context, in *dump.ClearAlarmReque
 	log.Infof("service center alarms are cleared")
 	return &dump.ClearAlarmResponse{}, nil
 }
+
+func (service *Service) AuditLog(ctx context.Context, in *dump.AuditLogRequest) (*dump.AuditLogResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !datasource.IsDefaultDomainProject(domainProject) {
+		return &dump.AuditLogResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	logs, err := auditlog.Query(ctx, in.Filter)
+	if err != nil {
+		log.Errorf(err, "query audit logs failed")
+		return &dump.AuditLogResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, err
+	}
+	return &dump.AuditLogResponse{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Query audit logs successfully"),
+		Logs:     logs,
+	}, nil
+}
+
+func (service *Service) StaleDependencies(ctx context.Context, in *StaleDependenciesRequest) (*StaleDependenciesResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !datasource.IsDefaultDomainProject(domainProject) {
+		return &StaleDependenciesResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	if in.TTL <= 0 {
+		return &StaleDependenciesResponse{
+			Response: discovery.CreateResponse(discovery.ErrInvalidParams, "Required a positive ttl"),
+		}, nil
+	}
+
+	stales, err := datasource.GetDependencyManager().ClearStaleDependencies(ctx, in.TTL, in.DryRun)
+	if err != nil {
+		log.Errorf(err, "clear stale dependencies failed, ttl: %s, dry run: %t", in.TTL, in.DryRun)
+		return &StaleDependenciesResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, err
+	}
+	if !in.DryRun {
+		log.Infof("%d stale dependency rules are cleared, ttl: %s", len(stales), in.TTL)
+	}
+	return &StaleDependenciesResponse{
+		Response:     discovery.CreateResponse(discovery.ResponseSuccess, "Clear stale dependencies successfully"),
+		Dependencies: stales,
+	}, nil
+}
+
+func (service *Service) ListQuotaOverrides(ctx context.Context) (*QuotaOverrideResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !datasource.IsDefaultDomainProject(domainProject) {
+		return &QuotaOverrideResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	overrides, err := datasource.GetQuotaOverrideManager().ListQuotaOverride(ctx)
+	if err != nil {
+		log.Errorf(err, "list quota overrides failed")
+		return &QuotaOverrideResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, err
+	}
+	return &QuotaOverrideResponse{
+		Response:  discovery.CreateResponse(discovery.ResponseSuccess, "List quota overrides successfully"),
+		Overrides: overrides,
+	}, nil
+}
+
+func (service *Service) GetQuotaOverride(ctx context.Context, in *QuotaOverrideRequest) (*QuotaOverrideResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !datasource.IsDefaultDomainProject(domainProject) {
+		return &QuotaOverrideResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	override, err := datasource.GetQuotaOverrideManager().GetQuotaOverride(ctx, in.Domain, in.Project)
+	if err != nil {
+		if errors.Is(err, datasource.ErrQuotaOverrideNotExist) {
+			return &QuotaOverrideResponse{
+				Response: discovery.CreateResponse(ErrQuotaOverrideNotExist, err.Error()),
+			}, nil
+		}
+		log.Errorf(err, "get quota override of %s/%s failed", in.Domain, in.Project)
+		return &QuotaOverrideResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, err
+	}
+	return &QuotaOverrideResponse{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Get quota override successfully"),
+		Override: override,
+	}, nil
+}
+
+func (service *Service) UpsertQuotaOverride(ctx context.Context, in *QuotaOverrideRequest) (*QuotaOverrideResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !datasource.IsDefaultDomainProject(domainProject) {
+		return &QuotaOverrideResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	if err := validateQuotaLimits(in.Limits); err != nil {
+		return &QuotaOverrideResponse{
+			Response: discovery.CreateResponse(discovery.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+	override := &datasource.QuotaOverride{
+		Domain:     in.Domain,
+		Project:    in.Project,
+		Limits:     in.Limits,
+		UpdateTime: time.Now().UTC().Format(time.RFC3339),
+	}
+	err := datasource.GetQuotaOverrideManager().UpsertQuotaOverride(ctx, override)
+	if err != nil {
+		log.Errorf(err, "save quota override of %s/%s failed", in.Domain, in.Project)
+		return &QuotaOverrideResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, err
+	}
+	datasource.InvalidateQuotaOverride(in.Domain, in.Project)
+	log.Infof("quota override of %s/%s is saved, limits: %v", in.Domain, in.Project, in.Limits)
+	return &QuotaOverrideResponse{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Save quota override successfully"),
+		Override: override,
+	}, nil
+}
+
+func (service *Service) DeleteQuotaOverride(ctx context.Context, in *QuotaOverrideRequest) (*QuotaOverrideResponse, error) {
+	domainProject := util.ParseDomainProject(ctx)
+	if !datasource.IsDefaultDomainProject(domainProject) {
+		return &QuotaOverrideResponse{
+			Response: discovery.CreateResponse(discovery.ErrForbidden, "Required admin permission"),
+		}, nil
+	}
+	err := datasource.GetQuotaOverrideManager().DeleteQuotaOverride(ctx, in.Domain, in.Project)
+	if err != nil {
+		log.Errorf(err, "delete quota override of %s/%s failed", in.Domain, in.Project)
+		return &QuotaOverrideResponse{
+			Response: discovery.CreateResponse(discovery.ErrInternal, err.Error()),
+		}, err
+	}
+	datasource.InvalidateQuotaOverride(in.Domain, in.Project)
+	log.Infof("quota override of %s/%s is deleted", in.Domain, in.Project)
+	return &QuotaOverrideResponse{
+		Response: discovery.CreateResponse(discovery.ResponseSuccess, "Delete quota override successfully"),
+	}, nil
+}
+
+func validateQuotaLimits(limits map[string]int64) error {
+	if len(limits) == 0 {
+		return errors.New("required at least one limit")
+	}
+	for name, limit := range limits {
+		if _, ok := quota.ParseResourceType(name); !ok {
+			return fmt.Errorf("unknown resource type '%s'", name)
+		}
+		if limit < 0 {
+			return fmt.Errorf("the limit of %s must not be negative", name)
+		}
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/core"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
 	pb "github.com/go-chassis/cari/discovery"
 )
 
@@ -96,7 +97,11 @@ func (s *DependencyService) GetConProDependencies(w http.ResponseWriter, r *http
 		SameDomain: query.Get("sameDomain") == "1",
 		NoSelf:     query.Get("noSelf") == "1",
 	}
-	resp, _ := core.ServiceAPI.GetConsumerDependencies(r.Context(), request)
+	resp, err := discosvc.SearchConsumerDependencies(r.Context(), request)
+	if resp == nil {
+		rest.WriteError(w, pb.ErrInternal, err.Error())
+		return
+	}
 	rest.WriteResponse(w, r, resp.Response, resp)
 }
 
@@ -107,6 +112,10 @@ func (s *DependencyService) GetProConDependencies(w http.ResponseWriter, r *http
 		SameDomain: query.Get("sameDomain") == "1",
 		NoSelf:     query.Get("noSelf") == "1",
 	}
-	resp, _ := core.ServiceAPI.GetProviderDependencies(r.Context(), request)
+	resp, err := discosvc.SearchProviderDependencies(r.Context(), request)
+	if resp == nil {
+		rest.WriteError(w, pb.ErrInternal, err.Error())
+		return
+	}
 	rest.WriteResponse(w, r, resp.Response, resp)
 }
@@ -22,10 +22,12 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/core"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
 	pb "github.com/go-chassis/cari/discovery"
 )
 
@@ -44,6 +46,7 @@ func (s *MicroServiceService) URLPatterns() []rest.Route {
 		{Method: http.MethodPut, Path: "/v4/:project/registry/microservices/:serviceId/properties", Func: s.Update},
 		{Method: http.MethodDelete, Path: "/v4/:project/registry/microservices/:serviceId", Func: s.Unregister},
 		{Method: http.MethodDelete, Path: "/v4/:project/registry/microservices", Func: s.UnregisterServices},
+		{Method: http.MethodPost, Path: "/v4/:project/registry/apply", Func: s.Apply},
 	}
 }
 
@@ -70,6 +73,30 @@ func (s *MicroServiceService) Register(w http.ResponseWriter, r *http.Request) {
 	rest.WriteResponse(w, r, resp.Response, resp)
 }
 
+func (s *MicroServiceService) Apply(w http.ResponseWriter, r *http.Request) {
+	message, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Error("read body failed", err)
+		rest.WriteError(w, pb.ErrInvalidParams, err.Error())
+		return
+	}
+	var request datasource.ServiceDocument
+	err = json.Unmarshal(message, &request)
+	if err != nil {
+		log.Errorf(err, "invalid json: %s", util.BytesToStringWithNoCopy(message))
+		rest.WriteError(w, pb.ErrInvalidParams, err.Error())
+		return
+	}
+	request.DryRun = r.URL.Query().Get("dryRun") == "1"
+	resp, err := discosvc.ApplyService(r.Context(), &request)
+	if err != nil {
+		log.Errorf(err, "apply service failed")
+		rest.WriteError(w, pb.ErrInternal, err.Error())
+		return
+	}
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
 func (s *MicroServiceService) Update(w http.ResponseWriter, r *http.Request) {
 	message, err := ioutil.ReadAll(r.Body)
 	if err != nil {
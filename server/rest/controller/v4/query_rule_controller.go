@@ -27,6 +27,7 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/rest"
 	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/core"
+	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
 	pb "github.com/go-chassis/cari/discovery"
 )
 
@@ -40,6 +41,7 @@ func (s *RuleService) URLPatterns() []rest.Route {
 		{Method: http.MethodGet, Path: "/v4/:project/registry/microservices/:serviceId/rules", Func: s.GetRules},
 		{Method: http.MethodPut, Path: "/v4/:project/registry/microservices/:serviceId/rules/:rule_id", Func: s.UpdateRule},
 		{Method: http.MethodDelete, Path: "/v4/:project/registry/microservices/:serviceId/rules/:rule_id", Func: s.DeleteRule},
+		{Method: http.MethodGet, Path: "/v4/:project/registry/microservices/:serviceId/access", Func: s.ExplainAccess},
 	}
 }
 func (s *RuleService) AddRule(w http.ResponseWriter, r *http.Request) {
@@ -49,7 +51,7 @@ func (s *RuleService) AddRule(w http.ResponseWriter, r *http.Request) {
 		rest.WriteError(w, pb.ErrInvalidParams, err.Error())
 		return
 	}
-	rule := map[string][]*pb.AddOrUpdateServiceRule{}
+	rule := map[string][]*discosvc.PriorityRule{}
 	err = json.Unmarshal(message, &rule)
 	if err != nil {
 		log.Errorf(err, "invalid json: %s", util.BytesToStringWithNoCopy(message))
@@ -57,10 +59,7 @@ func (s *RuleService) AddRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := core.ServiceAPI.AddRule(r.Context(), &pb.AddServiceRulesRequest{
-		ServiceId: r.URL.Query().Get(":serviceId"),
-		Rules:     rule["rules"],
-	})
+	resp, err := discosvc.AddPriorityRules(r.Context(), r.URL.Query().Get(":serviceId"), rule["rules"])
 	if err != nil {
 		log.Errorf(err, "add rule failed")
 		rest.WriteError(w, pb.ErrInternal, "add rule failed")
@@ -89,7 +88,7 @@ func (s *RuleService) UpdateRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rule := pb.AddOrUpdateServiceRule{}
+	rule := discosvc.PriorityRule{}
 	err = json.Unmarshal(message, &rule)
 	if err != nil {
 		log.Errorf(err, "invalid json: %s", util.BytesToStringWithNoCopy(message))
@@ -97,11 +96,7 @@ func (s *RuleService) UpdateRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	query := r.URL.Query()
-	resp, err := core.ServiceAPI.UpdateRule(r.Context(), &pb.UpdateServiceRuleRequest{
-		ServiceId: query.Get(":serviceId"),
-		RuleId:    query.Get(":rule_id"),
-		Rule:      &rule,
-	})
+	resp, err := discosvc.UpdatePriorityRule(r.Context(), query.Get(":serviceId"), query.Get(":rule_id"), &rule)
 	if err != nil {
 		log.Errorf(err, "update rule failed")
 		rest.WriteError(w, pb.ErrInternal, "update rule failed")
@@ -111,8 +106,28 @@ func (s *RuleService) UpdateRule(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *RuleService) GetRules(w http.ResponseWriter, r *http.Request) {
-	resp, _ := core.ServiceAPI.GetRule(r.Context(), &pb.GetServiceRulesRequest{
-		ServiceId: r.URL.Query().Get(":serviceId"),
+	resp, err := discosvc.GetPriorityRules(r.Context(), r.URL.Query().Get(":serviceId"))
+	if err != nil {
+		log.Errorf(err, "get rules failed")
+		rest.WriteError(w, pb.ErrInternal, "get rules failed")
+		return
+	}
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
+
+func (s *RuleService) ExplainAccess(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	// the provider is resolved in the target domain project as the instances discovery does
+	ctx := util.SetTargetDomainProject(r.Context(), r.Header.Get("X-Domain-Name"), query.Get(":project"))
+	resp, err := discosvc.ExplainAccess(ctx, &discosvc.AccessRequest{
+		ProviderServiceID: query.Get(":serviceId"),
+		ConsumerServiceID: query.Get("consumerId"),
+		IP:                query.Get("ip"),
 	})
+	if err != nil {
+		log.Errorf(err, "explain access failed")
+		rest.WriteError(w, pb.ErrInternal, "explain access failed")
+		return
+	}
 	rest.WriteResponse(w, r, resp.Response, resp)
 }
@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v4
+
+import (
+	"net/http"
+
+	"github.com/apache/servicecomb-service-center/pkg/rest"
+	"github.com/apache/servicecomb-service-center/server/plugin/quota"
+	pb "github.com/go-chassis/cari/discovery"
+)
+
+type QuotaService struct {
+	//
+}
+
+// QuotaUsageResponse is the usage of each resource type in the current domain project
+type QuotaUsageResponse struct {
+	Quotas []*quota.ResourceUsage `json:"quotas"`
+}
+
+func (s *QuotaService) URLPatterns() []rest.Route {
+	return []rest.Route{
+		{Method: http.MethodGet, Path: "/v4/:project/registry/quotas", Func: s.GetUsage},
+	}
+}
+
+// GetUsage reports the usage versus limit of the resources,
+// the per service resources are included if query param 'serviceId' is set
+func (s *QuotaService) GetUsage(w http.ResponseWriter, r *http.Request) {
+	usages, err := quota.GetUsage(r.Context(), r.URL.Query().Get("serviceId"))
+	if err != nil {
+		rest.WriteError(w, pb.ErrInternal, err.Error())
+		return
+	}
+	rest.WriteResponse(w, r, nil, &QuotaUsageResponse{Quotas: usages})
+}
@@ -34,4 +34,5 @@ func initRouter() {
 	roa.RegisterServant(&RuleService{})
 	roa.RegisterServant(&MicroServiceInstanceService{})
 	roa.RegisterServant(&WatchService{})
+	roa.RegisterServant(&QuotaService{})
 }
@@ -42,6 +42,7 @@ func (governService *ResourceV4) URLPatterns() []rest.Route {
 		{Method: http.MethodGet, Path: "/v4/:project/govern/microservices", Func: governService.GetAllServicesInfo},
 		{Method: http.MethodGet, Path: "/v4/:project/govern/apps", Func: governService.GetAllApplications},
 		{Method: http.MethodGet, Path: "/v4/:project/govern/statistics", Func: governService.GetAllServicesStatistics},
+		{Method: http.MethodGet, Path: "/v4/:project/govern/catalog", Func: governService.GetServicesCatalog},
 	}
 }
 
@@ -173,3 +174,20 @@ func (governService *ResourceV4) GetAllApplications(w http.ResponseWriter, r *ht
 	resp, _ := ServiceAPI.GetApplications(ctx, request)
 	rest.WriteResponse(w, r, resp.Response, resp)
 }
+
+// GetServicesCatalog 按负责团队、等级等归属信息查询服务
+func (governService *ResourceV4) GetServicesCatalog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &ServicesCatalogRequest{
+		AppID:       query.Get("appId"),
+		Environment: query.Get("env"),
+		WithShared:  util.StringTRUE(query.Get("withShared")),
+		Filter: datasource.OwnershipFilter{
+			Team:       query.Get("team"),
+			Tier:       query.Get("tier"),
+			NoInstance: util.StringTRUE(query.Get("noInstance")),
+		},
+	}
+	resp, _ := GetServicesCatalog(r.Context(), request)
+	rest.WriteResponse(w, r, resp.Response, resp)
+}
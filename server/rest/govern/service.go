@@ -65,3 +65,87 @@ func (governService *Service) GetServicesStatistics(ctx context.Context, in *pb.
 	ctx = util.WithCacheOnly(ctx)
 	return datasource.GetMetadataManager().GetServicesStatistics(ctx, in)
 }
+
+// ServicesCatalogRequest is the request of searching the services by ownership
+type ServicesCatalogRequest struct {
+	AppID       string
+	Environment string
+	WithShared  bool
+	Filter      datasource.OwnershipFilter
+}
+
+type CatalogService struct {
+	ServiceID   string                `json:"serviceId"`
+	AppID       string                `json:"appId"`
+	ServiceName string                `json:"serviceName"`
+	Version     string                `json:"version"`
+	Environment string                `json:"environment,omitempty"`
+	Ownership   *datasource.Ownership `json:"ownership,omitempty"`
+	Instances   int64                 `json:"instances"`
+}
+
+type ServicesCatalogResponse struct {
+	Response *pb.Response      `json:"-"`
+	Services []*CatalogService `json:"services"`
+}
+
+// GetServicesCatalog returns the services with ownership and instances count matched the filter
+func GetServicesCatalog(ctx context.Context, in *ServicesCatalogRequest) (*ServicesCatalogResponse, error) {
+	ctx = util.WithCacheOnly(ctx)
+	servicesResp, err := datasource.GetMetadataManager().GetServices(ctx, &pb.GetServicesRequest{})
+	if err != nil {
+		return &ServicesCatalogResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, err.Error()),
+		}, err
+	}
+	// the properties are omitted in services info, so only count the instances
+	infoResp, err := datasource.GetMetadataManager().GetServicesInfo(ctx, &pb.GetServicesInfoRequest{
+		Options:     []string{"instances"},
+		AppId:       in.AppID,
+		Environment: in.Environment,
+		WithShared:  in.WithShared,
+		CountOnly:   true,
+	})
+	if err != nil {
+		return &ServicesCatalogResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, err.Error()),
+		}, err
+	}
+	if infoResp.Response.GetCode() != pb.ResponseSuccess {
+		return &ServicesCatalogResponse{Response: infoResp.Response}, nil
+	}
+
+	counts := make(map[string]int64, len(infoResp.AllServicesDetail))
+	for _, detail := range infoResp.AllServicesDetail {
+		var count int64
+		if detail.Statics != nil && detail.Statics.Instances != nil {
+			count = detail.Statics.Instances.Count
+		}
+		counts[detail.MicroService.ServiceId] = count
+	}
+
+	services := make([]*CatalogService, 0, len(counts))
+	for _, service := range servicesResp.Services {
+		count, ok := counts[service.ServiceId]
+		if !ok {
+			continue
+		}
+		ownership := datasource.GetOwnership(service)
+		if !in.Filter.Match(ownership, count) {
+			continue
+		}
+		services = append(services, &CatalogService{
+			ServiceID:   service.ServiceId,
+			AppID:       service.AppId,
+			ServiceName: service.ServiceName,
+			Version:     service.Version,
+			Environment: service.Environment,
+			Ownership:   ownership,
+			Instances:   count,
+		})
+	}
+	return &ServicesCatalogResponse{
+		Response: pb.CreateResponse(pb.ResponseSuccess, "Get services catalog successfully."),
+		Services: services,
+	}, nil
+}
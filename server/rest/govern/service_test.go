@@ -24,6 +24,7 @@ import (
 
 	"github.com/apache/servicecomb-service-center/server/service/disco"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/server/core"
 	"github.com/apache/servicecomb-service-center/server/rest/govern"
 	pb "github.com/go-chassis/cari/discovery"
@@ -189,6 +190,61 @@ var _ = Describe("'Govern' service", func() {
 		})
 	})
 
+	Describe("execute 'get catalog' operation", func() {
+		var (
+			serviceId string
+		)
+
+		It("should be passed", func() {
+			resp, err := core.ServiceAPI.Create(getContext(), &pb.CreateServiceRequest{
+				Service: &pb.MicroService{
+					AppId:       "govern_service_catalog",
+					ServiceName: "govern_service_owned",
+					Version:     "1.0.0",
+					Level:       "FRONT",
+					Status:      pb.MS_UP,
+					Properties: map[string]string{
+						datasource.PropOwnerTeam:     "team-a",
+						datasource.PropOwnerContacts: "a@example.com, b@example.com",
+						datasource.PropOwnerTier:     "tier-1",
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(resp.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+			serviceId = resp.ServiceId
+		})
+
+		Context("when search by ownership", func() {
+			It("should be passed", func() {
+				resp, err := govern.GetServicesCatalog(getContext(), &govern.ServicesCatalogRequest{
+					AppID:  "govern_service_catalog",
+					Filter: datasource.OwnershipFilter{Team: "team-a", Tier: "tier-1", NoInstance: true},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(len(resp.Services)).To(Equal(1))
+				Expect(resp.Services[0].ServiceID).To(Equal(serviceId))
+				Expect(resp.Services[0].Ownership.Contacts).To(Equal([]string{"a@example.com", "b@example.com"}))
+
+				resp, err = govern.GetServicesCatalog(getContext(), &govern.ServicesCatalogRequest{
+					AppID:  "govern_service_catalog",
+					Filter: datasource.OwnershipFilter{Team: "team-b"},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(len(resp.Services)).To(Equal(0))
+
+				respDelete, err := core.ServiceAPI.Delete(getContext(), &pb.DeleteServiceRequest{
+					ServiceId: serviceId,
+					Force:     true,
+				})
+				Expect(err).To(BeNil())
+				Expect(respDelete.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+			})
+		})
+	})
+
 	Describe("execute 'get apps' operation", func() {
 		Context("when request is invalid", func() {
 			It("should be failed", func() {
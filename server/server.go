@@ -31,6 +31,8 @@ import (
 	"github.com/apache/servicecomb-service-center/pkg/plugin"
 	"github.com/apache/servicecomb-service-center/pkg/signal"
 	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/alarm"
+	"github.com/apache/servicecomb-service-center/server/alarm/sink"
 	"github.com/apache/servicecomb-service-center/server/command"
 	"github.com/apache/servicecomb-service-center/server/config"
 	"github.com/apache/servicecomb-service-center/server/core"
@@ -42,7 +44,10 @@ import (
 	snf "github.com/apache/servicecomb-service-center/server/syncernotify"
 )
 
-const defaultCollectPeriod = 30 * time.Second
+const (
+	defaultCollectPeriod  = 30 * time.Second
+	defaultAlarmRetention = 30 * 24 * time.Hour
+)
 
 var server ServiceCenterServer
 
@@ -91,10 +96,13 @@ func (s *ServiceCenterServer) initialize() {
 	s.initEndpoints()
 	// Metrics
 	s.initMetrics()
+	// Alarm
+	s.initAlarm()
 	// SSL
 	s.initSSL()
 	// Datasource
 	s.initDatasource()
+	s.initAlarmPersistence()
 	s.apiService = GetAPIServer()
 	s.eventCenter = event.Center()
 	s.syncerNotifyService = snf.GetSyncerNotifyCenter()
@@ -116,11 +124,14 @@ func (s *ServiceCenterServer) initDatasource() {
 		log.Warn("releaseAfter is invalid, use default config")
 	}
 	if err := datasource.Init(datasource.Options{
-		Kind:                kind,
-		SslEnabled:          config.GetSSL().SslEnabled,
-		InstanceTTL:         config.GetRegistry().InstanceTTL,
-		SchemaNotEditable:   config.GetRegistry().SchemaNotEditable,
-		ReleaseAccountAfter: d,
+		Kind:                    kind,
+		SslEnabled:              config.GetSSL().SslEnabled,
+		InstanceTTL:             config.GetRegistry().InstanceTTL,
+		SchemaNotEditable:       config.GetRegistry().SchemaNotEditable,
+		ReleaseAccountAfter:     d,
+		DependencyStaleTTL:      config.GetDuration("registry.dependency.staleTTL", 0),
+		DependencyClearInterval: config.GetDuration("registry.dependency.clearInterval", 24*time.Hour),
+		DependencyClearDryRun:   config.GetBool("registry.dependency.dryRun", true),
 	}); err != nil {
 		log.Fatal("init datasource failed", err)
 	}
@@ -137,15 +148,9 @@ func (s *ServiceCenterServer) initMetrics() {
 	if interval <= time.Second {
 		interval = defaultCollectPeriod
 	}
-	var instance string
-	if len(s.REST.Host) > 0 {
-		instance = net.JoinHostPort(s.REST.Host, s.REST.Port)
-	} else {
-		if len(s.GRPC.Host) > 0 {
-			instance = net.JoinHostPort(s.GRPC.Host, s.GRPC.Port)
-		} else {
-			log.Fatal("init metrics InstanceName failed", nil)
-		}
+	instance := s.instanceName()
+	if len(instance) == 0 {
+		log.Fatal("init metrics InstanceName failed", nil)
 	}
 
 	if err := metrics.Init(metrics.Options{
@@ -156,6 +161,32 @@ func (s *ServiceCenterServer) initMetrics() {
 	}
 }
 
+func (s *ServiceCenterServer) initAlarm() {
+	alarm.SetInstance(s.instanceName())
+	sink.Init()
+}
+
+// initAlarmPersistence must be called after the datasource initialized
+func (s *ServiceCenterServer) initAlarmPersistence() {
+	if !config.GetBool("alarm.persistence.enable", false) {
+		return
+	}
+	alarm.EnablePersistence(alarm.PersistOptions{
+		Retention: config.GetDuration("alarm.persistence.retention", defaultAlarmRetention),
+	})
+}
+
+// instanceName returns the REST endpoint, or the GRPC endpoint if REST host is not set
+func (s *ServiceCenterServer) instanceName() string {
+	if len(s.REST.Host) > 0 {
+		return net.JoinHostPort(s.REST.Host, s.REST.Port)
+	}
+	if len(s.GRPC.Host) > 0 {
+		return net.JoinHostPort(s.GRPC.Host, s.GRPC.Port)
+	}
+	return ""
+}
+
 func (s *ServiceCenterServer) initSSL() {
 	if !config.GetSSL().SslEnabled {
 		return
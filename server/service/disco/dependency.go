@@ -53,23 +53,45 @@ func (s *MicroServiceService) CreateDependenciesForMicroServices(ctx context.Con
 
 func (s *MicroServiceService) GetProviderDependencies(ctx context.Context,
 	in *pb.GetDependenciesRequest) (*pb.GetProDependenciesResponse, error) {
+	resp, err := SearchProviderDependencies(ctx, in)
+	if resp == nil {
+		return nil, err
+	}
+	return resp.GetProDependenciesResponse, err
+}
+
+func (s *MicroServiceService) GetConsumerDependencies(ctx context.Context, in *pb.GetDependenciesRequest) (*pb.GetConDependenciesResponse, error) {
+	resp, err := SearchConsumerDependencies(ctx, in)
+	if resp == nil {
+		return nil, err
+	}
+	return resp.GetConDependenciesResponse, err
+}
+
+// SearchProviderDependencies returns the consumers of the provider with the last discovered time
+func SearchProviderDependencies(ctx context.Context, in *pb.GetDependenciesRequest) (*datasource.ProviderDependencyResponse, error) {
 	err := validator.Validate(in)
 	if err != nil {
 		log.Errorf(err, "GetProviderDependencies failed for validating parameters failed")
-		return &pb.GetProDependenciesResponse{
-			Response: pb.CreateResponse(pb.ErrInvalidParams, err.Error()),
+		return &datasource.ProviderDependencyResponse{
+			GetProDependenciesResponse: &pb.GetProDependenciesResponse{
+				Response: pb.CreateResponse(pb.ErrInvalidParams, err.Error()),
+			},
 		}, nil
 	}
 
 	return datasource.GetDependencyManager().SearchProviderDependency(ctx, in)
 }
 
-func (s *MicroServiceService) GetConsumerDependencies(ctx context.Context, in *pb.GetDependenciesRequest) (*pb.GetConDependenciesResponse, error) {
+// SearchConsumerDependencies returns the providers of the consumer with the last discovered time
+func SearchConsumerDependencies(ctx context.Context, in *pb.GetDependenciesRequest) (*datasource.ConsumerDependencyResponse, error) {
 	err := validator.Validate(in)
 	if err != nil {
 		log.Errorf(err, "GetConsumerDependencies failed for validating parameters failed")
-		return &pb.GetConDependenciesResponse{
-			Response: pb.CreateResponse(pb.ErrInvalidParams, err.Error()),
+		return &datasource.ConsumerDependencyResponse{
+			GetConDependenciesResponse: &pb.GetConDependenciesResponse{
+				Response: pb.CreateResponse(pb.ErrInvalidParams, err.Error()),
+			},
 		}, nil
 	}
 
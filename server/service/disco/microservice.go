@@ -67,6 +67,9 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 
 	datasource.SetServiceDefaultValue(service)
 	err := validator.Validate(in)
+	if err == nil {
+		err = OwnershipSchema().Validate(service.Properties)
+	}
 	if err != nil {
 		log.Errorf(err, "create micro-service[%s] failed, operator: %s",
 			serviceFlag, remoteIP)
@@ -90,6 +93,38 @@ func (s *MicroServiceService) CreateServicePri(ctx context.Context, in *pb.Creat
 	return RegisterService(ctx, in)
 }
 
+// ApplyService creates or updates the service and the schemas, tags, rules and instances
+// belong to it as the document described
+func ApplyService(ctx context.Context, in *datasource.ServiceDocument) (*datasource.ApplyServiceResponse, error) {
+	remoteIP := util.GetIPFromContext(ctx)
+	if in == nil || in.Service == nil {
+		log.Errorf(nil, "apply micro-service failed: request body is empty")
+		return &datasource.ApplyServiceResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, "Request body is empty"),
+		}, nil
+	}
+	service := in.Service
+	serviceFlag := util.StringJoin([]string{
+		service.Environment, service.AppId, service.ServiceName, service.Version}, "/")
+
+	datasource.SetServiceDefaultValue(service)
+	err := validator.Validate(in)
+	if err == nil {
+		err = OwnershipSchema().Validate(service.Properties)
+	}
+	if err == nil {
+		err = validateRules(in.Rules)
+	}
+	if err != nil {
+		log.Errorf(err, "apply micro-service[%s] failed, operator: %s", serviceFlag, remoteIP)
+		return &datasource.ApplyServiceResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+
+	return datasource.GetMetadataManager().ApplyService(ctx, in)
+}
+
 func (s *MicroServiceService) Delete(ctx context.Context, in *pb.DeleteServiceRequest) (*pb.DeleteServiceResponse, error) {
 	remoteIP := util.GetIPFromContext(ctx)
 	err := validator.Validate(in)
@@ -215,6 +250,9 @@ func (s *MicroServiceService) GetServices(ctx context.Context, in *pb.GetService
 
 func (s *MicroServiceService) UpdateProperties(ctx context.Context, in *pb.UpdateServicePropsRequest) (*pb.UpdateServicePropsResponse, error) {
 	err := validator.Validate(in)
+	if err == nil {
+		err = OwnershipSchema().Validate(in.Properties)
+	}
 	if err != nil {
 		remoteIP := util.GetIPFromContext(ctx)
 		log.Errorf(err, "update service[%s] properties failed, operator: %s", in.ServiceId, remoteIP)
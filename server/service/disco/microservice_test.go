@@ -20,6 +20,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/server/service/disco"
 
 	"github.com/apache/servicecomb-service-center/server/core"
@@ -411,6 +412,39 @@ var _ = Describe("'Micro-service' service", func() {
 			})
 		})
 
+		Context("when service with invalid ownership", func() {
+			It("should be failed", func() {
+				r := &pb.CreateServiceRequest{
+					Service: &pb.MicroService{
+						ServiceName: "some-owned-backend",
+						AppId:       "default",
+						Version:     "1.0.0",
+						Level:       "BACK",
+						Properties: map[string]string{
+							"owner.team":       "team-a",
+							"owner.repository": "not-a-url",
+						},
+						Status: "UP",
+					},
+				}
+				resp, err := serviceResource.Create(getContext(), r)
+				Expect(err).To(BeNil())
+				Expect(resp.Response.GetCode()).To(Equal(pb.ErrInvalidParams))
+
+				r.Service.Properties["owner.repository"] = "https://github.com/apache/servicecomb-service-center"
+				resp, err = serviceResource.Create(getContext(), r)
+				Expect(err).To(BeNil())
+				Expect(resp.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+
+				respUpdate, err := serviceResource.UpdateProperties(getContext(), &pb.UpdateServicePropsRequest{
+					ServiceId:  resp.ServiceId,
+					Properties: map[string]string{"owner.onCall": "oncall"},
+				})
+				Expect(err).To(BeNil())
+				Expect(respUpdate.Response.GetCode()).To(Equal(pb.ErrInvalidParams))
+			})
+		})
+
 		Context("when service body is invalid", func() {
 			It("should be failed", func() {
 				By("invalid serviceId")
@@ -770,6 +804,88 @@ var _ = Describe("'Micro-service' service", func() {
 		})
 	})
 
+	Describe("execute 'apply' operation", func() {
+		Context("when document is invalid", func() {
+			It("should be failed", func() {
+				resp, err := disco.ApplyService(getContext(), nil)
+				Expect(err).To(BeNil())
+				Expect(resp.Response.GetCode()).To(Equal(pb.ErrInvalidParams))
+
+				resp, err = disco.ApplyService(getContext(), &datasource.ServiceDocument{
+					Service: &pb.MicroService{ServiceName: "apply-service", Version: "1.0.0"},
+					Rules:   []*pb.AddOrUpdateServiceRule{{RuleType: "WHITE", Attribute: "notexist", Pattern: "a"}},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.GetCode()).To(Equal(pb.ErrInvalidParams))
+
+				resp, err = disco.ApplyService(getContext(), &datasource.ServiceDocument{
+					Service: &pb.MicroService{ServiceName: "apply-service", Version: "1.0.0"},
+					Rules:   []*pb.AddOrUpdateServiceRule{{RuleType: "ALLOW", Attribute: "expr", Pattern: "ip=10.0.0.0/8 &&"}},
+				})
+				Expect(err).To(BeNil())
+				Expect(resp.Response.GetCode()).To(Equal(pb.ErrInvalidParams))
+			})
+		})
+
+		Context("when document is valid", func() {
+			It("should be applied", func() {
+				doc := &datasource.ServiceDocument{
+					Service: &pb.MicroService{ServiceName: "apply-service", Version: "1.0.0"},
+					Schemas: []*pb.Schema{{SchemaId: "hello", Schema: "hello", Summary: "s1"}},
+					Tags:    map[string]string{"zone": "z1"},
+					Rules:   []*pb.AddOrUpdateServiceRule{{RuleType: "WHITE", Attribute: "ServiceName", Pattern: "a"}},
+					Instances: []*pb.MicroServiceInstance{{
+						HostName:  "apply-host",
+						Endpoints: []string{"rest://127.0.0.1:8080"},
+					}},
+				}
+				resp, err := disco.ApplyService(getContext(), doc)
+				Expect(err).To(BeNil())
+				Expect(resp.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(resp.ServiceID).ToNot(BeEmpty())
+				Expect(len(resp.Items)).To(Equal(5))
+				for _, item := range resp.Items {
+					Expect(item.Action).To(Equal(datasource.ApplyActionCreated))
+				}
+				serviceID := resp.ServiceID
+
+				respGetTags, err := serviceResource.GetTags(getContext(), &pb.GetServiceTagsRequest{
+					ServiceId: serviceID,
+				})
+				Expect(err).To(BeNil())
+				Expect(respGetTags.Tags["zone"]).To(Equal("z1"))
+
+				By("apply the same document again")
+				doc = &datasource.ServiceDocument{
+					Service: &pb.MicroService{ServiceName: "apply-service", Version: "1.0.0"},
+					Schemas: []*pb.Schema{{SchemaId: "hello", Schema: "hello", Summary: "s1"}},
+					Tags:    map[string]string{"zone": "z2"},
+					Rules:   []*pb.AddOrUpdateServiceRule{{RuleType: "WHITE", Attribute: "ServiceName", Pattern: "a"}},
+				}
+				resp, err = disco.ApplyService(getContext(), doc)
+				Expect(err).To(BeNil())
+				Expect(resp.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(resp.ServiceID).To(Equal(serviceID))
+				Expect(resp.Items[0].Action).To(Equal(datasource.ApplyActionUnchanged))
+				Expect(resp.Items[1].Action).To(Equal(datasource.ApplyActionUnchanged))
+				Expect(resp.Items[2].Action).To(Equal(datasource.ApplyActionUpdated))
+
+				By("mix black and white rules")
+				doc.Rules = []*pb.AddOrUpdateServiceRule{{RuleType: "BLACK", Attribute: "ServiceName", Pattern: "b"}}
+				resp, err = disco.ApplyService(getContext(), doc)
+				Expect(err).To(BeNil())
+				Expect(resp.Response.GetCode()).To(Equal(pb.ErrBlackAndWhiteRule))
+
+				respDelete, err := serviceResource.Delete(getContext(), &pb.DeleteServiceRequest{
+					ServiceId: serviceID,
+					Force:     true,
+				})
+				Expect(err).To(BeNil())
+				Expect(respDelete.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+			})
+		})
+	})
+
 	Describe("execute 'exists' operation", func() {
 		var (
 			serviceId1 string
@@ -29,16 +29,27 @@ import (
 )
 
 func (s *MicroServiceService) AddRule(ctx context.Context, in *pb.AddServiceRulesRequest) (*pb.AddServiceRulesResponse, error) {
+	if resp := checkAddRuleRequest(ctx, in); resp != nil {
+		return &pb.AddServiceRulesResponse{Response: resp}, nil
+	}
+	return datasource.GetMetadataManager().AddRule(ctx, in)
+}
+
+func checkAddRuleRequest(ctx context.Context, in *pb.AddServiceRulesRequest) *pb.Response {
 	err := validator.Validate(in)
 	if err != nil {
 		remoteIP := util.GetIPFromContext(ctx)
 		log.Errorf(err, "add service[%s] rule failed, operator: %s", in.ServiceId, remoteIP)
-		return &pb.AddServiceRulesResponse{
-			Response: pb.CreateResponse(pb.ErrInvalidParams, err.Error()),
-		}, nil
+		return pb.CreateResponse(pb.ErrInvalidParams, err.Error())
 	}
 
-	return datasource.GetMetadataManager().AddRule(ctx, in)
+	for _, rule := range in.Rules {
+		if err := validateRule(rule.RuleType, rule.Attribute, rule.Pattern); err != nil {
+			log.Errorf(err, "add service[%s] rule failed, operator: %s", in.ServiceId, util.GetIPFromContext(ctx))
+			return pb.CreateResponse(pb.ErrInvalidParams, err.Error())
+		}
+	}
+	return nil
 }
 
 func (s *MicroServiceService) UpdateRule(ctx context.Context, in *pb.UpdateServiceRuleRequest) (*pb.UpdateServiceRuleResponse, error) {
@@ -51,6 +62,13 @@ func (s *MicroServiceService) UpdateRule(ctx context.Context, in *pb.UpdateServi
 		}, nil
 	}
 
+	if err := validateRule(in.Rule.RuleType, in.Rule.Attribute, in.Rule.Pattern); err != nil {
+		log.Errorf(err, "update service rule[%s/%s] failed, operator: %s", in.ServiceId, in.RuleId, util.GetIPFromContext(ctx))
+		return &pb.UpdateServiceRuleResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, err.Error()),
+		}, nil
+	}
+
 	return datasource.GetMetadataManager().UpdateRule(ctx, in)
 }
 
@@ -78,3 +96,251 @@ func (s *MicroServiceService) DeleteRule(ctx context.Context, in *pb.DeleteServi
 
 	return datasource.GetMetadataManager().DeleteRule(ctx, in)
 }
+
+// PriorityRule is the rule to add or update with its evaluation priority
+type PriorityRule struct {
+	*pb.AddOrUpdateServiceRule
+	Priority int32 `json:"priority,omitempty"`
+}
+
+func (r *PriorityRule) serviceRule() *pb.AddOrUpdateServiceRule {
+	if r == nil || r.AddOrUpdateServiceRule == nil {
+		return &pb.AddOrUpdateServiceRule{}
+	}
+	return r.AddOrUpdateServiceRule
+}
+
+// GetPriorityRulesResponse is the service rules with their priorities
+type GetPriorityRulesResponse struct {
+	Response *pb.Response       `json:"-"`
+	Rules    []*datasource.Rule `json:"rules,omitempty"`
+}
+
+// AddPriorityRules adds the rules and saves their priorities at once,
+// the priorities of the existing rules are updated if specified
+func AddPriorityRules(ctx context.Context, serviceID string, rules []*PriorityRule) (*pb.AddServiceRulesResponse, error) {
+	in := &pb.AddServiceRulesRequest{ServiceId: serviceID}
+	priorities := make([]int32, 0, len(rules))
+	for _, rule := range rules {
+		in.Rules = append(in.Rules, rule.serviceRule())
+		var priority int32
+		if rule != nil {
+			priority = rule.Priority
+		}
+		priorities = append(priorities, priority)
+	}
+	if resp := checkAddRuleRequest(ctx, in); resp != nil {
+		return &pb.AddServiceRulesResponse{Response: resp}, nil
+	}
+	return datasource.GetMetadataManager().AddPriorityRules(ctx, in, priorities)
+}
+
+// UpdatePriorityRule updates the rule and its priority
+func UpdatePriorityRule(ctx context.Context, serviceID, ruleID string, rule *PriorityRule) (*pb.UpdateServiceRuleResponse, error) {
+	resp, err := NewMicroServiceService().UpdateRule(ctx, &pb.UpdateServiceRuleRequest{
+		ServiceId: serviceID,
+		RuleId:    ruleID,
+		Rule:      rule.serviceRule(),
+	})
+	if err != nil || resp.Response.GetCode() != pb.ResponseSuccess {
+		return resp, err
+	}
+	err = datasource.GetMetadataManager().UpdateRulePriorities(ctx, serviceID, map[string]int32{ruleID: rule.Priority})
+	if err != nil {
+		log.Errorf(err, "update service rule[%s/%s] priority failed, operator: %s", serviceID, ruleID, util.GetIPFromContext(ctx))
+		return &pb.UpdateServiceRuleResponse{
+			Response: pb.CreateResponse(pb.ErrInternal, err.Error()),
+		}, err
+	}
+	return resp, nil
+}
+
+// GetPriorityRules returns the service rules with their priorities
+func GetPriorityRules(ctx context.Context, serviceID string) (*GetPriorityRulesResponse, error) {
+	resp, err := NewMicroServiceService().GetRule(ctx, &pb.GetServiceRulesRequest{ServiceId: serviceID})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response.GetCode() != pb.ResponseSuccess {
+		return &GetPriorityRulesResponse{Response: resp.Response}, nil
+	}
+	priorities, err := datasource.GetMetadataManager().GetRulePriorities(ctx, serviceID)
+	if err != nil {
+		log.Errorf(err, "get service[%s] rule priorities failed", serviceID)
+		return nil, err
+	}
+	return &GetPriorityRulesResponse{
+		Response: resp.Response,
+		Rules:    datasource.NewRules(resp.Rules, priorities),
+	}, nil
+}
+
+// explainRules evaluates the provider rules for the consumer without any side effect
+func explainRules(ctx context.Context, providerID, consumerID, ip string) (*pb.Response, *datasource.RuleDecision, error) {
+	rulesResp, err := GetPriorityRules(ctx, providerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rulesResp.Response.GetCode() != pb.ResponseSuccess {
+		return rulesResp.Response, nil, nil
+	}
+	serviceResp, err := datasource.GetMetadataManager().GetService(ctx, &pb.GetServiceRequest{ServiceId: consumerID})
+	if err != nil {
+		return nil, nil, err
+	}
+	if serviceResp.Response.GetCode() != pb.ResponseSuccess {
+		return serviceResp.Response, nil, nil
+	}
+	tagsResp, err := datasource.GetMetadataManager().GetTags(ctx, &pb.GetServiceTagsRequest{ServiceId: consumerID})
+	if err != nil {
+		return nil, nil, err
+	}
+	if tagsResp.Response.GetCode() != pb.ResponseSuccess {
+		return tagsResp.Response, nil, nil
+	}
+
+	decision, err := datasource.EvaluateRules(rulesResp.Rules, &datasource.RuleSubject{
+		Consumer: serviceResp.Service,
+		Tags:     tagsResp.Tags,
+		IP:       ip,
+	}, true)
+	if err != nil {
+		log.Errorf(err, "explain service[%s] rules for consumer[%s] failed", providerID, consumerID)
+		return pb.CreateResponse(pb.ErrInternal, err.Error()), nil, nil
+	}
+	return pb.CreateResponse(pb.ResponseSuccess, "Explain service rules successfully."), decision, nil
+}
+
+// AccessRequest is the dry run request of checking whether the consumer can access the provider
+type AccessRequest struct {
+	ProviderServiceID string
+	ConsumerServiceID string
+	// IP is the source ip of the consumer, the rules comparing the ip are skipped if it is empty
+	IP string
+}
+
+type AccessResponse struct {
+	Response *pb.Response `json:"-"`
+	Allowed  bool         `json:"allowed"`
+	Reason   string       `json:"reason"`
+	// CrossDimension is the reason of denying the access across app or environment
+	CrossDimension string `json:"crossDimension,omitempty"`
+	// Rule is the matched rule which makes the decision
+	Rule     *datasource.RuleTrace    `json:"rule,omitempty"`
+	Decision *datasource.RuleDecision `json:"decision,omitempty"`
+}
+
+// ReasonSharedProvider is the reason of allowing the access to the shared provider
+const ReasonSharedProvider = "provider is a shared service"
+
+// getAccessProvider resolves the provider as the instances discovery does:
+// the shared provider is found in the target domain project,
+// others are found in the domain project of the consumer, nil if not found
+func getAccessProvider(ctx context.Context, providerID string) (*pb.MicroService, bool, error) {
+	targetDomainProject := util.ParseTargetDomainProject(ctx)
+	if targetDomainProject != util.ParseDomainProject(ctx) {
+		resp, err := datasource.GetMetadataManager().GetService(util.SetDomainProjectString(ctx, targetDomainProject),
+			&pb.GetServiceRequest{ServiceId: providerID})
+		if err != nil {
+			return nil, false, err
+		}
+		if resp.Response.GetCode() == pb.ResponseSuccess &&
+			datasource.IsGlobal(pb.MicroServiceToKey(targetDomainProject, resp.Service)) {
+			return resp.Service, true, nil
+		}
+	}
+	resp, err := datasource.GetMetadataManager().GetService(ctx, &pb.GetServiceRequest{ServiceId: providerID})
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.Response.GetCode() != pb.ResponseSuccess {
+		return nil, false, nil
+	}
+	return resp.Service, datasource.IsGlobal(pb.MicroServiceToKey(util.ParseDomainProject(ctx), resp.Service)), nil
+}
+
+// ExplainAccess runs the same checks as the instances discovery without any side effect,
+// and explains why the consumer is allowed or denied to access the provider
+func ExplainAccess(ctx context.Context, in *AccessRequest) (*AccessResponse, error) {
+	if len(in.ConsumerServiceID) == 0 {
+		return &AccessResponse{
+			Response: pb.CreateResponse(pb.ErrInvalidParams, "Required consumerId"),
+		}, nil
+	}
+	consumerResp, err := datasource.GetMetadataManager().GetService(ctx, &pb.GetServiceRequest{ServiceId: in.ConsumerServiceID})
+	if err != nil {
+		return nil, err
+	}
+	if consumerResp.Response.GetCode() != pb.ResponseSuccess {
+		return &AccessResponse{
+			Response: pb.CreateResponse(pb.ErrServiceNotExists, "consumer serviceID is invalid"),
+		}, nil
+	}
+	provider, shared, err := getAccessProvider(ctx, in.ProviderServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return &AccessResponse{
+			Response: pb.CreateResponse(pb.ErrServiceNotExists, "provider serviceID is invalid"),
+		}, nil
+	}
+
+	success := pb.CreateResponse(pb.ResponseSuccess, "Explain access successfully.")
+	if shared {
+		// the instances of the shared services are found without checking the dimension and rules
+		return &AccessResponse{
+			Response: success,
+			Allowed:  true,
+			Reason:   ReasonSharedProvider,
+		}, nil
+	}
+	if err := datasource.AllowAcrossDimension(ctx, provider, consumerResp.Service); err != nil {
+		return &AccessResponse{
+			Response:       success,
+			Reason:         err.Error(),
+			CrossDimension: err.Error(),
+		}, nil
+	}
+
+	rulesResp, decision, err := explainRules(ctx, in.ProviderServiceID, in.ConsumerServiceID, in.IP)
+	if err != nil {
+		return nil, err
+	}
+	if rulesResp.GetCode() != pb.ResponseSuccess {
+		return &AccessResponse{Response: rulesResp}, nil
+	}
+	resp := &AccessResponse{
+		Response: success,
+		Allowed:  decision.Allowed,
+		Reason:   decision.Reason,
+		Decision: decision,
+	}
+	for _, trace := range decision.Traces {
+		if len(decision.RuleID) > 0 && trace.RuleID == decision.RuleID {
+			resp.Rule = trace
+			break
+		}
+	}
+	return resp, nil
+}
+
+func validateRule(ruleType, attribute, pattern string) error {
+	return datasource.ValidateRule(&pb.ServiceRule{
+		RuleType:  ruleType,
+		Attribute: attribute,
+		Pattern:   pattern,
+	})
+}
+
+func validateRules(rules []*pb.AddOrUpdateServiceRule) error {
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		if err := validateRule(rule.RuleType, rule.Attribute, rule.Pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
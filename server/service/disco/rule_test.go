@@ -19,6 +19,7 @@ package disco_test
 import (
 	"strconv"
 
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	discosvc "github.com/apache/servicecomb-service-center/server/service/disco"
 
 	"github.com/apache/servicecomb-service-center/server/plugin/quota"
@@ -747,4 +748,276 @@ var _ = Describe("'Rule' service", func() {
 			})
 		})
 	})
+
+	Describe("execute 'priority' operartion", func() {
+		var (
+			providerID string
+			consumerID string
+			exprRuleID string
+		)
+
+		It("should be passed", func() {
+			respCreateService, err := serviceResource.Create(getContext(), &pb.CreateServiceRequest{
+				Service: &pb.MicroService{
+					AppId:       "priority_rule_group",
+					ServiceName: "priority_rule_provider",
+					Version:     "1.0.0",
+					Level:       "FRONT",
+					Status:      pb.MS_UP,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(respCreateService.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+			providerID = respCreateService.ServiceId
+
+			respCreateService, err = serviceResource.Create(getContext(), &pb.CreateServiceRequest{
+				Service: &pb.MicroService{
+					AppId:       "priority_rule_group",
+					ServiceName: "priority_rule_consumer",
+					Version:     "1.0.0",
+					Level:       "FRONT",
+					Status:      pb.MS_UP,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(respCreateService.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+			consumerID = respCreateService.ServiceId
+
+			respAddRule, err := discosvc.AddPriorityRules(getContext(), providerID, []*discosvc.PriorityRule{
+				{
+					AddOrUpdateServiceRule: &pb.AddOrUpdateServiceRule{
+						RuleType:  "ALLOW",
+						Attribute: "expr",
+						Pattern:   "ServiceName=priority_rule_consumer && ip=10.0.0.0/8",
+					},
+					Priority: 10,
+				},
+				{
+					AddOrUpdateServiceRule: &pb.AddOrUpdateServiceRule{
+						RuleType:  "DENY",
+						Attribute: "AppId",
+						Pattern:   "priority_rule_group",
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(respAddRule.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+
+			respGetRules, err := discosvc.GetPriorityRules(getContext(), providerID)
+			Expect(err).To(BeNil())
+			Expect(respGetRules.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+			Expect(len(respGetRules.Rules)).To(Equal(2))
+			for _, rule := range respGetRules.Rules {
+				if rule.Attribute == "expr" {
+					exprRuleID = rule.RuleId
+					Expect(rule.Priority).To(Equal(int32(10)))
+				} else {
+					Expect(rule.Priority).To(Equal(int32(0)))
+				}
+			}
+		})
+
+		Context("when request is invalid", func() {
+			It("should be failed", func() {
+				respAddRule, err := discosvc.AddPriorityRules(getContext(), providerID, []*discosvc.PriorityRule{
+					{
+						AddOrUpdateServiceRule: &pb.AddOrUpdateServiceRule{
+							RuleType:  "WHITE",
+							Attribute: "expr",
+							Pattern:   "ServiceName=a",
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(respAddRule.Response.GetCode()).To(Equal(pb.ErrInvalidParams))
+
+				respAddRule, err = discosvc.AddPriorityRules(getContext(), providerID, []*discosvc.PriorityRule{
+					{
+						AddOrUpdateServiceRule: &pb.AddOrUpdateServiceRule{
+							RuleType:  "ALLOW",
+							Attribute: "expr_10",
+							Pattern:   "ServiceName=a",
+						},
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(respAddRule.Response.GetCode()).To(Equal(pb.ErrInvalidParams))
+			})
+		})
+
+		Context("when request is valid", func() {
+			It("should be matched by priority", func() {
+				respAccess, err := discosvc.ExplainAccess(getContext(), &discosvc.AccessRequest{
+					ProviderServiceID: providerID,
+					ConsumerServiceID: consumerID,
+					IP:                "10.0.0.1",
+				})
+				Expect(err).To(BeNil())
+				Expect(respAccess.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(respAccess.Allowed).To(BeTrue())
+				Expect(respAccess.Rule.RuleID).To(Equal(exprRuleID))
+				Expect(len(respAccess.Decision.Traces)).To(Equal(2))
+
+				respAccess, err = discosvc.ExplainAccess(getContext(), &discosvc.AccessRequest{
+					ProviderServiceID: providerID,
+					ConsumerServiceID: consumerID,
+					IP:                "192.168.0.1",
+				})
+				Expect(err).To(BeNil())
+				Expect(respAccess.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(respAccess.Allowed).To(BeFalse())
+
+				By("the allow ip rule is not matched without consumer ip")
+				respAccess, err = discosvc.ExplainAccess(getContext(), &discosvc.AccessRequest{
+					ProviderServiceID: providerID,
+					ConsumerServiceID: consumerID,
+				})
+				Expect(err).To(BeNil())
+				Expect(respAccess.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(respAccess.Allowed).To(BeFalse())
+				Expect(respAccess.Decision.Traces[0].Skipped).To(BeTrue())
+
+				By("reset the priority")
+				respUpdateRule, err := discosvc.UpdatePriorityRule(getContext(), providerID, exprRuleID, &discosvc.PriorityRule{
+					AddOrUpdateServiceRule: &pb.AddOrUpdateServiceRule{
+						RuleType:  "ALLOW",
+						Attribute: "expr",
+						Pattern:   "ServiceName=priority_rule_consumer && ip=10.0.0.0/8",
+					},
+				})
+				Expect(err).To(BeNil())
+				Expect(respUpdateRule.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+
+				respAccess, err = discosvc.ExplainAccess(getContext(), &discosvc.AccessRequest{
+					ProviderServiceID: providerID,
+					ConsumerServiceID: consumerID,
+					IP:                "10.0.0.1",
+				})
+				Expect(err).To(BeNil())
+				Expect(respAccess.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(respAccess.Allowed).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("execute 'access' operartion", func() {
+		var (
+			providerID string
+			consumerID string
+			otherID    string
+		)
+
+		It("should be passed", func() {
+			respCreateService, err := serviceResource.Create(getContext(), &pb.CreateServiceRequest{
+				Service: &pb.MicroService{
+					AppId:       "access_rule_group",
+					ServiceName: "access_rule_provider",
+					Version:     "1.0.0",
+					Level:       "FRONT",
+					Status:      pb.MS_UP,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(respCreateService.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+			providerID = respCreateService.ServiceId
+
+			respCreateService, err = serviceResource.Create(getContext(), &pb.CreateServiceRequest{
+				Service: &pb.MicroService{
+					AppId:       "access_rule_group",
+					ServiceName: "access_rule_consumer",
+					Version:     "1.0.0",
+					Level:       "FRONT",
+					Status:      pb.MS_UP,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(respCreateService.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+			consumerID = respCreateService.ServiceId
+
+			respCreateService, err = serviceResource.Create(getContext(), &pb.CreateServiceRequest{
+				Service: &pb.MicroService{
+					AppId:       "access_rule_other",
+					ServiceName: "access_rule_consumer",
+					Version:     "1.0.0",
+					Level:       "FRONT",
+					Status:      pb.MS_UP,
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(respCreateService.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+			otherID = respCreateService.ServiceId
+
+			respAddRule, err := serviceResource.AddRule(getContext(), &pb.AddServiceRulesRequest{
+				ServiceId: providerID,
+				Rules: []*pb.AddOrUpdateServiceRule{
+					{
+						RuleType:  "BLACK",
+						Attribute: "ServiceName",
+						Pattern:   "access_rule_consumer",
+					},
+				},
+			})
+			Expect(err).To(BeNil())
+			Expect(respAddRule.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+		})
+
+		Context("when request is invalid", func() {
+			It("should be failed", func() {
+				respAccess, err := discosvc.ExplainAccess(getContext(), &discosvc.AccessRequest{
+					ProviderServiceID: providerID,
+				})
+				Expect(err).To(BeNil())
+				Expect(respAccess.Response.GetCode()).To(Equal(pb.ErrInvalidParams))
+
+				respAccess, err = discosvc.ExplainAccess(getContext(), &discosvc.AccessRequest{
+					ProviderServiceID: providerID,
+					ConsumerServiceID: "not_exist",
+				})
+				Expect(err).To(BeNil())
+				Expect(respAccess.Response.GetCode()).To(Equal(pb.ErrServiceNotExists))
+
+				respAccess, err = discosvc.ExplainAccess(getContext(), &discosvc.AccessRequest{
+					ProviderServiceID: "not_exist",
+					ConsumerServiceID: consumerID,
+				})
+				Expect(err).To(BeNil())
+				Expect(respAccess.Response.GetCode()).To(Equal(pb.ErrServiceNotExists))
+			})
+		})
+
+		Context("when request is valid", func() {
+			It("should be explained", func() {
+				respAccess, err := discosvc.ExplainAccess(getContext(), &discosvc.AccessRequest{
+					ProviderServiceID: providerID,
+					ConsumerServiceID: consumerID,
+				})
+				Expect(err).To(BeNil())
+				Expect(respAccess.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(respAccess.Allowed).To(BeFalse())
+				Expect(respAccess.Rule).ToNot(BeNil())
+				Expect(respAccess.Rule.Values["ServiceName"]).To(Equal("access_rule_consumer"))
+
+				By("deny across app")
+				respAccess, err = discosvc.ExplainAccess(getContext(), &discosvc.AccessRequest{
+					ProviderServiceID: providerID,
+					ConsumerServiceID: otherID,
+				})
+				Expect(err).To(BeNil())
+				Expect(respAccess.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(respAccess.Allowed).To(BeFalse())
+				Expect(respAccess.CrossDimension).ToNot(BeEmpty())
+				Expect(respAccess.Rule).To(BeNil())
+
+				By("the provider not shared is found in the domain project of the consumer")
+				respAccess, err = discosvc.ExplainAccess(util.SetTargetDomainProject(getContext(), "other", "other"),
+					&discosvc.AccessRequest{
+						ProviderServiceID: providerID,
+						ConsumerServiceID: consumerID,
+					})
+				Expect(err).To(BeNil())
+				Expect(respAccess.Response.GetCode()).To(Equal(pb.ResponseSuccess))
+				Expect(respAccess.Rule).ToNot(BeNil())
+			})
+		})
+	})
 })
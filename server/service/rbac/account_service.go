@@ -47,7 +47,7 @@ func CreateAccount(ctx context.Context, a *rbac.Account) error {
 		return discovery.NewError(discovery.ErrInvalidParams, err.Error())
 	}
 	if len(a.Status) == 0 {
-		a.Status = "active"
+		a.Status = StatusActive
 	}
 	err = a.Check()
 	if err != nil {
@@ -61,6 +61,10 @@ func CreateAccount(ctx context.Context, a *rbac.Account) error {
 	err = datasource.GetAccountManager().CreateAccount(ctx, a)
 	if err == nil {
 		log.Infof("create account [%s] success", a.Name)
+		// a.Password is hashed by datasource
+		if err = recordPassword(ctx, a.Name, a.Password); err != nil {
+			log.Errorf(err, "save account [%s] state failed", a.Name)
+		}
 		return nil
 	}
 	log.Errorf(err, "create account [%s] failed", a.Name)
@@ -90,9 +94,17 @@ func UpdateAccount(ctx context.Context, name string, a *rbac.Account) error {
 		return err
 	}
 	if len(a.Status) != 0 {
+		if oldAccount.Status == StatusInactive && a.Status == StatusActive {
+			// restart the inactivity period, otherwise it expires at next login again
+			if err = activateAccount(ctx, name); err != nil {
+				return err
+			}
+		}
 		oldAccount.Status = a.Status
 	}
+	rolesRemoved := false
 	if len(a.Roles) != 0 {
+		rolesRemoved = hasRemovedRole(oldAccount.Roles, a.Roles)
 		oldAccount.Roles = a.Roles
 	}
 	if err = checkRoleNames(ctx, oldAccount.Roles); err != nil {
@@ -104,9 +116,22 @@ func UpdateAccount(ctx context.Context, name string, a *rbac.Account) error {
 		return err
 	}
 	log.Infof("account [%s] is edit", oldAccount.ID)
+	if rolesRemoved {
+		// the tokens carry the removed roles
+		return RevokeAccountSessions(ctx, name)
+	}
 	return nil
 }
 
+func hasRemovedRole(oldRoles, newRoles []string) bool {
+	for _, r := range oldRoles {
+		if !util.SliceHave(newRoles, r) {
+			return true
+		}
+	}
+	return false
+}
+
 func GetAccount(ctx context.Context, name string) (*rbac.Account, error) {
 	r, err := datasource.GetAccountManager().GetAccount(ctx, name)
 	if err != nil {
@@ -138,7 +163,17 @@ func DeleteAccount(ctx context.Context, name string) error {
 		return rbac.NewError(rbac.ErrAccountNotExist, msg)
 	}
 	_, err = datasource.GetAccountManager().DeleteAccount(ctx, []string{name})
-	return err
+	if err != nil {
+		return err
+	}
+	revokeAccountAPIKeys(ctx, name)
+	if err = datasource.GetAccountStateManager().DeleteAccountState(ctx, name); err != nil {
+		log.Errorf(err, "remove account [%s] state failed", name)
+	}
+	if err = datasource.GetTOTPManager().DeleteTOTP(ctx, name); err != nil {
+		log.Errorf(err, "remove account [%s] totp failed", name)
+	}
+	return RevokeAccountSessions(ctx, name)
 }
 
 //CreateAccount save 2 kv
@@ -0,0 +1,267 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+// ClaimsAPIKey is the claim of the api key id, set when the request is authenticated by an api key
+const ClaimsAPIKey = "apikey"
+
+const (
+	ErrAPIKeyNotExist int32 = 400220
+	ErrAPIKeyInvalid  int32 = 401220
+	ErrAPIKeyExpired  int32 = 401221
+)
+
+const (
+	apiKeySecretSize = 32
+	apiKeySeparator  = "."
+)
+
+func init() {
+	rbac.MustRegisterErrs(map[int32]string{
+		ErrAPIKeyNotExist: "API key not exists",
+		ErrAPIKeyInvalid:  "API key is invalid",
+		ErrAPIKeyExpired:  "API key is expired",
+	})
+}
+
+//CreateAPIKey creates an api key of the account, the plain key is only returned this time
+func CreateAPIKey(ctx context.Context, account string, req *rbacpkg.APIKeyRequest) (*rbacpkg.APIKey, error) {
+	a, err := GetAccount(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range req.Roles {
+		if !util.SliceHave(a.Roles, r) {
+			return nil, rbac.NewError(rbac.ErrAccountHasInvalidRole,
+				fmt.Sprintf("role [%s] is not bound to account [%s]", r, account))
+		}
+	}
+	k := &datasource.APIKey{
+		ID:      util.GenerateUUID(),
+		Account: account,
+		Name:    req.Name,
+		Roles:   req.Roles,
+	}
+	if len(req.ExpireAfter) > 0 {
+		d, err := time.ParseDuration(req.ExpireAfter)
+		if err != nil || d <= 0 {
+			return nil, discovery.NewError(discovery.ErrInvalidParams, "invalid expireAfter: "+req.ExpireAfter)
+		}
+		k.ExpireAt = time.Now().Add(d).Unix()
+	}
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		return nil, err
+	}
+	k.Hash = hashAPIKeySecret(secret)
+	err = datasource.GetAPIKeyManager().CreateAPIKey(ctx, k)
+	if err != nil {
+		log.Errorf(err, "create api key of account [%s] failed", account)
+		return nil, err
+	}
+	log.Infof("create api key [%s] of account [%s]", k.ID, account)
+	resp := toAPIKeyResponse(k)
+	resp.Key = k.ID + apiKeySeparator + secret
+	return resp, nil
+}
+
+//ListAPIKey returns the api keys of the account, without the secrets
+func ListAPIKey(ctx context.Context, account string) ([]*rbacpkg.APIKey, error) {
+	keys, err := datasource.GetAPIKeyManager().ListAPIKey(ctx, account)
+	if err != nil {
+		log.Errorf(err, "list api keys of account [%s] failed", account)
+		return nil, err
+	}
+	l := make([]*rbacpkg.APIKey, 0, len(keys))
+	for _, k := range keys {
+		l = append(l, toAPIKeyResponse(k))
+	}
+	return l, nil
+}
+
+//RotateAPIKey replaces the secret of the api key, the old key is invalid at once
+func RotateAPIKey(ctx context.Context, account, id string) (*rbacpkg.APIKey, error) {
+	k, err := getAPIKey(ctx, account, id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		return nil, err
+	}
+	k.Hash = hashAPIKeySecret(secret)
+	err = datasource.GetAPIKeyManager().UpdateAPIKey(ctx, k)
+	if err != nil {
+		log.Errorf(err, "rotate api key [%s] failed", id)
+		return nil, err
+	}
+	log.Infof("api key [%s] of account [%s] is rotated", id, account)
+	resp := toAPIKeyResponse(k)
+	resp.Key = k.ID + apiKeySeparator + secret
+	return resp, nil
+}
+
+//RevokeAPIKey deletes the api key
+func RevokeAPIKey(ctx context.Context, account, id string) error {
+	if _, err := getAPIKey(ctx, account, id); err != nil {
+		return err
+	}
+	err := datasource.GetAPIKeyManager().DeleteAPIKey(ctx, id)
+	if err != nil {
+		log.Errorf(err, "revoke api key [%s] failed", id)
+		return err
+	}
+	log.Infof("api key [%s] of account [%s] is revoked", id, account)
+	return nil
+}
+
+// revokeAccountAPIKeys deletes all the api keys of the deleted account
+func revokeAccountAPIKeys(ctx context.Context, account string) {
+	keys, err := datasource.GetAPIKeyManager().ListAPIKey(ctx, account)
+	if err != nil {
+		log.Errorf(err, "list api keys of account [%s] failed", account)
+		return
+	}
+	for _, k := range keys {
+		if err := datasource.GetAPIKeyManager().DeleteAPIKey(ctx, k.ID); err != nil {
+			log.Errorf(err, "revoke api key [%s] failed", k.ID)
+		}
+	}
+}
+
+//AuthenticateAPIKey verifies the api key and returns the claims same as the token's,
+//the roles are the key roles which are still bound to the account
+func AuthenticateAPIKey(ctx context.Context, key string) (map[string]interface{}, error) {
+	i := strings.Index(key, apiKeySeparator)
+	if i <= 0 {
+		return nil, rbac.NewError(ErrAPIKeyInvalid, "")
+	}
+	id, secret := key[:i], key[i+1:]
+	k, err := datasource.GetAPIKeyManager().GetAPIKey(ctx, id)
+	if err != nil {
+		if err == datasource.ErrAPIKeyNotExist {
+			return nil, rbac.NewError(ErrAPIKeyInvalid, "")
+		}
+		log.Errorf(err, "get api key [%s] failed", id)
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(k.Hash), []byte(hashAPIKeySecret(secret))) != 1 {
+		return nil, rbac.NewError(ErrAPIKeyInvalid, "")
+	}
+	if k.ExpireAt > 0 && time.Now().Unix() >= k.ExpireAt {
+		return nil, rbac.NewError(ErrAPIKeyExpired, "")
+	}
+	a, err := datasource.GetAccountManager().GetAccount(ctx, k.Account)
+	if err != nil {
+		if err == datasource.ErrAccountNotExist {
+			return nil, rbac.NewError(rbac.ErrTokenOwnedAccountDeleted, "")
+		}
+		log.Errorf(err, "get the account of api key [%s] failed", id)
+		return nil, err
+	}
+	// the key is disabled along with its account
+	if a.Status == StatusInactive {
+		return nil, rbac.NewError(ErrAccountInactive, "")
+	}
+	if ip := util.GetIPFromContext(ctx); IsBanned(MakeBanKey(a.Name, ip)) {
+		log.Warnf("ip [%s] is banned, account: %s, api key: %s", ip, a.Name, id)
+		return nil, rbac.NewError(rbac.ErrAccountBlocked, "")
+	}
+	return map[string]interface{}{
+		rbac.ClaimsUser:  a.Name,
+		rbac.ClaimsRoles: toInterfaces(APIKeyRoles(a.Roles, k.Roles)),
+		ClaimsAPIKey:     k.ID,
+	}, nil
+}
+
+//APIKeyRoles returns the key roles which are still bound to the account,
+//all the account roles if the key roles are empty
+func APIKeyRoles(accountRoles, keyRoles []string) []string {
+	if len(keyRoles) == 0 {
+		return accountRoles
+	}
+	roles := make([]string, 0, len(keyRoles))
+	for _, r := range keyRoles {
+		if util.SliceHave(accountRoles, r) {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+func getAPIKey(ctx context.Context, account, id string) (*datasource.APIKey, error) {
+	k, err := datasource.GetAPIKeyManager().GetAPIKey(ctx, id)
+	if err != nil {
+		if err == datasource.ErrAPIKeyNotExist {
+			return nil, rbac.NewError(ErrAPIKeyNotExist, fmt.Sprintf("api key [%s] not exist", id))
+		}
+		log.Errorf(err, "get api key [%s] failed", id)
+		return nil, err
+	}
+	if k.Account != account {
+		return nil, rbac.NewError(ErrAPIKeyNotExist, fmt.Sprintf("api key [%s] not exist", id))
+	}
+	return k, nil
+}
+
+func newAPIKeySecret() (string, error) {
+	b := make([]byte, apiKeySecretSize)
+	if _, err := rand.Read(b); err != nil {
+		log.Error("generate api key secret failed", err)
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashAPIKeySecret uses sha256 instead of scrypt, the secret is random
+// enough and the hash is calculated for every request
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func toAPIKeyResponse(k *datasource.APIKey) *rbacpkg.APIKey {
+	return &rbacpkg.APIKey{
+		ID:         k.ID,
+		Account:    k.Account,
+		Name:       k.Name,
+		Roles:      k.Roles,
+		ExpireAt:   k.ExpireAt,
+		CreateTime: k.CreateTime,
+		UpdateTime: k.UpdateTime,
+	}
+}
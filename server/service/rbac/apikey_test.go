@@ -0,0 +1,143 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestAPIKey(t *testing.T) {
+	ctx := context.TODO()
+	name := "TestAPIKey_account"
+	a := newAccount(name)
+	a.Roles = []string{rbac.RoleAdmin, rbac.RoleDeveloper}
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, a))
+	defer rbacsvc.DeleteAccount(ctx, name)
+
+	var key *rbacpkg.APIKey
+	t.Run("create api key, should succeed", func(t *testing.T) {
+		var err error
+		key, err = rbacsvc.CreateAPIKey(ctx, name, &rbacpkg.APIKeyRequest{
+			Name:        "ci",
+			Roles:       []string{rbac.RoleDeveloper},
+			ExpireAfter: "1h",
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, key.Key)
+		assert.Equal(t, name, key.Account)
+		assert.True(t, key.ExpireAt > time.Now().Unix())
+	})
+	t.Run("create api key with role not bound, should fail", func(t *testing.T) {
+		_, err := rbacsvc.CreateAPIKey(ctx, name, &rbacpkg.APIKeyRequest{Roles: []string{"other"}})
+		assert.Error(t, err)
+		assert.Equal(t, rbac.ErrAccountHasInvalidRole, err.(*errsvc.Error).Code)
+	})
+	t.Run("create api key with invalid expireAfter, should fail", func(t *testing.T) {
+		_, err := rbacsvc.CreateAPIKey(ctx, name, &rbacpkg.APIKeyRequest{ExpireAfter: "-1h"})
+		assert.Error(t, err)
+	})
+	t.Run("list api keys, should not return the secret", func(t *testing.T) {
+		keys, err := rbacsvc.ListAPIKey(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(keys))
+		assert.Equal(t, key.ID, keys[0].ID)
+		assert.Empty(t, keys[0].Key)
+	})
+	t.Run("authenticate api key, should return the key roles", func(t *testing.T) {
+		claims, err := rbacsvc.AuthenticateAPIKey(ctx, key.Key)
+		assert.NoError(t, err)
+		account, err := rbac.GetAccount(claims)
+		assert.NoError(t, err)
+		assert.Equal(t, name, account.Name)
+		assert.Equal(t, []string{rbac.RoleDeveloper}, account.Roles)
+		assert.Equal(t, key.ID, claims[rbacsvc.ClaimsAPIKey])
+
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, key.ID+".wrong")
+		assert.Equal(t, rbacsvc.ErrAPIKeyInvalid, err.(*errsvc.Error).Code)
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, "wrong")
+		assert.Equal(t, rbacsvc.ErrAPIKeyInvalid, err.(*errsvc.Error).Code)
+	})
+	t.Run("rotate api key, the old key should be invalid", func(t *testing.T) {
+		rotated, err := rbacsvc.RotateAPIKey(ctx, name, key.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, key.ID, rotated.ID)
+		assert.NotEqual(t, key.Key, rotated.Key)
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, key.Key)
+		assert.Error(t, err)
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, rotated.Key)
+		assert.NoError(t, err)
+		key = rotated
+	})
+	t.Run("rotate the key of other account, should fail", func(t *testing.T) {
+		_, err := rbacsvc.RotateAPIKey(ctx, "other", key.ID)
+		assert.Equal(t, rbacsvc.ErrAPIKeyNotExist, err.(*errsvc.Error).Code)
+	})
+	t.Run("revoke api key, should succeed", func(t *testing.T) {
+		assert.NoError(t, rbacsvc.RevokeAPIKey(ctx, name, key.ID))
+		_, err := rbacsvc.AuthenticateAPIKey(ctx, key.Key)
+		assert.Equal(t, rbacsvc.ErrAPIKeyInvalid, err.(*errsvc.Error).Code)
+		err = rbacsvc.RevokeAPIKey(ctx, name, key.ID)
+		assert.Equal(t, rbacsvc.ErrAPIKeyNotExist, err.(*errsvc.Error).Code)
+	})
+	t.Run("inactive or banned account, should reject its api keys", func(t *testing.T) {
+		other := newAccount("TestAPIKey_inactive_account")
+		assert.NoError(t, rbacsvc.CreateAccount(ctx, other))
+		defer rbacsvc.DeleteAccount(ctx, other.Name)
+		k, err := rbacsvc.CreateAPIKey(ctx, other.Name, &rbacpkg.APIKeyRequest{})
+		assert.NoError(t, err)
+
+		banKey := rbacsvc.MakeBanKey(other.Name, util.GetIPFromContext(ctx))
+		for i := 0; i <= rbacsvc.MaxAttempts; i++ {
+			rbacsvc.TryLockAccount(banKey)
+		}
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, k.Key)
+		assert.Equal(t, rbac.ErrAccountBlocked, err.(*errsvc.Error).Code)
+
+		a, err := rbacsvc.GetAccount(ctx, other.Name)
+		assert.NoError(t, err)
+		a.Status = rbacsvc.StatusInactive
+		assert.NoError(t, datasource.GetAccountManager().UpdateAccount(ctx, other.Name, a))
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, k.Key)
+		assert.Equal(t, rbacsvc.ErrAccountInactive, err.(*errsvc.Error).Code)
+	})
+	t.Run("delete account, should revoke its api keys", func(t *testing.T) {
+		other := newAccount("TestAPIKey_delete_account")
+		assert.NoError(t, rbacsvc.CreateAccount(ctx, other))
+		k, err := rbacsvc.CreateAPIKey(ctx, other.Name, &rbacpkg.APIKeyRequest{})
+		assert.NoError(t, err)
+		assert.NoError(t, rbacsvc.DeleteAccount(ctx, other.Name))
+		_, err = rbacsvc.AuthenticateAPIKey(ctx, k.Key)
+		assert.Error(t, err)
+	})
+}
+
+func TestAPIKeyRoles(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, rbacsvc.APIKeyRoles([]string{"a", "b"}, nil))
+	assert.Equal(t, []string{"b"}, rbacsvc.APIKeyRoles([]string{"a", "b"}, []string{"b", "c"}))
+	assert.Empty(t, rbacsvc.APIKeyRoles([]string{"a"}, []string{"c"}))
+}
@@ -69,17 +69,47 @@ func (a *EmbeddedAuthenticator) Login(ctx context.Context, user string, password
 		TryLockAccount(MakeBanKey(user, ip))
 		return "", rbac.NewError(rbac.ErrUserOrPwdWrong, "")
 	}
-
-	secret, err := GetPrivateKey()
+	passwordExpired, err := checkAccountExpiry(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	totpRequired, err := checkTOTP(ctx, account)
 	if err != nil {
+		if errsvc.IsErrEqualCode(err, ErrTOTPInvalid) {
+			TryLockAccount(MakeBanKey(user, ip))
+		}
+		return "", err
+	}
+	if err = activateAccount(ctx, user); err != nil {
 		return "", err
 	}
-	tokenStr, err := token.Sign(map[string]interface{}{
+
+	claims := map[string]interface{}{
 		rbac.ClaimsUser:  user,
 		rbac.ClaimsRoles: account.Roles,
-	},
+		ClaimsSource:     SourceLocal,
+	}
+	if passwordExpired {
+		claims[ClaimsPasswordExpired] = true
+	}
+	if totpRequired {
+		claims[ClaimsTOTPRequired] = true
+	}
+	return signToken(ctx, claims, opt.ExpireAfter)
+}
+
+//signToken signs the claims with a new session
+func signToken(ctx context.Context, claims map[string]interface{}, expireAfter string) (string, error) {
+	secret, err := GetPrivateKey()
+	if err != nil {
+		return "", err
+	}
+	if err = newSession(ctx, claims, expireAfter); err != nil {
+		return "", err
+	}
+	tokenStr, err := token.Sign(claims,
 		secret,
-		token.WithExpTime(opt.ExpireAfter),
+		token.WithExpTime(expireAfter),
 		token.WithSigningMethod(token.RS512)) //TODO config for each user
 	if err != nil {
 		log.Errorf(err, "can not sign a token")
@@ -90,18 +120,15 @@ func (a *EmbeddedAuthenticator) Login(ctx context.Context, user string, password
 
 //Authenticate parse a token to claims
 func (a *EmbeddedAuthenticator) Authenticate(ctx context.Context, tokenStr string) (interface{}, error) {
-	p, err := jwt.ParseRSAPublicKeyFromPEM([]byte(PublicKey()))
-	if err != nil {
-		log.Error("can not parse public key", err)
-		return nil, err
-	}
-	claims, err := a.authToken(tokenStr, p)
+	claims, err := a.verifyToken(tokenStr)
 	if err != nil {
-		if a.isTokenExpiredError(err) {
-			return nil, rbac.NewError(rbac.ErrTokenExpired, "")
-		}
 		return nil, err
 	}
+	return a.checkAccount(ctx, claims)
+}
+
+//checkAccount return the claims if the token owned account still exists
+func (a *EmbeddedAuthenticator) checkAccount(ctx context.Context, claims map[string]interface{}) (interface{}, error) {
 	accountNameI := claims[rbac.ClaimsUser]
 	n, ok := accountNameI.(string)
 	if !ok {
@@ -118,6 +145,27 @@ func (a *EmbeddedAuthenticator) Authenticate(ctx context.Context, tokenStr strin
 	return claims, nil
 }
 
+//verifyToken parse the token signed by sc private key to claims
+func (a *EmbeddedAuthenticator) verifyToken(tokenStr string) (map[string]interface{}, error) {
+	p, err := jwt.ParseRSAPublicKeyFromPEM([]byte(PublicKey()))
+	if err != nil {
+		log.Error("can not parse public key", err)
+		return nil, err
+	}
+	claims, err := a.authToken(tokenStr, p)
+	if err != nil {
+		if a.isTokenExpiredError(err) {
+			return nil, rbac.NewError(rbac.ErrTokenExpired, "")
+		}
+		return nil, err
+	}
+	if IsTokenRevoked(claims) {
+		return nil, rbac.NewError(ErrTokenRevoked, "")
+	}
+	claims[claimsVerifier] = embeddedVerifier{}
+	return claims, nil
+}
+
 func (a *EmbeddedAuthenticator) isTokenExpiredError(err error) bool {
 	if err == nil {
 		return false
@@ -24,6 +24,7 @@ import (
 
 	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
 	"github.com/apache/servicecomb-service-center/server/plugin/auth"
 )
 
@@ -73,9 +74,11 @@ func FilterLabel(targetResourceLabel []map[string]string, permLabelList []map[st
 	return l
 }
 
+// LabelMatched checks the target labels match all the permission labels,
+// the permission label value supports the wildcard '*', e.g. order-*
 func LabelMatched(targetResourceLabel map[string]string, permLabel map[string]string) bool {
 	for k, v := range permLabel {
-		if vv := targetResourceLabel[k]; vv != v {
+		if vv := targetResourceLabel[k]; !util.WildcardMatch(v, vv) {
 			return false
 		}
 	}
@@ -83,11 +86,29 @@ func LabelMatched(targetResourceLabel map[string]string, permLabel map[string]st
 }
 
 func getPermsByRoles(ctx context.Context, roleList []string) ([]*rbac.Permission, error) {
+	roles, err := getRoles(ctx, roleList)
+	if err != nil {
+		return nil, err
+	}
 	var allPerms = make([]*rbac.Permission, 0)
+	for _, r := range roles {
+		allPerms = append(allPerms, r.Perms...)
+	}
+	return allPerms, nil
+}
+
+// getRoles returns the existing roles of the role list in order,
+// followed by the ancestors they inherit
+func getRoles(ctx context.Context, roleList []string) ([]*rbac.Role, error) {
+	roleList, err := expandRoles(ctx, roleList)
+	if err != nil {
+		return nil, err
+	}
+	var roles = make([]*rbac.Role, 0, len(roleList))
 	for _, name := range roleList {
 		r, err := datasource.GetRoleManager().GetRole(ctx, name)
 		if err == nil {
-			allPerms = append(allPerms, r.Perms...)
+			roles = append(roles, r)
 			continue
 		}
 		if err == datasource.ErrRoleNotExist {
@@ -97,7 +118,7 @@ func getPermsByRoles(ctx context.Context, roleList []string) ([]*rbac.Permission
 		log.Errorf(err, "get role [%s] failed", name)
 		return nil, err
 	}
-	return allPerms, nil
+	return roles, nil
 }
 
 // GetLabel checks if the perms have permission to operate the resource(ignore label),
@@ -172,6 +172,12 @@ func TestLabelMatched(t *testing.T) {
 		}
 		assert.True(t, rbacsvc.LabelMatched(targetResourceLabel, permResourceLabel))
 	})
+	t.Run("permission resource label has wildcard value, should match by prefix", func(t *testing.T) {
+		assert.True(t, rbacsvc.LabelMatched(targetResourceLabel, map[string]string{"environment": "prod*"}))
+		assert.True(t, rbacsvc.LabelMatched(targetResourceLabel, map[string]string{"appId": "*"}))
+		assert.False(t, rbacsvc.LabelMatched(targetResourceLabel, map[string]string{"environment": "test*"}))
+		assert.False(t, rbacsvc.LabelMatched(targetResourceLabel, map[string]string{"serviceName": "*-order"}))
+	})
 }
 func TestFilterLabel(t *testing.T) {
 	targetResourceLabel := []map[string]string{
@@ -0,0 +1,232 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+)
+
+const (
+	jwksFetchTimeout     = 10 * time.Second
+	jwksMinFetchInterval = 10 * time.Second
+)
+
+var ErrJWKSKeyNotFound = errors.New("key not found in JWKS")
+
+// JSONWebKey is the public key in the JSON Web Key Set, RFC 7517
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// PublicKey converts the key to *rsa.PublicKey or *ecdsa.PublicKey
+func (k *JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// ParseJWKS parses the JSON Web Key Set to the map of kid to public key,
+// the keys not for signature are ignored
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	set := &JSONWebKeySet{}
+	if err := json.Unmarshal(data, set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for i, k := range set.Keys {
+		if len(k.Use) > 0 && k.Use != "sig" {
+			continue
+		}
+		pub, err := set.Keys[i].PublicKey()
+		if err != nil {
+			log.Warnf("ignore the key [%s] in JWKS: %s", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	return keys, nil
+}
+
+// JWKSCache caches the keys from the local file or the http(s) url, and reloads
+// them on the refresh interval, or when a key is not found
+type JWKSCache struct {
+	source          string
+	refreshInterval time.Duration
+	client          *http.Client
+
+	mux       sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+func NewJWKSCache(source string, refreshInterval time.Duration) *JWKSCache {
+	return &JWKSCache{
+		source:          source,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: jwksFetchTimeout},
+	}
+}
+
+// Key returns the public key of the kid, the only key is returned if kid is empty
+func (c *JWKSCache) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	c.mux.RLock()
+	key, ok := c.lookup(kid)
+	expired := c.refreshInterval > 0 && time.Since(c.fetchedAt) > c.refreshInterval
+	c.mux.RUnlock()
+	if ok && !expired {
+		return key, nil
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if key, ok = c.lookup(kid); ok && !expired {
+		return key, nil
+	}
+	if time.Since(c.fetchedAt) < jwksMinFetchInterval {
+		// avoid fetching frequently by the tokens with unknown kid
+		if ok {
+			return key, nil
+		}
+		return nil, ErrJWKSKeyNotFound
+	}
+	if err := c.fetch(ctx); err != nil {
+		log.Errorf(err, "fetch JWKS from %s failed", c.source)
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+	if key, ok = c.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrJWKSKeyNotFound
+}
+
+func (c *JWKSCache) lookup(kid string) (crypto.PublicKey, bool) {
+	if len(kid) == 0 && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// fetch must be called with the write lock held
+func (c *JWKSCache) fetch(ctx context.Context) error {
+	c.fetchedAt = time.Now()
+	data, err := c.read(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+	c.keys = keys
+	log.Infof("load %d keys from JWKS %s", len(keys), c.source)
+	return nil
+}
+
+func (c *JWKSCache) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(c.source, "http://") && !strings.HasPrefix(c.source, "https://") {
+		return ioutil.ReadFile(c.source)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,365 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-chassis/v2/security/authr"
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/plugin/security/cipher"
+)
+
+const (
+	AuthenticatorLDAP = "ldap"
+
+	// ClaimsSource is the identity source of the token signed by sc
+	ClaimsSource = "source"
+	ClaimsDN     = "dn"
+	SourceLDAP   = "ldap"
+	// SourceLocal is the source of the token signed for the local account
+	SourceLocal = "local"
+	// ClaimsGroups is the claim name of the ldap groups used by the role mappings
+	ClaimsGroups = "groups"
+
+	defaultLDAPUserFilter     = "(uid=%s)"
+	defaultLDAPGroupFilter    = "(member=%s)"
+	defaultLDAPGroupAttribute = "cn"
+	defaultLDAPRoleCacheTTL   = 5 * time.Minute
+	ldapTimeout               = 10 * time.Second
+)
+
+var (
+	ErrLDAPNotConfigured = errors.New("ldap url and baseDN are required")
+	ErrLDAPUserNotFound  = errors.New("user not found in ldap")
+)
+
+//LDAPAuthenticator authenticates the accounts by the corporate directory,
+//the groups of the user are mapped to the roles and cached with a TTL.
+//The local accounts, e.g. root, are still handled by EmbeddedAuthenticator
+type LDAPAuthenticator struct {
+	*EmbeddedAuthenticator
+	cfg          *config.LDAP
+	bindPassword string
+	local        map[string]bool
+	roles        *roleCache
+}
+
+func newLDAPAuthenticator(opts *authr.Options) (authr.Authenticator, error) {
+	return NewLDAPAuthenticator(config.GetLDAP())
+}
+
+func NewLDAPAuthenticator(cfg *config.LDAP) (*LDAPAuthenticator, error) {
+	if cfg == nil || len(cfg.URL) == 0 || len(cfg.BaseDN) == 0 {
+		return nil, ErrLDAPNotConfigured
+	}
+	c := *cfg
+	if len(c.UserFilter) == 0 {
+		c.UserFilter = defaultLDAPUserFilter
+	}
+	if len(c.GroupBaseDN) == 0 {
+		c.GroupBaseDN = c.BaseDN
+	}
+	if len(c.GroupFilter) == 0 {
+		c.GroupFilter = defaultLDAPGroupFilter
+	}
+	if len(c.GroupAttribute) == 0 {
+		c.GroupAttribute = defaultLDAPGroupAttribute
+	}
+	ttl := defaultLDAPRoleCacheTTL
+	if len(c.RoleCacheTTL) > 0 {
+		d, err := time.ParseDuration(c.RoleCacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid roleCacheTTL: %v", err)
+		}
+		ttl = d
+	}
+	local := map[string]bool{RootName: true}
+	if len(c.LocalAccounts) > 0 {
+		local = make(map[string]bool, len(c.LocalAccounts))
+		for _, name := range c.LocalAccounts {
+			local[name] = true
+		}
+	}
+	bindPassword, err := cipher.Decrypt(c.BindPassword)
+	if err != nil {
+		log.Warn("cipher fallback: " + err.Error())
+		bindPassword = c.BindPassword
+	}
+	return &LDAPAuthenticator{
+		EmbeddedAuthenticator: &EmbeddedAuthenticator{},
+		cfg:                   &c,
+		bindPassword:          bindPassword,
+		local:                 local,
+		roles:                 newRoleCache(ttl),
+	}, nil
+}
+
+//Login binds the user with the password in ldap, and signs a token with the mapped roles
+func (a *LDAPAuthenticator) Login(ctx context.Context, user string, password string, opts ...authr.LoginOption) (string, error) {
+	if a.local[user] {
+		return a.EmbeddedAuthenticator.Login(ctx, user, password, opts...)
+	}
+	ip := util.GetIPFromContext(ctx)
+	banKey := MakeBanKey(user, ip)
+	if IsBanned(banKey) {
+		log.Warnf("ip [%s] is banned, account: %s", ip, user)
+		return "", rbac.NewError(rbac.ErrAccountBlocked, "")
+	}
+	opt := &authr.LoginOptions{}
+	for _, o := range opts {
+		o(opt)
+	}
+	// empty password means an unauthenticated bind, which always succeeds
+	if len(password) == 0 {
+		TryLockAccount(banKey)
+		return "", rbac.NewError(rbac.ErrUserOrPwdWrong, "")
+	}
+
+	conn, err := a.dial()
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	dn, err := a.searchUser(conn, user)
+	if err != nil {
+		if err == ErrLDAPUserNotFound {
+			TryLockAccount(banKey)
+			return "", rbac.NewError(rbac.ErrUserOrPwdWrong, "")
+		}
+		return "", err
+	}
+	if err := conn.Bind(dn, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			TryLockAccount(banKey)
+			return "", rbac.NewError(rbac.ErrUserOrPwdWrong, "")
+		}
+		log.Errorf(err, "ldap bind user [%s] failed", dn)
+		return "", err
+	}
+	roles, err := a.searchRoles(conn, dn)
+	if err != nil {
+		return "", err
+	}
+	if len(roles) == 0 {
+		log.Warnf("no role mapped for ldap user [%s]", user)
+		return "", rbac.NewError(rbac.ErrNoPermission, ErrNoRoleMapped.Error())
+	}
+	a.roles.Set(user, roles)
+
+	return signToken(ctx, map[string]interface{}{
+		rbac.ClaimsUser:  user,
+		rbac.ClaimsRoles: roles,
+		ClaimsSource:     SourceLDAP,
+		ClaimsDN:         dn,
+	}, opt.ExpireAfter)
+}
+
+//Authenticate parse the token to claims, the roles of the ldap user are
+//replaced by the cached roles, which are reloaded from ldap after TTL
+func (a *LDAPAuthenticator) Authenticate(ctx context.Context, tokenStr string) (interface{}, error) {
+	claims, err := a.verifyToken(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	if source, _ := claims[ClaimsSource].(string); source != SourceLDAP {
+		return a.checkAccount(ctx, claims)
+	}
+	user, ok := claims[rbac.ClaimsUser].(string)
+	if !ok {
+		return nil, rbac.ErrConvert
+	}
+	dn, _ := claims[ClaimsDN].(string)
+	roles, ok := a.roles.Get(user)
+	if !ok {
+		roles, err = a.reloadRoles(dn)
+		if err != nil {
+			log.Errorf(err, "reload the roles of ldap user [%s] failed", user)
+			return nil, err
+		}
+		a.roles.Set(user, roles)
+	}
+	if len(roles) == 0 {
+		return nil, rbac.NewError(rbac.ErrNoPermission, ErrNoRoleMapped.Error())
+	}
+	claims[rbac.ClaimsRoles] = toInterfaces(roles)
+	return claims, nil
+}
+
+func (a *LDAPAuthenticator) dial() (*ldap.Conn, error) {
+	u, err := url.Parse(a.cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: a.cfg.InsecureSkipVerify, //nolint:gosec
+	}
+	conn, err := ldap.DialURL(a.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: ldapTimeout}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		log.Errorf(err, "connect to ldap [%s] failed", a.cfg.URL)
+		return nil, err
+	}
+	conn.SetTimeout(ldapTimeout)
+	if a.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			log.Errorf(err, "ldap [%s] start tls failed", a.cfg.URL)
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// bindService binds the service account, anonymous if BindDN is empty
+func (a *LDAPAuthenticator) bindService(conn *ldap.Conn) error {
+	if len(a.cfg.BindDN) == 0 {
+		return conn.UnauthenticatedBind("")
+	}
+	if err := conn.Bind(a.cfg.BindDN, a.bindPassword); err != nil {
+		log.Errorf(err, "ldap bind [%s] failed", a.cfg.BindDN)
+		return err
+	}
+	return nil
+}
+
+func (a *LDAPAuthenticator) searchUser(conn *ldap.Conn, user string) (string, error) {
+	if err := a.bindService(conn); err != nil {
+		return "", err
+	}
+	req := ldap.NewSearchRequest(a.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf(a.cfg.UserFilter, ldap.EscapeFilter(user)), []string{"dn"}, nil)
+	resp, err := conn.Search(req)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return "", ErrLDAPUserNotFound
+		}
+		log.Errorf(err, "ldap search user [%s] failed", user)
+		return "", err
+	}
+	if len(resp.Entries) != 1 {
+		if len(resp.Entries) > 1 {
+			log.Warnf("ldap user [%s] is not unique", user)
+		}
+		return "", ErrLDAPUserNotFound
+	}
+	return resp.Entries[0].DN, nil
+}
+
+// searchRoles binds the service account and maps the groups of the user DN to the roles
+func (a *LDAPAuthenticator) searchRoles(conn *ldap.Conn, dn string) ([]string, error) {
+	if err := a.bindService(conn); err != nil {
+		return nil, err
+	}
+	req := ldap.NewSearchRequest(a.cfg.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf(a.cfg.GroupFilter, ldap.EscapeFilter(dn)), []string{a.cfg.GroupAttribute}, nil)
+	resp, err := conn.Search(req)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+		log.Errorf(err, "ldap search the groups of [%s] failed", dn)
+		return nil, err
+	}
+	var groups []interface{}
+	if resp != nil {
+		for _, e := range resp.Entries {
+			for _, g := range e.GetAttributeValues(a.cfg.GroupAttribute) {
+				groups = append(groups, g)
+			}
+		}
+	}
+	mapped := MapRoles(a.cfg.RoleMappings, map[string]interface{}{ClaimsGroups: groups})
+	roles := make([]string, 0, len(mapped))
+	for _, r := range mapped {
+		roles = append(roles, r.(string))
+	}
+	return roles, nil
+}
+
+func (a *LDAPAuthenticator) reloadRoles(dn string) ([]string, error) {
+	if len(dn) == 0 {
+		return nil, ErrLDAPUserNotFound
+	}
+	conn, err := a.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return a.searchRoles(conn, dn)
+}
+
+func toInterfaces(roles []string) []interface{} {
+	l := make([]interface{}, 0, len(roles))
+	for _, r := range roles {
+		l = append(l, r)
+	}
+	return l
+}
+
+// roleCache caches the role bindings of the users with a TTL
+type roleCache struct {
+	ttl   time.Duration
+	mux   sync.RWMutex
+	items map[string]*roleCacheItem
+}
+
+type roleCacheItem struct {
+	roles    []string
+	expireAt time.Time
+}
+
+func newRoleCache(ttl time.Duration) *roleCache {
+	return &roleCache{ttl: ttl, items: make(map[string]*roleCacheItem)}
+}
+
+func (c *roleCache) Get(user string) ([]string, bool) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	item, ok := c.items[user]
+	if !ok || time.Now().After(item.expireAt) {
+		return nil, false
+	}
+	return item.roles, true
+}
+
+func (c *roleCache) Set(user string, roles []string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	now := time.Now()
+	for name, item := range c.items {
+		if now.After(item.expireAt) {
+			delete(c.items, name)
+		}
+	}
+	c.items[user] = &roleCacheItem{roles: roles, expireAt: now.Add(c.ttl)}
+}
+
+func init() {
+	authr.Install(AuthenticatorLDAP, newLDAPAuthenticator)
+}
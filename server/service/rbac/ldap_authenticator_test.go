@@ -0,0 +1,220 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/lor00x/goldap/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/vjeantet/ldapserver"
+
+	"github.com/apache/servicecomb-service-center/server/config"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+const (
+	testLDAPBindDN   = "cn=admin,dc=example,dc=com"
+	testLDAPBindPwd  = "admin_pwd"
+	testLDAPPeople   = "ou=people,dc=example,dc=com"
+	testLDAPGroups   = "ou=groups,dc=example,dc=com"
+	testLDAPPassword = "ldap_pwd"
+)
+
+// fakeDirectory is an in-process ldap server with the users and the groups
+type fakeDirectory struct {
+	mux    sync.RWMutex
+	users  []string
+	groups map[string][]string // group -> member uid
+	server *ldapserver.Server
+}
+
+func userDN(uid string) string {
+	return "uid=" + uid + "," + testLDAPPeople
+}
+
+func (d *fakeDirectory) SetGroups(groups map[string][]string) {
+	d.mux.Lock()
+	d.groups = groups
+	d.mux.Unlock()
+}
+
+func (d *fakeDirectory) bind(w ldapserver.ResponseWriter, m *ldapserver.Message) {
+	r := m.GetBindRequest()
+	name, pwd := string(r.Name()), string(r.AuthenticationSimple())
+	code := ldapserver.LDAPResultInvalidCredentials
+	switch {
+	case name == testLDAPBindDN && pwd == testLDAPBindPwd:
+		code = ldapserver.LDAPResultSuccess
+	case strings.HasSuffix(name, ","+testLDAPPeople) && pwd == testLDAPPassword:
+		code = ldapserver.LDAPResultSuccess
+	}
+	w.Write(ldapserver.NewBindResponse(code))
+}
+
+func (d *fakeDirectory) search(w ldapserver.ResponseWriter, m *ldapserver.Message) {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	r := m.GetSearchRequest()
+	filter := r.FilterString()
+	switch string(r.BaseObject()) {
+	case testLDAPPeople:
+		for _, uid := range d.users {
+			if strings.Contains(filter, "uid="+uid+")") {
+				w.Write(ldapserver.NewSearchResultEntry(userDN(uid)))
+			}
+		}
+	case testLDAPGroups:
+		for group, members := range d.groups {
+			for _, uid := range members {
+				if strings.Contains(filter, userDN(uid)) {
+					e := ldapserver.NewSearchResultEntry("cn=" + group + "," + testLDAPGroups)
+					e.AddAttribute("cn", message.AttributeValue(group))
+					w.Write(e)
+				}
+			}
+		}
+	}
+	w.Write(ldapserver.NewSearchResultDoneResponse(ldapserver.LDAPResultSuccess))
+}
+
+func startFakeDirectory(t *testing.T) (*fakeDirectory, string) {
+	ldapserver.Logger = ldapserver.DiscardingLogger
+	d := &fakeDirectory{
+		users: []string{"peter", "jack"},
+		groups: map[string][]string{
+			"sc-admins":     {"peter"},
+			"sc-developers": {"peter"},
+			"others":        {"jack"},
+		},
+		server: ldapserver.NewServer(),
+	}
+	routes := ldapserver.NewRouteMux()
+	routes.Bind(d.bind)
+	routes.Search(d.search)
+	d.server.Handle(routes)
+
+	addr := make(chan string, 1)
+	go func() {
+		err := d.server.ListenAndServe("127.0.0.1:0", func(s *ldapserver.Server) {
+			addr <- s.Listener.Addr().String()
+		})
+		if err != nil {
+			t.Log(err)
+		}
+	}()
+	select {
+	case a := <-addr:
+		return d, "ldap://" + a
+	case <-time.After(5 * time.Second):
+		t.Fatal("start ldap server timeout")
+	}
+	return nil, ""
+}
+
+func newTestLDAPAuthenticator(t *testing.T, url string, ttl string) *rbacsvc.LDAPAuthenticator {
+	a, err := rbacsvc.NewLDAPAuthenticator(&config.LDAP{
+		URL:          url,
+		BindDN:       testLDAPBindDN,
+		BindPassword: testLDAPBindPwd,
+		BaseDN:       testLDAPPeople,
+		GroupBaseDN:  testLDAPGroups,
+		GroupFilter:  "(&(objectClass=groupOfNames)(member=%s))",
+		RoleMappings: []config.RoleMapping{
+			{Claim: rbacsvc.ClaimsGroups, Value: "sc-admins", Roles: []string{rbac.RoleAdmin}},
+			{Claim: rbacsvc.ClaimsGroups, Value: "sc-developers", Roles: []string{rbac.RoleDeveloper}},
+		},
+		RoleCacheTTL: ttl,
+	})
+	assert.NoError(t, err)
+	return a
+}
+
+func TestLDAPAuthenticator(t *testing.T) {
+	d, url := startFakeDirectory(t)
+	defer d.server.Stop()
+
+	ctx := context.Background()
+	a := newTestLDAPAuthenticator(t, url, "100ms")
+
+	t.Run("login with the mapped groups, should pass", func(t *testing.T) {
+		token, err := a.Login(ctx, "peter", testLDAPPassword)
+		assert.NoError(t, err)
+		claims, err := a.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		m := claims.(map[string]interface{})
+		assert.Equal(t, rbacsvc.SourceLDAP, m[rbacsvc.ClaimsSource])
+		assert.Equal(t, userDN("peter"), m[rbacsvc.ClaimsDN])
+		account, err := rbac.GetAccount(m)
+		assert.NoError(t, err)
+		assert.Equal(t, "peter", account.Name)
+		assert.ElementsMatch(t, []string{rbac.RoleAdmin, rbac.RoleDeveloper}, account.Roles)
+
+		t.Run("roles are cached until ttl", func(t *testing.T) {
+			d.SetGroups(map[string][]string{"sc-developers": {"peter"}})
+			defer d.SetGroups(map[string][]string{
+				"sc-admins":     {"peter"},
+				"sc-developers": {"peter"},
+				"others":        {"jack"},
+			})
+			claims, err := a.Authenticate(ctx, token)
+			assert.NoError(t, err)
+			account, err := rbac.GetAccount(claims.(map[string]interface{}))
+			assert.NoError(t, err)
+			assert.Len(t, account.Roles, 2)
+
+			time.Sleep(200 * time.Millisecond)
+			claims, err = a.Authenticate(ctx, token)
+			assert.NoError(t, err)
+			account, err = rbac.GetAccount(claims.(map[string]interface{}))
+			assert.NoError(t, err)
+			assert.Equal(t, []string{rbac.RoleDeveloper}, account.Roles)
+		})
+	})
+	t.Run("wrong password, should fail", func(t *testing.T) {
+		_, err := a.Login(ctx, "peter", "wrong_pwd")
+		assert.Error(t, err)
+		assert.Equal(t, rbac.ErrUserOrPwdWrong, err.(*errsvc.Error).Code)
+	})
+	t.Run("empty password, should fail", func(t *testing.T) {
+		_, err := a.Login(ctx, "peter", "")
+		assert.Error(t, err)
+		assert.Equal(t, rbac.ErrUserOrPwdWrong, err.(*errsvc.Error).Code)
+	})
+	t.Run("unknown user, should fail", func(t *testing.T) {
+		_, err := a.Login(ctx, "unknown", testLDAPPassword)
+		assert.Error(t, err)
+		assert.Equal(t, rbac.ErrUserOrPwdWrong, err.(*errsvc.Error).Code)
+	})
+	t.Run("user without mapped roles, should fail", func(t *testing.T) {
+		_, err := a.Login(ctx, "jack", testLDAPPassword)
+		assert.Error(t, err)
+		assert.Equal(t, rbac.ErrNoPermission, err.(*errsvc.Error).Code)
+	})
+	t.Run("invalid config, should fail", func(t *testing.T) {
+		_, err := rbacsvc.NewLDAPAuthenticator(&config.LDAP{URL: url})
+		assert.Equal(t, rbacsvc.ErrLDAPNotConfigured, err)
+		_, err = rbacsvc.NewLDAPAuthenticator(&config.LDAP{URL: url, BaseDN: testLDAPPeople, RoleCacheTTL: "x"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,181 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-chassis/v2/security/authr"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/server/config"
+)
+
+const (
+	AuthenticatorOIDC = "oidc"
+	// SourceOIDC is the source of the claims verified from the external token
+	SourceOIDC = "oidc"
+
+	defaultUserClaim           = "sub"
+	defaultJWKSRefreshInterval = time.Hour
+)
+
+var (
+	ErrOIDCNotConfigured = errors.New("oidc issuer and jwks are required")
+	ErrNoRoleMapped      = errors.New("no role mapped from the token claims")
+)
+
+//OIDCAuthenticator accepts the JWTs issued by the external OIDC provider,
+//and maps the claims to the roles by the role mapping rules.
+//The tokens of the other issuers and Login are handled by EmbeddedAuthenticator
+type OIDCAuthenticator struct {
+	*EmbeddedAuthenticator
+	cfg       *config.OIDC
+	userClaim string
+	keys      *JWKSCache
+}
+
+func newOIDCAuthenticator(opts *authr.Options) (authr.Authenticator, error) {
+	return NewOIDCAuthenticator(config.GetOIDC())
+}
+
+func NewOIDCAuthenticator(cfg *config.OIDC) (*OIDCAuthenticator, error) {
+	if cfg == nil || len(cfg.Issuer) == 0 || len(cfg.JWKS) == 0 {
+		return nil, ErrOIDCNotConfigured
+	}
+	interval := defaultJWKSRefreshInterval
+	if len(cfg.JWKSRefreshInterval) > 0 {
+		d, err := time.ParseDuration(cfg.JWKSRefreshInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwksRefreshInterval: %v", err)
+		}
+		interval = d
+	}
+	userClaim := cfg.UserClaim
+	if len(userClaim) == 0 {
+		userClaim = defaultUserClaim
+	}
+	return &OIDCAuthenticator{
+		EmbeddedAuthenticator: &EmbeddedAuthenticator{},
+		cfg:                   cfg,
+		userClaim:             userClaim,
+		keys:                  NewJWKSCache(cfg.JWKS, interval),
+	}, nil
+}
+
+//Authenticate verifies the token issued by the OIDC provider, and returns
+//the claims with the account name and the mapped roles only, the other
+//claims of the external token are never trusted
+func (a *OIDCAuthenticator) Authenticate(ctx context.Context, tokenStr string) (interface{}, error) {
+	unverified := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenStr, unverified); err != nil {
+		return nil, err
+	}
+	if iss, _ := unverified["iss"].(string); iss != a.cfg.Issuer {
+		return a.EmbeddedAuthenticator.Authenticate(ctx, tokenStr)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA, *jwt.SigningMethodRSAPSS:
+		default:
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		if a.isTokenExpiredError(err) {
+			return nil, rbac.NewError(rbac.ErrTokenExpired, "")
+		}
+		log.Error("verify oidc token failed", err)
+		return nil, err
+	}
+	if len(a.cfg.Audience) > 0 && !containsClaim(claims["aud"], a.cfg.Audience) {
+		return nil, fmt.Errorf("token audience does not contain %s", a.cfg.Audience)
+	}
+
+	user, ok := claims[a.userClaim].(string)
+	if !ok || len(user) == 0 {
+		return nil, fmt.Errorf("claim %s not found in token", a.userClaim)
+	}
+	roles := MapRoles(a.cfg.RoleMappings, claims)
+	if len(roles) == 0 {
+		log.Warnf("no role mapped for oidc user [%s]", user)
+		return nil, rbac.NewError(rbac.ErrNoPermission, ErrNoRoleMapped.Error())
+	}
+	return map[string]interface{}{
+		rbac.ClaimsUser:  user,
+		rbac.ClaimsRoles: roles,
+		ClaimsSource:     SourceOIDC,
+	}, nil
+}
+
+// MapRoles returns the roles of the matched mappings without duplicates
+func MapRoles(mappings []config.RoleMapping, claims map[string]interface{}) []interface{} {
+	var roles []interface{}
+	set := make(map[string]struct{})
+	for _, m := range mappings {
+		v, ok := claims[m.Claim]
+		if !ok {
+			continue
+		}
+		if len(m.Value) > 0 && !containsClaim(v, m.Value) {
+			continue
+		}
+		for _, r := range m.Roles {
+			if _, ok := set[r]; ok {
+				continue
+			}
+			set[r] = struct{}{}
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+// containsClaim returns true if the claim equals to the value,
+// or the claim is an array containing the value
+func containsClaim(claim interface{}, value string) bool {
+	switch v := claim.(type) {
+	case string:
+		return v == value
+	case []interface{}:
+		for _, e := range v {
+			if s, ok := e.(string); ok && s == value {
+				return true
+			}
+		}
+	case []string:
+		for _, s := range v {
+			if s == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func init() {
+	authr.Install(AuthenticatorOIDC, newOIDCAuthenticator)
+}
@@ -0,0 +1,220 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/server/config"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+const testIssuer = "https://idp.example.com"
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func writeJWKS(t *testing.T, path string, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) {
+	set := rbacsvc.JSONWebKeySet{Keys: []rbacsvc.JSONWebKey{
+		{Kty: "RSA", Kid: "rsa1", Use: "sig", N: encodeBigInt(rsaKey.N), E: encodeBigInt(big.NewInt(int64(rsaKey.E)))},
+		{Kty: "EC", Kid: "ec1", Crv: "P-256", X: encodeBigInt(ecKey.X), Y: encodeBigInt(ecKey.Y)},
+	}}
+	b, err := json.Marshal(set)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(path, b, 0600))
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	to := jwt.NewWithClaims(method, claims)
+	to.Header["kid"] = kid
+	s, err := to.SignedString(key)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestOIDCAuthenticator_Authenticate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "oidc")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	jwksFile := filepath.Join(dir, "jwks.json")
+	writeJWKS(t, jwksFile, rsaKey, ecKey)
+
+	a, err := rbacsvc.NewOIDCAuthenticator(&config.OIDC{
+		Issuer:    testIssuer,
+		Audience:  "service-center",
+		JWKS:      jwksFile,
+		UserClaim: "preferred_username",
+		RoleMappings: []config.RoleMapping{
+			{Claim: "groups", Value: "sc-admins", Roles: []string{rbac.RoleAdmin}},
+			{Claim: "groups", Value: "sc-developers", Roles: []string{rbac.RoleDeveloper}},
+			{Claim: "email_verified", Roles: []string{rbac.RoleDeveloper}},
+		},
+	})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	newClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":                testIssuer,
+			"aud":                []string{"service-center", "other"},
+			"sub":                "0001",
+			"preferred_username": "peter",
+			"groups":             []string{"sc-developers", "sc-admins"},
+			"exp":                time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	t.Run("rsa token with mapped roles, should pass", func(t *testing.T) {
+		claims, err := a.Authenticate(ctx, signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, newClaims()))
+		assert.NoError(t, err)
+		account, err := rbac.GetAccount(claims.(map[string]interface{}))
+		assert.NoError(t, err)
+		assert.Equal(t, "peter", account.Name)
+		assert.Equal(t, []string{rbac.RoleAdmin, rbac.RoleDeveloper}, account.Roles)
+	})
+	t.Run("token with the claims of sc, should not be forwarded", func(t *testing.T) {
+		c := newClaims()
+		c[rbacsvc.ClaimsSource] = rbacsvc.SourceLocal
+		c[rbacsvc.ClaimsTokenID] = "forged"
+		c[rbacsvc.ClaimsPasswordExpired] = true
+		claims, err := a.Authenticate(ctx, signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, c))
+		assert.NoError(t, err)
+		m := claims.(map[string]interface{})
+		assert.Equal(t, rbacsvc.SourceOIDC, m[rbacsvc.ClaimsSource])
+		assert.NotContains(t, m, rbacsvc.ClaimsTokenID)
+		assert.NotContains(t, m, rbacsvc.ClaimsPasswordExpired)
+		assert.NotContains(t, m, "groups")
+	})
+	t.Run("ecdsa token, should pass", func(t *testing.T) {
+		c := newClaims()
+		c["groups"] = "sc-developers"
+		claims, err := a.Authenticate(ctx, signToken(t, jwt.SigningMethodES256, "ec1", ecKey, c))
+		assert.NoError(t, err)
+		account, err := rbac.GetAccount(claims.(map[string]interface{}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{rbac.RoleDeveloper}, account.Roles)
+	})
+	t.Run("token signed by the other key, should fail", func(t *testing.T) {
+		_, err := a.Authenticate(ctx, signToken(t, jwt.SigningMethodRS256, "rsa1", otherKey, newClaims()))
+		assert.Error(t, err)
+	})
+	t.Run("token with unknown kid, should fail", func(t *testing.T) {
+		_, err := a.Authenticate(ctx, signToken(t, jwt.SigningMethodRS256, "unknown", rsaKey, newClaims()))
+		assert.Error(t, err)
+	})
+	t.Run("expired token, should fail", func(t *testing.T) {
+		c := newClaims()
+		c["exp"] = time.Now().Add(-time.Minute).Unix()
+		_, err := a.Authenticate(ctx, signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, c))
+		assert.Error(t, err)
+	})
+	t.Run("token of the other audience, should fail", func(t *testing.T) {
+		c := newClaims()
+		c["aud"] = "other"
+		_, err := a.Authenticate(ctx, signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, c))
+		assert.Error(t, err)
+	})
+	t.Run("token without user claim, should fail", func(t *testing.T) {
+		c := newClaims()
+		delete(c, "preferred_username")
+		_, err := a.Authenticate(ctx, signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, c))
+		assert.Error(t, err)
+	})
+	t.Run("token without mapped roles, should fail", func(t *testing.T) {
+		c := newClaims()
+		c["groups"] = []string{"others"}
+		_, err := a.Authenticate(ctx, signToken(t, jwt.SigningMethodRS256, "rsa1", rsaKey, c))
+		assert.Error(t, err)
+	})
+	t.Run("hmac token, should fail", func(t *testing.T) {
+		_, err := a.Authenticate(ctx, signToken(t, jwt.SigningMethodHS256, "rsa1", []byte("secret"), newClaims()))
+		assert.Error(t, err)
+	})
+}
+
+func TestJWKSCache_Key(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "jwks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	jwksFile := filepath.Join(dir, "jwks.json")
+	writeJWKS(t, jwksFile, rsaKey, ecKey)
+	data, err := ioutil.ReadFile(jwksFile)
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	for _, source := range []string{jwksFile, server.URL} {
+		c := rbacsvc.NewJWKSCache(source, time.Hour)
+		key, err := c.Key(context.Background(), "rsa1")
+		assert.NoError(t, err)
+		assert.Equal(t, rsaKey.N, key.(*rsa.PublicKey).N)
+		key, err = c.Key(context.Background(), "ec1")
+		assert.NoError(t, err)
+		assert.Equal(t, ecKey.X, key.(*ecdsa.PublicKey).X)
+		_, err = c.Key(context.Background(), "unknown")
+		assert.Equal(t, rbacsvc.ErrJWKSKeyNotFound, err)
+	}
+}
+
+func TestMapRoles(t *testing.T) {
+	mappings := []config.RoleMapping{
+		{Claim: "groups", Value: "a", Roles: []string{"r1", "r2"}},
+		{Claim: "department", Value: "rd", Roles: []string{"r2", "r3"}},
+		{Claim: "any", Roles: []string{"r4"}},
+	}
+	roles := rbacsvc.MapRoles(mappings, map[string]interface{}{
+		"groups":     []interface{}{"b", "a"},
+		"department": "rd",
+	})
+	assert.Equal(t, []interface{}{"r1", "r2", "r3"}, roles)
+	roles = rbacsvc.MapRoles(mappings, map[string]interface{}{"any": "x", "groups": "b"})
+	assert.Equal(t, []interface{}{"r4"}, roles)
+}
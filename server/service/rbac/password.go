@@ -95,6 +95,10 @@ func changePassword(ctx context.Context, name, currentPassword, pwd string) erro
 }
 
 func doChangePassword(ctx context.Context, old *rbac.Account, pwd string) error {
+	err := checkPasswordHistory(ctx, old, pwd)
+	if err != nil {
+		return err
+	}
 	hash, err := bcrypt.GenerateFromPassword([]byte(pwd), 14)
 	if err != nil {
 		log.Error("pwd hash failed", err)
@@ -106,5 +110,10 @@ func doChangePassword(ctx context.Context, old *rbac.Account, pwd string) error
 		log.Error("can not change pwd", err)
 		return err
 	}
-	return nil
+	if err = recordPassword(ctx, old.Name, old.Password); err != nil {
+		log.Errorf(err, "save account [%s] state failed", old.Name)
+		return err
+	}
+	// the tokens issued with the old password are invalid
+	return RevokeAccountSessions(ctx, old.Name)
 }
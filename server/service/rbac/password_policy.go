@@ -0,0 +1,209 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	"github.com/apache/servicecomb-service-center/pkg/privacy"
+	"github.com/apache/servicecomb-service-center/pkg/validate"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/service/validator"
+)
+
+const (
+	// ClaimsPasswordExpired is true if the password exceeds the max age when login,
+	// the token can only be used to change the password
+	ClaimsPasswordExpired = "passwordExpired"
+
+	StatusActive   = "active"
+	StatusInactive = "inactive"
+
+	ErrPasswordExpired int32 = 403220
+	ErrAccountInactive int32 = 403221
+
+	day = 24 * time.Hour
+)
+
+func init() {
+	rbac.MustRegisterErrs(map[int32]string{
+		ErrPasswordExpired: "Password is expired, change the password first",
+		ErrAccountInactive: "Account is inactive",
+	})
+}
+
+//PasswordPolicy is the password and account expiry rules, 0 means no limit
+type PasswordPolicy struct {
+	MinLength  int
+	MaxLength  int
+	MinClasses int
+	// HistorySize is the number of the recent passwords can not be reused
+	HistorySize int
+	// MaxAgeDays is the days the password must be changed after
+	MaxAgeDays int
+	// InactiveDays is the days the account becomes inactive after the last login
+	InactiveDays int
+}
+
+var passwordPolicy = &PasswordPolicy{}
+
+func initPasswordPolicy() {
+	SetPasswordPolicy(&PasswordPolicy{
+		MinLength:    config.GetInt("rbac.passwordPolicy.minLength", validate.DefaultPasswordMinLength),
+		MaxLength:    config.GetInt("rbac.passwordPolicy.maxLength", validate.DefaultPasswordMaxLength),
+		MinClasses:   config.GetInt("rbac.passwordPolicy.minCharacterClasses", validate.DefaultPasswordMinClasses),
+		HistorySize:  config.GetInt("rbac.passwordPolicy.historySize", 0),
+		MaxAgeDays:   config.GetInt("rbac.passwordPolicy.maxAgeDays", 0),
+		InactiveDays: config.GetInt("rbac.passwordPolicy.inactiveDays", 0),
+	})
+}
+
+//SetPasswordPolicy replaces the password policy
+func SetPasswordPolicy(p *PasswordPolicy) {
+	passwordPolicy = p
+	validator.SetPasswordPolicy(p.MinLength, p.MaxLength, p.MinClasses)
+}
+
+func getAccountState(ctx context.Context, name string) (*datasource.AccountState, error) {
+	s, err := datasource.GetAccountStateManager().GetAccountState(ctx, name)
+	if err == datasource.ErrAccountStateNotExist {
+		// the account created before the policy enabled starts from now
+		now := time.Now().Unix()
+		return &datasource.AccountState{Account: name, PasswordChangedAt: now, LastLoginAt: now}, nil
+	}
+	return s, err
+}
+
+//checkPasswordHistory returns error if the password is one of the recent passwords
+func checkPasswordHistory(ctx context.Context, old *rbac.Account, pwd string) error {
+	n := passwordPolicy.HistorySize
+	if n <= 0 {
+		return nil
+	}
+	s, err := getAccountState(ctx, old.Name)
+	if err != nil {
+		log.Errorf(err, "get account [%s] state failed", old.Name)
+		return err
+	}
+	history := s.PasswordHistory
+	if len(history) == 0 || history[0] != old.Password {
+		// the history is not recorded before the policy enabled
+		history = append([]string{old.Password}, history...)
+	}
+	if len(history) > n {
+		history = history[:n]
+	}
+	for _, hash := range history {
+		if privacy.SamePassword(hash, pwd) {
+			msg := fmt.Sprintf("the password can not be same as the last %d passwords", n)
+			return rbac.NewError(rbac.ErrNewPwdBad, msg)
+		}
+	}
+	return nil
+}
+
+//recordPassword saves the password hash to the history and restarts the password age
+func recordPassword(ctx context.Context, name, hash string) error {
+	s, err := getAccountState(ctx, name)
+	if err != nil {
+		log.Errorf(err, "get account [%s] state failed", name)
+		return err
+	}
+	var history []string
+	if n := passwordPolicy.HistorySize; n > 0 {
+		history = append([]string{hash}, s.PasswordHistory...)
+		if len(history) > n {
+			history = history[:n]
+		}
+	}
+	s.PasswordHistory = history
+	s.PasswordChangedAt = time.Now().Unix()
+	return datasource.GetAccountStateManager().UpsertAccountState(ctx, s)
+}
+
+//activateAccount restarts the inactivity period of the account
+func activateAccount(ctx context.Context, name string) error {
+	s, err := getAccountState(ctx, name)
+	if err != nil {
+		log.Errorf(err, "get account [%s] state failed", name)
+		return err
+	}
+	s.LastLoginAt = time.Now().Unix()
+	return datasource.GetAccountStateManager().UpsertAccountState(ctx, s)
+}
+
+//checkAccountExpiry is called after the password is verified, it marks the account
+//inactive if it did not login for the inactive days, the login time is recorded by
+//activateAccount after all the factors are verified.
+//the returned bool is true if the password must be changed
+func checkAccountExpiry(ctx context.Context, a *rbac.Account) (bool, error) {
+	if a.Status == StatusInactive {
+		return false, rbac.NewError(ErrAccountInactive, "")
+	}
+	s, err := getAccountState(ctx, a.Name)
+	if err != nil {
+		log.Errorf(err, "get account [%s] state failed", a.Name)
+		return false, err
+	}
+	// root never expires, otherwise nobody can activate the accounts
+	if inactive := passwordPolicy.InactiveDays; inactive > 0 && a.Name != RootName &&
+		time.Since(time.Unix(s.LastLoginAt, 0)) > time.Duration(inactive)*day {
+		a.Status = StatusInactive
+		if err = datasource.GetAccountManager().UpdateAccount(ctx, a.Name, a); err != nil {
+			log.Errorf(err, "inactivate account [%s] failed", a.Name)
+			return false, err
+		}
+		log.Warnf("account [%s] did not login for %d days, it is inactive", a.Name, inactive)
+		return false, rbac.NewError(ErrAccountInactive, "")
+	}
+	return passwordRemainingDays(s) == 0, nil
+}
+
+//passwordRemainingDays returns the days before the password expires, -1 if never
+func passwordRemainingDays(s *datasource.AccountState) int {
+	maxAge := passwordPolicy.MaxAgeDays
+	if maxAge <= 0 {
+		return -1
+	}
+	remaining := maxAge - int(time.Since(time.Unix(s.PasswordChangedAt, 0))/day)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+//PasswordRemainingDays returns the days before the password of the account expires,
+//the bool is false if the password never expires
+func PasswordRemainingDays(ctx context.Context, name string) (int, bool) {
+	if passwordPolicy.MaxAgeDays <= 0 {
+		return 0, false
+	}
+	s, err := datasource.GetAccountStateManager().GetAccountState(ctx, name)
+	if err != nil {
+		if err != datasource.ErrAccountStateNotExist {
+			log.Errorf(err, "get account [%s] state failed", name)
+		}
+		return 0, false
+	}
+	return passwordRemainingDays(s), true
+}
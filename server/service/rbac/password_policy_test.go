@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-chassis/v2/security/authr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	ctx := context.TODO()
+	name := "TestPasswordPolicy_account"
+	a := newAccount(name)
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, a))
+	defer rbacsvc.DeleteAccount(ctx, name)
+	defer rbacsvc.SetPasswordPolicy(&rbacsvc.PasswordPolicy{})
+
+	claims := map[string]interface{}{
+		rbac.ClaimsUser:  "TestPasswordPolicy_admin",
+		rbac.ClaimsRoles: []interface{}{rbac.RoleAdmin},
+	}
+	adminCtx := context.WithValue(ctx, rbacsvc.CtxRequestClaims, claims)
+	changePassword := func(pwd string) error {
+		return rbacsvc.ChangePassword(adminCtx, &rbac.Account{Name: name, Password: pwd})
+	}
+	setState := func(t *testing.T, f func(s *datasource.AccountState)) {
+		s, err := datasource.GetAccountStateManager().GetAccountState(ctx, name)
+		assert.NoError(t, err)
+		f(s)
+		assert.NoError(t, datasource.GetAccountStateManager().UpsertAccountState(ctx, s))
+	}
+	daysAgo := func(days int) int64 {
+		return time.Now().Add(-time.Duration(days) * 24 * time.Hour).Unix()
+	}
+
+	t.Run("change password with short length, should be failed", func(t *testing.T) {
+		rbacsvc.SetPasswordPolicy(&rbacsvc.PasswordPolicy{MinLength: 10})
+		err := changePassword("Ab@000000")
+		assert.Error(t, err)
+		assert.NoError(t, changePassword("Ab@0000000"))
+	})
+	t.Run("reuse the recent password, should be failed", func(t *testing.T) {
+		rbacsvc.SetPasswordPolicy(&rbacsvc.PasswordPolicy{HistorySize: 2})
+		assert.NoError(t, changePassword(testPwd0))
+		assert.NoError(t, changePassword(testPwd1))
+		err := changePassword(testPwd0)
+		assert.Error(t, err)
+		assert.Equal(t, rbac.ErrNewPwdBad, err.(*errsvc.Error).Code)
+		assert.NoError(t, changePassword("Ab@22222"))
+		assert.NoError(t, changePassword(testPwd0))
+	})
+	t.Run("login with the password older than max age, should be forced to change password", func(t *testing.T) {
+		rbacsvc.SetPasswordPolicy(&rbacsvc.PasswordPolicy{MaxAgeDays: 30})
+		days, ok := rbacsvc.PasswordRemainingDays(ctx, name)
+		assert.True(t, ok)
+		assert.Equal(t, 30, days)
+
+		setState(t, func(s *datasource.AccountState) { s.PasswordChangedAt = daysAgo(31) })
+		days, ok = rbacsvc.PasswordRemainingDays(ctx, name)
+		assert.True(t, ok)
+		assert.Equal(t, 0, days)
+		token, err := authr.Login(ctx, name, testPwd0, authr.ExpireAfter("1h"))
+		assert.NoError(t, err)
+		c, err := authr.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, true, c.(map[string]interface{})[rbacsvc.ClaimsPasswordExpired])
+
+		assert.NoError(t, changePassword(testPwd1))
+		token, err = authr.Login(ctx, name, testPwd1, authr.ExpireAfter("1h"))
+		assert.NoError(t, err)
+		c, err = authr.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		assert.Nil(t, c.(map[string]interface{})[rbacsvc.ClaimsPasswordExpired])
+	})
+	t.Run("login after inactive days, should be inactive", func(t *testing.T) {
+		rbacsvc.SetPasswordPolicy(&rbacsvc.PasswordPolicy{InactiveDays: 30})
+		setState(t, func(s *datasource.AccountState) { s.LastLoginAt = daysAgo(31) })
+		_, err := authr.Login(ctx, name, testPwd1)
+		assert.Error(t, err)
+		assert.Equal(t, rbacsvc.ErrAccountInactive, err.(*errsvc.Error).Code)
+		r, err := rbacsvc.GetAccount(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, rbacsvc.StatusInactive, r.Status)
+
+		err = rbacsvc.UpdateAccount(adminCtx, name, &rbac.Account{Status: rbacsvc.StatusActive})
+		assert.NoError(t, err)
+		_, err = authr.Login(ctx, name, testPwd1)
+		assert.NoError(t, err)
+	})
+}
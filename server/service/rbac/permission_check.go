@@ -0,0 +1,122 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
+)
+
+var ErrNoPermCheckAccount = errors.New("can not check other account permissions")
+
+// CheckPermission evaluates the request the same as the auth plugin does when
+// the request is applied. The subject is the account of the request token,
+// or the named account which only the admin role can check
+func CheckPermission(ctx context.Context, req *rbacpkg.PermissionCheckRequest) (*rbacpkg.PermissionCheckResponse, error) {
+	if len(req.Resource) == 0 || len(req.Verb) == 0 {
+		return nil, discovery.NewError(discovery.ErrInvalidParams, "resource and verb are required")
+	}
+	subject, err := AccountFromContext(ctx)
+	if err != nil {
+		return nil, discovery.NewError(discovery.ErrInternal, err.Error())
+	}
+	if len(req.Account) > 0 && req.Account != subject.Name {
+		if !hasAdminRole(subject.Roles) {
+			return nil, discovery.NewError(discovery.ErrForbidden, ErrNoPermCheckAccount.Error())
+		}
+		subject, err = GetAccount(ctx, req.Account)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &rbacpkg.PermissionCheckResponse{Account: subject.Name}
+	if hasAdminRole(subject.Roles) {
+		resp.Allowed = true
+		resp.Role = rbac.RoleAdmin
+		resp.Reason = "admin role has all permissions"
+		return resp, nil
+	}
+
+	target := &auth.ResourceScope{Type: req.Resource, Verb: req.Verb}
+	if len(req.Labels) > 0 {
+		target.Labels = []map[string]string{req.Labels}
+	}
+	resp.Allowed, resp.Labels, err = Allow(ctx, "", subject.Roles, target)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := getRoles(ctx, subject.Roles)
+	if err != nil {
+		return nil, err
+	}
+	resp.Role, resp.Permission, _ = Explain(roles, target)
+	resp.Reason = reason(resp)
+	log.Debugf("account [%s] check permission [%s %s %v]: %s",
+		subject.Name, req.Verb, req.Resource, req.Labels, resp.Reason)
+	return resp, nil
+}
+
+// Explain finds the role and permission decided the target resource scope.
+// If allowed, returns the first permission granted the target, otherwise
+// returns the first permission matched the resource type and verb but
+// denied by the labels, the role is empty if no permission matched
+func Explain(roles []*rbac.Role, target *auth.ResourceScope) (role string, perm *rbac.Permission, allow bool) {
+	for _, r := range roles {
+		for _, p := range r.Perms {
+			matched, labelList := GetLabelFromSinglePerm(p, target.Type, target.Verb)
+			if !matched {
+				continue
+			}
+			if len(labelList) == 0 || len(target.Labels) == 0 || len(FilterLabel(target.Labels, labelList)) > 0 {
+				return r.Name, p, true
+			}
+			if len(role) == 0 {
+				role, perm = r.Name, p
+			}
+		}
+	}
+	return
+}
+
+func reason(resp *rbacpkg.PermissionCheckResponse) string {
+	switch {
+	case resp.Allowed:
+		return fmt.Sprintf("granted by role [%s]", resp.Role)
+	case len(resp.Role) > 0:
+		return fmt.Sprintf("the labels do not match the permission of role [%s]", resp.Role)
+	default:
+		return "no permission of the roles matches the resource and verb"
+	}
+}
+
+func hasAdminRole(roleList []string) bool {
+	for _, r := range roleList {
+		if r == rbac.RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac_test
+
+import (
+	"testing"
+
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestExplain(t *testing.T) {
+	appX := &rbac.Permission{
+		Resources: []*rbac.Resource{{Type: rbacsvc.ResourceService, Labels: map[string]string{"app": "x"}}},
+		Verbs:     []string{"*"},
+	}
+	viewer := &rbac.Permission{
+		Resources: []*rbac.Resource{{Type: rbacsvc.ResourceService}},
+		Verbs:     []string{"get"},
+	}
+	roles := []*rbac.Role{
+		{Name: "app-x", Perms: []*rbac.Permission{appX}},
+		{Name: "viewer", Perms: []*rbac.Permission{viewer}},
+	}
+
+	t.Run("granted by labels", func(t *testing.T) {
+		role, perm, allow := rbacsvc.Explain(roles, &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "delete",
+			Labels: []map[string]string{{"app": "x", "env": "prod"}}})
+		assert.True(t, allow)
+		assert.Equal(t, "app-x", role)
+		assert.Equal(t, appX, perm)
+	})
+	t.Run("granted without labels", func(t *testing.T) {
+		role, perm, allow := rbacsvc.Explain(roles, &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "get",
+			Labels: []map[string]string{{"app": "y"}}})
+		assert.True(t, allow)
+		assert.Equal(t, "viewer", role)
+		assert.Equal(t, viewer, perm)
+	})
+	t.Run("denied by labels", func(t *testing.T) {
+		role, perm, allow := rbacsvc.Explain(roles, &auth.ResourceScope{Type: rbacsvc.ResourceService, Verb: "delete",
+			Labels: []map[string]string{{"app": "y"}}})
+		assert.False(t, allow)
+		assert.Equal(t, "app-x", role)
+		assert.Equal(t, appX, perm)
+	})
+	t.Run("no permission matched", func(t *testing.T) {
+		role, perm, allow := rbacsvc.Explain(roles, &auth.ResourceScope{Type: rbacsvc.ResourceAccount, Verb: "get"})
+		assert.False(t, allow)
+		assert.Empty(t, role)
+		assert.Nil(t, perm)
+	})
+}
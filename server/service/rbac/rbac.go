@@ -55,10 +55,12 @@ func Init() {
 		return
 	}
 	InitResourceMap()
-	err := authr.Init()
+	err := authr.Init(authr.WithPlugin(config.GetString("rbac.authenticator", "default")))
 	if err != nil {
 		log.Fatal("can not enable auth module", err)
 	}
+	initPasswordPolicy()
+	initTOTPPolicy()
 	// role init before account
 	initBuildInRole()
 	accountExist, err := AccountExist(context.Background(), RootName)
@@ -70,6 +72,7 @@ func Init() {
 	}
 	readPrivateKey()
 	readPublicKey()
+	startRevocationSync()
 	rbac.Add2WhiteAPIList(APITokenGranter)
 	log.Info("rbac is enabled")
 }
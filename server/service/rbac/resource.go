@@ -39,6 +39,14 @@ var (
 
 	APIAccountPassword = "/v4/accounts/:name/password"
 
+	APIAccountAPIKeys = "/v4/accounts/:name/apikeys"
+
+	APIAccountSessions = "/v4/accounts/:name/sessions"
+
+	APIAccountTOTP = "/v4/accounts/:name/totp"
+
+	APIPermissionCheck = "/v4/auth/permissions/check"
+
 	APIOps = "/v4/:project/admin"
 
 	APIGov = "/v1/:project/gov/"
@@ -49,6 +57,8 @@ var (
 	APIServicesList      = "/v4/:project/registry/microservices"
 	APIServiceProperties = "/v4/:project/registry/microservices/:serviceId/properties"
 	APIServiceExistence  = "/v4/:project/registry/existence"
+	APIServiceApply      = "/v4/:project/registry/apply"
+	APIQuotaUsage        = "/v4/:project/registry/quotas"
 
 	APIProConDependency = "/v4/:project/registry/microservices/:providerId/consumers"
 	APIConProDependency = "/v4/:project/registry/microservices/:consumerId/providers"
@@ -62,6 +72,7 @@ var (
 
 	APIServiceRule     = "/v4/:project/registry/microservices/:serviceId/rules"
 	APIServiceRuleList = "/v4/:project/registry/microservices/:serviceId/rules/rule_id"
+	APIServiceAccess   = "/v4/:project/registry/microservices/:serviceId/access"
 
 	APIServiceSchema = "/v4/:project/registry/microservices/:serviceId/schemas"
 )
@@ -84,6 +95,8 @@ func InitResourceMap() {
 	rbac.MapResource(APIServicesList, ResourceService)
 	rbac.MapResource(APIServiceProperties, ResourceService)
 	rbac.MapResource(APIServiceExistence, ResourceService)
+	rbac.MapResource(APIServiceApply, ResourceService)
+	rbac.MapResource(APIQuotaUsage, ResourceService)
 	rbac.MapResource(APIProConDependency, ResourceService)
 	rbac.MapResource(APIConProDependency, ResourceService)
 	rbac.MapResource(APIHeartbeats, ResourceService)
@@ -91,6 +104,7 @@ func InitResourceMap() {
 	rbac.MapResource(APIInstanceListWatcher, ResourceService)
 	rbac.MapResource(APIServiceRuleList, ResourceService)
 	rbac.MapResource(APIServiceRule, ResourceService)
+	rbac.MapResource(APIServiceAccess, ResourceService)
 	rbac.MapResource(APIServiceTag, ResourceService)
 	rbac.MapResource(APIServiceTagKey, ResourceService)
 }
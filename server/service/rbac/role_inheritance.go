@@ -0,0 +1,208 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-chassis/cari/rbac"
+	"github.com/patrickmn/go-cache"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
+)
+
+const ErrRoleInheritanceCycle int32 = 400224
+
+//ExpandedRolesCacheTTL is how long the expanded roles are cached,
+//the parent changes made by other instances are seen after it
+const ExpandedRolesCacheTTL = 30 * time.Second
+
+var expandedRoles = cache.New(ExpandedRolesCacheTTL, ExpandedRolesCacheTTL)
+
+func init() {
+	rbac.MustRegisterErrs(map[int32]string{
+		ErrRoleInheritanceCycle: "Role inheritance has a cycle",
+	})
+}
+
+//GetRoleParents returns the parent roles of the role, empty if it inherits nothing
+func GetRoleParents(ctx context.Context, role string) ([]string, error) {
+	r, err := datasource.GetRoleInheritanceManager().GetRoleInheritance(ctx, role)
+	if err != nil {
+		if err == datasource.ErrRoleInheritanceNotExist {
+			return nil, nil
+		}
+		log.Errorf(err, "get role [%s] parents failed", role)
+		return nil, err
+	}
+	return r.Parents, nil
+}
+
+//GetRoleWithParents returns the role and its parent roles
+func GetRoleWithParents(ctx context.Context, name string) (*rbacpkg.Role, error) {
+	r, err := GetRole(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := GetRoleParents(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &rbacpkg.Role{Role: *r, Parents: parents}, nil
+}
+
+//ListRoleWithParents returns all roles and their parent roles
+func ListRoleWithParents(ctx context.Context) ([]*rbacpkg.Role, int64, error) {
+	roles, num, err := ListRole(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	graph, err := roleGraph(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	l := make([]*rbacpkg.Role, 0, len(roles))
+	for _, r := range roles {
+		l = append(l, &rbacpkg.Role{Role: *r, Parents: graph[r.Name]})
+	}
+	return l, num, nil
+}
+
+//checkRoleParents returns error if the parents not exist or the role inherits itself through them
+func checkRoleParents(ctx context.Context, role string, parents []string) error {
+	if len(parents) == 0 {
+		return nil
+	}
+	if err := checkRoleNames(ctx, parents); err != nil {
+		return rbac.NewError(rbac.ErrRoleNotExist, fmt.Sprintf("parent role not exist: %s", err.Error()))
+	}
+	graph, err := roleGraph(ctx)
+	if err != nil {
+		return err
+	}
+	graph[role] = parents
+	if path := findRoleCycle(graph, role); len(path) > 0 {
+		return rbac.NewError(ErrRoleInheritanceCycle, strings.Join(path, " -> "))
+	}
+	return nil
+}
+
+//setRoleParents replaces the parents of the role, they are checked by checkRoleParents
+func setRoleParents(ctx context.Context, role string, parents []string) error {
+	var err error
+	if len(parents) == 0 {
+		err = datasource.GetRoleInheritanceManager().DeleteRoleInheritance(ctx, role)
+	} else {
+		err = datasource.GetRoleInheritanceManager().UpsertRoleInheritance(ctx,
+			&datasource.RoleInheritance{Role: role, Parents: parents})
+	}
+	if err != nil {
+		log.Errorf(err, "save role [%s] parents failed", role)
+		return err
+	}
+	expandedRoles.Flush()
+	return nil
+}
+
+//getRoleChildren returns the roles inherit the role directly
+func getRoleChildren(ctx context.Context, role string) ([]string, error) {
+	graph, err := roleGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var children []string
+	for child, parents := range graph {
+		for _, p := range parents {
+			if p == role {
+				children = append(children, child)
+				break
+			}
+		}
+	}
+	return children, nil
+}
+
+func roleGraph(ctx context.Context) (map[string][]string, error) {
+	rs, err := datasource.GetRoleInheritanceManager().ListRoleInheritance(ctx)
+	if err != nil {
+		log.Error("list role inheritance failed", err)
+		return nil, err
+	}
+	graph := make(map[string][]string, len(rs))
+	for _, r := range rs {
+		graph[r.Role] = r.Parents
+	}
+	return graph, nil
+}
+
+//findRoleCycle returns the path from the role back to itself, empty if no cycle
+func findRoleCycle(graph map[string][]string, role string) []string {
+	visited := make(map[string]bool)
+	var walk func(path []string) []string
+	walk = func(path []string) []string {
+		current := path[len(path)-1]
+		for _, p := range graph[current] {
+			if p == role {
+				return append(path, p)
+			}
+			if visited[p] {
+				continue
+			}
+			visited[p] = true
+			if cycle := walk(append(path, p)); len(cycle) > 0 {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk([]string{role})
+}
+
+//expandRoles returns the roles and all their ancestors, the roles first,
+//then the ancestors level by level, the result is cached for ExpandedRolesCacheTTL
+func expandRoles(ctx context.Context, roleList []string) ([]string, error) {
+	key := strings.Join(roleList, ",")
+	if v, ok := expandedRoles.Get(key); ok {
+		return v.([]string), nil
+	}
+	visited := make(map[string]bool, len(roleList))
+	expanded := make([]string, 0, len(roleList))
+	queue := roleList
+	for len(queue) > 0 {
+		var next []string
+		for _, name := range queue {
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			expanded = append(expanded, name)
+			parents, err := GetRoleParents(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, parents...)
+		}
+		queue = next
+	}
+	expandedRoles.SetDefault(key, expanded)
+	return expanded, nil
+}
@@ -0,0 +1,84 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-chassis/cari/discovery"
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/server/plugin/auth"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestRoleInheritance(t *testing.T) {
+	ctx := context.TODO()
+	parent := newRole("TestRoleInheritance_parent")
+	parent.Perms[0].Resources[0].Type = rbacsvc.ResourceSchema
+	child := newRole("TestRoleInheritance_child")
+	assert.NoError(t, rbacsvc.CreateRole(ctx, parent))
+
+	t.Run("create role with no exist parent, should return: "+rbac.NewError(rbac.ErrRoleNotExist, "").Error(), func(t *testing.T) {
+		err := rbacsvc.CreateRole(ctx, newRole("TestRoleInheritance_orphan"), "TestRoleInheritance_none")
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrRoleNotExist))
+	})
+	t.Run("create role inherits itself, should return: "+discovery.NewError(discovery.ErrInvalidParams, "").Error(), func(t *testing.T) {
+		err := rbacsvc.CreateRole(ctx, newRole("TestRoleInheritance_self"), "TestRoleInheritance_self")
+		assert.True(t, errsvc.IsErrEqualCode(err, discovery.ErrInvalidParams))
+	})
+	t.Run("create role with parent, should inherit the parent permissions", func(t *testing.T) {
+		err := rbacsvc.CreateRole(ctx, child, parent.Name)
+		assert.NoError(t, err)
+
+		r, err := rbacsvc.GetRoleWithParents(ctx, child.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{parent.Name}, r.Parents)
+
+		allow, _, err := rbacsvc.Allow(ctx, "", []string{child.Name},
+			&auth.ResourceScope{Type: rbacsvc.ResourceSchema, Verb: "get"})
+		assert.NoError(t, err)
+		assert.True(t, allow)
+	})
+	t.Run("edit parent to inherit the child, should return: "+rbac.NewError(rbacsvc.ErrRoleInheritanceCycle, "").Error(), func(t *testing.T) {
+		err := rbacsvc.EditRole(ctx, parent.Name, parent, child.Name)
+		assert.True(t, errsvc.IsErrEqualCode(err, rbacsvc.ErrRoleInheritanceCycle))
+	})
+	t.Run("delete the inherited role, should return: "+rbac.NewError(rbac.ErrRoleIsBound, "").Error(), func(t *testing.T) {
+		err := rbacsvc.DeleteRole(ctx, parent.Name)
+		assert.True(t, errsvc.IsErrEqualCode(err, rbac.ErrRoleIsBound))
+	})
+	t.Run("edit child to inherit nothing, should lose the parent permissions", func(t *testing.T) {
+		err := rbacsvc.EditRole(ctx, child.Name, child)
+		assert.NoError(t, err)
+
+		parents, err := rbacsvc.GetRoleParents(ctx, child.Name)
+		assert.NoError(t, err)
+		assert.Empty(t, parents)
+
+		allow, _, err := rbacsvc.Allow(ctx, "", []string{child.Name},
+			&auth.ResourceScope{Type: rbacsvc.ResourceSchema, Verb: "get"})
+		assert.NoError(t, err)
+		assert.False(t, allow)
+
+		assert.NoError(t, rbacsvc.DeleteRole(ctx, parent.Name))
+	})
+}
@@ -20,6 +20,7 @@ package rbac
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/go-chassis/cari/discovery"
 	"github.com/go-chassis/cari/rbac"
@@ -32,12 +33,18 @@ import (
 	"github.com/apache/servicecomb-service-center/server/service/validator"
 )
 
-func CreateRole(ctx context.Context, r *rbac.Role) error {
-	err := validator.ValidateCreateRole(r)
+//CreateRole creates the role, it inherits the permissions of the parent roles
+func CreateRole(ctx context.Context, r *rbac.Role, parents ...string) error {
+	err := validator.ValidateCreateRole(r, parents...)
 	if err != nil {
 		log.Errorf(err, "create role [%s] failed", r.Name)
 		return discovery.NewError(discovery.ErrInvalidParams, err.Error())
 	}
+	err = checkRoleParents(ctx, r.Name, parents)
+	if err != nil {
+		log.Errorf(err, "create role [%s] failed", r.Name)
+		return err
+	}
 	quotaErr := quota.Apply(ctx, quota.NewApplyQuotaResource(quota.TypeRole,
 		util.ParseDomainProject(ctx), "", 1))
 	if quotaErr != nil {
@@ -46,7 +53,7 @@ func CreateRole(ctx context.Context, r *rbac.Role) error {
 	err = datasource.GetRoleManager().CreateRole(ctx, r)
 	if err == nil {
 		log.Infof("create role [%s] success", r.Name)
-		return nil
+		return setRoleParents(ctx, r.Name, parents)
 	}
 
 	log.Errorf(err, "create role [%s] failed", r.Name)
@@ -89,6 +96,13 @@ func DeleteRole(ctx context.Context, name string) error {
 		log.Errorf(err, "role [%s] not exist", name)
 		return rbac.NewError(rbac.ErrRoleNotExist, "")
 	}
+	children, err := getRoleChildren(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return rbac.NewError(rbac.ErrRoleIsBound, fmt.Sprintf("role is inherited by %v", children))
+	}
 	succeed, err := datasource.GetRoleManager().DeleteRole(ctx, name)
 	if err != nil {
 		if errors.Is(err, datasource.ErrRoleBindingExist) {
@@ -99,10 +113,11 @@ func DeleteRole(ctx context.Context, name string) error {
 	if !succeed {
 		return errors.New("delete role failed, please retry")
 	}
-	return nil
+	return setRoleParents(ctx, name, nil)
 }
 
-func EditRole(ctx context.Context, name string, a *rbac.Role) error {
+//EditRole replaces the permissions and the parent roles of the role
+func EditRole(ctx context.Context, name string, a *rbac.Role, parents ...string) error {
 	if err := illegalRoleCheck(name); err != nil {
 		return err
 	}
@@ -121,6 +136,11 @@ func EditRole(ctx context.Context, name string, a *rbac.Role) error {
 		return err
 	}
 
+	err = checkRoleParents(ctx, name, parents)
+	if err != nil {
+		log.Errorf(err, "edit role [%s] failed", name)
+		return err
+	}
 	oldRole.Perms = a.Perms
 
 	err = datasource.GetRoleManager().UpdateRole(ctx, name, oldRole)
@@ -129,7 +149,7 @@ func EditRole(ctx context.Context, name string, a *rbac.Role) error {
 		return err
 	}
 	log.Infof("role [%s] is edit", oldRole.ID)
-	return nil
+	return setRoleParents(ctx, name, parents)
 }
 
 func illegalRoleCheck(role string) error {
@@ -0,0 +1,337 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/gopool"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+)
+
+const (
+	// ClaimsTokenID is the token id claim, it is the session id
+	ClaimsTokenID = "jti"
+	// ClaimsIssuedAt is the unix time in milliseconds the token issued at, not the
+	// standard iat claim which is rejected by the instances whose clock is behind
+	ClaimsIssuedAt = "issuedAt"
+
+	ErrSessionNotExist int32 = 400221
+	ErrTokenRevoked    int32 = 401222
+
+	revocationSyncInterval = 10 * time.Second
+	accountRevocationKey   = "account/"
+
+	// claimsVerifier marks the claims verified by EmbeddedAuthenticator
+	claimsVerifier = "_verifier"
+)
+
+// embeddedVerifier is the mark of the claims verified from the token signed by sc,
+// the value of the unexported type can not be set by the claims of any token
+type embeddedVerifier struct{}
+
+func init() {
+	rbac.MustRegisterErrs(map[int32]string{
+		ErrSessionNotExist: "Session not exists",
+		ErrTokenRevoked:    "Token is revoked",
+	})
+}
+
+// revocations is the memory cache of the revocation list, it is reloaded
+// from the datasource periodically to sync the revocations of other instances
+var revocations = newRevocationList()
+
+type revocationList struct {
+	mux sync.RWMutex
+	// tokens is the revoked token id to the token expiry
+	tokens map[string]int64
+	// accounts is the account name to the time all tokens revoked at
+	accounts map[string]int64
+}
+
+func newRevocationList() *revocationList {
+	return &revocationList{
+		tokens:   make(map[string]int64),
+		accounts: make(map[string]int64),
+	}
+}
+
+func (l *revocationList) Add(r *datasource.Revocation) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.add(r)
+}
+
+func (l *revocationList) add(r *datasource.Revocation) {
+	if len(r.TokenID) > 0 {
+		l.tokens[r.TokenID] = r.ExpireAt
+		return
+	}
+	if r.RevokedAt > l.accounts[r.Account] {
+		l.accounts[r.Account] = r.RevokedAt
+	}
+}
+
+// Merge adds the revocations loaded from the datasource and removes
+// the expired ones, a revocation is never canceled so merging is safe
+// even if the local revocations are added during loading
+func (l *revocationList) Merge(rs []*datasource.Revocation) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	for _, r := range rs {
+		l.add(r)
+	}
+	now := time.Now().Unix()
+	for id, expireAt := range l.tokens {
+		if expireAt > 0 && expireAt <= now {
+			delete(l.tokens, id)
+		}
+	}
+}
+
+// Revoked returns true if the token id is revoked, or the token is issued
+// not after the account revoked all tokens, the token without iat is issued
+// by the old version and treated as issued at 0
+func (l *revocationList) Revoked(account, tokenID string, issuedAt int64) bool {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	if _, ok := l.tokens[tokenID]; ok && len(tokenID) > 0 {
+		return true
+	}
+	revokedAt, ok := l.accounts[account]
+	return ok && issuedAt <= revokedAt
+}
+
+//IsTokenRevoked checks the claims of the token signed by sc against the revocation list
+func IsTokenRevoked(claims map[string]interface{}) bool {
+	account, _ := claims[rbac.ClaimsUser].(string)
+	tokenID, _ := claims[ClaimsTokenID].(string)
+	var issuedAt int64
+	if iat, ok := claims[ClaimsIssuedAt].(float64); ok {
+		issuedAt = int64(iat)
+	}
+	return revocations.Revoked(account, tokenID, issuedAt)
+}
+
+//IsLocalSession returns true if the claims are verified from the token signed
+//for the local account by sc, and the session of the token is still alive
+func IsLocalSession(ctx context.Context, claims map[string]interface{}) bool {
+	if _, ok := claims[claimsVerifier].(embeddedVerifier); !ok {
+		return false
+	}
+	if source, _ := claims[ClaimsSource].(string); source != SourceLocal {
+		return false
+	}
+	tokenID, _ := claims[ClaimsTokenID].(string)
+	if len(tokenID) == 0 {
+		return false
+	}
+	account, _ := claims[rbac.ClaimsUser].(string)
+	sessions, err := datasource.GetSessionManager().ListSession(ctx, account)
+	if err != nil {
+		log.Errorf(err, "list the sessions of account [%s] failed", account)
+		return false
+	}
+	now := time.Now().Unix()
+	for _, s := range sessions {
+		if s.ID == tokenID {
+			return s.ExpireAt == 0 || s.ExpireAt > now
+		}
+	}
+	return false
+}
+
+// newSession sets the token id and issued time claims, and saves the session
+func newSession(ctx context.Context, claims map[string]interface{}, expireAfter string) error {
+	now := time.Now()
+	s := &datasource.Session{
+		ID:       util.GenerateUUID(),
+		IP:       util.GetIPFromContext(ctx),
+		IssuedAt: now.Unix(),
+	}
+	issuedAt := now.UnixNano() / int64(time.Millisecond)
+	s.Account, _ = claims[rbac.ClaimsUser].(string)
+	if len(expireAfter) > 0 {
+		d, err := time.ParseDuration(expireAfter)
+		if err != nil {
+			return err
+		}
+		s.ExpireAt = now.Add(d).Unix()
+	}
+	claims[ClaimsTokenID] = s.ID
+	claims[ClaimsIssuedAt] = issuedAt
+	err := datasource.GetSessionManager().CreateSession(ctx, s)
+	if err != nil {
+		log.Errorf(err, "save the session of account [%s] failed", s.Account)
+		return err
+	}
+	return nil
+}
+
+//ListSession returns the alive sessions of the account
+func ListSession(ctx context.Context, account string) ([]*rbacpkg.Session, error) {
+	sessions, err := datasource.GetSessionManager().ListSession(ctx, account)
+	if err != nil {
+		log.Errorf(err, "list the sessions of account [%s] failed", account)
+		return nil, err
+	}
+	var current string
+	if claims, ok := ctx.Value(CtxRequestClaims).(map[string]interface{}); ok {
+		current, _ = claims[ClaimsTokenID].(string)
+	}
+	now := time.Now().Unix()
+	l := make([]*rbacpkg.Session, 0, len(sessions))
+	for _, s := range sessions {
+		if s.ExpireAt > 0 && s.ExpireAt <= now {
+			if err := datasource.GetSessionManager().DeleteSession(ctx, s.Account, s.ID); err != nil {
+				log.Errorf(err, "remove expired session [%s] failed", s.ID)
+			}
+			continue
+		}
+		if revocations.Revoked(s.Account, s.ID, s.IssuedAt) {
+			continue
+		}
+		l = append(l, &rbacpkg.Session{
+			ID:       s.ID,
+			Account:  s.Account,
+			IP:       s.IP,
+			IssuedAt: s.IssuedAt,
+			ExpireAt: s.ExpireAt,
+			Current:  s.ID == current,
+		})
+	}
+	return l, nil
+}
+
+//RevokeToken revokes the token of the claims, it is used to logout
+func RevokeToken(ctx context.Context, claims map[string]interface{}) error {
+	account, _ := claims[rbac.ClaimsUser].(string)
+	tokenID, _ := claims[ClaimsTokenID].(string)
+	if len(tokenID) == 0 {
+		// the token issued by the old version has no id, revoke all
+		return RevokeAccountSessions(ctx, account)
+	}
+	var expireAt int64
+	if exp, ok := claims["exp"].(float64); ok {
+		expireAt = int64(exp)
+	}
+	return revokeSession(ctx, &datasource.Session{ID: tokenID, Account: account, ExpireAt: expireAt})
+}
+
+//RevokeSession revokes a session of the account
+func RevokeSession(ctx context.Context, account, id string) error {
+	sessions, err := datasource.GetSessionManager().ListSession(ctx, account)
+	if err != nil {
+		log.Errorf(err, "list the sessions of account [%s] failed", account)
+		return err
+	}
+	for _, s := range sessions {
+		if s.ID == id {
+			return revokeSession(ctx, s)
+		}
+	}
+	return rbac.NewError(ErrSessionNotExist, "")
+}
+
+//RevokeAccountSessions revokes all the tokens of the account issued before now
+func RevokeAccountSessions(ctx context.Context, account string) error {
+	sessions, err := datasource.GetSessionManager().ListSession(ctx, account)
+	if err != nil {
+		log.Errorf(err, "list the sessions of account [%s] failed", account)
+		return err
+	}
+	for _, s := range sessions {
+		if err := revokeSession(ctx, s); err != nil {
+			return err
+		}
+	}
+	// revoke the tokens without session, e.g. issued by the old version
+	r := &datasource.Revocation{
+		Key:       accountRevocationKey + account,
+		Account:   account,
+		RevokedAt: time.Now().UnixNano() / int64(time.Millisecond),
+	}
+	if err := datasource.GetSessionManager().Revoke(ctx, r); err != nil {
+		log.Errorf(err, "revoke the sessions of account [%s] failed", account)
+		return err
+	}
+	revocations.Add(r)
+	log.Infof("all the sessions of account [%s] are revoked", account)
+	return nil
+}
+
+func revokeSession(ctx context.Context, s *datasource.Session) error {
+	r := &datasource.Revocation{
+		Key:       s.ID,
+		Account:   s.Account,
+		TokenID:   s.ID,
+		RevokedAt: time.Now().UnixNano() / int64(time.Millisecond),
+		ExpireAt:  s.ExpireAt,
+	}
+	if err := datasource.GetSessionManager().Revoke(ctx, r); err != nil {
+		log.Errorf(err, "revoke session [%s] failed", s.ID)
+		return err
+	}
+	revocations.Add(r)
+	if err := datasource.GetSessionManager().DeleteSession(ctx, s.Account, s.ID); err != nil {
+		log.Errorf(err, "remove session [%s] failed", s.ID)
+	}
+	log.Infof("session [%s] of account [%s] is revoked", s.ID, s.Account)
+	return nil
+}
+
+// syncRevocations reloads the revocation list and removes the expired revocations
+func syncRevocations(ctx context.Context) {
+	rs, err := datasource.GetSessionManager().ListRevocation(ctx)
+	if err != nil {
+		log.Errorf(err, "load the token revocation list failed")
+		return
+	}
+	now := time.Now().Unix()
+	alive := make([]*datasource.Revocation, 0, len(rs))
+	for _, r := range rs {
+		if r.ExpireAt > 0 && r.ExpireAt <= now {
+			if err := datasource.GetSessionManager().DeleteRevocation(ctx, r.Key); err != nil {
+				log.Errorf(err, "remove expired revocation [%s] failed", r.Key)
+			}
+			continue
+		}
+		alive = append(alive, r)
+	}
+	revocations.Merge(alive)
+}
+
+func startRevocationSync() {
+	syncRevocations(context.Background())
+	gopool.Go(func(ctx context.Context) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(revocationSyncInterval):
+				syncRevocations(ctx)
+			}
+		}
+	})
+}
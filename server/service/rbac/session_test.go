@@ -0,0 +1,132 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-chassis/v2/security/authr"
+	"github.com/stretchr/testify/assert"
+
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestSession(t *testing.T) {
+	ctx := context.TODO()
+	name := "TestSession_account"
+	a := newAccount(name)
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, a))
+	defer rbacsvc.DeleteAccount(ctx, name)
+
+	login := func(t *testing.T, pwd string) (string, map[string]interface{}) {
+		token, err := authr.Login(ctx, name, pwd, authr.ExpireAfter("1h"))
+		assert.NoError(t, err)
+		claims, err := authr.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		return token, claims.(map[string]interface{})
+	}
+	assertRevoked := func(t *testing.T, token string) {
+		_, err := authr.Authenticate(ctx, token)
+		assert.Error(t, err)
+		assert.Equal(t, rbacsvc.ErrTokenRevoked, err.(*errsvc.Error).Code)
+	}
+
+	t.Run("login, should create a session", func(t *testing.T) {
+		_, claims := login(t, testPwd0)
+		assert.NotEmpty(t, claims[rbacsvc.ClaimsTokenID])
+		assert.NotEmpty(t, claims[rbacsvc.ClaimsIssuedAt])
+
+		sessionCtx := context.WithValue(ctx, rbacsvc.CtxRequestClaims, claims)
+		sessions, err := rbacsvc.ListSession(sessionCtx, name)
+		assert.NoError(t, err)
+		found := false
+		for _, s := range sessions {
+			if s.ID == claims[rbacsvc.ClaimsTokenID] {
+				found = true
+				assert.True(t, s.Current)
+				assert.True(t, s.ExpireAt > s.IssuedAt)
+			}
+		}
+		assert.True(t, found)
+	})
+	t.Run("verified session, should be a local session", func(t *testing.T) {
+		_, claims := login(t, testPwd0)
+		assert.True(t, rbacsvc.IsLocalSession(ctx, claims))
+
+		forged := map[string]interface{}{
+			rbac.ClaimsUser:       name,
+			rbacsvc.ClaimsSource:  rbacsvc.SourceLocal,
+			rbacsvc.ClaimsTokenID: claims[rbacsvc.ClaimsTokenID],
+		}
+		assert.False(t, rbacsvc.IsLocalSession(ctx, forged))
+
+		assert.NoError(t, rbacsvc.RevokeToken(ctx, claims))
+		assert.False(t, rbacsvc.IsLocalSession(ctx, claims))
+	})
+	t.Run("revoke the token, should not be authenticated", func(t *testing.T) {
+		token, claims := login(t, testPwd0)
+		other, _ := login(t, testPwd0)
+		assert.NoError(t, rbacsvc.RevokeToken(ctx, claims))
+		assertRevoked(t, token)
+		_, err := authr.Authenticate(ctx, other)
+		assert.NoError(t, err)
+	})
+	t.Run("revoke a session, should not be authenticated", func(t *testing.T) {
+		token, claims := login(t, testPwd0)
+		assert.NoError(t, rbacsvc.RevokeSession(ctx, name, claims[rbacsvc.ClaimsTokenID].(string)))
+		assertRevoked(t, token)
+
+		err := rbacsvc.RevokeSession(ctx, name, "not-exist")
+		assert.Equal(t, rbacsvc.ErrSessionNotExist, err.(*errsvc.Error).Code)
+	})
+	t.Run("revoke all sessions, should revoke all the tokens", func(t *testing.T) {
+		token1, _ := login(t, testPwd0)
+		token2, _ := login(t, testPwd0)
+		assert.NoError(t, rbacsvc.RevokeAccountSessions(ctx, name))
+		assertRevoked(t, token1)
+		assertRevoked(t, token2)
+		sessions, err := rbacsvc.ListSession(ctx, name)
+		assert.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+	t.Run("change password, should revoke the old tokens", func(t *testing.T) {
+		token, _ := login(t, testPwd0)
+		claims := map[string]interface{}{
+			rbac.ClaimsUser:  "TestSession_admin",
+			rbac.ClaimsRoles: []interface{}{rbac.RoleAdmin},
+		}
+		adminCtx := context.WithValue(ctx, rbacsvc.CtxRequestClaims, claims)
+		err := rbacsvc.ChangePassword(adminCtx, &rbac.Account{Name: name, Password: testPwd1})
+		assert.NoError(t, err)
+		assertRevoked(t, token)
+		login(t, testPwd1)
+	})
+	t.Run("remove a role, should revoke the old tokens", func(t *testing.T) {
+		token, _ := login(t, testPwd1)
+		claims := map[string]interface{}{
+			rbac.ClaimsUser:  "TestSession_admin",
+			rbac.ClaimsRoles: []interface{}{rbac.RoleAdmin},
+		}
+		adminCtx := context.WithValue(ctx, rbacsvc.CtxRequestClaims, claims)
+		err := rbacsvc.UpdateAccount(adminCtx, name, &rbac.Account{Roles: []string{rbac.RoleDeveloper}})
+		assert.NoError(t, err)
+		assertRevoked(t, token)
+	})
+}
@@ -0,0 +1,286 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chassis/cari/rbac"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/log"
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
+	"github.com/apache/servicecomb-service-center/pkg/totp"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	"github.com/apache/servicecomb-service-center/server/plugin/security/cipher"
+)
+
+const (
+	// ClaimsTOTPRequired is true if the account of the enforced roles does not enroll TOTP
+	// when login, the token can only be used to enroll TOTP
+	ClaimsTOTPRequired = "totpRequired"
+	// CtxTOTPCode is the second factor of the login request
+	CtxTOTPCode util.CtxKey = "_totp_code"
+
+	ErrTOTPNotEnrolled    int32 = 400222
+	ErrTOTPEnabled        int32 = 400223
+	ErrTOTPRequired       int32 = 401223
+	ErrTOTPInvalid        int32 = 401224
+	ErrTOTPEnrollRequired int32 = 403222
+
+	defaultTOTPIssuer = "servicecomb-service-center"
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 8
+)
+
+func init() {
+	rbac.MustRegisterErrs(map[int32]string{
+		ErrTOTPNotEnrolled:    "TOTP is not enrolled",
+		ErrTOTPEnabled:        "TOTP is already enabled",
+		ErrTOTPRequired:       "TOTP code is required",
+		ErrTOTPInvalid:        "TOTP code is invalid",
+		ErrTOTPEnrollRequired: "TOTP must be enrolled first",
+	})
+}
+
+var totpPolicy = &config.TOTP{}
+
+func initTOTPPolicy() {
+	if c := config.GetTOTP(); c != nil {
+		SetTOTPPolicy(c)
+	}
+}
+
+//SetTOTPPolicy replaces the TOTP configs
+func SetTOTPPolicy(c *config.TOTP) {
+	totpPolicy = c
+}
+
+//EnrollTOTP generates a new secret and the recovery codes of the account,
+//the enrolment is enabled after the first code is verified by ConfirmTOTP
+func EnrollTOTP(ctx context.Context, account string) (*rbacpkg.TOTPEnrollment, error) {
+	if _, err := GetAccount(ctx, account); err != nil {
+		return nil, err
+	}
+	old, err := datasource.GetTOTPManager().GetTOTP(ctx, account)
+	if err != nil && err != datasource.ErrTOTPNotExist {
+		log.Errorf(err, "get account [%s] totp failed", account)
+		return nil, err
+	}
+	if old != nil && old.Enabled {
+		return nil, rbac.NewError(ErrTOTPEnabled, "disable it before enrolling again")
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		log.Error("generate totp secret failed", err)
+		return nil, err
+	}
+	encrypted, err := cipher.Encrypt(secret)
+	if err != nil {
+		log.Error("encrypt totp secret failed", err)
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = datasource.GetTOTPManager().UpsertTOTP(ctx, &datasource.TOTP{
+		Account:       account,
+		Secret:        encrypted,
+		RecoveryCodes: hashes,
+		CreateTime:    strconv.FormatInt(time.Now().Unix(), 10),
+	})
+	if err != nil {
+		log.Errorf(err, "save account [%s] totp failed", account)
+		return nil, err
+	}
+	log.Infof("account [%s] enrolls totp", account)
+	issuer := totpPolicy.Issuer
+	if len(issuer) == 0 {
+		issuer = defaultTOTPIssuer
+	}
+	return &rbacpkg.TOTPEnrollment{
+		Secret:        secret,
+		URI:           totp.URI(issuer, account, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+//ConfirmTOTP enables the enrolment if the code is valid
+func ConfirmTOTP(ctx context.Context, account, code string) error {
+	t, err := getTOTP(ctx, account)
+	if err != nil {
+		return err
+	}
+	if t.Enabled {
+		return rbac.NewError(ErrTOTPEnabled, "")
+	}
+	step, ok, err := validateTOTPCode(t, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return rbac.NewError(ErrTOTPInvalid, "")
+	}
+	t.Enabled = true
+	t.LastStep = step
+	err = datasource.GetTOTPManager().UpsertTOTP(ctx, t)
+	if err != nil {
+		log.Errorf(err, "save account [%s] totp failed", account)
+		return err
+	}
+	log.Infof("account [%s] enables totp", account)
+	return nil
+}
+
+func GetTOTPStatus(ctx context.Context, account string) (*rbacpkg.TOTPStatus, error) {
+	t, err := getTOTP(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	return &rbacpkg.TOTPStatus{
+		Account:       t.Account,
+		Enabled:       t.Enabled,
+		RecoveryCodes: len(t.RecoveryCodes),
+		CreateTime:    t.CreateTime,
+	}, nil
+}
+
+//DisableTOTP removes the enrolment, the admin can reset the account which loses the device
+func DisableTOTP(ctx context.Context, account string) error {
+	if _, err := getTOTP(ctx, account); err != nil {
+		return err
+	}
+	err := datasource.GetTOTPManager().DeleteTOTP(ctx, account)
+	if err != nil {
+		log.Errorf(err, "remove account [%s] totp failed", account)
+		return err
+	}
+	log.Infof("account [%s] disables totp", account)
+	return nil
+}
+
+//checkTOTP is called after the password is verified, it verifies the code in ctx
+//if the account enables TOTP. the returned bool is true if the account must enroll TOTP
+func checkTOTP(ctx context.Context, a *rbac.Account) (bool, error) {
+	t, err := datasource.GetTOTPManager().GetTOTP(ctx, a.Name)
+	if err != nil && err != datasource.ErrTOTPNotExist {
+		log.Errorf(err, "get account [%s] totp failed", a.Name)
+		return false, err
+	}
+	if t == nil || !t.Enabled {
+		return mustEnrollTOTP(a.Roles), nil
+	}
+	code, _ := ctx.Value(CtxTOTPCode).(string)
+	if len(code) == 0 {
+		return false, rbac.NewError(ErrTOTPRequired, "")
+	}
+	if len(code) == totp.Digits {
+		step, ok, err := validateTOTPCode(t, code)
+		if err != nil {
+			return false, err
+		}
+		// the code can not be replayed
+		if !ok || step <= t.LastStep {
+			return false, rbac.NewError(ErrTOTPInvalid, "")
+		}
+		t.LastStep = step
+	} else if !useRecoveryCode(t, code) {
+		return false, rbac.NewError(ErrTOTPInvalid, "")
+	}
+	err = datasource.GetTOTPManager().UpsertTOTP(ctx, t)
+	if err != nil {
+		log.Errorf(err, "save account [%s] totp failed", a.Name)
+		return false, err
+	}
+	return false, nil
+}
+
+func mustEnrollTOTP(roles []string) bool {
+	for _, r := range roles {
+		if util.SliceHave(totpPolicy.EnforceRoles, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func getTOTP(ctx context.Context, account string) (*datasource.TOTP, error) {
+	t, err := datasource.GetTOTPManager().GetTOTP(ctx, account)
+	if err != nil {
+		if err == datasource.ErrTOTPNotExist {
+			return nil, rbac.NewError(ErrTOTPNotEnrolled, fmt.Sprintf("account [%s] does not enroll totp", account))
+		}
+		log.Errorf(err, "get account [%s] totp failed", account)
+		return nil, err
+	}
+	return t, nil
+}
+
+func validateTOTPCode(t *datasource.TOTP, code string) (int64, bool, error) {
+	secret, err := cipher.Decrypt(t.Secret)
+	if err != nil {
+		log.Errorf(err, "decrypt account [%s] totp secret failed", t.Account)
+		return 0, false, err
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	return step, ok, nil
+}
+
+//useRecoveryCode removes the recovery code if it is valid, each code can be used once
+func useRecoveryCode(t *datasource.TOTP, code string) bool {
+	hash := hashRecoveryCode(code)
+	for i, h := range t.RecoveryCodes {
+		if h == hash {
+			t.RecoveryCodes = append(t.RecoveryCodes[:i:i], t.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, recoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			log.Error("generate recovery code failed", err)
+			return nil, nil, err
+		}
+		s := hex.EncodeToString(b)
+		code := s[:len(s)/2] + "-" + s[len(s)/2:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode ignores the case and the separator the user typed
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package rbac_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-chassis/cari/pkg/errsvc"
+	"github.com/go-chassis/cari/rbac"
+	"github.com/go-chassis/go-chassis/v2/security/authr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/servicecomb-service-center/datasource"
+	"github.com/apache/servicecomb-service-center/pkg/totp"
+	"github.com/apache/servicecomb-service-center/pkg/util"
+	"github.com/apache/servicecomb-service-center/server/config"
+	rbacsvc "github.com/apache/servicecomb-service-center/server/service/rbac"
+)
+
+func TestTOTP(t *testing.T) {
+	ctx := context.TODO()
+	name := "TestTOTP_account"
+	a := newAccount(name)
+	assert.NoError(t, rbacsvc.CreateAccount(ctx, a))
+	defer rbacsvc.DeleteAccount(ctx, name)
+
+	login := func(code string) (map[string]interface{}, error) {
+		token, err := authr.Login(util.SetContext(ctx, rbacsvc.CtxTOTPCode, code), name, testPwd0)
+		if err != nil {
+			return nil, err
+		}
+		claims, err := authr.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		return claims.(map[string]interface{}), nil
+	}
+	assertCode := func(t *testing.T, code int32, err error) {
+		assert.Error(t, err)
+		assert.Equal(t, code, err.(*errsvc.Error).Code)
+	}
+
+	t.Run("enforce the role without enrolment, should be required to enroll", func(t *testing.T) {
+		rbacsvc.SetTOTPPolicy(&config.TOTP{EnforceRoles: []string{rbac.RoleDeveloper}})
+		defer rbacsvc.SetTOTPPolicy(&config.TOTP{})
+		claims, err := login("")
+		assert.NoError(t, err)
+		assert.Equal(t, true, claims[rbacsvc.ClaimsTOTPRequired])
+	})
+	var e struct {
+		secret        string
+		recoveryCodes []string
+	}
+	t.Run("enroll and verify, should require the code when login", func(t *testing.T) {
+		enrollment, err := rbacsvc.EnrollTOTP(ctx, name)
+		assert.NoError(t, err)
+		assert.Contains(t, enrollment.URI, "otpauth://totp/")
+		assert.Len(t, enrollment.RecoveryCodes, 10)
+		e.secret, e.recoveryCodes = enrollment.Secret, enrollment.RecoveryCodes
+
+		// not enabled before verified
+		_, err = login("")
+		assert.NoError(t, err)
+		assertCode(t, rbacsvc.ErrTOTPInvalid, rbacsvc.ConfirmTOTP(ctx, name, "000000x"))
+		code, err := totp.Code(e.secret, totp.Step(time.Now().Add(-totp.Period)))
+		assert.NoError(t, err)
+		assert.NoError(t, rbacsvc.ConfirmTOTP(ctx, name, code))
+		status, err := rbacsvc.GetTOTPStatus(ctx, name)
+		assert.NoError(t, err)
+		assert.True(t, status.Enabled)
+
+		_, err = login("")
+		assertCode(t, rbacsvc.ErrTOTPRequired, err)
+		// the code used to verify can not be replayed
+		_, err = login(code)
+		assertCode(t, rbacsvc.ErrTOTPInvalid, err)
+		code, err = totp.Code(e.secret, totp.Step(time.Now()))
+		assert.NoError(t, err)
+		_, err = login(code)
+		assert.NoError(t, err)
+	})
+	t.Run("login without the code, should not record the login", func(t *testing.T) {
+		s, err := datasource.GetAccountStateManager().GetAccountState(ctx, name)
+		assert.NoError(t, err)
+		s.LastLoginAt = 1
+		assert.NoError(t, datasource.GetAccountStateManager().UpsertAccountState(ctx, s))
+		_, err = login("")
+		assertCode(t, rbacsvc.ErrTOTPRequired, err)
+		s, err = datasource.GetAccountStateManager().GetAccountState(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), s.LastLoginAt)
+	})
+	t.Run("login with recovery code, should be used once", func(t *testing.T) {
+		_, err := login(e.recoveryCodes[0])
+		assert.NoError(t, err)
+		_, err = login(e.recoveryCodes[0])
+		assertCode(t, rbacsvc.ErrTOTPInvalid, err)
+		status, err := rbacsvc.GetTOTPStatus(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, status.RecoveryCodes)
+	})
+	t.Run("disable, should not require the code", func(t *testing.T) {
+		_, err := rbacsvc.EnrollTOTP(ctx, name)
+		assertCode(t, rbacsvc.ErrTOTPEnabled, err)
+		assert.NoError(t, rbacsvc.DisableTOTP(ctx, name))
+		_, err = login("")
+		assert.NoError(t, err)
+		assertCode(t, rbacsvc.ErrTOTPNotEnrolled, rbacsvc.DisableTOTP(ctx, name))
+	})
+}
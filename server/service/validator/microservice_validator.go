@@ -32,6 +32,7 @@ var (
 	getServiceReqValidator         validate.Validator
 	createServiceReqValidator      validate.Validator
 	updateServicePropsReqValidator validate.Validator
+	applyServiceReqValidator       validate.Validator
 )
 
 var (
@@ -113,3 +114,23 @@ func UpdateServicePropsReqValidator() *validate.Validator {
 		v.AddRule("ServiceId", GetServiceReqValidator().GetRule("ServiceId"))
 	})
 }
+
+func ApplyServiceReqValidator() *validate.Validator {
+	return applyServiceReqValidator.Init(func(v *validate.Validator) {
+		// the instances belong to the service in document, allow empty serviceId
+		var instanceValidator validate.Validator
+		instanceValidator.AddRules(RegisterInstanceReqValidator().GetSub("Instance").GetRules())
+		instanceValidator.AddSubs(RegisterInstanceReqValidator().GetSub("Instance").GetSubs())
+		instanceValidator.AddRule("ServiceId", CreateServiceReqValidator().GetSub("Service").GetRule("ServiceId"))
+
+		v.AddRules(CreateServiceReqValidator().GetRules())
+		v.AddSubs(CreateServiceReqValidator().GetSubs())
+		v.AddRule("Schemas", &validate.Rule{Max: quota.DefaultSchemaQuota})
+		v.AddSub("Schemas", ModifySchemasReqValidator().GetSub("Schemas"))
+		v.AddRule("Tags", AddTagsReqValidator().GetRule("Tags"))
+		v.AddRule("Rules", &validate.Rule{Max: quota.DefaultRuleQuota})
+		v.AddSub("Rules", AddRulesReqValidator().GetSub("Rules"))
+		v.AddRule("Instances", &validate.Rule{Max: quota.DefaultInstanceQuota})
+		v.AddSub("Instances", &instanceValidator)
+	})
+}
@@ -19,7 +19,13 @@
 
 package validator
 
-import "github.com/go-chassis/cari/rbac"
+import (
+	"fmt"
+
+	"github.com/go-chassis/cari/rbac"
+
+	rbacpkg "github.com/apache/servicecomb-service-center/pkg/rbac"
+)
 
 func ValidateCreateAccount(a *rbac.Account) error {
 	err := baseCheck(a)
@@ -36,12 +42,27 @@ func ValidateUpdateAccount(a *rbac.Account) error {
 	}
 	return updateAccountValidator.Validate(a)
 }
-func ValidateCreateRole(a *rbac.Role) error {
+//ValidateCreateRole validates the role and its parent roles, the role can not inherit itself
+func ValidateCreateRole(a *rbac.Role, parents ...string) error {
 	err := baseCheck(a)
 	if err != nil {
 		return err
 	}
-	return createRoleValidator.Validate(a)
+	err = roleInheritanceValidator.Validate(&rbacpkg.Role{Role: *a, Parents: parents})
+	if err != nil {
+		return err
+	}
+	for i, p := range parents {
+		if p == a.Name {
+			return fmt.Errorf("role '%s' can not inherit itself", a.Name)
+		}
+		for _, q := range parents[:i] {
+			if p == q {
+				return fmt.Errorf("parent role '%s' is duplicated", p)
+			}
+		}
+	}
+	return nil
 }
 func ValidateAccountLogin(a *rbac.Account) error {
 	err := baseCheck(a)
@@ -50,6 +71,14 @@ func ValidateAccountLogin(a *rbac.Account) error {
 	}
 	return accountLoginValidator.Validate(a)
 }
+//SetPasswordPolicy changes the password length and character classes rule of
+//creating account and changing password, the zero value uses the default
+func SetPasswordPolicy(minLength, maxLength, minClasses int) {
+	passwordChecker.MinLength = minLength
+	passwordChecker.MaxLength = maxLength
+	passwordChecker.MinClasses = minClasses
+}
+
 func ValidateChangePWD(a *rbac.Account) error {
 	err := baseCheck(a)
 	if err != nil {
@@ -122,7 +122,8 @@ func TestValidateUpdateAccount(t *testing.T) {
 
 func TestValidateCreateRole(t *testing.T) {
 	type args struct {
-		a *rbac.Role
+		a       *rbac.Role
+		parents []string
 	}
 	tests := []struct {
 		name    string
@@ -141,9 +142,33 @@ func TestValidateCreateRole(t *testing.T) {
 			}},
 			wantErr: false,
 		},
+		{name: "given valid parent roles",
+			args: args{a: &rbac.Role{
+				Name: "tester-a",
+			}, parents: []string{"developer", "tester-b"}},
+			wantErr: false,
+		},
+		{name: "given invalid parent role name",
+			args: args{a: &rbac.Role{
+				Name: "tester-a",
+			}, parents: []string{"tester*b"}},
+			wantErr: true,
+		},
+		{name: "given the role itself as parent",
+			args: args{a: &rbac.Role{
+				Name: "tester-a",
+			}, parents: []string{"developer", "tester-a"}},
+			wantErr: true,
+		},
+		{name: "given duplicated parent roles",
+			args: args{a: &rbac.Role{
+				Name: "tester-a",
+			}, parents: []string{"developer", "developer"}},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
-		if err := validator.ValidateCreateRole(tt.args.a); (err != nil) != tt.wantErr {
+		if err := validator.ValidateCreateRole(tt.args.a, tt.args.parents...); (err != nil) != tt.wantErr {
 			t.Errorf("%q. ValidateCreateRole() error = %v, wantErr %v", tt.name, err, tt.wantErr)
 		}
 	}
@@ -32,8 +32,8 @@ var (
 )
 
 var (
-	ruleRegex, _     = regexp.Compile(`^(WHITE|BLACK)$`)
-	ruleAttrRegex, _ = regexp.Compile(`((^tag_[a-zA-Z][a-zA-Z0-9_\-.]{0,63}$)|(^ServiceId$)|(^AppId$)|(^ServiceName$)|(^Version$)|(^Description$)|(^Level$)|(^Status$))`)
+	ruleRegex, _     = regexp.Compile(`^(WHITE|BLACK|ALLOW|DENY)$`)
+	ruleAttrRegex, _ = regexp.Compile(`((^tag_[a-zA-Z][a-zA-Z0-9_\-.]{0,63}$)|(^ServiceId$)|(^AppId$)|(^ServiceName$)|(^Version$)|(^Description$)|(^Level$)|(^Status$)|(^Environment$)|(^ip$)|(^expr$))`)
 )
 
 func GetRulesReqValidator() *validate.Validator {
@@ -47,7 +47,7 @@ func UpdateRuleReqValidator() *validate.Validator {
 		var ruleValidator validate.Validator
 		ruleValidator.AddRule("RuleType", &validate.Rule{Regexp: ruleRegex})
 		ruleValidator.AddRule("Attribute", &validate.Rule{Regexp: ruleAttrRegex})
-		ruleValidator.AddRule("Pattern", &validate.Rule{Min: 1, Max: 64})
+		ruleValidator.AddRule("Pattern", &validate.Rule{Min: 1, Max: 256})
 		ruleValidator.AddRule("Description", CreateServiceReqValidator().GetSub("Service").GetRule("Description"))
 
 		v.AddRule("ServiceId", GetServiceReqValidator().GetRule("ServiceId"))
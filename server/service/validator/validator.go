@@ -23,6 +23,7 @@ import (
 
 	pb "github.com/go-chassis/cari/discovery"
 
+	"github.com/apache/servicecomb-service-center/datasource"
 	"github.com/apache/servicecomb-service-center/pkg/log"
 	"github.com/apache/servicecomb-service-center/pkg/validate"
 )
@@ -30,14 +31,17 @@ import (
 var createAccountValidator = &validate.Validator{}
 var updateAccountValidator = &validate.Validator{}
 var createRoleValidator = &validate.Validator{}
+var roleInheritanceValidator = &validate.Validator{}
 
 var changePWDValidator = &validate.Validator{}
 var accountLoginValidator = &validate.Validator{}
 
+var passwordChecker = &validate.PasswordChecker{}
+
 func init() {
 	createAccountValidator.AddRule("Name", &validate.Rule{Max: 64, Regexp: nameRegex})
 	createAccountValidator.AddRule("Roles", &validate.Rule{Min: 1, Max: 5, Regexp: nameRegex})
-	createAccountValidator.AddRule("Password", &validate.Rule{Regexp: &validate.PasswordChecker{}})
+	createAccountValidator.AddRule("Password", &validate.Rule{Regexp: passwordChecker})
 	createAccountValidator.AddRule("Status", &validate.Rule{Regexp: accountStatusRegex})
 
 	updateAccountValidator.AddRule("Roles", createAccountValidator.GetRule("Roles"))
@@ -45,7 +49,10 @@ func init() {
 
 	createRoleValidator.AddRule("Name", &validate.Rule{Max: 64, Regexp: nameRegex})
 
-	changePWDValidator.AddRule("Password", &validate.Rule{Regexp: &validate.PasswordChecker{}})
+	roleInheritanceValidator.AddSub("Role", createRoleValidator)
+	roleInheritanceValidator.AddRule("Parents", &validate.Rule{Max: 5, Regexp: nameRegex})
+
+	changePWDValidator.AddRule("Password", &validate.Rule{Regexp: passwordChecker})
 	changePWDValidator.AddRule("Name", &validate.Rule{Regexp: nameRegex})
 
 	accountLoginValidator.AddRule("TokenExpirationTime", &validate.Rule{Regexp: &validate.TokenExpirationTimeChecker{}})
@@ -111,6 +118,8 @@ func Validate(v interface{}) error {
 		return DeleteRulesReqValidator().Validate(v)
 	case *pb.GetAppsRequest:
 		return MicroServiceKeyValidator().Validate(v)
+	case *datasource.ServiceDocument:
+		return ApplyServiceReqValidator().Validate(v)
 	default:
 		log.Warnf("No validator for %T.", t)
 		return nil